@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"browser-agent/internal/di"
+	"browser-agent/internal/domain/entity"
+	"browser-agent/internal/infrastructure/batchfile"
+	"browser-agent/internal/infrastructure/config"
+	"browser-agent/internal/infrastructure/logger"
+	"browser-agent/internal/infrastructure/transcript"
+	"browser-agent/internal/infrastructure/userinteraction"
+	"browser-agent/internal/usecase/batch"
+)
+
+// runCommand handles "agent run". The task is taken from the arguments or
+// read from the console; with --tasks the file is run without a user.
+func runCommand(args []string) int {
+	var opts options
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	opts.register(flags)
+	opts.registerOutput(flags)
+	tasksPath := flags.String("tasks", "", "YAML task file; enables non-interactive batch mode")
+	outputPath := flags.String("output", "results.jsonl", "batch results file (.jsonl or .csv)")
+	concurrency := flags.Int("concurrency", 0, "batch tasks run in parallel, each in its own browser (budgets.batch_concurrency)")
+	transcriptPath := flags.String("transcript", "", "write the run report to this file: .md, .html or .json")
+	workflowPath := flags.String("workflow", "", "compile a successful run into a workflow JSON file (see: ai-agent workflow)")
+	transcriptDir := flags.String("transcripts", "", "batch mode: write a run report per task to this directory")
+	transcriptFormat := flags.String("transcript-format", "html", "format of --transcripts reports: md, html or json")
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+	cfg, err := opts.load(flags)
+	if err != nil {
+		log.Printf("Ошибка конфигурации: %v", err)
+		return 2
+	}
+
+	if *tasksPath == "" {
+		if *transcriptPath != "" {
+			if _, err := transcript.FormatFromPath(*transcriptPath); err != nil {
+				log.Printf("Ошибка параметров: %v", err)
+				return 2
+			}
+		}
+		return runInteractive(cfg, strings.Join(flags.Args(), " "), runExports{transcript: *transcriptPath, workflow: *workflowPath})
+	}
+	if flags.NArg() > 0 {
+		log.Printf("Задача в аргументах не используется вместе с --tasks")
+		return 2
+	}
+	if *workflowPath != "" {
+		log.Printf("--workflow работает только для одной задачи, без --tasks")
+		return 2
+	}
+	if *concurrency > 0 {
+		cfg.Budgets.BatchConcurrency = *concurrency
+	}
+	var reports *transcriptOptions
+	if *transcriptDir != "" {
+		format, err := transcript.ParseFormat(*transcriptFormat)
+		if err != nil {
+			log.Printf("Ошибка параметров: %v", err)
+			return 2
+		}
+		reports = &transcriptOptions{dir: *transcriptDir, format: format}
+	}
+	return runBatch(cfg, *tasksPath, *outputPath, reports)
+}
+
+// transcriptOptions select where batch mode writes the per-task reports.
+type transcriptOptions struct {
+	dir    string
+	format transcript.Format
+}
+
+func runBatch(appCfg *config.Config, tasksPath, outputPath string, reports *transcriptOptions) int {
+	tasks, err := batchfile.Load(tasksPath)
+	if err != nil {
+		log.Printf("Ошибка файла задач: %v", err)
+		return 1
+	}
+	for i := range tasks {
+		if tasks[i].StartURL == "" {
+			tasks[i].StartURL = appCfg.Browser.StartURL
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Nobody watches a batch: headless unless configured otherwise.
+	cfg, redactor, err := loadContainerConfig(appCfg, true)
+	if err != nil {
+		log.Printf("Ошибка конфигурации: %v", err)
+		return 1
+	}
+
+	batchLog, err := logger.NewLoggerAdapter()
+	if err != nil {
+		log.Printf("Ошибка инициализации: %v", err)
+		return 1
+	}
+	defer batchLog.Close()
+	batchLog.SetRedactor(redactor)
+	batchLog.SetLevel(cfg.LogLevel)
+
+	scripted := userinteraction.NewScriptedUserInteraction(batchLog)
+	cfg.UserInteraction = scripted
+	cfg.RecordTranscripts = reports != nil
+	if reports != nil {
+		if err := os.MkdirAll(reports.dir, 0o755); err != nil {
+			log.Printf("Ошибка каталога отчётов: %v", err)
+			return 1
+		}
+	}
+
+	concurrency := max(1, min(appCfg.Budgets.BatchConcurrency, len(tasks)))
+	cfg.BrowserPoolSize = concurrency
+	container, err := di.NewContainer(ctx, cfg)
+	if err != nil {
+		log.Printf("Ошибка инициализации: %v", err)
+		return 1
+	}
+	defer container.Close()
+
+	// The browser of a task is leased when it starts; the executor opens
+	// the start URL in it.
+	workers := make([]batch.Worker, concurrency)
+	for i := range workers {
+		workers[i] = batch.Worker{Executor: container.TaskExecutor}
+	}
+
+	writer, err := batchfile.NewResultWriter(outputPath)
+	if err != nil {
+		log.Printf("Ошибка файла результатов: %v", err)
+		return 1
+	}
+	defer writer.Close()
+
+	batchCfg := batch.DefaultConfig()
+	batchCfg.TaskTimeout = appCfg.Budgets.TaskTimeout
+	done := 0
+	batchCfg.OnResult = func(result entity.BatchResult) {
+		done++
+		if reports != nil {
+			if err := writeBatchTranscript(container.Transcripts, reports, result); err != nil {
+				batchLog.Warn("Transcript not written", "task", result.ID, "error", err)
+			}
+		}
+		if appCfg.Output.Format == formatJSON {
+			printJSON(redactor, result)
+			return
+		}
+		line := fmt.Sprintf("[%d/%d] %s: %s", done, len(tasks), result.ID, result.Status)
+		if result.Error != "" {
+			line += " — " + result.Error
+		}
+		fmt.Println(redactor.Redact(line))
+	}
+
+	if appCfg.Output.Format == formatText {
+		fmt.Printf("Запуск %d задач (параллельно: %d)...\n", len(tasks), concurrency)
+	}
+	results, err := batch.New(workers, scripted, writer, batchLog, batchCfg).Run(ctx, tasks)
+	if err != nil {
+		log.Printf("Ошибка записи результатов: %v", err)
+		return 1
+	}
+
+	completed := 0
+	for _, result := range results {
+		if result.Status == entity.TaskStatusCompleted {
+			completed++
+		}
+	}
+	if appCfg.Output.Format == formatText {
+		fmt.Printf("Готово: %d из %d задач выполнено, результаты в %s\n", completed, len(results), outputPath)
+	}
+
+	if completed != len(results) {
+		return 1
+	}
+	return 0
+}
+
+// writeBatchTranscript writes the report of a finished task and frees the
+// recording.
+func writeBatchTranscript(recorder *transcript.Recorder, reports *transcriptOptions, result entity.BatchResult) error {
+	if _, ok := recorder.Transcript(result.ID); !ok {
+		return transcript.ErrNoTranscript
+	}
+	defer recorder.Forget(result.ID)
+
+	recorder.Complete(result.ID, result.Status, result.FinalAnswer, result.Error)
+	path := filepath.Join(reports.dir, result.ID+"."+string(reports.format))
+	return writeTranscript(recorder, result.ID, path)
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/di"
+	"browser-agent/internal/domain/entity"
+	"browser-agent/internal/infrastructure/config"
+)
+
+// profilesCommand lists the profiles defined by .env.<profile> files of the
+// working directory and by the profiles section of the config file. A
+// profile is selected with --profile or APP_ENV.
+func profilesCommand(args []string) int {
+	flags := flag.NewFlagSet("profiles", flag.ContinueOnError)
+	configFile := flags.String("config", "", "YAML config file (env CONFIG_FILE, default "+config.DefaultFile+" if present)")
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	active := os.Getenv("APP_ENV")
+	if active == "" {
+		active = "dev"
+	}
+
+	files, err := filepath.Glob(".env.*")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка поиска профилей: %v\n", err)
+		return 1
+	}
+
+	sources := make(map[string][]string)
+	for _, file := range files {
+		if name := strings.TrimPrefix(file, ".env."); name != "example" {
+			sources[name] = append(sources[name], file)
+		}
+	}
+
+	if path := config.FindFile(*configFile, os.LookupEnv); path != "" {
+		names, err := config.FileProfiles(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Ошибка чтения профилей: %v\n", err)
+			return 1
+		}
+		for _, name := range names {
+			sources[name] = append(sources[name], path+" (profiles."+name+")")
+		}
+	}
+
+	if len(sources) == 0 {
+		fmt.Println("Профили не найдены: создайте файл .env.<профиль> или секцию profiles в файле конфигурации")
+		return 0
+	}
+
+	profiles := make([]string, 0, len(sources))
+	for name := range sources {
+		profiles = append(profiles, name)
+	}
+	sort.Strings(profiles)
+
+	for _, name := range profiles {
+		marker := " "
+		if name == active {
+			marker = "*"
+		}
+		fmt.Printf("%s %-10s %s\n", marker, name, strings.Join(sources[name], ", "))
+	}
+	return 0
+}
+
+// toolsCommand implements "tools list": the tools the orchestrator and the
+// sub-agents can call, without starting a browser.
+func toolsCommand(args []string) int {
+	if len(args) == 0 || args[0] != "list" {
+		fmt.Fprintln(os.Stderr, "Использование: ai-agent tools list [--format text|json]")
+		return 2
+	}
+
+	flags := flag.NewFlagSet("tools list", flag.ContinueOnError)
+	format := flags.String("format", formatText, "output format: text or json")
+	if code, ok := parseFlags(flags, args[1:]); !ok {
+		return code
+	}
+
+	orchestratorTools, agentTools := di.ToolCatalog()
+
+	switch *format {
+	case formatJSON:
+		data, err := json.MarshalIndent(map[string]any{
+			"orchestrator": sortedDefinitions(orchestratorTools),
+			"agents":       sortedDefinitions(agentTools),
+		}, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Ошибка кодирования: %v\n", err)
+			return 1
+		}
+		fmt.Println(string(data))
+	case formatText:
+		printTools("Оркестратор", orchestratorTools)
+		fmt.Println()
+		printTools("Агенты (navigation, extraction, form)", agentTools)
+	default:
+		fmt.Fprintf(os.Stderr, "Неизвестный формат %q (text или json)\n", *format)
+		return 2
+	}
+	return 0
+}
+
+func printTools(title string, registry output.ToolRegistry) {
+	fmt.Printf("%s:\n", title)
+	for _, def := range sortedDefinitions(registry) {
+		// The first sentence is enough for a listing.
+		summary, _, _ := strings.Cut(strings.TrimSpace(def.Description), "\n")
+		if end := strings.Index(summary, ". "); end >= 0 {
+			summary = summary[:end+1]
+		}
+		fmt.Printf("  %-24s %s\n", def.Name, summary)
+	}
+}
+
+type toolInfo struct {
+	Name        entity.ToolName `json:"name"`
+	Description string          `json:"description"`
+	Parameters  map[string]any  `json:"parameters"`
+}
+
+func sortedDefinitions(registry output.ToolRegistry) []toolInfo {
+	defs := registry.Definitions()
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+
+	tools := make([]toolInfo, 0, len(defs))
+	for _, def := range defs {
+		tools = append(tools, toolInfo{Name: def.Name, Description: def.Description, Parameters: def.Parameters})
+	}
+	return tools
+}
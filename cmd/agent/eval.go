@@ -0,0 +1,307 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"browser-agent/internal/di"
+	"browser-agent/internal/domain/entity"
+	"browser-agent/internal/infrastructure/batchfile"
+	"browser-agent/internal/infrastructure/config"
+	"browser-agent/internal/infrastructure/fixtures"
+	"browser-agent/internal/infrastructure/logger"
+	"browser-agent/internal/infrastructure/prompts"
+	"browser-agent/internal/infrastructure/redaction"
+	"browser-agent/internal/infrastructure/userinteraction"
+	"browser-agent/internal/usecase/benchmark"
+)
+
+const (
+	defaultSuite    = "test/integration/testdata/eval/suite.yaml"
+	defaultFixtures = "test/integration/testdata"
+	// embeddedPrompts selects the prompts built into the binary in
+	// --prompts.
+	embeddedPrompts = "embedded"
+)
+
+// promptFlags collects repeated --prompts directories.
+type promptFlags []string
+
+func (p *promptFlags) String() string     { return strings.Join(*p, ",") }
+func (p *promptFlags) Set(v string) error { *p = append(*p, v); return nil }
+
+// promptVariant is a prompt set with the name it is reported under.
+type promptVariant struct {
+	source string
+	set    prompts.Set
+}
+
+// evalCommand runs the eval suite against the local fixture sites with every
+// combination of the given models and prompt sets.
+func evalCommand(args []string) int {
+	var opts options
+	flags := flag.NewFlagSet("eval", flag.ContinueOnError)
+	opts.register(flags)
+	flags.StringVar(&opts.format, "format", "", "output format: text or json (output.format)")
+	suitePath := flags.String("suite", defaultSuite, "YAML eval suite")
+	fixturesDir := flags.String("fixtures", defaultFixtures, "directory served to the tasks; start_url paths are relative to it")
+	models := flags.String("models", "", "comma-separated models to compare (default: llm.model)")
+	var promptDirs promptFlags
+	flags.Var(&promptDirs, "prompts", "directory with *.txt prompts to evaluate, or \""+embeddedPrompts+"\" (repeatable, default: embedded)")
+	only := flags.String("only", "", "comma-separated task ids to run")
+	reportPath := flags.String("report", "", "write the full report to this JSON file")
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	cases, err := batchfile.LoadSuite(*suitePath)
+	if err != nil {
+		log.Printf("Ошибка набора задач: %v", err)
+		return 2
+	}
+	if cases, err = filterCases(cases, *only); err != nil {
+		log.Printf("Ошибка параметров: %v", err)
+		return 2
+	}
+
+	variants, err := loadPromptVariants(promptDirs)
+	if err != nil {
+		log.Printf("Ошибка промптов: %v", err)
+		return 2
+	}
+
+	appCfg, err := opts.load(flags)
+	if err != nil {
+		log.Printf("Ошибка конфигурации: %v", err)
+		return 2
+	}
+	modelList := splitList(*models)
+	if len(modelList) == 0 {
+		modelList = []string{appCfg.LLM.Model}
+	}
+
+	server, err := fixtures.Serve(*fixturesDir)
+	if err != nil {
+		log.Printf("Ошибка запуска фикстур: %v", err)
+		return 1
+	}
+	defer server.Close()
+	for i := range cases {
+		if strings.HasPrefix(cases[i].StartURL, "/") {
+			cases[i].StartURL = server.URL + cases[i].StartURL
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	textOutput := appCfg.Output.Format == formatText
+	if textOutput {
+		fmt.Printf("Задач: %d, моделей: %d, версий промптов: %d (фикстуры: %s)\n", len(cases), len(modelList), len(variants), server.URL)
+	}
+
+	var results []entity.EvalResult
+	var redactor *redaction.Pipeline
+targets:
+	for _, model := range modelList {
+		for _, variant := range variants {
+			var targetResults []entity.EvalResult
+			targetResults, redactor, err = runEvalTarget(ctx, appCfg, model, variant, cases)
+			results = append(results, targetResults...)
+			switch {
+			case err != nil && ctx.Err() != nil:
+				// Report what finished before the interrupt.
+				log.Printf("Прогон прерван")
+				break targets
+			case err != nil:
+				log.Printf("Ошибка прогона: %v", err)
+				return 1
+			}
+		}
+	}
+	if redactor == nil {
+		return 130
+	}
+
+	report := &entity.EvalReport{Results: results, Summaries: benchmark.Summarize(results)}
+	if *reportPath != "" {
+		if err := writeEvalReport(*reportPath, redactor, report); err != nil {
+			log.Printf("Ошибка записи отчёта: %v", err)
+			return 1
+		}
+	}
+
+	if textOutput {
+		printEvalSummaries(report.Summaries, variants)
+	} else {
+		printJSON(redactor, report.Summaries)
+	}
+
+	if ctx.Err() != nil {
+		return 130
+	}
+	for _, result := range results {
+		if !result.Passed {
+			return 1
+		}
+	}
+	return 0
+}
+
+// runEvalTarget runs the cases with one model and prompt set in a browser of
+// their own.
+func runEvalTarget(ctx context.Context, appCfg *config.Config, model string, variant promptVariant, cases []entity.EvalCase) ([]entity.EvalResult, *redaction.Pipeline, error) {
+	targetCfg := *appCfg
+	targetCfg.LLM.Model = model
+
+	// Nobody watches an eval: headless unless configured otherwise.
+	cfg, redactor, err := loadContainerConfig(&targetCfg, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	evalLog, err := logger.NewLoggerAdapter()
+	if err != nil {
+		return nil, redactor, err
+	}
+	defer evalLog.Close()
+	evalLog.SetRedactor(redactor)
+	evalLog.SetLevel(cfg.LogLevel)
+
+	scripted := userinteraction.NewScriptedUserInteraction(evalLog)
+	cfg.UserInteraction = scripted
+	cfg.RecordTranscripts = true
+	cfg.Prompts = &variant.set
+
+	container, err := di.NewContainer(ctx, cfg)
+	if err != nil {
+		return nil, redactor, err
+	}
+	defer container.Close()
+
+	version := variant.set.Version()
+	done := 0
+	harness := benchmark.New(scripted, evalLog, benchmark.Config{
+		TaskTimeout: appCfg.Budgets.TaskTimeout,
+		OnResult: func(result entity.EvalResult) {
+			done++
+			if appCfg.Output.Format == formatJSON {
+				printJSON(redactor, result)
+				return
+			}
+			verdict := "OK"
+			if !result.Passed {
+				verdict = "FAIL"
+			}
+			line := fmt.Sprintf("[%d/%d] %s %s %s: %s", done, len(cases), model, version, result.ID, verdict)
+			for _, failure := range result.Failures {
+				line += "\n      " + failure
+			}
+			fmt.Println(redactor.Redact(line))
+		},
+	})
+
+	report, err := harness.Run(ctx, []benchmark.Target{{
+		Model:         model,
+		PromptVersion: version,
+		Executor:      container.TaskExecutor,
+		Browser:       container.Browser,
+		Usage: func(taskID string) entity.Usage {
+			recorded, ok := container.Transcripts.Transcript(taskID)
+			container.Transcripts.Forget(taskID)
+			if !ok {
+				return entity.Usage{}
+			}
+			return recorded.Usage
+		},
+	}}, cases)
+	if report == nil {
+		return nil, redactor, err
+	}
+	return report.Results, redactor, err
+}
+
+func loadPromptVariants(dirs []string) ([]promptVariant, error) {
+	if len(dirs) == 0 {
+		dirs = []string{embeddedPrompts}
+	}
+	variants := make([]promptVariant, 0, len(dirs))
+	for _, dir := range dirs {
+		if dir == embeddedPrompts {
+			variants = append(variants, promptVariant{source: embeddedPrompts, set: prompts.Default()})
+			continue
+		}
+		set, err := prompts.LoadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		variants = append(variants, promptVariant{source: dir, set: set})
+	}
+	return variants, nil
+}
+
+func filterCases(cases []entity.EvalCase, only string) ([]entity.EvalCase, error) {
+	ids := splitList(only)
+	if len(ids) == 0 {
+		return cases, nil
+	}
+	byID := make(map[string]entity.EvalCase, len(cases))
+	for _, c := range cases {
+		byID[c.ID] = c
+	}
+	selected := make([]entity.EvalCase, 0, len(ids))
+	for _, id := range ids {
+		c, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("--only: unknown task %q", id)
+		}
+		selected = append(selected, c)
+	}
+	return selected, nil
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func writeEvalReport(path string, redactor *redaction.Pipeline, report *entity.EvalReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	if redactor != nil {
+		data = []byte(redactor.Redact(string(data)))
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func printEvalSummaries(summaries []entity.EvalSummary, variants []promptVariant) {
+	sources := make(map[string]string, len(variants))
+	for _, variant := range variants {
+		sources[variant.set.Version()] = variant.source
+	}
+
+	fmt.Printf("\n%-32s %-20s %9s %9s %10s %10s %8s\n", "Модель", "Промпты", "Успех", "Итерации", "Токены", "Стоимость", "Время")
+	for _, s := range summaries {
+		label := s.PromptVersion
+		if source := sources[s.PromptVersion]; source != "" {
+			label += " (" + source + ")"
+		}
+		fmt.Printf("%-32s %-20s %4d/%-4d %9.1f %10d %10s %7.1fs\n",
+			s.Model, label, s.Passed, s.Tasks, s.AvgIterations, s.Usage.TotalTokens,
+			fmt.Sprintf("$%.4f", s.Usage.CostUSD), float64(s.AvgDurationMS)/1000)
+	}
+}
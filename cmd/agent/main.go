@@ -1,63 +1,421 @@
 package main
 
 import (
-	"bufio"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
+	"browser-agent/internal/application/port/input"
+	"browser-agent/internal/application/port/output"
 	"browser-agent/internal/di"
-	"browser-agent/internal/infrastructure/env"
+	"browser-agent/internal/domain/entity"
+	"browser-agent/internal/domain/policy"
+	"browser-agent/internal/infrastructure/config"
+	"browser-agent/internal/infrastructure/logger"
+	"browser-agent/internal/infrastructure/redaction"
+	"browser-agent/internal/infrastructure/secrets"
+	"browser-agent/internal/infrastructure/transcript"
+	"browser-agent/internal/infrastructure/userinteraction"
+	"browser-agent/internal/usecase/approval"
+	"browser-agent/internal/usecase/batch"
 )
 
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) int
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"run", "выполнить задачу (интерактивно, из аргументов или из файла --tasks)", runCommand},
+		{"serve", "HTTP API и веб-дашборд", serve},
+		{"replay", "повторить записанный запуск без LLM", replayCommand},
+		{"workflow", "сценарии из успешных запусков: workflow compile | run", workflowCommand},
+		{"eval", "прогнать набор задач на локальных фикстурах", evalCommand},
+		{"profiles", "показать доступные профили окружения", profilesCommand},
+		{"tools", "инструменты агентов: tools list", toolsCommand},
+	}
+}
+
 func main() {
-	envService := env.NewEnvService()
+	// Without a command the agent starts the interactive console, as before.
+	if len(os.Args) < 2 || strings.HasPrefix(os.Args[1], "-") {
+		os.Exit(runCommand(os.Args[1:]))
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	name := os.Args[1]
+	if name == "help" {
+		printUsage(os.Stdout)
+		os.Exit(0)
+	}
+	for _, cmd := range commands {
+		if cmd.name == name {
+			os.Exit(cmd.run(os.Args[2:]))
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Неизвестная команда %q\n\n", name)
+	printUsage(os.Stderr)
+	os.Exit(2)
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Использование: ai-agent <команда> [флаги]")
+	fmt.Fprintln(w, "\nКоманды:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w, "\nФлаги команды: ai-agent <команда> -h")
+}
+
+// runExports name the files written after an interactive run; empty paths
+// are skipped.
+type runExports struct {
+	// transcript is the run report, in the format given by the extension.
+	transcript string
+	// workflow receives the run compiled into a workflow if it succeeded.
+	workflow string
+}
+
+// runInteractive runs one task with the user at the console. The task comes
+// from the command line or is read from stdin.
+func runInteractive(appCfg *config.Config, task string, exports runExports) int {
+	ctx, cancel := context.WithTimeout(context.Background(), appCfg.Budgets.TaskTimeout)
 	defer cancel()
 
-	thinkingMode := envService.GetBool("THINKING_MODE", true)
-	thinkingBudget := envService.GetInt("THINKING_BUDGET", 10000)
-	browserTrace := envService.GetBool("BROWSER_TRACE", false)
+	console := userinteraction.NewConsoleUserInteraction()
+	watchInterrupts(ctx, cancel, console)
 
-	container, err := di.NewContainer(ctx, di.Config{
-		OpenRouterAPIKey:   envService.MustGet("OPENROUTER_API_KEY"),
-		OpenRouterModel:    envService.MustGet("OPENROUTER_MODEL_NAME"),
-		BrowserHeadless:    false,
-		BrowserEnableTrace: browserTrace,
-		ThinkingMode:       thinkingMode,
-		ThinkingBudget:     thinkingBudget,
-	})
+	cfg, redactor, err := loadContainerConfig(appCfg, false)
 	if err != nil {
-		log.Fatalf("Ошибка инициализации: %v", err)
+		log.Printf("Ошибка конфигурации: %v", err)
+		return 1
 	}
-	defer container.Close()
+	console.SetRedactor(redactor)
+	cfg.UserInteraction = console
+	cfg.RecordTranscripts = exports.transcript != "" || exports.workflow != ""
 
-	fmt.Println("\nВведите задачу для агента:")
-	reader := bufio.NewReader(os.Stdin)
-	task, err := reader.ReadString('\n')
+	container, err := di.NewContainer(ctx, cfg)
 	if err != nil {
-		log.Fatal("Ошибка чтения ввода: ", err)
+		log.Printf("Ошибка инициализации: %v", err)
+		return 1
+	}
+	defer container.Close()
+
+	if task == "" {
+		fmt.Println("\nВведите задачу для агента:")
+		task, err = console.ReadLine(ctx)
+		if err != nil {
+			log.Print("Ошибка чтения ввода: ", err)
+			return 1
+		}
 	}
-	task = strings.TrimSpace(task)
 
 	container.Logger.Info("Task started", "task", task)
-	fmt.Println("\nАгент начал работу...")
-
-	result, err := container.TaskExecutor.Execute(ctx, task)
+	description, err := batch.OpenStartURL(ctx, container.Browser, task, appCfg.Browser.StartURL)
 	if err != nil {
 		container.Logger.Error("Task failed", "error", err)
 		fmt.Printf("\nОшибка выполнения: %v\n", err)
-		os.Exit(1)
+		return 1
 	}
 
-	container.Logger.Info("Task completed", "iterations", result.Iterations)
-	fmt.Println("\nФИНАЛЬНЫЙ ОТВЕТ:")
-	fmt.Println(result.FinalAnswer)
+	if appCfg.Output.Format == formatText {
+		fmt.Println("\nАгент начал работу... (Ctrl+C — пауза)")
+	}
+	started := time.Now()
+	result, err := container.TaskExecutor.Execute(ctx, input.TaskRequest{Task: description})
+
+	exitCode := 0
+	report := entity.BatchResult{Task: task, Status: entity.TaskStatusCompleted, StartedAt: started}
+	switch {
+	case err == nil:
+		container.Logger.Info("Task completed", "iterations", result.Iterations)
+		report.FinalAnswer = result.FinalAnswer
+		report.Iterations = result.Iterations
+	case errors.Is(err, output.ErrTaskAborted) || errors.Is(err, context.Canceled):
+		container.Logger.Warn("Task interrupted", "error", err)
+		report.Status = entity.TaskStatusCanceled
+		report.Error = err.Error()
+		exitCode = 130
+	default:
+		container.Logger.Error("Task failed", "error", err)
+		report.Status = entity.TaskStatusFailed
+		report.Error = err.Error()
+		exitCode = 1
+	}
+	report.DurationMS = time.Since(started).Milliseconds()
+
+	if container.Transcripts != nil {
+		container.Transcripts.Complete("", report.Status, report.FinalAnswer, report.Error)
+	}
+	if exports.transcript != "" {
+		if err := writeTranscript(container.Transcripts, "", exports.transcript); err != nil {
+			log.Printf("Ошибка записи отчёта: %v", err)
+		} else if appCfg.Output.Format == formatText {
+			fmt.Printf("\nОтчёт о запуске: %s\n", exports.transcript)
+		}
+	}
+	if exports.workflow != "" && report.Status == entity.TaskStatusCompleted {
+		if err := writeWorkflow(container.Transcripts, exports.workflow); err != nil {
+			log.Printf("Ошибка записи сценария: %v", err)
+		} else if appCfg.Output.Format == formatText {
+			fmt.Printf("Сценарий: %s\n", exports.workflow)
+		}
+	}
+
+	if appCfg.Output.Format == formatJSON {
+		printJSON(redactor, report)
+	} else {
+		switch report.Status {
+		case entity.TaskStatusCompleted:
+			fmt.Println("\nФИНАЛЬНЫЙ ОТВЕТ:")
+			fmt.Println(report.FinalAnswer)
+		case entity.TaskStatusCanceled:
+			fmt.Println("\nЗадача прервана пользователем")
+		default:
+			fmt.Printf("\nОшибка выполнения: %v\n", err)
+		}
+	}
+
+	// Keep the browser open for a look at the result, but never wait on a
+	// pipe or /dev/null: that would hang scripted runs. An attached browser
+	// stays open anyway.
+	if exitCode == 0 && !cfg.BrowserHeadless && cfg.BrowserRemoteURL == "" && isTerminal(os.Stdin) {
+		fmt.Println("\nНажмите Enter чтобы закрыть браузер...")
+		_, _ = console.ReadLine(ctx)
+	}
+	return exitCode
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// printJSON writes value as one redacted JSON line to stdout.
+func printJSON(redactor *redaction.Pipeline, value any) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		log.Printf("Ошибка кодирования результата: %v", err)
+		return
+	}
+	fmt.Println(redactor.Redact(string(data)))
+}
+
+func writeTranscript(recorder *transcript.Recorder, taskID, path string) error {
+	recorded, ok := recorder.Transcript(taskID)
+	if !ok {
+		return transcript.ErrNoTranscript
+	}
+	return transcript.WriteFile(path, recorded)
+}
+
+// loadContainerConfig converts the settings shared by every mode. The caller
+// provides the UserInteraction implementation; headless is the command's
+// default when browser.headless is not configured.
+func loadContainerConfig(cfg *config.Config, headless bool) (di.Config, *redaction.Pipeline, error) {
+	approvalPolicy, err := loadApprovalPolicy(cfg.Policies.Approval)
+	if err != nil {
+		return di.Config{}, nil, err
+	}
+
+	secretStore, err := loadSecrets(cfg.Secrets)
+	if err != nil {
+		return di.Config{}, nil, fmt.Errorf("load secrets: %w", err)
+	}
+	redactor, err := loadRedaction(cfg.Policies.Redaction, secretStore)
+	if err != nil {
+		return di.Config{}, nil, err
+	}
+
+	interceptRules, err := loadInterceptRules(cfg.Browser.Intercept)
+	if err != nil {
+		return di.Config{}, nil, err
+	}
+	emulation, err := loadEmulation(cfg.Browser.Emulation)
+	if err != nil {
+		return di.Config{}, nil, err
+	}
+
+	logLevel, err := logger.ParseLevel(cfg.Logging.Level)
+	if err != nil {
+		return di.Config{}, nil, err
+	}
+
+	return di.Config{
+		OpenRouterAPIKey:      cfg.LLM.APIKey,
+		OpenRouterModel:       cfg.LLM.Model,
+		BrowserHeadless:       cfg.HeadlessOr(headless),
+		BrowserEnableTrace:    cfg.Browser.Trace,
+		BrowserRemoteURL:      cfg.Browser.RemoteURL,
+		BrowserRemoteTab:      cfg.Browser.RemoteTab,
+		BrowserRecordHAR:      cfg.Browser.HAR,
+		BrowserPoolIsolation:  cfg.Browser.Pool.Isolation,
+		BrowserPoolMaxUses:    cfg.Browser.Pool.MaxUses,
+		DialogPolicy:          entity.DialogPolicy(cfg.Browser.DialogPolicy),
+		Emulation:             emulation,
+		Screenshot:            cfg.Browser.Screenshot.Request(),
+		ThinkingMode:          cfg.LLM.ThinkingMode,
+		ThinkingBudget:        cfg.LLM.ThinkingBudget,
+		ApprovalPolicy:        approvalPolicy,
+		NavigationPolicy:      loadNavigationPolicy(cfg.Policies.Navigation),
+		NetworkCapture:        cfg.Browser.Network.Capture,
+		NetworkBodyURLs:       cfg.Browser.Network.BodyURLs,
+		NetworkMaxEntries:     cfg.Browser.Network.MaxEntries,
+		NetworkMaxBodyBytes:   cfg.Browser.Network.MaxBodyBytes,
+		BlockResources:        cfg.Browser.Intercept.BlockResources,
+		BlockAds:              cfg.Browser.Intercept.BlockAds,
+		InterceptRules:        interceptRules,
+		Secrets:               secretStore,
+		Redactor:              redactor,
+		RedactLLM:             cfg.LLM.Redact,
+		OnSensitiveInput:      redactor.AddSensitive,
+		LogLevel:              logLevel,
+		MaxIterations:         cfg.Agents.MaxIterations,
+		SubAgentMaxIterations: cfg.Agents.SubAgentMaxIterations,
+		LLMPricing: transcript.Pricing{
+			PromptPerMillion:     cfg.LLM.PromptPrice,
+			CompletionPerMillion: cfg.LLM.CompletionPrice,
+		},
+	}, redactor, nil
+}
+
+func loadApprovalPolicy(cfg config.Approval) (approval.Policy, error) {
+	policy := approval.DefaultPolicy()
+	policy.Enabled = cfg.Enabled
+	policy.ConfirmSubmits = cfg.ConfirmSubmits
+
+	policy.Keywords = append(policy.Keywords, cfg.Keywords...)
+
+	rules, err := approval.ParseRules(strings.Join(cfg.Rules, ";"))
+	if err != nil {
+		return policy, err
+	}
+	policy.Rules = rules
+
+	return policy, nil
+}
+
+func loadNavigationPolicy(cfg config.Navigation) policy.Navigation {
+	return policy.Navigation{
+		AllowedHosts:   cfg.AllowedHosts,
+		DeniedHosts:    cfg.DeniedHosts,
+		BlockedPaths:   cfg.BlockedPaths,
+		DenyFileAccess: cfg.DenyFileAccess,
+	}
+}
+
+func loadInterceptRules(cfg config.Intercept) ([]entity.InterceptRule, error) {
+	rules := make([]entity.InterceptRule, 0, len(cfg.Rules))
+	for _, line := range cfg.Rules {
+		rule, err := entity.ParseInterceptRule(line)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func loadEmulation(cfg config.Emulation) (entity.Emulation, error) {
+	emulation := entity.Emulation{
+		Device:      cfg.Device,
+		UserAgent:   cfg.UserAgent,
+		Locale:      cfg.Locale,
+		Timezone:    cfg.Timezone,
+		ColorScheme: entity.ColorScheme(cfg.ColorScheme),
+	}
+	if cfg.Viewport != "" {
+		width, height, err := entity.ParseViewport(cfg.Viewport)
+		if err != nil {
+			return emulation, err
+		}
+		emulation.Width, emulation.Height = width, height
+	}
+	if cfg.Geolocation != "" {
+		geo, err := entity.ParseGeolocation(cfg.Geolocation)
+		if err != nil {
+			return emulation, err
+		}
+		emulation.Geolocation = geo
+	}
+	return emulation, emulation.Validate()
+}
+
+// loadSecrets collects SECRET_* environment variables and, when a secrets
+// file is configured, the entries of the encrypted secrets file.
+func loadSecrets(cfg config.Secrets) (*secrets.Store, error) {
+	store := secrets.NewStore()
+	store.LoadEnv(secrets.DefaultEnvPrefix)
+
+	if cfg.File != "" {
+		if _, err := store.LoadFile(cfg.File, cfg.Passphrase); err != nil {
+			return nil, err
+		}
+	}
+
+	if short := store.Unredacted(); len(short) > 0 {
+		log.Printf("Внимание: секреты %s короче %d символов и не маскируются в логах и сообщениях модели",
+			strings.Join(short, ", "), secrets.MinRedactLength)
+	}
+
+	return store, nil
+}
+
+// loadRedaction builds the pipeline that scrubs secrets, values typed into
+// password fields and the configured pattern matches from all agent output.
+func loadRedaction(cfg config.Redaction, store *secrets.Store) (*redaction.Pipeline, error) {
+	if !cfg.Enabled {
+		return redaction.NewPipeline(nil, store), nil
+	}
+
+	specs := cfg.Patterns
+	if len(specs) == 0 {
+		specs = redaction.DefaultPatternNames
+	}
+	specs = append(append([]string{}, specs...), cfg.CustomPatterns...)
+
+	patterns, err := redaction.ParsePatterns(specs)
+	if err != nil {
+		return nil, err
+	}
+
+	return redaction.NewPipeline(patterns, store), nil
+}
+
+// watchInterrupts turns the first Ctrl+C into a pause request and the second
+// one (or SIGTERM) into cancellation of ctx, so deferred cleanup still closes
+// the browser.
+func watchInterrupts(ctx context.Context, cancel context.CancelFunc, console *userinteraction.ConsoleUserInteraction) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
 
-	fmt.Println("\nНажмите Enter чтобы закрыть браузер...")
-	_, _ = reader.ReadString('\n')
+	go func() {
+		defer signal.Stop(sigCh)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case sig := <-sigCh:
+				if sig == os.Interrupt && console.RequestPause() {
+					fmt.Println("\n⏸  Пауза после текущего действия. Ctrl+C ещё раз — отменить задачу.")
+					continue
+				}
+				fmt.Println("\n⛔ Отмена...")
+				cancel()
+				return
+			}
+		}
+	}()
 }
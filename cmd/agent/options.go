@@ -0,0 +1,127 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"browser-agent/internal/infrastructure/config"
+	"browser-agent/internal/infrastructure/env"
+)
+
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+// options are the command line flags shared by the commands. They are the
+// last configuration layer: a flag that is given overrides the config file
+// and the environment.
+type options struct {
+	profile    string
+	configFile string
+	model      string
+	headless   bool
+	timeout    time.Duration
+	startURL   string
+	format     string
+	logLevel   string
+	settings   settingFlags
+	// withoutLLM skips the check of the model credentials for commands
+	// that never call the LLM.
+	withoutLLM bool
+}
+
+// settingFlags collects repeated -set key=value flags.
+type settingFlags []string
+
+func (s *settingFlags) String() string     { return strings.Join(*s, ",") }
+func (s *settingFlags) Set(v string) error { *s = append(*s, v); return nil }
+
+// register adds the flags every agent command accepts.
+func (o *options) register(flags *flag.FlagSet) {
+	flags.StringVar(&o.profile, "profile", "", "environment profile: .env.<profile> and profiles.<profile> of the config file (env APP_ENV)")
+	flags.StringVar(&o.configFile, "config", "", "YAML config file (env CONFIG_FILE, default "+config.DefaultFile+" if present)")
+	flags.StringVar(&o.model, "model", "", "LLM model name (llm.model)")
+	flags.BoolVar(&o.headless, "headless", false, "run the browser without a window (browser.headless)")
+	flags.DurationVar(&o.timeout, "timeout", 0, "time limit per task (budgets.task_timeout, default 30m)")
+	flags.StringVar(&o.logLevel, "log-level", "", "log file level: debug, info, warn, error (logging.level)")
+	flags.Var(&o.settings, "set", "override any setting, e.g. -set agents.max_iterations=20 (repeatable)")
+}
+
+// registerOutput adds the flags of commands that execute tasks directly.
+func (o *options) registerOutput(flags *flag.FlagSet) {
+	flags.StringVar(&o.startURL, "url", "", "page to open before the task starts (browser.start_url)")
+	flags.StringVar(&o.format, "format", "", "output format: text or json (output.format)")
+}
+
+// load builds the configuration for the selected profile and applies the
+// flags that were given on top of it.
+func (o *options) load(flags *flag.FlagSet) (*config.Config, error) {
+	if o.profile != "" {
+		os.Setenv("APP_ENV", o.profile)
+	}
+	envService := env.NewEnvService()
+
+	cfg, warnings, err := config.Load(envService, config.Options{
+		File:           o.configFile,
+		Profile:        envService.Profile(),
+		RequireProfile: o.profile != "",
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, warning := range warnings {
+		log.Printf("Предупреждение конфигурации: %s", warning)
+	}
+
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "model":
+			cfg.LLM.Model = o.model
+		case "headless":
+			cfg.Browser.Headless = &o.headless
+		case "timeout":
+			cfg.Budgets.TaskTimeout = o.timeout
+		case "url":
+			cfg.Browser.StartURL = o.startURL
+		case "format":
+			cfg.Output.Format = o.format
+		case "log-level":
+			cfg.Logging.Level = o.logLevel
+		}
+	})
+
+	for _, setting := range o.settings {
+		key, value, ok := strings.Cut(setting, "=")
+		if !ok {
+			return nil, fmt.Errorf("-set %q: want key=value", setting)
+		}
+		if err := cfg.Set(strings.TrimSpace(key), value); err != nil {
+			return nil, err
+		}
+	}
+
+	if o.withoutLLM {
+		return cfg, cfg.ValidateWithoutLLM()
+	}
+	return cfg, cfg.Validate()
+}
+
+// parseFlags parses args and reports whether the command should go on. When
+// it should not, exitCode is 0 for -h and 2 for invalid flags.
+func parseFlags(flags *flag.FlagSet, args []string) (exitCode int, ok bool) {
+	err := flags.Parse(args)
+	switch {
+	case err == nil:
+		return 0, true
+	case errors.Is(err, flag.ErrHelp):
+		return 0, false
+	default:
+		return 2, false
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"browser-agent/internal/di"
+	"browser-agent/internal/domain/entity"
+	"browser-agent/internal/infrastructure/transcript"
+	"browser-agent/internal/infrastructure/userinteraction"
+	"browser-agent/internal/usecase/replay"
+)
+
+// replayCommand re-executes the browser tool calls of a JSON transcript
+// without the LLM. The exit code is 0 only if every step behaved as
+// recorded, so it can serve as a regression check.
+func replayCommand(args []string) int {
+	opts := options{withoutLLM: true}
+	flags := flag.NewFlagSet("replay", flag.ContinueOnError)
+	opts.register(flags)
+	opts.registerOutput(flags)
+	keepGoing := flags.Bool("keep-going", false, "continue after a step fails instead of stopping")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Использование: ai-agent replay [флаги] transcript.json")
+		flags.PrintDefaults()
+	}
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	recorded, err := transcript.Load(flags.Arg(0))
+	if err != nil {
+		log.Printf("Ошибка чтения записи: %v", err)
+		return 2
+	}
+
+	appCfg, err := opts.load(flags)
+	if err != nil {
+		log.Printf("Ошибка конфигурации: %v", err)
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// A replay is a script: headless unless configured otherwise.
+	cfg, redactor, err := loadContainerConfig(appCfg, true)
+	if err != nil {
+		log.Printf("Ошибка конфигурации: %v", err)
+		return 1
+	}
+	console := userinteraction.NewConsoleUserInteraction()
+	console.SetRedactor(redactor)
+	cfg.UserInteraction = console
+
+	container, err := di.NewContainer(ctx, cfg)
+	if err != nil {
+		log.Printf("Ошибка инициализации: %v", err)
+		return 1
+	}
+	defer container.Close()
+
+	textOutput := appCfg.Output.Format == formatText
+	replayCfg := replay.Config{StartURL: appCfg.Browser.StartURL, KeepGoing: *keepGoing}
+	if textOutput {
+		replayCfg.OnStep = func(step entity.ReplayStep) {
+			fmt.Println(redactor.Redact(formatReplayStep(step)))
+		}
+		fmt.Printf("Повтор: %s\n", redactor.Redact(recorded.Task))
+	}
+
+	report, err := replay.New(container.Tools, container.Browser, redactor, container.Logger, replayCfg).Replay(ctx, recorded)
+	if err != nil {
+		log.Printf("Ошибка повтора: %v", err)
+		return 1
+	}
+
+	if textOutput {
+		fmt.Printf("\nСовпало: %d, расхождений: %d, пропущено: %d из %d шагов\n",
+			report.Matched, report.Diverged, report.Skipped, len(report.Steps))
+	} else {
+		printJSON(redactor, report)
+	}
+
+	if !report.Passed() {
+		return 1
+	}
+	return 0
+}
+
+func formatReplayStep(step entity.ReplayStep) string {
+	line := fmt.Sprintf("[%d] %-8s %s %s", step.Index, step.Status, step.Tool, step.Arguments)
+	switch {
+	case step.Kind != "":
+		line += fmt.Sprintf("\n      %s: ожидалось %q, получено %q", step.Kind, step.Expected, step.Actual)
+	case step.Expected != "":
+		line += "\n      " + step.Expected
+	}
+	return line
+}
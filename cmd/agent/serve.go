@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"browser-agent/internal/adapter/httpapi"
+	"browser-agent/internal/di"
+	"browser-agent/internal/domain/entity"
+	"browser-agent/internal/infrastructure/eventbus"
+	"browser-agent/internal/infrastructure/userinteraction"
+	"browser-agent/internal/usecase/taskmanager"
+)
+
+const shutdownTimeout = 10 * time.Second
+
+// serve runs the agent as an HTTP service: tasks are submitted over REST,
+// progress is streamed over SSE and questions are answered by the client.
+func serve(args []string) int {
+	var opts options
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	opts.register(flags)
+	addr := flags.String("addr", "", "listen address (server.addr, default 127.0.0.1:8080)")
+	workers := flags.Int("workers", 0, "tasks run at once, each in its own browser (server.workers)")
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	appCfg, err := opts.load(flags)
+	if err != nil {
+		log.Printf("Ошибка конфигурации: %v", err)
+		return 2
+	}
+	if *addr == "" {
+		*addr = appCfg.Server.Addr
+	}
+	if *workers > 0 {
+		appCfg.Server.Workers = *workers
+	}
+	// The API drives a browser that can type vault secrets, so it is never
+	// exposed beyond this machine without a token.
+	if appCfg.Server.Token == "" && !isLoopbackAddr(*addr) {
+		log.Printf("Ошибка конфигурации: адрес %s доступен из сети, задайте SERVE_TOKEN (server.token) или слушайте 127.0.0.1", *addr)
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cfg, redactor, err := loadContainerConfig(appCfg, true)
+	if err != nil {
+		log.Printf("Ошибка конфигурации: %v", err)
+		return 1
+	}
+
+	bus := eventbus.New()
+	remote := userinteraction.NewRemoteUserInteraction(bus)
+	remote.SetRedactor(redactor)
+	cfg.UserInteraction = remote
+	cfg.RecordTranscripts = true
+	cfg.BrowserPoolSize = appCfg.Server.Workers
+
+	container, err := di.NewContainer(ctx, cfg)
+	if err != nil {
+		log.Printf("Ошибка инициализации: %v", err)
+		return 1
+	}
+	defer container.Close()
+
+	managerCfg := taskmanager.DefaultConfig()
+	managerCfg.TaskTimeout = appCfg.Budgets.TaskTimeout
+	managerCfg.Workers = appCfg.Server.Workers
+	managerCfg.OnFinish = func(task entity.Task) {
+		container.Transcripts.Complete(task.ID, task.Status, task.FinalAnswer, task.Error)
+	}
+	managerCfg.OnEvict = func(taskID string) {
+		bus.Forget(taskID)
+		container.Transcripts.Forget(taskID)
+	}
+	manager := taskmanager.New(container.TaskExecutor, bus, container.Logger, managerCfg)
+	manager.Start(ctx)
+
+	server := &http.Server{
+		Addr: *addr,
+		Handler: httpapi.NewServer(httpapi.Config{
+			Tasks:        manager,
+			Interactions: remote,
+			Events:       bus,
+			Logger:       container.Logger,
+			Screenshots:  container.Screenshots,
+			Transcripts:  container.Transcripts,
+			Token:        appCfg.Server.Token,
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+
+	container.Logger.Info("HTTP API started", "addr", *addr)
+	fmt.Printf("API и дашборд: http://%s (Ctrl+C — остановка)\n", displayAddr(*addr))
+
+	exitCode := 0
+	select {
+	case <-ctx.Done():
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Ошибка HTTP сервера: %v", err)
+			exitCode = 1
+		}
+		stop()
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		container.Logger.Warn("HTTP shutdown failed", "error", err)
+	}
+	manager.Wait()
+
+	container.Logger.Info("HTTP API stopped")
+	return exitCode
+}
+
+// isLoopbackAddr reports whether addr only accepts local connections; an
+// empty host listens on every interface.
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func displayAddr(addr string) string {
+	if strings.HasPrefix(addr, ":") {
+		return "localhost" + addr
+	}
+	return addr
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/di"
+	"browser-agent/internal/domain/entity"
+	"browser-agent/internal/infrastructure/transcript"
+	"browser-agent/internal/infrastructure/userinteraction"
+	"browser-agent/internal/infrastructure/workflowfile"
+	"browser-agent/internal/usecase/workflow"
+)
+
+const workflowUsage = "Использование: ai-agent workflow compile [--name имя] transcript.json workflow.json\n" +
+	"               ai-agent workflow run [флаги] [--var имя=значение]... workflow.json"
+
+// workflowCommand implements "workflow compile", which turns a recorded run
+// into a workflow file, and "workflow run", which executes one.
+func workflowCommand(args []string) int {
+	if len(args) > 0 {
+		switch args[0] {
+		case "compile":
+			return workflowCompile(args[1:])
+		case "run":
+			return workflowRun(args[1:])
+		}
+	}
+	fmt.Fprintln(os.Stderr, workflowUsage)
+	return 2
+}
+
+func workflowCompile(args []string) int {
+	flags := flag.NewFlagSet("workflow compile", flag.ContinueOnError)
+	name := flags.String("name", "", "workflow name (default: output file name)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), workflowUsage)
+		flags.PrintDefaults()
+	}
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+
+	recorded, err := transcript.Load(flags.Arg(0))
+	if err != nil {
+		log.Printf("Ошибка чтения записи: %v", err)
+		return 2
+	}
+	compiled, err := compileWorkflow(recorded, flags.Arg(1), *name)
+	if err != nil {
+		log.Printf("Ошибка компиляции: %v", err)
+		return 1
+	}
+	printWorkflowSummary(flags.Arg(1), compiled)
+	return 0
+}
+
+// writeWorkflow compiles the run recorded by recorder into path.
+func writeWorkflow(recorder *transcript.Recorder, path string) error {
+	recorded, ok := recorder.Transcript("")
+	if !ok {
+		return transcript.ErrNoTranscript
+	}
+	_, err := compileWorkflow(recorded, path, "")
+	return err
+}
+
+func compileWorkflow(recorded *entity.Transcript, path, name string) (*entity.Workflow, error) {
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	compiled, err := workflow.Compile(recorded, name)
+	if err != nil {
+		return nil, err
+	}
+	return compiled, workflowfile.Write(path, compiled)
+}
+
+func printWorkflowSummary(path string, compiled *entity.Workflow) {
+	fmt.Printf("Сценарий %s: %d шагов\n", path, len(compiled.Steps))
+	for _, variable := range compiled.Variables {
+		switch {
+		case variable.Secret:
+			fmt.Printf("  секрет     %s\n", variable.Name)
+		case variable.Default != "":
+			fmt.Printf("  переменная %s (по умолчанию из записи) %s\n", variable.Name, variable.Description)
+		default:
+			fmt.Printf("  переменная %s — обязательна: --var %s=...\n", variable.Name, variable.Name)
+		}
+	}
+}
+
+// variableFlags collects repeated --var name=value flags.
+type variableFlags map[string]string
+
+func (v variableFlags) String() string { return fmt.Sprint(map[string]string(v)) }
+func (v variableFlags) Set(s string) error {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok {
+		return fmt.Errorf("want name=value")
+	}
+	v[strings.TrimSpace(name)] = value
+	return nil
+}
+
+func workflowRun(args []string) int {
+	var opts options
+	flags := flag.NewFlagSet("workflow run", flag.ContinueOnError)
+	opts.register(flags)
+	opts.registerOutput(flags)
+	vars := variableFlags{}
+	flags.Var(vars, "var", "value of a workflow variable, name=value (repeatable)")
+	noFallback := flags.Bool("no-fallback", false, "fail on the first broken step instead of asking the LLM to perform it")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), workflowUsage)
+		flags.PrintDefaults()
+	}
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	script, err := workflowfile.Load(flags.Arg(0))
+	if err != nil {
+		log.Printf("Ошибка чтения сценария: %v", err)
+		return 2
+	}
+
+	// Without the fallback the LLM is never called and needs no key.
+	opts.withoutLLM = *noFallback
+	appCfg, err := opts.load(flags)
+	if err != nil {
+		log.Printf("Ошибка конфигурации: %v", err)
+		return 2
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), appCfg.Budgets.TaskTimeout)
+	defer cancel()
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// A workflow is a script: headless unless configured otherwise.
+	cfg, redactor, err := loadContainerConfig(appCfg, true)
+	if err != nil {
+		log.Printf("Ошибка конфигурации: %v", err)
+		return 1
+	}
+	console := userinteraction.NewConsoleUserInteraction()
+	console.SetRedactor(redactor)
+	cfg.UserInteraction = console
+
+	container, err := di.NewContainer(ctx, cfg)
+	if err != nil {
+		log.Printf("Ошибка инициализации: %v", err)
+		return 1
+	}
+	defer container.Close()
+
+	if appCfg.Browser.StartURL != "" {
+		if err := container.Browser.Navigate(ctx, appCfg.Browser.StartURL); err != nil {
+			log.Printf("Ошибка открытия стартовой страницы: %v", err)
+			return 1
+		}
+	}
+
+	textOutput := appCfg.Output.Format == formatText
+	runCfg := workflow.Config{}
+	if textOutput {
+		runCfg.OnStep = func(step entity.WorkflowStepResult) {
+			fmt.Println(redactor.Redact(formatWorkflowStep(step)))
+		}
+		fmt.Printf("Сценарий: %s\n", script.Name)
+	}
+
+	var agents output.SimpleAgentRegistry
+	if !*noFallback {
+		agents = container.SimpleAgents
+	}
+	result, err := workflow.NewRunner(container.Browser, cfg.Secrets, agents, container.Logger, runCfg).Run(ctx, script, vars)
+	if err != nil {
+		log.Printf("Ошибка выполнения сценария: %v", err)
+		return 1
+	}
+
+	if textOutput {
+		if result.Output != "" {
+			fmt.Println("\nРЕЗУЛЬТАТ:")
+			fmt.Println(redactor.Redact(result.Output))
+		}
+		fmt.Printf("\nШагов: %d, выполнено с помощью LLM: %d\n", len(result.Steps), result.Recovered)
+	} else {
+		printJSON(redactor, result)
+	}
+
+	if !result.Succeeded() {
+		return 1
+	}
+	return 0
+}
+
+func formatWorkflowStep(step entity.WorkflowStepResult) string {
+	line := fmt.Sprintf("[%d] %-9s %s", step.Index, step.Status, step.Action)
+	if step.Error != "" {
+		line += "\n      " + step.Error
+	}
+	return line
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+
+	"browser-agent/internal/infrastructure/secrets"
+)
+
+// Encrypts a plain JSON object {"name": "value"} into a secrets file that the
+// agent loads via SECRETS_FILE, or lists secret names stored in such a file.
+// The passphrase is taken from SECRETS_PASSPHRASE.
+func main() {
+	in := flag.String("in", "", "plain JSON file with secrets to encrypt")
+	out := flag.String("out", "secrets.enc", "encrypted output file")
+	list := flag.String("list", "", "print secret names stored in an encrypted file")
+	flag.Parse()
+
+	passphrase := os.Getenv("SECRETS_PASSPHRASE")
+	if passphrase == "" {
+		log.Fatal("SECRETS_PASSPHRASE is not set")
+	}
+
+	if *list != "" {
+		data, err := os.ReadFile(*list)
+		if err != nil {
+			log.Fatal(err)
+		}
+		values, err := secrets.Decrypt(data, passphrase)
+		if err != nil {
+			log.Fatal(err)
+		}
+		names := make([]string, 0, len(values))
+		for name := range values {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Println(secrets.Placeholder(name))
+		}
+		return
+	}
+
+	if *in == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	plain, err := os.ReadFile(*in)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var values map[string]string
+	if err := json.Unmarshal(plain, &values); err != nil {
+		log.Fatalf("invalid secrets JSON: %v", err)
+	}
+
+	data, err := secrets.Encrypt(values, passphrase)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile(*out, data, 0600); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("✓ %d secrets written to %s\n", len(values), *out)
+}
@@ -0,0 +1,41 @@
+package httpapi
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+	"strconv"
+
+	"browser-agent/internal/application/runctx"
+)
+
+//go:embed web
+var webFiles embed.FS
+
+func dashboardHandler() http.Handler {
+	files, err := fs.Sub(webFiles, "web")
+	if err != nil {
+		panic(err)
+	}
+	return http.FileServerFS(files)
+}
+
+// handleScreenshot returns the current page so the dashboard can show what
+// the agent sees while a task runs. With several tasks running at once,
+// ?task= picks the one whose browser is captured.
+func (s *Server) handleScreenshot(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if taskID := r.URL.Query().Get("task"); taskID != "" {
+		ctx = runctx.WithTaskID(ctx, taskID)
+	}
+	screenshot, err := s.screenshots.Screenshot(ctx)
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/"+screenshot.Format)
+	w.Header().Set("Content-Length", strconv.Itoa(len(screenshot.Data)))
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write(screenshot.Data)
+}
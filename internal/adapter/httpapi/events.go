@@ -0,0 +1,122 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"browser-agent/internal/domain/entity"
+)
+
+const heartbeatInterval = 15 * time.Second
+
+// handleEvents streams task events as Server-Sent Events. Events recorded
+// before the request are replayed first; Last-Event-ID skips those the
+// client has already seen. The stream ends once the final task status has
+// been sent, or early when the client falls behind the event bus, in which
+// case it reconnects with Last-Event-ID.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New("streaming is not supported"))
+		return
+	}
+
+	history, events, unsubscribe := s.events.Subscribe(id)
+	defer unsubscribe()
+
+	if _, err := s.tasks.Get(id); err != nil {
+		writeTaskError(w, err)
+		return
+	}
+
+	lastID := lastEventID(r)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	// The task may finish between Subscribe and Get, so whether it is done
+	// is decided by the events themselves: the final status is either in
+	// the history or still on its way through the channel.
+	for _, event := range history {
+		if event.ID <= lastID {
+			if finishedEvent(event) {
+				return
+			}
+			continue
+		}
+		if err := writeEvent(w, event); err != nil {
+			return
+		}
+		lastID = event.ID
+		if finishedEvent(event) {
+			flusher.Flush()
+			return
+		}
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if event.ID <= lastID {
+				continue
+			}
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+			flusher.Flush()
+			lastID = event.ID
+
+			if finishedEvent(event) {
+				return
+			}
+		}
+	}
+}
+
+func lastEventID(r *http.Request) int64 {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("since")
+	}
+	id, _ := strconv.ParseInt(value, 10, 64)
+	return id
+}
+
+func writeEvent(w http.ResponseWriter, event entity.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
+
+func finishedEvent(event entity.Event) bool {
+	if event.Type != entity.EventTaskStatus {
+		return false
+	}
+	task, ok := event.Data["task"].(entity.Task)
+	return ok && task.Status.Finished()
+}
@@ -0,0 +1,252 @@
+// Package httpapi exposes the task manager over REST with Server-Sent Events
+// for live progress, and serves the embedded web dashboard.
+package httpapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"browser-agent/internal/application/port/input"
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/domain/entity"
+)
+
+const maxRequestBody = 1 << 20
+
+// ScreenshotSource captures the page the agent is working on.
+type ScreenshotSource interface {
+	Screenshot(ctx context.Context) (*entity.Screenshot, error)
+}
+
+// TranscriptSource renders the recorded run of a task.
+type TranscriptSource interface {
+	// Export returns the transcript in format (md, html or json) and its
+	// content type.
+	Export(taskID, format string) (data []byte, contentType string, err error)
+}
+
+type Config struct {
+	Tasks        input.TaskManager
+	Interactions input.InteractionResponder
+	Events       input.EventSource
+	Logger       output.LoggerPort
+	// Screenshots is optional; without it the screenshot endpoint is absent.
+	Screenshots ScreenshotSource
+	// Transcripts is optional; without it the transcript endpoint is absent.
+	Transcripts TranscriptSource
+	// Token, when set, must be sent as "Authorization: Bearer <token>"; the
+	// event stream also accepts it as ?token=.
+	Token string
+}
+
+type Server struct {
+	tasks        input.TaskManager
+	interactions input.InteractionResponder
+	events       input.EventSource
+	logger       output.LoggerPort
+	screenshots  ScreenshotSource
+	transcripts  TranscriptSource
+	token        string
+	mux          *http.ServeMux
+}
+
+func NewServer(cfg Config) *Server {
+	s := &Server{
+		tasks:        cfg.Tasks,
+		interactions: cfg.Interactions,
+		events:       cfg.Events,
+		logger:       cfg.Logger,
+		screenshots:  cfg.Screenshots,
+		transcripts:  cfg.Transcripts,
+		token:        cfg.Token,
+		mux:          http.NewServeMux(),
+	}
+	s.routes()
+	return s
+}
+
+// eventsPattern is the only route that accepts the token as a query
+// parameter.
+const eventsPattern = "GET /api/tasks/{id}/events"
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("POST /api/tasks", s.handleSubmit)
+	s.mux.HandleFunc("GET /api/tasks", s.handleList)
+	s.mux.HandleFunc("GET /api/tasks/{id}", s.handleGet)
+	s.mux.HandleFunc("POST /api/tasks/{id}/cancel", s.handleCancel)
+	s.mux.HandleFunc(eventsPattern, s.handleEvents)
+	s.mux.HandleFunc("GET /api/tasks/{id}/interactions", s.handleInteractions)
+	s.mux.HandleFunc("POST /api/tasks/{id}/interactions/{interactionID}", s.handleRespond)
+	if s.screenshots != nil {
+		s.mux.HandleFunc("GET /api/screenshot", s.handleScreenshot)
+	}
+	if s.transcripts != nil {
+		s.mux.HandleFunc("GET /api/tasks/{id}/transcript", s.handleTranscript)
+	}
+	s.mux.Handle("GET /", dashboardHandler())
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// The dashboard's static files are public; its API calls carry the token.
+	if s.token != "" && strings.HasPrefix(r.URL.Path, "/api/") && !s.authorized(r) {
+		writeError(w, http.StatusUnauthorized, errors.New("missing or invalid token"))
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		// EventSource cannot set headers, so the event stream accepts a query
+		// token. Elsewhere it would only leak into access logs and history.
+		if _, pattern := s.mux.Handler(r); pattern != eventsPattern {
+			return false
+		}
+		token = r.URL.Query().Get("token")
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+type submitRequest struct {
+	Task string `json:"task"`
+}
+
+type respondRequest struct {
+	Answer string `json:"answer"`
+}
+
+func (s *Server) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	var req submitRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	task, err := s.tasks.Submit(req.Task)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, input.ErrQueueFull) {
+			status = http.StatusServiceUnavailable
+		}
+		writeError(w, status, err)
+		return
+	}
+
+	w.Header().Set("Location", "/api/tasks/"+task.ID)
+	writeJSON(w, http.StatusAccepted, task)
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.tasks.List())
+}
+
+func (s *Server) handleGet(w http.ResponseWriter, r *http.Request) {
+	task, err := s.tasks.Get(r.PathValue("id"))
+	if err != nil {
+		writeTaskError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, task)
+}
+
+func (s *Server) handleCancel(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := s.tasks.Cancel(id); err != nil {
+		writeTaskError(w, err)
+		return
+	}
+
+	task, err := s.tasks.Get(id)
+	if err != nil {
+		writeTaskError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, task)
+}
+
+func (s *Server) handleInteractions(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if _, err := s.tasks.Get(id); err != nil {
+		writeTaskError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.interactions.Pending(id))
+}
+
+func (s *Server) handleRespond(w http.ResponseWriter, r *http.Request) {
+	var req respondRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := s.interactions.Respond(r.PathValue("id"), r.PathValue("interactionID"), req.Answer); err != nil {
+		writeTaskError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleTranscript exports the run report of a task; the format query
+// parameter is md, html (the default) or json.
+func (s *Server) handleTranscript(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if _, err := s.tasks.Get(id); err != nil {
+		writeTaskError(w, err)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	switch format {
+	case "":
+		format = "html"
+	case "md", "html", "json":
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Errorf("unknown format %q (md, html or json)", format))
+		return
+	}
+	data, contentType, err := s.transcripts.Export(id, format)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	if r.URL.Query().Has("download") {
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "transcript-"+id+"."+format))
+	}
+	_, _ = w.Write(data)
+}
+
+func decodeJSON(r *http.Request, v any) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(nil, r.Body, maxRequestBody))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeTaskError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, input.ErrTaskNotFound), errors.Is(err, input.ErrInteractionNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case errors.Is(err, input.ErrTaskFinished):
+		writeError(w, http.StatusConflict, err)
+	default:
+		writeError(w, http.StatusInternalServerError, err)
+	}
+}
@@ -0,0 +1,282 @@
+package httpapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"browser-agent/internal/application/port/input"
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/application/runctx"
+	"browser-agent/internal/domain/entity"
+	"browser-agent/internal/infrastructure/eventbus"
+	"browser-agent/internal/infrastructure/userinteraction"
+	"browser-agent/internal/usecase/taskmanager"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...any)                          {}
+func (nopLogger) Info(string, ...any)                           {}
+func (nopLogger) Warn(string, ...any)                           {}
+func (nopLogger) Error(string, ...any)                          {}
+func (l nopLogger) WithField(string, any) output.LoggerPort     { return l }
+func (l nopLogger) WithFields(map[string]any) output.LoggerPort { return l }
+func (nopLogger) Close() error                                  { return nil }
+
+type askingExecutor struct {
+	ui output.UserInteractionPort
+}
+
+func (e askingExecutor) Execute(ctx context.Context, req input.TaskRequest) (*input.ExecuteResult, error) {
+	e.ui.ShowIteration(ctx, 1, 30)
+	answer, err := e.ui.AskQuestion(ctx, "Which city?")
+	if err != nil {
+		return nil, err
+	}
+	return &input.ExecuteResult{FinalAnswer: req.Task + " in " + answer, Iterations: 1}, nil
+}
+
+type staticScreenshots struct{}
+
+func (staticScreenshots) Screenshot(ctx context.Context) (*entity.Screenshot, error) {
+	data := "jpeg-bytes"
+	if taskID := runctx.TaskID(ctx); taskID != "" {
+		data += " of " + taskID
+	}
+	return &entity.Screenshot{Data: []byte(data), Format: "jpeg"}, nil
+}
+
+type staticTranscripts struct{}
+
+func (staticTranscripts) Export(taskID, format string) ([]byte, string, error) {
+	return []byte(format + ":" + taskID), "text/plain", nil
+}
+
+func newTestServer(t *testing.T, token string) *httptest.Server {
+	t.Helper()
+
+	bus := eventbus.New()
+	ui := userinteraction.NewRemoteUserInteraction(bus)
+	manager := taskmanager.New(askingExecutor{ui: ui}, bus, nopLogger{}, taskmanager.DefaultConfig())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	manager.Start(ctx)
+	t.Cleanup(func() {
+		cancel()
+		manager.Wait()
+	})
+
+	server := httptest.NewServer(NewServer(Config{
+		Tasks:        manager,
+		Interactions: ui,
+		Events:       bus,
+		Logger:       nopLogger{},
+		Screenshots:  staticScreenshots{},
+		Transcripts:  staticTranscripts{},
+		Token:        token,
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func doJSON(t *testing.T, method, url, body string, out any) int {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	if out != nil {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+	}
+	return resp.StatusCode
+}
+
+func TestServer_TaskLifecycle(t *testing.T) {
+	server := newTestServer(t, "")
+
+	var task entity.Task
+	status := doJSON(t, http.MethodPost, server.URL+"/api/tasks", `{"task":"find weather"}`, &task)
+	require.Equal(t, http.StatusAccepted, status)
+	require.NotEmpty(t, task.ID)
+
+	var pending []entity.PendingInteraction
+	require.Eventually(t, func() bool {
+		doJSON(t, http.MethodGet, server.URL+"/api/tasks/"+task.ID+"/interactions", "", &pending)
+		return len(pending) == 1
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "Which city?", pending[0].Prompt)
+
+	status = doJSON(t, http.MethodPost,
+		server.URL+"/api/tasks/"+task.ID+"/interactions/"+pending[0].ID, `{"answer":"Berlin"}`, nil)
+	require.Equal(t, http.StatusNoContent, status)
+
+	require.Eventually(t, func() bool {
+		doJSON(t, http.MethodGet, server.URL+"/api/tasks/"+task.ID, "", &task)
+		return task.Status == entity.TaskStatusCompleted
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "find weather in Berlin", task.FinalAnswer)
+
+	var tasks []entity.Task
+	require.Equal(t, http.StatusOK, doJSON(t, http.MethodGet, server.URL+"/api/tasks", "", &tasks))
+	assert.Len(t, tasks, 1)
+
+	resp, err := http.Get(server.URL + "/api/tasks/" + task.ID + "/transcript?format=md&download")
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "md:"+task.ID, string(body))
+	assert.Contains(t, resp.Header.Get("Content-Disposition"), "transcript-"+task.ID+".md")
+	assert.Equal(t, http.StatusBadRequest, doJSON(t, http.MethodGet, server.URL+"/api/tasks/"+task.ID+"/transcript?format=pdf", "", nil))
+	assert.Equal(t, http.StatusNotFound, doJSON(t, http.MethodGet, server.URL+"/api/tasks/missing/transcript", "", nil))
+
+	assert.Equal(t, http.StatusConflict, doJSON(t, http.MethodPost, server.URL+"/api/tasks/"+task.ID+"/cancel", "", nil))
+	assert.Equal(t, http.StatusNotFound, doJSON(t, http.MethodGet, server.URL+"/api/tasks/missing", "", nil))
+	assert.Equal(t, http.StatusBadRequest, doJSON(t, http.MethodPost, server.URL+"/api/tasks", `{"task":""}`, nil))
+}
+
+func TestServer_EventStream(t *testing.T) {
+	server := newTestServer(t, "")
+
+	var task entity.Task
+	require.Equal(t, http.StatusAccepted,
+		doJSON(t, http.MethodPost, server.URL+"/api/tasks", `{"task":"cancel me"}`, &task))
+
+	resp, err := http.Get(server.URL + "/api/tasks/" + task.ID + "/events")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	var types []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		eventType, ok := strings.CutPrefix(line, "event: ")
+		if !ok {
+			continue
+		}
+		types = append(types, eventType)
+		if eventType == string(entity.EventInteraction) {
+			doJSON(t, http.MethodPost, server.URL+"/api/tasks/"+task.ID+"/cancel", "", nil)
+		}
+	}
+
+	assert.Contains(t, types, string(entity.EventIteration))
+	assert.Contains(t, types, string(entity.EventInteraction))
+	assert.Equal(t, string(entity.EventTaskStatus), types[len(types)-1])
+
+	doJSON(t, http.MethodGet, server.URL+"/api/tasks/"+task.ID, "", &task)
+	assert.Equal(t, entity.TaskStatusCanceled, task.Status)
+}
+
+func TestServer_EventStreamOfFinishedTask(t *testing.T) {
+	server := newTestServer(t, "")
+
+	var task entity.Task
+	require.Equal(t, http.StatusAccepted,
+		doJSON(t, http.MethodPost, server.URL+"/api/tasks", `{"task":"cancel me"}`, &task))
+	doJSON(t, http.MethodPost, server.URL+"/api/tasks/"+task.ID+"/cancel", "", nil)
+
+	stream := func(lastID string) []string {
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/api/tasks/"+task.ID+"/events", nil)
+		require.NoError(t, err)
+		req.Header.Set("Last-Event-ID", lastID)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var lines []string
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if line, ok := strings.CutPrefix(scanner.Text(), "id: "); ok {
+				lines = append(lines, line)
+			} else if line, ok := strings.CutPrefix(scanner.Text(), "event: "); ok {
+				lines = append(lines, line)
+			}
+		}
+		return lines
+	}
+
+	lines := stream("")
+	require.GreaterOrEqual(t, len(lines), 2)
+	assert.Equal(t, string(entity.EventTaskStatus), lines[len(lines)-1])
+
+	assert.Empty(t, stream(lines[len(lines)-2]))
+}
+
+func TestServer_Token(t *testing.T) {
+	server := newTestServer(t, "s3cret")
+
+	resp, err := http.Get(server.URL + "/api/tasks")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/api/tasks", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	for _, path := range []string{"/api/tasks", "/api/screenshot", "/api/tasks/missing/transcript"} {
+		resp, err = http.Get(server.URL + path + "?token=s3cret")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, path)
+	}
+
+	// The event stream is the only route that takes the query token.
+	resp, err = http.Get(server.URL + "/api/tasks/missing/events?token=s3cret")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestServer_Dashboard(t *testing.T) {
+	server := newTestServer(t, "s3cret")
+
+	resp, err := http.Get(server.URL + "/")
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), "app.js")
+
+	screenshot := func(query string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/api/screenshot"+query, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer s3cret")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp = screenshot("")
+	body, err = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "image/jpeg", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "jpeg-bytes", string(body))
+
+	resp = screenshot("?task=t1")
+	body, err = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "jpeg-bytes of t1", string(body))
+}
@@ -0,0 +1,358 @@
+"use strict";
+
+// The token can be passed once as ?token=... and is remembered for the tab.
+const params = new URLSearchParams(location.search);
+if (params.has("token")) {
+  sessionStorage.setItem("token", params.get("token"));
+}
+const token = sessionStorage.getItem("token") || "";
+
+const SCREENSHOT_INTERVAL_MS = 2000;
+const TASK_LIST_INTERVAL_MS = 3000;
+
+const state = {
+  selectedId: null,
+  stream: null,
+  task: null,
+  stacks: [],
+  tools: [],
+  interactions: new Map(),
+  screenshotTimer: null,
+};
+
+const $ = (id) => document.getElementById(id);
+
+function withToken(url) {
+  if (!token) return url;
+  return url + (url.includes("?") ? "&" : "?") + "token=" + encodeURIComponent(token);
+}
+
+async function api(method, path, body) {
+  const headers = { "Content-Type": "application/json" };
+  if (token) headers.Authorization = "Bearer " + token;
+
+  const resp = await fetch(path, {
+    method,
+    headers,
+    body: body === undefined ? undefined : JSON.stringify(body),
+  });
+  if (resp.status === 204) return null;
+
+  const data = await resp.json();
+  if (!resp.ok) throw new Error(data.error || resp.statusText);
+  return data;
+}
+
+function el(tag, className, text) {
+  const node = document.createElement(tag);
+  if (className) node.className = className;
+  if (text !== undefined) node.textContent = text;
+  return node;
+}
+
+function prettyJSON(text) {
+  try {
+    return JSON.stringify(JSON.parse(text), null, 2);
+  } catch {
+    return text;
+  }
+}
+
+// ---- Task list ----
+
+async function refreshTasks() {
+  let tasks;
+  try {
+    tasks = await api("GET", "/api/tasks");
+  } catch (err) {
+    console.error(err);
+    return;
+  }
+
+  const list = $("task-list");
+  list.replaceChildren();
+  for (const task of tasks) {
+    const item = el("li");
+    item.dataset.id = task.id;
+    if (task.id === state.selectedId) item.classList.add("selected");
+    item.append(el("span", "status " + task.status, task.status), el("span", "description", task.description));
+    item.addEventListener("click", () => selectTask(task.id));
+    list.append(item);
+  }
+
+  if (!state.selectedId && tasks.length > 0) {
+    const active = tasks.find((t) => t.status === "running") || tasks[0];
+    selectTask(active.id);
+  }
+}
+
+$("submit-form").addEventListener("submit", async (event) => {
+  event.preventDefault();
+  const input = $("task-input");
+  const description = input.value.trim();
+  if (!description) return;
+
+  try {
+    const task = await api("POST", "/api/tasks", { task: description });
+    input.value = "";
+    await refreshTasks();
+    selectTask(task.id);
+  } catch (err) {
+    alert(err.message);
+  }
+});
+
+// ---- Run view ----
+
+function selectTask(id) {
+  if (state.stream) state.stream.close();
+
+  state.selectedId = id;
+  state.task = null;
+  state.tools = [];
+  state.interactions.clear();
+
+  const timeline = $("timeline");
+  timeline.replaceChildren();
+  state.stacks = [timeline];
+
+  $("interactions").replaceChildren();
+  $("final-answer").hidden = true;
+
+  for (const item of document.querySelectorAll("#task-list li")) {
+    item.classList.toggle("selected", item.dataset.id === id);
+  }
+
+  state.stream = new EventSource(withToken("/api/tasks/" + id + "/events"));
+  for (const type of ["task_status", "iteration", "tool_start", "tool_result", "thinking", "interaction", "answered"]) {
+    state.stream.addEventListener(type, (message) => handleEvent(JSON.parse(message.data)));
+  }
+  // The server closes the stream when the task finishes; do not reconnect.
+  state.stream.onerror = () => {
+    if (state.task && isFinished(state.task.status)) state.stream.close();
+  };
+}
+
+function isFinished(status) {
+  return status === "completed" || status === "failed" || status === "canceled";
+}
+
+function handleEvent(event) {
+  if (event.task_id !== state.selectedId) return;
+  const data = event.data || {};
+
+  switch (event.type) {
+    case "task_status":
+      renderTask(data.task);
+      break;
+    case "iteration":
+      container(data.depth).append(
+        el("div", "iteration", data.agent + " · итерация " + data.iteration + "/" + data.max_iterations));
+      break;
+    case "thinking":
+      container(data.depth).append(el("div", "thinking", data.content));
+      break;
+    case "tool_start":
+      startTool(data);
+      break;
+    case "tool_result":
+      finishTool(data);
+      break;
+    case "interaction":
+      addInteraction(data.interaction);
+      break;
+    case "answered":
+      removeInteraction(data.interaction_id);
+      break;
+  }
+}
+
+// container returns the element events of the given agent depth go into.
+// Sub-agent events nest under the run_agent call that started them.
+function container(depth) {
+  depth = depth || 0;
+  while (state.stacks.length <= depth) {
+    const block = el("div", "agent-block");
+    state.stacks[state.stacks.length - 1].append(block);
+    state.stacks.push(block);
+  }
+  return state.stacks[depth];
+}
+
+function startTool(data) {
+  const depth = data.depth || 0;
+  const details = el("details", "tool pending");
+  details.append(el("summary", null, data.tool), el("pre", "arguments", prettyJSON(data.arguments)));
+  container(depth).append(details);
+
+  state.tools.push({ depth, tool: data.tool, node: details });
+
+  if (data.tool === "run_agent") {
+    const block = el("div", "agent-block");
+    let agentName = "";
+    try {
+      agentName = JSON.parse(data.arguments).agent_type;
+    } catch {}
+    block.append(el("div", "agent-name", agentName));
+    container(depth).append(block);
+    state.stacks.length = depth + 1;
+    state.stacks.push(block);
+  }
+}
+
+function finishTool(data) {
+  const depth = data.depth || 0;
+  for (let i = state.tools.length - 1; i >= 0; i--) {
+    const call = state.tools[i];
+    if (call.depth !== depth || call.tool !== data.tool) continue;
+
+    call.node.classList.remove("pending");
+    if (data.is_error) call.node.classList.add("error");
+    call.node.append(el("pre", "result", data.result));
+    state.tools.splice(i, 1);
+    break;
+  }
+
+  if (data.tool === "run_agent") {
+    state.stacks.length = depth + 1;
+  }
+}
+
+function renderTask(task) {
+  state.task = task;
+
+  const header = $("run-header");
+  header.className = "";
+  header.replaceChildren(el("span", "status " + task.status, task.status), el("span", "title", task.description));
+
+  if (!isFinished(task.status)) {
+    const cancel = el("button", null, "Отменить");
+    cancel.addEventListener("click", () => api("POST", "/api/tasks/" + task.id + "/cancel").catch((err) => alert(err.message)));
+    header.append(cancel);
+  } else {
+    const report = el("span", "reports", "Отчёт:");
+    for (const format of ["html", "md", "json"]) {
+      const link = el("a", null, format);
+      link.href = "#";
+      link.addEventListener("click", (event) => {
+        event.preventDefault();
+        downloadTranscript(task.id, format).catch((err) => alert(err.message));
+      });
+      report.append(" ", link);
+    }
+    header.append(report);
+  }
+
+  const answer = $("final-answer");
+  if (task.final_answer || task.error) {
+    answer.textContent = task.final_answer || task.error;
+    answer.hidden = false;
+  }
+
+  if (isFinished(task.status)) {
+    $("interactions").replaceChildren();
+    state.interactions.clear();
+  }
+
+  updateScreenshotPolling();
+  refreshTasks();
+}
+
+// ---- Pending questions ----
+
+function addInteraction(interaction) {
+  const node = $("interaction-template").content.firstElementChild.cloneNode(true);
+  const kinds = { question: "Вопрос", action: "Требуется действие", approval: "Подтверждение" };
+
+  node.querySelector(".kind").textContent = kinds[interaction.kind] || interaction.kind;
+  node.querySelector(".prompt").textContent = interaction.prompt;
+
+  const form = node.querySelector("form");
+  const input = form.querySelector("input");
+  const respond = (answer) =>
+    api("POST", "/api/tasks/" + interaction.task_id + "/interactions/" + interaction.id, { answer })
+      .then(() => removeInteraction(interaction.id))
+      .catch((err) => alert(err.message));
+
+  if (interaction.kind === "approval") {
+    const { screenshot, ...approval } = interaction.approval;
+    const details = node.querySelector(".details");
+    details.textContent = JSON.stringify(approval, null, 2);
+    details.hidden = false;
+    if (screenshot) {
+      const image = node.querySelector(".approval-screenshot");
+      image.src = "data:image/" + screenshot.format + ";base64," + screenshot.data;
+      image.hidden = false;
+    }
+    input.hidden = true;
+    form.querySelector("button[type=submit]").hidden = true;
+    form.querySelector(".approve").hidden = false;
+    form.querySelector(".reject").hidden = false;
+    form.querySelector(".approve").addEventListener("click", () => respond("yes"));
+    form.querySelector(".reject").addEventListener("click", () => respond("no"));
+  } else if (interaction.kind === "action") {
+    input.placeholder = "Комментарий (необязательно)";
+    form.querySelector("button[type=submit]").textContent = "Готово";
+  }
+
+  form.addEventListener("submit", (event) => {
+    event.preventDefault();
+    respond(input.value);
+  });
+
+  state.interactions.set(interaction.id, node);
+  $("interactions").append(node);
+}
+
+function removeInteraction(id) {
+  const node = state.interactions.get(id);
+  if (node) node.remove();
+  state.interactions.delete(id);
+}
+
+// ---- Screenshot ----
+
+function updateScreenshotPolling() {
+  const running = state.task && state.task.status === "running";
+  if (running && !state.screenshotTimer) {
+    refreshScreenshot();
+    state.screenshotTimer = setInterval(refreshScreenshot, SCREENSHOT_INTERVAL_MS);
+  } else if (!running && state.screenshotTimer) {
+    clearInterval(state.screenshotTimer);
+    state.screenshotTimer = null;
+  }
+}
+
+// Only the event stream accepts ?token=, so files are fetched with the
+// header and saved from a blob.
+async function downloadTranscript(id, format) {
+  const headers = token ? { Authorization: "Bearer " + token } : {};
+  const resp = await fetch("/api/tasks/" + id + "/transcript?format=" + format, { headers });
+  if (!resp.ok) throw new Error(resp.statusText);
+
+  const link = document.createElement("a");
+  link.href = URL.createObjectURL(await resp.blob());
+  link.download = "transcript-" + id + "." + format;
+  link.click();
+  setTimeout(() => URL.revokeObjectURL(link.href), 0);
+}
+
+async function refreshScreenshot() {
+  const headers = token ? { Authorization: "Bearer " + token } : {};
+  try {
+    const query = state.task ? "?task=" + encodeURIComponent(state.task.id) : "";
+    const resp = await fetch("/api/screenshot" + query, { headers, cache: "no-store" });
+    if (!resp.ok) throw new Error(resp.statusText);
+
+    const img = $("screenshot");
+    const previous = img.src;
+    img.src = URL.createObjectURL(await resp.blob());
+    if (previous.startsWith("blob:")) URL.revokeObjectURL(previous);
+    $("screenshot-status").textContent = "Обновлено " + new Date().toLocaleTimeString();
+  } catch {
+    $("screenshot-status").textContent = "Скриншот недоступен";
+  }
+}
+
+refreshTasks();
+setInterval(refreshTasks, TASK_LIST_INTERVAL_MS);
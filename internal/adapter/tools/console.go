@@ -0,0 +1,122 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/domain/entity"
+)
+
+const (
+	defaultConsoleLimit = 20
+	maxConsoleLimit     = 100
+	// maxNotedErrors is how many errors an action result lists.
+	maxNotedErrors = 5
+)
+
+type ConsoleTool struct {
+	browser output.BrowserPort
+	logger  output.LoggerPort
+}
+
+func NewConsoleTool(browser output.BrowserPort, logger output.LoggerPort) *ConsoleTool {
+	return &ConsoleTool{browser: browser, logger: logger}
+}
+
+func (t *ConsoleTool) Name() entity.ToolName { return entity.ToolBrowserConsole }
+func (t *ConsoleTool) Description() string {
+	return "Read the browser console of the page: console messages, uncaught JavaScript exceptions and browser errors such as failed resource loads. Use it when a click or form submit seems to do nothing, or the page looks broken or empty: a JavaScript error means repeating the action will not help."
+}
+func (t *ConsoleTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"level": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{"all", "warning", "error"},
+				"description": "Lowest level to show: 'error' for errors and exceptions only, 'warning' to add warnings (default: all)",
+			},
+			"limit": map[string]interface{}{
+				"type":        "number",
+				"description": "Most recent messages to show (default: 20, max: 100)",
+			},
+		},
+	}
+}
+
+func (t *ConsoleTool) Execute(ctx context.Context, args string) (string, error) {
+	var input struct {
+		Level string  `json:"level"`
+		Limit float64 `json:"limit"`
+	}
+	if err := json.Unmarshal([]byte(args), &input); err != nil {
+		return "", fmt.Errorf("invalid arguments: %w", err)
+	}
+
+	filter := entity.ConsoleFilter{Limit: int(input.Limit)}
+	if input.Level != "" && input.Level != "all" {
+		filter.MinLevel = entity.ConsoleLevel(input.Level)
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultConsoleLimit
+	}
+	if filter.Limit > maxConsoleLimit {
+		filter.Limit = maxConsoleLimit
+	}
+
+	messages, err := t.browser.ConsoleMessages(ctx, filter)
+	if err != nil {
+		return "", err
+	}
+	if len(messages) == 0 {
+		return "No console messages", nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d console messages (oldest first):\n", len(messages))
+	for _, msg := range messages {
+		fmt.Fprintf(&b, "#%d %s\n", msg.ID, msg)
+	}
+	return b.String(), nil
+}
+
+// consoleMark returns the ID of the newest console message, so the errors
+// logged by the following action can be told apart.
+func consoleMark(ctx context.Context, browser output.BrowserPort) int {
+	messages, err := browser.ConsoleMessages(ctx, entity.ConsoleFilter{Limit: 1})
+	if err != nil || len(messages) == 0 {
+		return 0
+	}
+	return messages[0].ID
+}
+
+// consoleErrorsSince notes the errors logged after the message with ID
+// mark; empty when there were none.
+func consoleErrorsSince(ctx context.Context, browser output.BrowserPort, mark int) string {
+	messages, err := browser.ConsoleMessages(ctx, entity.ConsoleFilter{MinLevel: entity.ConsoleError, AfterID: mark})
+	if err != nil {
+		return ""
+	}
+	return formatConsoleErrors(messages)
+}
+
+func formatConsoleErrors(messages []entity.ConsoleMessage) string {
+	if len(messages) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n⚠ %d JavaScript/browser errors during this action:", len(messages))
+	for i, msg := range messages {
+		if i >= maxNotedErrors {
+			fmt.Fprintf(&b, "\n  ... and %d more (see console)", len(messages)-maxNotedErrors)
+			break
+		}
+		fmt.Fprintf(&b, "\n  - %s", msg)
+	}
+	b.WriteString("\nTIP: The page may be broken; check the result before repeating the action")
+	return b.String()
+}
@@ -0,0 +1,80 @@
+package tool
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"browser-agent/internal/testkit"
+)
+
+const brokenCheckoutSite = `
+pages:
+  cart:
+    url: https://shop.test/cart
+    console:
+      - {text: cart loaded}
+      - {level: warning, text: deprecated API}
+    elements:
+      - {selector: "#checkout", tag: button, text: Checkout, goto: failed}
+      - {selector: "#help", tag: a, text: Help}
+  failed:
+    url: https://shop.test/checkout
+    console:
+      - {level: exception, text: "Uncaught TypeError: cart.total is undefined\n    at checkout (app.js:40)", source: "https://shop.test/app.js:40"}
+      - {level: error, text: "Failed to load resource: the server responded with a status of 500 ()", source: "https://shop.test/api/checkout"}
+    elements:
+      - {selector: "#checkout", tag: button, text: Checkout}
+`
+
+func TestConsoleTool(t *testing.T) {
+	ctx := context.Background()
+	browser := testkit.NewBrowser(testkit.MustParseSite(brokenCheckoutSite))
+	console := NewConsoleTool(browser, testkit.NopLogger{})
+
+	result, err := console.Execute(ctx, `{}`)
+	require.NoError(t, err)
+	assert.Equal(t, "No console messages", result)
+
+	navigate := NewNavigateTool(browser, testkit.NopLogger{})
+	result, err = navigate.Execute(ctx, `{"url":"https://shop.test/cart"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "Navigated to https://shop.test/cart", result)
+
+	click := NewClickTool(browser, testkit.NopLogger{})
+	result, err = click.Execute(ctx, `{"selectors":["#checkout"]}`)
+	require.NoError(t, err)
+	assert.Equal(t, "Click successful\n"+
+		"⚠ 2 JavaScript/browser errors during this action:\n"+
+		"  - [exception] Uncaught TypeError: cart.total is undefined ... (https://shop.test/app.js:40)\n"+
+		"  - [error] Failed to load resource: the server responded with a status of 500 () (https://shop.test/api/checkout)\n"+
+		"TIP: The page may be broken; check the result before repeating the action", result)
+
+	result, err = console.Execute(ctx, `{"level":"warning"}`)
+	require.NoError(t, err)
+	assert.Contains(t, result, "3 console messages")
+	assert.Contains(t, result, "#2 [warning] deprecated API")
+	assert.NotContains(t, result, "cart loaded")
+
+	result, err = console.Execute(ctx, `{"limit":1}`)
+	require.NoError(t, err)
+	assert.Contains(t, result, "1 console messages")
+	assert.Contains(t, result, "#4 [error]")
+
+	_, err = console.Execute(ctx, `{"level":"fatal"}`)
+	assert.ErrorContains(t, err, `invalid level "fatal"`)
+}
+
+func TestClickObserveReportsConsoleErrors(t *testing.T) {
+	ctx := context.Background()
+	browser := testkit.NewBrowser(testkit.MustParseSite(brokenCheckoutSite))
+	require.NoError(t, browser.Navigate(ctx, "https://shop.test/cart"))
+	click := NewClickTool(browser, testkit.NopLogger{})
+
+	result, err := click.Execute(ctx, `{"selectors":["#checkout"],"observe":true}`)
+	require.NoError(t, err)
+	assert.Contains(t, result, "⚠ 2 JavaScript/browser errors during this action")
+	assert.Contains(t, result, "[exception] Uncaught TypeError")
+}
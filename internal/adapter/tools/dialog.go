@@ -0,0 +1,141 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/domain/entity"
+)
+
+// maxListedDialogs is how many closed dialogs the status action shows.
+const maxListedDialogs = 5
+
+type DialogTool struct {
+	browser output.BrowserPort
+	logger  output.LoggerPort
+}
+
+func NewDialogTool(browser output.BrowserPort, logger output.LoggerPort) *DialogTool {
+	return &DialogTool{browser: browser, logger: logger}
+}
+
+func (t *DialogTool) Name() entity.ToolName { return entity.ToolBrowserDialog }
+func (t *DialogTool) Description() string {
+	return "Handle native JavaScript dialogs (alert, confirm, prompt, 'leave site?'). While a dialog is open the page is frozen and every other browser tool fails with an error naming the dialog. Use 'accept' to press OK (with 'prompt_text' for a prompt), 'dismiss' to press Cancel, 'status' to see the open dialog and recent ones. Accept a confirm only if it matches the task, e.g. confirming a deletion the user asked for."
+}
+func (t *DialogTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"action": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{"accept", "dismiss", "status"},
+				"description": "accept (OK), dismiss (Cancel) or status (default)",
+			},
+			"prompt_text": map[string]interface{}{
+				"type":        "string",
+				"description": "Text to enter into a prompt dialog before accepting it (default: the prompt's default value)",
+			},
+		},
+	}
+}
+
+func (t *DialogTool) Execute(ctx context.Context, args string) (string, error) {
+	var input struct {
+		Action     string  `json:"action"`
+		PromptText *string `json:"prompt_text"`
+	}
+	if err := json.Unmarshal([]byte(args), &input); err != nil {
+		return "", fmt.Errorf("invalid arguments: %w", err)
+	}
+
+	switch input.Action {
+	case "", "status":
+		return t.status(ctx)
+	case "accept", "dismiss":
+	default:
+		return "", fmt.Errorf("unknown action %q (must be accept, dismiss or status)", input.Action)
+	}
+
+	resp := entity.DialogResponse{Accept: input.Action == "accept"}
+	if resp.Accept {
+		resp.PromptText = t.promptText(ctx, input.PromptText)
+	}
+	dialog, err := t.browser.HandleDialog(ctx, resp)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Dialog closed: %s\nCurrent URL: %s", dialog, t.browser.CurrentURL()), nil
+}
+
+// promptText falls back to the default value of the open prompt, as
+// pressing OK without typing would.
+func (t *DialogTool) promptText(ctx context.Context, text *string) string {
+	if text != nil {
+		return *text
+	}
+	dialogs, err := t.browser.Dialogs(ctx)
+	if err != nil || len(dialogs) == 0 {
+		return ""
+	}
+	return dialogs[len(dialogs)-1].DefaultPrompt
+}
+
+func (t *DialogTool) status(ctx context.Context) (string, error) {
+	dialogs, err := t.browser.Dialogs(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(dialogs) == 0 {
+		return "No dialogs so far", nil
+	}
+
+	var b strings.Builder
+	last := dialogs[len(dialogs)-1]
+	if last.Open {
+		fmt.Fprintf(&b, "Open dialog: %s\n", last)
+		dialogs = dialogs[:len(dialogs)-1]
+	} else {
+		b.WriteString("No dialog is open\n")
+	}
+	if len(dialogs) > maxListedDialogs {
+		dialogs = dialogs[len(dialogs)-maxListedDialogs:]
+	}
+	if len(dialogs) > 0 {
+		b.WriteString("Recent dialogs:\n")
+		for _, d := range dialogs {
+			fmt.Fprintf(&b, "#%d %s\n", d.ID, d)
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+// dialogMark returns the ID of the newest dialog, so the dialogs of the
+// following action can be told apart.
+func dialogMark(ctx context.Context, browser output.BrowserPort) int {
+	dialogs, err := browser.Dialogs(ctx)
+	if err != nil || len(dialogs) == 0 {
+		return 0
+	}
+	return dialogs[len(dialogs)-1].ID
+}
+
+// dialogsSince notes the dialogs the policy closed after the one with ID
+// mark; open ones are reported by the action's error instead.
+func dialogsSince(ctx context.Context, browser output.BrowserPort, mark int) string {
+	dialogs, err := browser.Dialogs(ctx)
+	if err != nil {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, d := range dialogs {
+		if d.ID > mark && !d.Open {
+			fmt.Fprintf(&b, "\n⚠ Dialog %s", d)
+		}
+	}
+	return b.String()
+}
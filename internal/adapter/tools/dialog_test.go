@@ -0,0 +1,91 @@
+package tool
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"browser-agent/internal/domain/entity"
+	"browser-agent/internal/testkit"
+)
+
+const dialogSite = `
+start: cart
+pages:
+  cart:
+    url: https://shop.test/cart
+    elements:
+      - selector: "#remove"
+        tag: button
+        text: Remove
+        goto: empty
+        dialog: {type: confirm, message: "Remove the kettle from the cart?"}
+      - selector: "#coupon"
+        tag: button
+        text: Coupon
+        goto: coupon
+        dialog: {type: prompt, message: Coupon code, default: SALE}
+  empty:
+    url: https://shop.test/cart
+    text: Your cart is empty
+  coupon:
+    url: https://shop.test/cart?coupon=1
+`
+
+func TestDialogToolAgentPolicy(t *testing.T) {
+	ctx := context.Background()
+	browser := testkit.NewBrowser(testkit.MustParseSite(dialogSite))
+	dialog := NewDialogTool(browser, testkit.NopLogger{})
+	click := NewClickTool(browser, testkit.NopLogger{})
+
+	_, err := click.Execute(ctx, `{"selectors":["#remove"]}`)
+	require.ErrorIs(t, err, entity.ErrDialogOpen)
+	assert.Equal(t, `a JavaScript dialog is open: confirm "Remove the kettle from the cart?" (open); accept or dismiss it with browser_dialog first`, err.Error())
+
+	_, err = NewObserveTool(browser, testkit.NopLogger{}).Execute(ctx, `{}`)
+	assert.ErrorIs(t, err, entity.ErrDialogOpen)
+
+	result, err := dialog.Execute(ctx, `{}`)
+	require.NoError(t, err)
+	assert.Equal(t, `Open dialog: confirm "Remove the kettle from the cart?" (open)`, result)
+
+	result, err = dialog.Execute(ctx, `{"action":"dismiss"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "Dialog closed: confirm \"Remove the kettle from the cart?\" (dismissed)\nCurrent URL: https://shop.test/cart", result)
+	assert.Equal(t, "cart", browser.Page())
+
+	_, err = dialog.Execute(ctx, `{"action":"accept"}`)
+	assert.ErrorIs(t, err, entity.ErrNoDialog)
+
+	_, err = click.Execute(ctx, `{"selectors":["#coupon"]}`)
+	require.ErrorIs(t, err, entity.ErrDialogOpen)
+	result, err = dialog.Execute(ctx, `{"action":"accept"}`)
+	require.NoError(t, err)
+	assert.Contains(t, result, `prompt "Coupon code" [default: "SALE"] (accepted with "SALE")`)
+	assert.Equal(t, "coupon", browser.Page())
+
+	result, err = dialog.Execute(ctx, `{"action":"status"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "No dialog is open\nRecent dialogs:\n"+
+		"#1 confirm \"Remove the kettle from the cart?\" (dismissed)\n"+
+		"#2 prompt \"Coupon code\" [default: \"SALE\"] (accepted with \"SALE\")", result)
+
+	assert.Equal(t, []string{"click #remove", "dialog dismiss", "click #coupon", "dialog accept SALE"}, browser.Actions())
+}
+
+func TestDialogNotedUnderPolicy(t *testing.T) {
+	ctx := context.Background()
+	browser := testkit.NewBrowser(testkit.MustParseSite(dialogSite))
+	browser.SetDialogPolicy(entity.DialogPolicyAccept)
+	click := NewClickTool(browser, testkit.NopLogger{})
+
+	result, err := click.Execute(ctx, `{"selectors":["#remove"]}`)
+	require.NoError(t, err)
+	assert.Equal(t, "Click successful\n⚠ Dialog confirm \"Remove the kettle from the cart?\" (accepted by policy)", result)
+	assert.Equal(t, "empty", browser.Page())
+
+	_, err = NewDialogTool(browser, testkit.NopLogger{}).Execute(ctx, `{"action":"close"}`)
+	assert.ErrorContains(t, err, `unknown action "close"`)
+}
@@ -0,0 +1,129 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/domain/entity"
+)
+
+type EmulateTool struct {
+	browser output.BrowserPort
+	logger  output.LoggerPort
+}
+
+func NewEmulateTool(browser output.BrowserPort, logger output.LoggerPort) *EmulateTool {
+	return &EmulateTool{browser: browser, logger: logger}
+}
+
+func (t *EmulateTool) Name() entity.ToolName { return entity.ToolBrowserEmulate }
+func (t *EmulateTool) Description() string {
+	return "Make the browser look like another device, region or user preference: device preset (phone/tablet with touch and mobile user agent), viewport size, user agent, locale (language and Accept-Language), timezone, geolocation and light/dark color scheme. Only the given settings change, the others stay; reset returns to the configured defaults. Navigate to the page again afterwards, since sites read the user agent and language when they load."
+}
+func (t *EmulateTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"device": map[string]interface{}{
+				"type":        "string",
+				"enum":        entity.DeviceNames(),
+				"description": "Device preset: viewport, pixel ratio, touch and user agent",
+			},
+			"width": map[string]interface{}{
+				"type":        "number",
+				"description": "Viewport width in CSS pixels, together with height",
+			},
+			"height": map[string]interface{}{
+				"type":        "number",
+				"description": "Viewport height in CSS pixels, together with width",
+			},
+			"user_agent": map[string]interface{}{
+				"type":        "string",
+				"description": "User-Agent string, overrides the device preset's",
+			},
+			"locale": map[string]interface{}{
+				"type":        "string",
+				"description": "Locale such as 'de-DE' or 'ja-JP': language, number/date formats and Accept-Language",
+			},
+			"timezone": map[string]interface{}{
+				"type":        "string",
+				"description": "IANA timezone such as 'Europe/Berlin' or 'America/New_York'",
+			},
+			"latitude": map[string]interface{}{
+				"type":        "number",
+				"description": "Geolocation latitude, together with longitude",
+			},
+			"longitude": map[string]interface{}{
+				"type":        "number",
+				"description": "Geolocation longitude, together with latitude",
+			},
+			"accuracy": map[string]interface{}{
+				"type":        "number",
+				"description": "Geolocation accuracy in meters (default: 50)",
+			},
+			"color_scheme": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{"light", "dark", "no-preference"},
+				"description": "Value of the prefers-color-scheme media query",
+			},
+			"reset": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Return to the configured emulation, ignoring the other arguments",
+			},
+		},
+	}
+}
+
+func (t *EmulateTool) Execute(ctx context.Context, args string) (string, error) {
+	var input struct {
+		Device      string   `json:"device"`
+		Width       float64  `json:"width"`
+		Height      float64  `json:"height"`
+		UserAgent   string   `json:"user_agent"`
+		Locale      string   `json:"locale"`
+		Timezone    string   `json:"timezone"`
+		Latitude    *float64 `json:"latitude"`
+		Longitude   *float64 `json:"longitude"`
+		Accuracy    float64  `json:"accuracy"`
+		ColorScheme string   `json:"color_scheme"`
+		Reset       bool     `json:"reset"`
+	}
+	if err := json.Unmarshal([]byte(args), &input); err != nil {
+		return "", fmt.Errorf("invalid arguments: %w", err)
+	}
+
+	if input.Reset {
+		emulation, err := t.browser.ResetEmulation(ctx)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Emulation reset: %s", emulation), nil
+	}
+
+	e := entity.Emulation{
+		Device:      input.Device,
+		Width:       int(input.Width),
+		Height:      int(input.Height),
+		UserAgent:   input.UserAgent,
+		Locale:      input.Locale,
+		Timezone:    input.Timezone,
+		ColorScheme: entity.ColorScheme(input.ColorScheme),
+	}
+	if (input.Latitude == nil) != (input.Longitude == nil) {
+		return "", fmt.Errorf("latitude and longitude must be set together")
+	}
+	if input.Latitude != nil {
+		e.Geolocation = &entity.Geolocation{Latitude: *input.Latitude, Longitude: *input.Longitude, Accuracy: input.Accuracy}
+	}
+	if e.IsZero() {
+		return "", fmt.Errorf("nothing to emulate: set device, viewport, user_agent, locale, timezone, geolocation or color_scheme, or reset")
+	}
+
+	emulation, err := t.browser.Emulate(ctx, e)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Emulating: %s\nNavigate to the page again so the site picks up the new settings", emulation), nil
+}
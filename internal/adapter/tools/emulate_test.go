@@ -0,0 +1,39 @@
+package tool
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"browser-agent/internal/domain/entity"
+	"browser-agent/internal/testkit"
+)
+
+func TestEmulateTool(t *testing.T) {
+	ctx := context.Background()
+	browser := testkit.NewBrowser(testkit.MustParseSite(brokenCheckoutSite))
+	emulate := NewEmulateTool(browser, testkit.NopLogger{})
+
+	result, err := emulate.Execute(ctx, `{"device":"iphone-15","locale":"de-DE"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "Emulating: iphone-15 393x852@3x mobile touch, locale de-DE\nNavigate to the page again so the site picks up the new settings", result)
+
+	result, err = emulate.Execute(ctx, `{"timezone":"Europe/Berlin","latitude":52.52,"longitude":13.405}`)
+	require.NoError(t, err)
+	assert.Contains(t, result, "iphone-15 393x852@3x mobile touch, locale de-DE, timezone Europe/Berlin, geolocation 52.52,13.405 (±50m)")
+	assert.Equal(t, &entity.Geolocation{Latitude: 52.52, Longitude: 13.405}, browser.Emulation().Geolocation)
+
+	_, err = emulate.Execute(ctx, `{"latitude":0}`)
+	assert.ErrorContains(t, err, "latitude and longitude must be set together")
+	_, err = emulate.Execute(ctx, `{"device":"nokia"}`)
+	assert.ErrorContains(t, err, `unknown device "nokia"`)
+	_, err = emulate.Execute(ctx, `{}`)
+	assert.ErrorContains(t, err, "nothing to emulate")
+
+	result, err = emulate.Execute(ctx, `{"reset":true}`)
+	require.NoError(t, err)
+	assert.Equal(t, "Emulation reset: browser defaults", result)
+	assert.True(t, browser.Emulation().IsZero())
+}
@@ -0,0 +1,156 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/domain/entity"
+)
+
+type InterceptTool struct {
+	browser output.BrowserPort
+	logger  output.LoggerPort
+}
+
+func NewInterceptTool(browser output.BrowserPort, logger output.LoggerPort) *InterceptTool {
+	return &InterceptTool{browser: browser, logger: logger}
+}
+
+func (t *InterceptTool) Name() entity.ToolName { return entity.ToolBrowserIntercept }
+func (t *InterceptTool) Description() string {
+	return "Change the requests the page makes from now on. Rules: 'block' - fail matching requests (e.g. resource_types image, font, media to load heavy pages faster); 'mock' - answer matching requests with status and body without reaching the server (e.g. check how a page handles an API error); 'headers' - add headers to matching requests (auth tokens, feature flags). Rules apply after the next navigation or request; reload the page to see them take effect. Actions: 'add' a rule, 'list' rules, 'remove' one by id, 'clear' all."
+}
+func (t *InterceptTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"action": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{"add", "list", "remove", "clear"},
+				"description": "What to do with the rules",
+			},
+			"rule": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{"block", "mock", "headers"},
+				"description": "For 'add': kind of rule",
+			},
+			"url_pattern": map[string]interface{}{
+				"type":        "string",
+				"description": "For 'add': regular expression the request URL must match; empty matches all. Example: '/api/cart', 'analytics'",
+			},
+			"resource_types": map[string]interface{}{
+				"type":        "array",
+				"items":       map[string]interface{}{"type": "string"},
+				"description": "For 'add': request types the rule applies to; empty means all. Examples: image, font, media, stylesheet, script, xhr, fetch, document",
+			},
+			"status": map[string]interface{}{
+				"type":        "number",
+				"description": "For 'mock': response status (default: 200)",
+			},
+			"body": map[string]interface{}{
+				"type":        "string",
+				"description": "For 'mock': response body",
+			},
+			"content_type": map[string]interface{}{
+				"type":        "string",
+				"description": "For 'mock': response Content-Type (default: detected from the body)",
+			},
+			"headers": map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": map[string]interface{}{"type": "string"},
+				"description":          "For 'headers': headers added to the request; for 'mock': headers of the response",
+			},
+			"id": map[string]interface{}{
+				"type":        "string",
+				"description": "For 'remove': rule id from 'list'",
+			},
+		},
+		"required": []string{"action"},
+	}
+}
+
+func (t *InterceptTool) Execute(ctx context.Context, args string) (string, error) {
+	var input struct {
+		Action        string            `json:"action"`
+		Rule          string            `json:"rule"`
+		URLPattern    string            `json:"url_pattern"`
+		ResourceTypes []string          `json:"resource_types"`
+		Status        float64           `json:"status"`
+		Body          string            `json:"body"`
+		ContentType   string            `json:"content_type"`
+		Headers       map[string]string `json:"headers"`
+		ID            string            `json:"id"`
+	}
+	if err := json.Unmarshal([]byte(args), &input); err != nil {
+		return "", fmt.Errorf("invalid arguments: %w", err)
+	}
+
+	switch input.Action {
+	case "add":
+		rule := entity.InterceptRule{
+			Action:      entity.InterceptAction(input.Rule),
+			URLPattern:  input.URLPattern,
+			Status:      int(input.Status),
+			Body:        input.Body,
+			ContentType: input.ContentType,
+			Headers:     input.Headers,
+		}
+		for _, resourceType := range input.ResourceTypes {
+			rule.ResourceTypes = append(rule.ResourceTypes, strings.ToLower(resourceType))
+		}
+		added, err := t.browser.AddInterceptRule(ctx, rule)
+		if err != nil {
+			return "", err
+		}
+		t.logger.Info("Intercept rule added", "rule", added.Describe())
+		return fmt.Sprintf("Rule added: %s", added.Describe()), nil
+
+	case "list":
+		rules, err := t.browser.InterceptRules(ctx)
+		if err != nil {
+			return "", err
+		}
+		return formatInterceptRules(rules), nil
+
+	case "remove":
+		if input.ID == "" {
+			return "", fmt.Errorf("id is required for action 'remove'")
+		}
+		if err := t.browser.RemoveInterceptRule(ctx, input.ID); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Rule #%s removed", input.ID), nil
+
+	case "clear":
+		rules, err := t.browser.InterceptRules(ctx)
+		if err != nil {
+			return "", err
+		}
+		for _, rule := range rules {
+			if err := t.browser.RemoveInterceptRule(ctx, rule.ID); err != nil {
+				return "", err
+			}
+		}
+		return fmt.Sprintf("Removed %d rules", len(rules)), nil
+
+	default:
+		return "", fmt.Errorf("invalid action: %q (must be 'add', 'list', 'remove' or 'clear')", input.Action)
+	}
+}
+
+func formatInterceptRules(rules []entity.InterceptRule) string {
+	if len(rules) == 0 {
+		return "No intercept rules"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d intercept rules, applied in order:\n", len(rules))
+	for _, rule := range rules {
+		b.WriteString(rule.Describe())
+		b.WriteString("\n")
+	}
+	return b.String()
+}
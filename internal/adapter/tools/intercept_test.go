@@ -0,0 +1,70 @@
+package tool
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"browser-agent/internal/testkit"
+)
+
+func TestInterceptTool(t *testing.T) {
+	ctx := context.Background()
+	browser := testkit.NewBrowser(testkit.MustParseSite(apiSite))
+	intercept := NewInterceptTool(browser, testkit.NopLogger{})
+	network := NewNetworkTool(browser, testkit.NopLogger{})
+
+	result, err := intercept.Execute(ctx, `{"action":"add","rule":"mock","url_pattern":"/api/search","status":503,"body":"{\"error\":\"down\"}","content_type":"application/json"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "Rule added: #1 mock 503 /api/search", result)
+
+	result, err = intercept.Execute(ctx, `{"action":"add","rule":"block","resource_types":["Document"]}`)
+	require.NoError(t, err)
+	assert.Equal(t, "Rule added: #2 block * [document]", result)
+
+	result, err = intercept.Execute(ctx, `{"action":"add","rule":"headers","headers":{"X-Feature":"new-cart"}}`)
+	require.NoError(t, err)
+	assert.Equal(t, "Rule added: #3 headers * +X-Feature", result)
+
+	result, err = intercept.Execute(ctx, `{"action":"list"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "3 intercept rules, applied in order:\n#1 mock 503 /api/search\n#2 block * [document]\n#3 headers * +X-Feature\n", result)
+
+	require.NoError(t, browser.Navigate(ctx, "https://shop.test/?q=kettle"))
+	result, err = network.Execute(ctx, `{"action":"list"}`)
+	require.NoError(t, err)
+	assert.Contains(t, result, "#1 GET failed (net::ERR_BLOCKED_BY_CLIENT) document")
+	assert.Contains(t, result, "#2 GET 503 xhr https://shop.test/api/search?q=kettle [application/json]")
+
+	result, err = intercept.Execute(ctx, `{"action":"remove","id":"2"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "Rule #2 removed", result)
+
+	result, err = intercept.Execute(ctx, `{"action":"clear"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "Removed 2 rules", result)
+	result, err = intercept.Execute(ctx, `{"action":"list"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "No intercept rules", result)
+}
+
+func TestInterceptToolRejectsInvalidRules(t *testing.T) {
+	intercept := NewInterceptTool(testkit.NewBrowser(testkit.MustParseSite(apiSite)), testkit.NopLogger{})
+
+	for args, want := range map[string]string{
+		`{"action":"add","rule":"rewrite"}`:                          `unknown intercept action "rewrite"`,
+		`{"action":"add","rule":"block","url_pattern":"("}`:          "invalid url_pattern",
+		`{"action":"add","rule":"block","resource_types":["video"]}`: `unknown resource type "video"`,
+		`{"action":"add","rule":"mock","status":999}`:                "invalid mock status 999",
+		`{"action":"add","rule":"headers"}`:                          `headers are required for action "headers"`,
+		`{"action":"add","rule":"headers","headers":{"X A":"1"}}`:    `invalid header name "X A"`,
+		`{"action":"remove"}`:                                        "id is required",
+		`{"action":"remove","id":"9"}`:                               `intercept rule "9" not found`,
+		`{"action":"toggle"}`:                                        `invalid action: "toggle"`,
+	} {
+		_, err := intercept.Execute(context.Background(), args)
+		assert.ErrorContains(t, err, want, args)
+	}
+}
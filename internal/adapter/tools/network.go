@@ -0,0 +1,177 @@
+package tool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/domain/entity"
+)
+
+const (
+	defaultNetworkLimit = 20
+	maxNetworkLimit     = 100
+)
+
+type NetworkTool struct {
+	browser output.BrowserPort
+	logger  output.LoggerPort
+}
+
+func NewNetworkTool(browser output.BrowserPort, logger output.LoggerPort) *NetworkTool {
+	return &NetworkTool{browser: browser, logger: logger}
+}
+
+func (t *NetworkTool) Name() entity.ToolName { return entity.ToolBrowserNetwork }
+func (t *NetworkTool) Description() string {
+	return "Inspect the requests the page made (XHR, fetch and page loads). Many sites load their data as JSON from an API: reading that response is exact, while scraping the rendered page may lose fields. Action 'list' shows recent requests with id, method, status, type and URL (filter with url_pattern and type); action 'get' returns one request with its request and response bodies. Responses are recorded from the moment the page starts loading, so navigate or click first, then list."
+}
+func (t *NetworkTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"action": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{"list", "get"},
+				"description": "'list' to see captured requests, 'get' to read one with its bodies",
+			},
+			"url_pattern": map[string]interface{}{
+				"type":        "string",
+				"description": "For 'list': regular expression the URL must match. Example: '/api/', 'graphql'",
+			},
+			"type": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{"xhr", "fetch", "document"},
+				"description": "For 'list': only requests of this type",
+			},
+			"limit": map[string]interface{}{
+				"type":        "number",
+				"description": "For 'list': most recent requests to show (default: 20, max: 100)",
+			},
+			"id": map[string]interface{}{
+				"type":        "string",
+				"description": "For 'get': request id from 'list'",
+			},
+		},
+		"required": []string{"action"},
+	}
+}
+
+func (t *NetworkTool) Execute(ctx context.Context, args string) (string, error) {
+	var input struct {
+		Action     string  `json:"action"`
+		URLPattern string  `json:"url_pattern"`
+		Type       string  `json:"type"`
+		Limit      float64 `json:"limit"`
+		ID         string  `json:"id"`
+	}
+	if err := json.Unmarshal([]byte(args), &input); err != nil {
+		return "", fmt.Errorf("invalid arguments: %w", err)
+	}
+
+	switch input.Action {
+	case "list":
+		filter := entity.NetworkFilter{URLPattern: input.URLPattern, Limit: int(input.Limit)}
+		if input.Type != "" {
+			filter.Types = []string{input.Type}
+		}
+		if filter.Limit <= 0 {
+			filter.Limit = defaultNetworkLimit
+		}
+		if filter.Limit > maxNetworkLimit {
+			filter.Limit = maxNetworkLimit
+		}
+		entries, err := t.browser.NetworkRequests(ctx, filter)
+		if err != nil {
+			return "", err
+		}
+		return formatNetworkList(entries), nil
+
+	case "get":
+		if input.ID == "" {
+			return "", fmt.Errorf("id is required for action 'get'")
+		}
+		entry, err := t.browser.NetworkRequest(ctx, input.ID)
+		if err != nil {
+			return "", err
+		}
+		return formatNetworkEntry(entry), nil
+
+	default:
+		return "", fmt.Errorf("invalid action: %q (must be 'list' or 'get')", input.Action)
+	}
+}
+
+func formatNetworkList(entries []entity.NetworkEntry) string {
+	if len(entries) == 0 {
+		return "No matching requests captured"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Captured %d requests (oldest first):\n\n", len(entries))
+	for _, entry := range entries {
+		fmt.Fprintf(&b, "#%s %s %s %s %s", entry.ID, entry.Method, networkStatus(entry), entry.ResourceType, entry.URL)
+		if entry.MIMEType != "" {
+			fmt.Fprintf(&b, " [%s]", entry.MIMEType)
+		}
+		if entry.BodySize > 0 {
+			fmt.Fprintf(&b, " body: %d bytes", entry.BodySize)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\nTIP: Use action=\"get\" with an id to read the response body")
+	return b.String()
+}
+
+func formatNetworkEntry(entry *entity.NetworkEntry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "#%s %s %s\n", entry.ID, entry.Method, entry.URL)
+	fmt.Fprintf(&b, "Type: %s\nStatus: %s\n", entry.ResourceType, networkStatus(*entry))
+	if entry.MIMEType != "" {
+		fmt.Fprintf(&b, "Content-Type: %s\n", entry.MIMEType)
+	}
+	if entry.Finished {
+		fmt.Fprintf(&b, "Duration: %dms\n", entry.DurationMS)
+	}
+	if entry.RequestBody != "" {
+		fmt.Fprintf(&b, "\nRequest body:\n%s\n", prettyBody(entry.RequestBody))
+	}
+
+	switch {
+	case entry.ResponseBody != "":
+		fmt.Fprintf(&b, "\nResponse body:\n%s\n", prettyBody(entry.ResponseBody))
+		if entry.BodyTruncated {
+			fmt.Fprintf(&b, "... (truncated, %d bytes in total)\n", entry.BodySize)
+		}
+	case !entry.Finished:
+		b.WriteString("\nResponse is still loading; wait and get it again\n")
+	case entry.BodyPending:
+		b.WriteString("\nResponse body is still being read; get it again in a moment\n")
+	case entry.Error == "":
+		b.WriteString("\nResponse body was not captured (not text, or its URL is not in browser.network.body_urls)\n")
+	}
+	return b.String()
+}
+
+func networkStatus(entry entity.NetworkEntry) string {
+	switch {
+	case entry.Error != "":
+		return "failed (" + entry.Error + ")"
+	case entry.Status > 0:
+		return fmt.Sprintf("%d", entry.Status)
+	default:
+		return "pending"
+	}
+}
+
+// prettyBody indents JSON bodies, so nested payloads are readable.
+func prettyBody(body string) string {
+	var out bytes.Buffer
+	if err := json.Indent(&out, []byte(body), "", "  "); err != nil {
+		return body
+	}
+	return out.String()
+}
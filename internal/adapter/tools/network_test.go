@@ -0,0 +1,78 @@
+package tool
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"browser-agent/internal/domain/entity"
+	"browser-agent/internal/testkit"
+)
+
+const apiSite = `
+pages:
+  results:
+    url: https://shop.test/?q=kettle
+    requests:
+      - {url: "https://shop.test/?q=kettle", type: document, mime: text/html}
+      - {url: "https://shop.test/api/search?q=kettle", mime: application/json, body: '{"items":[{"name":"Kettle","price":25}]}'}
+      - {url: "https://ads.test/track", type: fetch, error: "net::ERR_BLOCKED_BY_CLIENT"}
+`
+
+func TestNetworkTool(t *testing.T) {
+	ctx := context.Background()
+	browser := testkit.NewBrowser(testkit.MustParseSite(apiSite))
+	require.NoError(t, browser.Navigate(ctx, "https://shop.test/?q=kettle"))
+	network := NewNetworkTool(browser, testkit.NopLogger{})
+
+	result, err := network.Execute(ctx, `{"action":"list"}`)
+	require.NoError(t, err)
+	assert.Contains(t, result, "Captured 3 requests")
+	assert.Contains(t, result, "#2 GET 200 xhr https://shop.test/api/search?q=kettle [application/json] body: 40 bytes")
+	assert.Contains(t, result, "#3 GET failed (net::ERR_BLOCKED_BY_CLIENT) fetch https://ads.test/track")
+	assert.NotContains(t, result, `"Kettle"`)
+
+	result, err = network.Execute(ctx, `{"action":"list","url_pattern":"/api/","type":"xhr"}`)
+	require.NoError(t, err)
+	assert.Contains(t, result, "Captured 1 requests")
+
+	result, err = network.Execute(ctx, `{"action":"list","limit":1}`)
+	require.NoError(t, err)
+	assert.Contains(t, result, "#3 ")
+	assert.NotContains(t, result, "#2 ")
+
+	result, err = network.Execute(ctx, `{"action":"get","id":"2"}`)
+	require.NoError(t, err)
+	assert.Contains(t, result, "Response body:\n{\n  \"items\": [\n    {\n      \"name\": \"Kettle\",")
+
+	result, err = network.Execute(ctx, `{"action":"get","id":"1"}`)
+	require.NoError(t, err)
+	assert.Contains(t, result, "Response body was not captured")
+}
+
+func TestNetworkToolRejectsInvalidRequests(t *testing.T) {
+	network := NewNetworkTool(testkit.NewBrowser(testkit.MustParseSite(apiSite)), testkit.NopLogger{})
+
+	for args, want := range map[string]string{
+		`{"action":"get"}`:                    "id is required",
+		`{"action":"get","id":"7"}`:           `network request "7" not found`,
+		`{"action":"list","url_pattern":"("}`: "invalid url_pattern",
+		`{"action":"clear"}`:                  `invalid action: "clear"`,
+	} {
+		_, err := network.Execute(context.Background(), args)
+		assert.ErrorContains(t, err, want, args)
+	}
+}
+
+func TestFormatNetworkEntryBodyPending(t *testing.T) {
+	entry := &entity.NetworkEntry{
+		ID: "4", Method: "GET", URL: "https://shop.test/api/cart", ResourceType: "fetch",
+		Status: 200, MIMEType: "application/json", Finished: true, BodyPending: true,
+	}
+
+	result := formatNetworkEntry(entry)
+	assert.Contains(t, result, "Response body is still being read")
+	assert.NotContains(t, result, "was not captured")
+}
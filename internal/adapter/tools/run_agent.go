@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/application/runctx"
 	"browser-agent/internal/domain/entity"
 )
 
@@ -91,7 +92,7 @@ func (t *RunAgentTool) Execute(ctx context.Context, arguments string) (string, e
 		return "", fmt.Errorf("agent not found: %s", subAgentType)
 	}
 
-	result, err := agent.Execute(ctx, args.Task)
+	result, err := agent.Execute(runctx.WithAgent(ctx, string(subAgentType)), args.Task)
 	if err != nil {
 		t.logger.Error("Agent execution failed", err, map[string]interface{}{
 			"agent_type": subAgentType,
@@ -0,0 +1,57 @@
+package tool
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"browser-agent/internal/application/runctx"
+	"browser-agent/internal/application/service"
+	"browser-agent/internal/domain/entity"
+	"browser-agent/internal/testkit"
+)
+
+type stubAgent struct {
+	subType entity.SubAgentType
+	err     error
+	agents  []string
+	task    string
+}
+
+func (a *stubAgent) GetType() entity.AgentType            { return entity.AgentType(a.subType) }
+func (a *stubAgent) GetSubAgentType() entity.SubAgentType { return a.subType }
+func (a *stubAgent) GetDescription() string               { return "does " + string(a.subType) }
+func (a *stubAgent) Execute(ctx context.Context, task string) (string, error) {
+	a.agents = runctx.Agents(ctx)
+	a.task = task
+	return "finished " + task, a.err
+}
+
+func TestRunAgentTool(t *testing.T) {
+	agents := service.NewSimpleAgentRegistry()
+	form := &stubAgent{subType: entity.SubAgentForm}
+	broken := &stubAgent{subType: entity.SubAgentExtraction, err: errors.New("boom")}
+	agents.Register(form)
+	agents.Register(broken)
+	runAgent := NewRunAgentTool(agents, testkit.NopLogger{})
+
+	assert.Contains(t, runAgent.Description(), "- form: does form")
+
+	ctx := runctx.WithAgent(context.Background(), "navigation")
+	result, err := runAgent.Execute(ctx, `{"agent_type":"form","task":"log in"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "finished log in", result)
+	assert.Equal(t, []string{"navigation", "form"}, form.agents)
+
+	_, err = runAgent.Execute(ctx, `{"agent_type":"extraction","task":"read"}`)
+	assert.EqualError(t, err, "agent execution failed: boom")
+
+	_, err = runAgent.Execute(ctx, `{"agent_type":"navigation","task":"go"}`)
+	assert.EqualError(t, err, "agent not found: navigation")
+
+	_, err = runAgent.Execute(ctx, `not json`)
+	assert.ErrorContains(t, err, "invalid arguments")
+}
@@ -0,0 +1,189 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/domain/entity"
+)
+
+const formatPDF = "pdf"
+
+type ScreenshotTool struct {
+	browser   output.BrowserPort
+	artifacts output.ArtifactPort
+	defaults  entity.CaptureRequest
+	logger    output.LoggerPort
+}
+
+func NewScreenshotTool(browser output.BrowserPort, artifacts output.ArtifactPort, logger output.LoggerPort) *ScreenshotTool {
+	return &ScreenshotTool{browser: browser, artifacts: artifacts, logger: logger}
+}
+
+// SetDefaults sets the format, quality and max width of screenshots that
+// do not choose their own.
+func (t *ScreenshotTool) SetDefaults(defaults entity.CaptureRequest) {
+	t.defaults = defaults
+}
+
+func (t *ScreenshotTool) Name() entity.ToolName { return entity.ToolBrowserScreenshot }
+func (t *ScreenshotTool) Description() string {
+	return "Save the page to a file in the run's artifact directory and return its path. Captures the visible viewport by default, the whole scrollable page with 'full_page', or a single element with 'selector'. Use format 'pdf' to export the page as a printable document, e.g. when the user asks to save an invoice, receipt or ticket. Name the file after its content with 'name'."
+}
+func (t *ScreenshotTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"full_page": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Capture the whole scrollable page instead of the visible viewport",
+			},
+			"selector": map[string]interface{}{
+				"type":        "string",
+				"description": "CSS or XPath selector of the single element to capture",
+			},
+			"format": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{"png", "jpeg", formatPDF},
+				"description": "Image format, or pdf to print the whole page as a document",
+			},
+			"name": map[string]interface{}{
+				"type":        "string",
+				"description": "File name without extension, e.g. 'invoice-2024-05'",
+			},
+			"quality": map[string]interface{}{
+				"type":        "number",
+				"description": "JPEG quality 1-100 (default: 90)",
+			},
+			"max_width": map[string]interface{}{
+				"type":        "number",
+				"description": "Scale wider images down to this width in pixels",
+			},
+			"landscape": map[string]interface{}{
+				"type":        "boolean",
+				"description": "PDF only: landscape orientation",
+			},
+			"background": map[string]interface{}{
+				"type":        "boolean",
+				"description": "PDF only: print background colors and images",
+			},
+			"paper": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{"a4", "letter", "legal", "a3"},
+				"description": "PDF only: paper size (default: a4)",
+			},
+			"scale": map[string]interface{}{
+				"type":        "number",
+				"description": "PDF only: scale of the page 0.1-2 (default: 1)",
+			},
+			"page_ranges": map[string]interface{}{
+				"type":        "string",
+				"description": "PDF only: pages to print, e.g. '1-3, 5' (default: all)",
+			},
+		},
+	}
+}
+
+func (t *ScreenshotTool) Execute(ctx context.Context, args string) (string, error) {
+	var input struct {
+		FullPage   bool    `json:"full_page"`
+		Selector   string  `json:"selector"`
+		Format     string  `json:"format"`
+		Name       string  `json:"name"`
+		Quality    float64 `json:"quality"`
+		MaxWidth   float64 `json:"max_width"`
+		Landscape  bool    `json:"landscape"`
+		Background bool    `json:"background"`
+		Paper      string  `json:"paper"`
+		Scale      float64 `json:"scale"`
+		PageRanges string  `json:"page_ranges"`
+	}
+	if err := json.Unmarshal([]byte(args), &input); err != nil {
+		return "", fmt.Errorf("invalid arguments: %w", err)
+	}
+
+	if input.Format == formatPDF {
+		if input.Selector != "" {
+			return "", fmt.Errorf("selector cannot be used with pdf, which always prints the whole page")
+		}
+		data, err := t.browser.PrintPDF(ctx, entity.PDFRequest{
+			Landscape:  input.Landscape,
+			Background: input.Background,
+			Paper:      input.Paper,
+			Scale:      input.Scale,
+			PageRanges: input.PageRanges,
+		})
+		if err != nil {
+			return "", err
+		}
+		path, err := t.save(input.Name, "page", formatPDF, data)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Saved PDF of %s (%s) to %s", t.browser.CurrentURL(), formatSize(len(data)), path), nil
+	}
+
+	req := entity.CaptureRequest{
+		FullPage: input.FullPage,
+		Selector: strings.TrimSpace(input.Selector),
+		Format:   entity.ImageFormat(input.Format),
+		Quality:  int(input.Quality),
+		MaxWidth: int(input.MaxWidth),
+	}
+	if req.Format == "" {
+		req.Format = t.defaults.Format
+	}
+	if req.Quality == 0 {
+		req.Quality = t.defaults.Quality
+	}
+	if req.MaxWidth == 0 {
+		req.MaxWidth = t.defaults.MaxWidth
+	}
+	req = req.WithDefaults()
+
+	screenshot, err := t.browser.Capture(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	path, err := t.save(input.Name, "screenshot", screenshot.Format, screenshot.Data)
+	if err != nil {
+		return "", err
+	}
+
+	what := "viewport"
+	if req.Selector != "" {
+		what = "element " + req.Selector
+	} else if req.FullPage {
+		what = "full page"
+	}
+	return fmt.Sprintf("Saved screenshot of %s (%dx%d %s, %s) to %s",
+		what, screenshot.Width, screenshot.Height, screenshot.Format, formatSize(len(screenshot.Data)), path), nil
+}
+
+func (t *ScreenshotTool) save(name, fallback, ext string, data []byte) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = fallback
+	}
+	path, err := t.artifacts.Save(name+"."+ext, data)
+	if err != nil {
+		return "", fmt.Errorf("failed to save %s: %w", ext, err)
+	}
+	t.logger.Info("Artifact saved", "path", path, "bytes", len(data))
+	return path, nil
+}
+
+// formatSize prints a byte count the way file managers do, e.g. "48 KB".
+func formatSize(n int) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%d KB", n>>10)
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
@@ -0,0 +1,63 @@
+package tool
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"browser-agent/internal/domain/entity"
+	"browser-agent/internal/testkit"
+)
+
+// savedArtifacts keeps artifacts in memory under "artifacts/<name>".
+type savedArtifacts map[string][]byte
+
+func (a savedArtifacts) Save(name string, data []byte) (string, error) {
+	path := "artifacts/" + name
+	if _, ok := a[path]; ok {
+		return "", os.ErrExist
+	}
+	a[path] = data
+	return path, nil
+}
+
+func TestScreenshotTool(t *testing.T) {
+	ctx := context.Background()
+	browser := testkit.NewBrowser(testkit.MustParseSite(dialogSite))
+	artifacts := savedArtifacts{}
+	screenshot := NewScreenshotTool(browser, artifacts, testkit.NopLogger{})
+	screenshot.SetDefaults(entity.CaptureRequest{Format: entity.ImageFormatJPEG})
+
+	result, err := screenshot.Execute(ctx, `{}`)
+	require.NoError(t, err)
+	assert.Equal(t, "Saved screenshot of viewport (1x1 jpeg, 15 B) to artifacts/screenshot.jpeg", result)
+	assert.Equal(t, "capture of cart", string(artifacts["artifacts/screenshot.jpeg"]))
+
+	result, err = screenshot.Execute(ctx, `{"full_page":true,"format":"png","name":"cart"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "Saved screenshot of full page (1x1 png, 27 B) to artifacts/cart.png", result)
+
+	result, err = screenshot.Execute(ctx, `{"selector":"#coupon","name":"coupon"}`)
+	require.NoError(t, err)
+	assert.Contains(t, result, "Saved screenshot of element #coupon")
+	assert.Equal(t, "capture of cart #coupon", string(artifacts["artifacts/coupon.jpeg"]))
+
+	result, err = screenshot.Execute(ctx, `{"format":"pdf","name":"invoice","paper":"letter"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "Saved PDF of https://shop.test/cart (13 B) to artifacts/invoice.pdf", result)
+	assert.Equal(t, "%PDF-1.4 cart", string(artifacts["artifacts/invoice.pdf"]))
+
+	_, err = screenshot.Execute(ctx, `{"format":"pdf","name":"invoice"}`)
+	assert.ErrorIs(t, err, os.ErrExist)
+	_, err = screenshot.Execute(ctx, `{"full_page":true,"selector":"#coupon"}`)
+	assert.ErrorContains(t, err, "full_page and selector cannot be combined")
+	_, err = screenshot.Execute(ctx, `{"format":"gif"}`)
+	assert.ErrorContains(t, err, `invalid image format "gif"`)
+	_, err = screenshot.Execute(ctx, `{"format":"pdf","paper":"a5"}`)
+	assert.ErrorContains(t, err, `unknown paper "a5"`)
+	_, err = screenshot.Execute(ctx, `{"selector":"#missing"}`)
+	assert.ErrorContains(t, err, "element not found")
+}
@@ -2,9 +2,9 @@ package tool
 
 import (
 	"context"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"browser-agent/internal/application/port/output"
 	"browser-agent/internal/domain/entity"
@@ -43,10 +43,11 @@ func (t *NavigateTool) Execute(ctx context.Context, args string) (string, error)
 	if err := json.Unmarshal([]byte(args), &input); err != nil {
 		return "", err
 	}
+	mark, dialogs := consoleMark(ctx, t.browser), dialogMark(ctx, t.browser)
 	if err := t.browser.Navigate(ctx, input.URL); err != nil {
 		return "", err
 	}
-	return fmt.Sprintf("Navigated to %s", t.browser.CurrentURL()), nil
+	return fmt.Sprintf("Navigated to %s", t.browser.CurrentURL()) + dialogsSince(ctx, t.browser, dialogs) + consoleErrorsSince(ctx, t.browser, mark), nil
 }
 
 type ClickTool struct {
@@ -103,6 +104,7 @@ func (t *ClickTool) Execute(ctx context.Context, args string) (string, error) {
 		return "", fmt.Errorf("observe mode only works with single element, not batch")
 	}
 
+	dialogs := dialogMark(ctx, t.browser)
 	if input.Observe {
 		result, err := t.browser.ClickWithChanges(ctx, input.Selectors[0])
 		if err != nil {
@@ -144,35 +146,52 @@ func (t *ClickTool) Execute(ctx context.Context, args string) (string, error) {
 			if changes.ElementsRemoved > 0 {
 				output += fmt.Sprintf("\n✓ %d elements removed", changes.ElementsRemoved)
 			}
+			output += formatConsoleErrors(changes.ConsoleErrors)
 		}
-		return output, nil
+		return output + dialogsSince(ctx, t.browser, dialogs), nil
 	}
 
 	if len(input.Selectors) == 1 {
+		mark := consoleMark(ctx, t.browser)
 		if err := t.browser.Click(ctx, input.Selectors[0]); err != nil {
 			return "", err
 		}
-		return "Click successful", nil
+		return "Click successful" + dialogsSince(ctx, t.browser, dialogs) + consoleErrorsSince(ctx, t.browser, mark), nil
 	}
 
 	if err := t.browser.BatchClick(ctx, input.Selectors); err != nil {
 		return "", err
 	}
-	return fmt.Sprintf("Successfully clicked %d elements", len(input.Selectors)), nil
+	return fmt.Sprintf("Successfully clicked %d elements", len(input.Selectors)) + dialogsSince(ctx, t.browser, dialogs), nil
 }
 
 type FillTool struct {
 	browser output.BrowserPort
+	secrets output.SecretsPort
 	logger  output.LoggerPort
 }
 
-func NewFillTool(browser output.BrowserPort, logger output.LoggerPort) *FillTool {
-	return &FillTool{browser: browser, logger: logger}
+func NewFillTool(browser output.BrowserPort, secrets output.SecretsPort, logger output.LoggerPort) *FillTool {
+	return &FillTool{browser: browser, secrets: secrets, logger: logger}
 }
 
 func (t *FillTool) Name() entity.ToolName { return entity.ToolBrowserFill }
 func (t *FillTool) Description() string {
-	return "Fill text into form fields. Supports single field or batch filling multiple fields. Use 'selector' and 'text' for single field, or 'fields' object for batch operations (up to 20 fields). Clears existing content before filling. All batch fills are executed without returning to LLM between fields. For submitting forms, use press_enter after filling or click on submit button."
+	description := "Fill text into form fields. Supports single field or batch filling multiple fields. Use 'selector' and 'text' for single field, or 'fields' object for batch operations (up to 20 fields). Clears existing content before filling. All batch fills are executed without returning to LLM between fields. For submitting forms, use press_enter after filling or click on submit button."
+
+	if t.secrets == nil {
+		return description
+	}
+	names := t.secrets.Names()
+	if len(names) == 0 {
+		return description
+	}
+
+	placeholders := make([]string, 0, len(names))
+	for _, name := range names {
+		placeholders = append(placeholders, "{{secret:"+name+"}}")
+	}
+	return description + " Text may contain secret placeholders that are substituted securely right before typing, you never see the real values. Pass them verbatim. Available: " + strings.Join(placeholders, ", ")
 }
 func (t *FillTool) Parameters() map[string]interface{} {
 	return map[string]interface{}{
@@ -213,7 +232,15 @@ func (t *FillTool) Execute(ctx context.Context, args string) (string, error) {
 		if len(input.Fields) > 20 {
 			return "", fmt.Errorf("too many fields (max 20, got %d)", len(input.Fields))
 		}
-		if err := t.browser.BatchFill(ctx, input.Fields); err != nil {
+		fields := make(map[string]string, len(input.Fields))
+		for selector, text := range input.Fields {
+			resolved, err := t.resolveSecrets(text)
+			if err != nil {
+				return "", fmt.Errorf("field %q: %w", selector, err)
+			}
+			fields[selector] = resolved
+		}
+		if err := t.browser.BatchFill(ctx, fields); err != nil {
 			return "", err
 		}
 		return fmt.Sprintf("Successfully filled %d fields", len(input.Fields)), nil
@@ -223,12 +250,24 @@ func (t *FillTool) Execute(ctx context.Context, args string) (string, error) {
 		return "", fmt.Errorf("either ('selector' and 'text') or 'fields' is required")
 	}
 
-	if err := t.browser.Fill(ctx, input.Selector, input.Text); err != nil {
+	text, err := t.resolveSecrets(input.Text)
+	if err != nil {
+		return "", err
+	}
+
+	if err := t.browser.Fill(ctx, input.Selector, text); err != nil {
 		return "", err
 	}
 	return fmt.Sprintf("Filled '%s' with text", input.Selector), nil
 }
 
+func (t *FillTool) resolveSecrets(text string) (string, error) {
+	if t.secrets == nil {
+		return text, nil
+	}
+	return t.secrets.Resolve(text)
+}
+
 type ScrollTool struct {
 	browser output.BrowserPort
 	logger  output.LoggerPort
@@ -269,36 +308,6 @@ func (t *ScrollTool) Execute(ctx context.Context, args string) (string, error) {
 	return fmt.Sprintf("Scrolled %s", input.Direction), nil
 }
 
-type ScreenshotTool struct {
-	browser output.BrowserPort
-	logger  output.LoggerPort
-}
-
-func NewScreenshotTool(browser output.BrowserPort, logger output.LoggerPort) *ScreenshotTool {
-	return &ScreenshotTool{browser: browser, logger: logger}
-}
-
-func (t *ScreenshotTool) Name() entity.ToolName { return entity.ToolBrowserScreenshot }
-func (t *ScreenshotTool) Description() string {
-	return "Capture a screenshot of the current visible viewport. Returns base64-encoded image data URL. Use this when you need visual confirmation of page state, to verify UI appearance, or to show results to user. The screenshot only captures the visible portion - use scroll to capture different sections. Useful after navigation or interactions to confirm success."
-}
-func (t *ScreenshotTool) Parameters() map[string]interface{} {
-	return map[string]interface{}{
-		"type":       "object",
-		"properties": map[string]interface{}{},
-		"required":   []string{},
-	}
-}
-
-func (t *ScreenshotTool) Execute(ctx context.Context, args string) (string, error) {
-	screenshot, err := t.browser.Screenshot(ctx)
-	if err != nil {
-		return "", err
-	}
-	b64 := base64.StdEncoding.EncodeToString(screenshot.Data)
-	return fmt.Sprintf("data:image/%s;base64,%s", screenshot.Format, b64), nil
-}
-
 type PressEnterTool struct {
 	browser output.BrowserPort
 	logger  output.LoggerPort
@@ -321,10 +330,11 @@ func (t *PressEnterTool) Parameters() map[string]interface{} {
 }
 
 func (t *PressEnterTool) Execute(ctx context.Context, args string) (string, error) {
+	dialogs := dialogMark(ctx, t.browser)
 	if err := t.browser.PressEnter(ctx); err != nil {
 		return "", err
 	}
-	return "Enter pressed", nil
+	return "Enter pressed" + dialogsSince(ctx, t.browser, dialogs), nil
 }
 
 type AskQuestionTool struct {
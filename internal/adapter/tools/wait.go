@@ -0,0 +1,115 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/domain/entity"
+)
+
+const (
+	defaultWaitTimeoutMS = 10000
+	maxWaitTimeoutMS     = 60000
+)
+
+type WaitTool struct {
+	browser output.BrowserPort
+	logger  output.LoggerPort
+}
+
+func NewWaitTool(browser output.BrowserPort, logger output.LoggerPort) *WaitTool {
+	return &WaitTool{browser: browser, logger: logger}
+}
+
+func (t *WaitTool) Name() entity.ToolName { return entity.ToolBrowserWait }
+func (t *WaitTool) Description() string {
+	return "Wait until the page reaches a state instead of guessing with repeated observe calls. Use it when content loads after the page itself (SPAs, search results, infinite lists, modals). Conditions: 'visible' - element matching selector is shown; 'hidden' - element disappeared (spinner, modal); 'text' - page text contains text; 'url' - URL matches the regular expression url_pattern; 'network_idle' - no requests for idle_ms; 'script' - JavaScript expression is truthy. Fails when the timeout expires."
+}
+func (t *WaitTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"condition": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{"visible", "hidden", "text", "url", "network_idle", "script"},
+				"description": "What to wait for",
+			},
+			"selector": map[string]interface{}{
+				"type":        "string",
+				"description": "CSS or XPath selector for 'visible' and 'hidden'. Example: '.results li', '.spinner'",
+			},
+			"text": map[string]interface{}{
+				"type":        "string",
+				"description": "Text to wait for with 'text'",
+			},
+			"url_pattern": map[string]interface{}{
+				"type":        "string",
+				"description": "Regular expression for 'url'. Example: '/checkout/[0-9]+', 'q=kettle'",
+			},
+			"idle_ms": map[string]interface{}{
+				"type":        "number",
+				"description": "Quiet period for 'network_idle' in milliseconds (default: 500)",
+			},
+			"script": map[string]interface{}{
+				"type":        "string",
+				"description": "JavaScript expression for 'script'. Example: 'document.querySelectorAll(\".item\").length >= 20'",
+			},
+			"timeout_ms": map[string]interface{}{
+				"type":        "number",
+				"description": "Maximum wait in milliseconds (default: 10000, max: 60000)",
+			},
+		},
+		"required": []string{"condition"},
+	}
+}
+
+func (t *WaitTool) Execute(ctx context.Context, args string) (string, error) {
+	var input struct {
+		Condition  string  `json:"condition"`
+		Selector   string  `json:"selector"`
+		Text       string  `json:"text"`
+		URLPattern string  `json:"url_pattern"`
+		IdleMS     float64 `json:"idle_ms"`
+		Script     string  `json:"script"`
+		TimeoutMS  float64 `json:"timeout_ms"`
+	}
+	if err := json.Unmarshal([]byte(args), &input); err != nil {
+		return "", fmt.Errorf("invalid arguments: %w", err)
+	}
+
+	req := entity.WaitRequest{
+		Condition:  entity.WaitCondition(input.Condition),
+		Selector:   input.Selector,
+		Text:       input.Text,
+		URLPattern: input.URLPattern,
+		IdleMS:     int(input.IdleMS),
+		Script:     input.Script,
+		TimeoutMS:  int(input.TimeoutMS),
+	}
+	if req.TimeoutMS <= 0 {
+		req.TimeoutMS = defaultWaitTimeoutMS
+	}
+	if req.TimeoutMS > maxWaitTimeoutMS {
+		req.TimeoutMS = maxWaitTimeoutMS
+	}
+	if err := req.Validate(); err != nil {
+		return "", err
+	}
+
+	result, err := t.browser.WaitFor(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	if !result.Met {
+		msg := fmt.Sprintf("timed out after %dms waiting for %s (URL: %s)", result.ElapsedMS, req.Describe(), result.URL)
+		if result.ScriptError != "" {
+			msg += "; last script error: " + result.ScriptError
+		}
+		return "", errors.New(msg)
+	}
+	return fmt.Sprintf("Condition met after %dms: %s (URL: %s)", result.ElapsedMS, req.Describe(), result.URL), nil
+}
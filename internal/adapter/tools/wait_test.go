@@ -0,0 +1,77 @@
+package tool
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/domain/entity"
+	"browser-agent/internal/testkit"
+)
+
+const resultsSite = `
+pages:
+  results:
+    url: https://shop.test/?q=kettle
+    text: 2 results
+    elements:
+      - {selector: ".item", tag: li, text: Kettle}
+      - {selector: ".spinner", tag: div, hidden: true}
+`
+
+// recordingBrowser captures the request passed to WaitFor.
+type recordingBrowser struct {
+	output.BrowserPort
+	req entity.WaitRequest
+}
+
+func (b *recordingBrowser) WaitFor(ctx context.Context, req entity.WaitRequest) (*entity.WaitResult, error) {
+	b.req = req
+	return b.BrowserPort.WaitFor(ctx, req)
+}
+
+func TestWaitTool(t *testing.T) {
+	ctx := context.Background()
+	fake := testkit.NewBrowser(testkit.MustParseSite(resultsSite))
+	require.NoError(t, fake.Navigate(ctx, "https://shop.test/?q=kettle"))
+	browser := &recordingBrowser{BrowserPort: fake}
+	wait := NewWaitTool(browser, testkit.NopLogger{})
+
+	for _, args := range []string{
+		`{"condition":"visible","selector":".item"}`,
+		`{"condition":"hidden","selector":".spinner"}`,
+		`{"condition":"text","text":"2 results"}`,
+		`{"condition":"url","url_pattern":"q=[a-z]+$"}`,
+		`{"condition":"network_idle","idle_ms":200}`,
+	} {
+		result, err := wait.Execute(ctx, args)
+		require.NoError(t, err, args)
+		assert.Contains(t, result, "Condition met", args)
+		assert.Contains(t, result, "URL: https://shop.test/?q=kettle", args)
+	}
+	assert.Equal(t, defaultWaitTimeoutMS, browser.req.TimeoutMS)
+	assert.Equal(t, 200, browser.req.IdleMS)
+
+	_, err := wait.Execute(ctx, `{"condition":"visible","selector":".spinner","timeout_ms":120000}`)
+	assert.EqualError(t, err, `timed out after 60000ms waiting for element ".spinner" visible (URL: https://shop.test/?q=kettle)`)
+	assert.Equal(t, maxWaitTimeoutMS, browser.req.TimeoutMS)
+}
+
+func TestWaitToolRejectsInvalidRequests(t *testing.T) {
+	wait := NewWaitTool(testkit.NewBrowser(testkit.MustParseSite(resultsSite)), testkit.NopLogger{})
+
+	for args, want := range map[string]string{
+		`{"condition":"visible"}`:                   `selector is required for condition "visible"`,
+		`{"condition":"text"}`:                      `text is required for condition "text"`,
+		`{"condition":"url","url_pattern":"("}`:     "invalid url_pattern",
+		`{"condition":"script"}`:                    `script is required for condition "script"`,
+		`{"condition":"sleep"}`:                     `unknown wait condition "sleep"`,
+		`{"condition":"network_idle","idle_ms":-1}`: "idle_ms must not be negative",
+	} {
+		_, err := wait.Execute(context.Background(), args)
+		assert.ErrorContains(t, err, want, args)
+	}
+}
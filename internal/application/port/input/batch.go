@@ -0,0 +1,13 @@
+package input
+
+import (
+	"context"
+
+	"browser-agent/internal/domain/entity"
+)
+
+// BatchRunner executes a list of tasks without a user and returns their
+// results in input order.
+type BatchRunner interface {
+	Run(ctx context.Context, tasks []entity.BatchTask) ([]entity.BatchResult, error)
+}
@@ -2,11 +2,24 @@ package input
 
 import "context"
 
+// TaskRequest is a task together with per-run limits. Zero values fall back
+// to the executor's defaults.
+type TaskRequest struct {
+	Task          string
+	MaxIterations int
+	// OutputSchema, when set, is a JSON schema the final answer must match.
+	OutputSchema map[string]any
+	// StartURL, when set, is opened before the agent starts by executors
+	// that pick the task's browser themselves; others mention it in the
+	// task.
+	StartURL string
+}
+
 type ExecuteResult struct {
 	FinalAnswer string
 	Iterations  int
 }
 
 type TaskExecutor interface {
-	Execute(ctx context.Context, task string) (*ExecuteResult, error)
+	Execute(ctx context.Context, req TaskRequest) (*ExecuteResult, error)
 }
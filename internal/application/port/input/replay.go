@@ -0,0 +1,13 @@
+package input
+
+import (
+	"context"
+
+	"browser-agent/internal/domain/entity"
+)
+
+// Replayer re-executes the browser tool calls of a recorded run without the
+// LLM and reports where the page behaves differently.
+type Replayer interface {
+	Replay(ctx context.Context, transcript *entity.Transcript) (*entity.ReplayReport, error)
+}
@@ -0,0 +1,36 @@
+package input
+
+import (
+	"errors"
+
+	"browser-agent/internal/domain/entity"
+)
+
+var (
+	ErrTaskNotFound        = errors.New("task not found")
+	ErrTaskFinished        = errors.New("task already finished")
+	ErrQueueFull           = errors.New("task queue is full")
+	ErrInteractionNotFound = errors.New("interaction not found")
+)
+
+// TaskManager runs submitted tasks in the background.
+type TaskManager interface {
+	Submit(description string) (entity.Task, error)
+	Get(id string) (entity.Task, error)
+	List() []entity.Task
+	Cancel(id string) error
+}
+
+// InteractionResponder exposes questions and approvals agents are waiting on.
+type InteractionResponder interface {
+	Pending(taskID string) []entity.PendingInteraction
+	Respond(taskID, interactionID, answer string) error
+}
+
+// EventSource streams task events. Subscribe returns the events already
+// recorded for the task followed by a channel of new ones; the returned
+// function must be called to unsubscribe. The channel is closed when the
+// subscriber falls too far behind, and the caller should subscribe again.
+type EventSource interface {
+	Subscribe(taskID string) ([]entity.Event, <-chan entity.Event, func())
+}
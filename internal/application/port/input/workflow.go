@@ -0,0 +1,13 @@
+package input
+
+import (
+	"context"
+
+	"browser-agent/internal/domain/entity"
+)
+
+// WorkflowRunner executes a compiled workflow through the browser, with
+// values for its variables.
+type WorkflowRunner interface {
+	Run(ctx context.Context, workflow *entity.Workflow, vars map[string]string) (*entity.WorkflowRunResult, error)
+}
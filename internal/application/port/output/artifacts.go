@@ -0,0 +1,9 @@
+package output
+
+// ArtifactPort keeps files the agent produces for the user, such as
+// screenshots and PDFs.
+type ArtifactPort interface {
+	// Save writes data under a file name derived from name and returns the
+	// path it was written to. An existing file is never overwritten.
+	Save(name string, data []byte) (string, error)
+}
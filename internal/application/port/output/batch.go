@@ -0,0 +1,14 @@
+package output
+
+import "browser-agent/internal/domain/entity"
+
+// BatchResultWriter receives batch results as soon as each task finishes.
+type BatchResultWriter interface {
+	Write(result entity.BatchResult) error
+}
+
+// InteractionScripts installs scripted answers for a task before it runs.
+type InteractionScripts interface {
+	Load(taskID string, script entity.InteractionScript) error
+	Forget(taskID string)
+}
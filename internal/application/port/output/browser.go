@@ -22,8 +22,41 @@ type BrowserPort interface {
 	GetPageContext(ctx context.Context) (*entity.PageContext, error)
 	GetPageStructure(ctx context.Context) (*entity.PageStructure, error)
 	Screenshot(ctx context.Context) (*entity.Screenshot, error)
+	// Capture takes a screenshot as described by req, at full resolution
+	// unless req.MaxWidth is set. PrintPDF prints the page as Chrome does.
+	Capture(ctx context.Context, req entity.CaptureRequest) (*entity.Screenshot, error)
+	PrintPDF(ctx context.Context, req entity.PDFRequest) ([]byte, error)
 	QueryElements(ctx context.Context, req entity.QueryElementsRequest) (*entity.QueryElementsResult, error)
 	Search(ctx context.Context, req entity.SearchRequest) (*entity.SearchResult, error)
+	// DescribeElement returns details of the element matched by selector,
+	// or of the focused element when selector is empty.
+	DescribeElement(ctx context.Context, selector string) (*entity.ElementInfo, error)
+	// WaitFor blocks until the condition holds or its timeout expires. An
+	// expired timeout is a result with Met false, not an error.
+	WaitFor(ctx context.Context, req entity.WaitRequest) (*entity.WaitResult, error)
+	// NetworkRequests lists the captured requests matching filter, oldest
+	// first and without bodies. NetworkRequest returns one of them with its
+	// bodies.
+	NetworkRequests(ctx context.Context, filter entity.NetworkFilter) ([]entity.NetworkEntry, error)
+	NetworkRequest(ctx context.Context, id string) (*entity.NetworkEntry, error)
+	// AddInterceptRule applies rule to the following requests of the page
+	// and returns it with its ID. Rules apply in the order they were added.
+	AddInterceptRule(ctx context.Context, rule entity.InterceptRule) (*entity.InterceptRule, error)
+	RemoveInterceptRule(ctx context.Context, id string) error
+	InterceptRules(ctx context.Context) ([]entity.InterceptRule, error)
+	// ConsoleMessages lists console messages, uncaught exceptions and
+	// browser errors of the page matching filter, oldest first.
+	ConsoleMessages(ctx context.Context, filter entity.ConsoleFilter) ([]entity.ConsoleMessage, error)
+	// Dialogs lists the recent JavaScript dialogs of the page, oldest
+	// first; an open one comes last. While a dialog is open the other page
+	// calls fail with *entity.DialogOpenError until HandleDialog closes it.
+	Dialogs(ctx context.Context) ([]entity.Dialog, error)
+	HandleDialog(ctx context.Context, resp entity.DialogResponse) (*entity.Dialog, error)
+	// Emulate applies the non-zero fields of e over the current emulation
+	// and returns the settings now in effect. ResetEmulation returns to the
+	// configured ones.
+	Emulate(ctx context.Context, e entity.Emulation) (*entity.Emulation, error)
+	ResetEmulation(ctx context.Context) (*entity.Emulation, error)
 
 	CurrentURL() string
 	Close()
@@ -0,0 +1,9 @@
+package output
+
+import "browser-agent/internal/domain/entity"
+
+type EventPublisher interface {
+	// Publish delivers event to subscribers. ID and Time are assigned by the
+	// publisher when empty.
+	Publish(event entity.Event)
+}
@@ -18,4 +18,6 @@ type ChatRequest struct {
 
 type ChatResponse struct {
 	Message entity.Message
+	// Usage is zero when the provider does not report it.
+	Usage entity.Usage
 }
@@ -0,0 +1,15 @@
+package output
+
+type Redactor interface {
+	Redact(text string) string
+}
+
+type SecretsPort interface {
+	Redactor
+
+	// Names returns the names of available secrets, never their values.
+	Names() []string
+	// Resolve replaces {{secret:name}} placeholders with secret values.
+	// It must only be called right before a value is handed to the browser.
+	Resolve(text string) (string, error)
+}
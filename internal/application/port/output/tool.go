@@ -19,3 +19,9 @@ type ToolRegistry interface {
 	All() []ToolPort
 	Definitions() []entity.ToolDefinition
 }
+
+// ToolGuard is consulted before a tool is executed. A non-nil error blocks
+// the call and is reported back to the agent as the tool result.
+type ToolGuard interface {
+	Check(ctx context.Context, name entity.ToolName, arguments string) error
+}
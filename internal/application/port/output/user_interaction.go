@@ -1,10 +1,23 @@
 package output
 
-import "context"
+import (
+	"context"
+	"errors"
+
+	"browser-agent/internal/domain/entity"
+)
+
+var ErrTaskAborted = errors.New("task aborted by user")
 
 type UserInteractionPort interface {
 	AskQuestion(ctx context.Context, question string) (string, error)
 	WaitForUserAction(ctx context.Context, message string) error
+	RequestApproval(ctx context.Context, req entity.ApprovalRequest) (bool, error)
+
+	// Checkpoint is called by agent loops after each tool call. It blocks while
+	// execution is paused and returns optional guidance from the user, or
+	// ErrTaskAborted if the user chose to stop the task.
+	Checkpoint(ctx context.Context) (string, error)
 
 	ShowIteration(ctx context.Context, iteration, maxIterations int)
 	ShowToolStart(ctx context.Context, toolName, arguments string)
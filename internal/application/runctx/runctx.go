@@ -0,0 +1,36 @@
+// Package runctx carries per-run identifiers through context so adapters
+// shared between tasks can tell which task and agent a call belongs to.
+package runctx
+
+import "context"
+
+type (
+	taskIDKey struct{}
+	agentsKey struct{}
+)
+
+func WithTaskID(ctx context.Context, taskID string) context.Context {
+	return context.WithValue(ctx, taskIDKey{}, taskID)
+}
+
+// TaskID returns the ID of the task ctx belongs to, or "" outside a task.
+func TaskID(ctx context.Context) string {
+	id, _ := ctx.Value(taskIDKey{}).(string)
+	return id
+}
+
+// WithAgent records that the calls made with the returned context come from
+// a sub-agent nested inside the agents already on ctx.
+func WithAgent(ctx context.Context, agent string) context.Context {
+	parent := Agents(ctx)
+	agents := make([]string, len(parent), len(parent)+1)
+	copy(agents, parent)
+	return context.WithValue(ctx, agentsKey{}, append(agents, agent))
+}
+
+// Agents returns the chain of sub-agents ctx belongs to, outermost first. It
+// is empty for calls made by the orchestrator itself.
+func Agents(ctx context.Context) []string {
+	agents, _ := ctx.Value(agentsKey{}).([]string)
+	return agents
+}
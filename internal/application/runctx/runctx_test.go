@@ -0,0 +1,22 @@
+package runctx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithAgent(t *testing.T) {
+	ctx := WithTaskID(context.Background(), "task-1")
+	assert.Empty(t, Agents(ctx))
+
+	outer := WithAgent(ctx, "navigation")
+	first := WithAgent(outer, "form")
+	second := WithAgent(outer, "extraction")
+
+	assert.Equal(t, []string{"navigation"}, Agents(outer))
+	assert.Equal(t, []string{"navigation", "form"}, Agents(first))
+	assert.Equal(t, []string{"navigation", "extraction"}, Agents(second))
+	assert.Equal(t, "task-1", TaskID(second))
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/domain/entity"
+)
+
+var _ output.ToolRegistry = (*GuardedToolRegistry)(nil)
+
+// GuardedToolRegistry wraps another registry so that every tool it hands out
+// consults the guards before executing.
+type GuardedToolRegistry struct {
+	inner  output.ToolRegistry
+	guards []output.ToolGuard
+}
+
+func NewGuardedToolRegistry(inner output.ToolRegistry, guards ...output.ToolGuard) *GuardedToolRegistry {
+	return &GuardedToolRegistry{
+		inner:  inner,
+		guards: guards,
+	}
+}
+
+func (r *GuardedToolRegistry) Register(tool output.ToolPort) {
+	r.inner.Register(tool)
+}
+
+func (r *GuardedToolRegistry) Get(name entity.ToolName) (output.ToolPort, bool) {
+	tool, ok := r.inner.Get(name)
+	if !ok {
+		return nil, false
+	}
+	return r.wrap(tool), true
+}
+
+func (r *GuardedToolRegistry) All() []output.ToolPort {
+	tools := r.inner.All()
+	result := make([]output.ToolPort, 0, len(tools))
+	for _, tool := range tools {
+		result = append(result, r.wrap(tool))
+	}
+	return result
+}
+
+func (r *GuardedToolRegistry) Definitions() []entity.ToolDefinition {
+	return r.inner.Definitions()
+}
+
+func (r *GuardedToolRegistry) wrap(tool output.ToolPort) output.ToolPort {
+	if len(r.guards) == 0 {
+		return tool
+	}
+	return &guardedTool{ToolPort: tool, guards: r.guards}
+}
+
+type guardedTool struct {
+	output.ToolPort
+	guards []output.ToolGuard
+}
+
+func (t *guardedTool) Execute(ctx context.Context, arguments string) (string, error) {
+	for _, guard := range t.guards {
+		if err := guard.Check(ctx, t.Name(), arguments); err != nil {
+			return "", err
+		}
+	}
+	return t.ToolPort.Execute(ctx, arguments)
+}
@@ -0,0 +1,26 @@
+package di
+
+import (
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/application/service"
+	"browser-agent/internal/domain/entity"
+	"browser-agent/internal/infrastructure/prompts"
+)
+
+// ToolCatalog builds the tool registries of a container without a browser or
+// LLM, so tool names and schemas can be inspected offline. The tools are not
+// wired to anything and must not be executed.
+func ToolCatalog() (orchestratorTools, subAgentTools output.ToolRegistry) {
+	subAgents := service.NewToolRegistry()
+	registerBrowserTools(subAgents, nil, nil, nil, entity.CaptureRequest{}, nil)
+	registerUserInteractionTools(subAgents, nil, nil)
+
+	agents := service.NewSimpleAgentRegistry()
+	registerSimpleAgents(agents, nil, subAgents, nil, nil, prompts.Default(), 0)
+
+	orchestrator := service.NewToolRegistry()
+	registerUserInteractionTools(orchestrator, nil, nil)
+	registerRunAgentTool(orchestrator, agents, nil)
+
+	return orchestrator, subAgents
+}
@@ -3,23 +3,32 @@ package di
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	tool "browser-agent/internal/adapter/tools"
 	"browser-agent/internal/application/port/input"
 	"browser-agent/internal/application/port/output"
 	"browser-agent/internal/application/service"
+	"browser-agent/internal/domain/entity"
+	"browser-agent/internal/domain/policy"
+	"browser-agent/internal/infrastructure/artifacts"
+	"browser-agent/internal/infrastructure/browser/pool"
 	"browser-agent/internal/infrastructure/browser/rod"
 	"browser-agent/internal/infrastructure/llm/openrouter"
 	"browser-agent/internal/infrastructure/logger"
 	"browser-agent/internal/infrastructure/prompts"
+	"browser-agent/internal/infrastructure/transcript"
 	"browser-agent/internal/infrastructure/userinteraction"
 	"browser-agent/internal/usecase/agents/extraction"
 	"browser-agent/internal/usecase/agents/form"
 	"browser-agent/internal/usecase/agents/navigation"
+	"browser-agent/internal/usecase/approval"
 	"browser-agent/internal/usecase/orchestrator"
 )
 
 type Container struct {
+	// Browser, Tools and SimpleAgents are nil with a browser pool, where
+	// every task gets its own.
 	Browser         output.BrowserPort
 	LLM             output.LLMPort
 	Logger          output.LoggerPort
@@ -27,16 +36,75 @@ type Container struct {
 	Tools           output.ToolRegistry
 	SimpleAgents    output.SimpleAgentRegistry
 	TaskExecutor    input.TaskExecutor
+	// Transcripts is nil unless Config.RecordTranscripts is set.
+	Transcripts *transcript.Recorder
+	// Screenshots captures the page of the task on the context, or the
+	// only browser when there is no pool.
+	Screenshots ScreenshotSource
+	// Pool is nil unless Config.BrowserPoolSize is set.
+	Pool *pool.Pool
+
+	shared *rod.SharedBrowser
+}
+
+type ScreenshotSource interface {
+	Screenshot(ctx context.Context) (*entity.Screenshot, error)
 }
 
 type Config struct {
-	OpenRouterAPIKey  string
-	OpenRouterModel   string
-	BrowserHeadless   bool
+	OpenRouterAPIKey   string
+	OpenRouterModel    string
+	BrowserHeadless    bool
 	BrowserEnableTrace bool
-	SystemPrompt      string
-	ThinkingMode      bool
-	ThinkingBudget    int
+	BrowserRemoteURL   string
+	BrowserRemoteTab   string
+	BrowserRecordHAR   bool
+	DialogPolicy       entity.DialogPolicy
+	Emulation          entity.Emulation
+	Screenshot         entity.CaptureRequest
+	SystemPrompt       string
+	ThinkingMode       bool
+	ThinkingBudget     int
+	UserInteraction    output.UserInteractionPort
+	ApprovalPolicy     approval.Policy
+	NavigationPolicy   policy.Navigation
+	// Network* configure the request log read by browser_network.
+	NetworkCapture      bool
+	NetworkBodyURLs     []string
+	NetworkMaxEntries   int
+	NetworkMaxBodyBytes int
+	// BlockResources, BlockAds and InterceptRules are applied to every
+	// request of the page from the start.
+	BlockResources []string
+	BlockAds       bool
+	InterceptRules []entity.InterceptRule
+	Secrets        output.SecretsPort
+	// Redactor scrubs logs and, with RedactLLM, outgoing LLM messages.
+	// Defaults to Secrets when nil. Secret values are always scrubbed from
+	// LLM messages, since pages echo typed secrets back in observations.
+	Redactor         output.Redactor
+	RedactLLM        bool
+	OnSensitiveInput func(value string)
+	// LogLevel is the lowest level written to the log file.
+	LogLevel logger.Level
+	// MaxIterations and SubAgentMaxIterations override the built-in
+	// iteration limits of the orchestrator and the sub-agents when positive.
+	MaxIterations         int
+	SubAgentMaxIterations int
+	// RecordTranscripts keeps a transcript of every run, priced with
+	// LLMPricing.
+	RecordTranscripts bool
+	LLMPricing        transcript.Pricing
+	// Prompts replaces the embedded system prompts when set.
+	Prompts *prompts.Set
+	// BrowserPoolSize runs that many tasks at once, each in a browser
+	// leased from a pool; zero shares one browser between all tasks.
+	// BrowserPoolIsolation is "context" for incognito contexts of one
+	// browser or "instance" for a browser per lease, reused up to
+	// BrowserPoolMaxUses times.
+	BrowserPoolSize      int
+	BrowserPoolIsolation string
+	BrowserPoolMaxUses   int
 }
 
 func NewContainer(ctx context.Context, cfg Config) (*Container, error) {
@@ -44,69 +112,220 @@ func NewContainer(ctx context.Context, cfg Config) (*Container, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create logger: %w", err)
 	}
+	log.SetLevel(cfg.LogLevel)
+	redactor := cfg.Redactor
+	if redactor == nil && cfg.Secrets != nil {
+		redactor = cfg.Secrets
+	}
+	if redactor != nil {
+		log.SetRedactor(redactor)
+	}
 
 	browserCfg := rod.DefaultConfig()
 	browserCfg.Headless = cfg.BrowserHeadless
 	browserCfg.EnableTrace = cfg.BrowserEnableTrace
-	browser, err := rod.NewBrowserAdapter(ctx, browserCfg)
-	if err != nil {
-		log.Close()
-		return nil, fmt.Errorf("failed to create browser: %w", err)
+	browserCfg.RemoteURL = cfg.BrowserRemoteURL
+	browserCfg.RemoteTab = cfg.BrowserRemoteTab
+	browserCfg.NavigationPolicy = cfg.NavigationPolicy
+	browserCfg.DialogPolicy = cfg.DialogPolicy
+	browserCfg.Emulation = cfg.Emulation
+	browserCfg.Network = rod.NetworkConfig{
+		Enabled:      cfg.NetworkCapture,
+		BodyURLs:     cfg.NetworkBodyURLs,
+		MaxEntries:   cfg.NetworkMaxEntries,
+		MaxBodyBytes: cfg.NetworkMaxBodyBytes,
+	}
+	browserCfg.Intercept = rod.InterceptConfig{
+		BlockResources: cfg.BlockResources,
+		BlockAds:       cfg.BlockAds,
+		Rules:          cfg.InterceptRules,
+	}
+	browserCfg.OnSensitiveInput = cfg.OnSensitiveInput
+	if cfg.BrowserRecordHAR {
+		browserCfg.HARPath = strings.TrimSuffix(log.Path(), ".log") + ".har"
+		if redactor != nil {
+			browserCfg.Redact = redactor.Redact
+		}
+		browserCfg.OnHARWritten = func(path string, err error) {
+			if err != nil {
+				log.Error("Failed to write HAR", "path", path, "error", err)
+				return
+			}
+			log.Info("HAR written", "path", path)
+		}
 	}
 
 	llmCfg := openrouter.DefaultConfig(cfg.OpenRouterAPIKey, cfg.OpenRouterModel)
 	llmCfg.Logger = log
 	llmCfg.ThinkingMode = cfg.ThinkingMode
+	if cfg.RedactLLM {
+		llmCfg.Redactor = redactor
+	} else if cfg.Secrets != nil {
+		llmCfg.Redactor = cfg.Secrets
+	}
 	if cfg.ThinkingBudget > 0 {
 		llmCfg.ThinkingBudget = cfg.ThinkingBudget
 	}
-	llm := openrouter.NewOpenRouterAdapter(llmCfg)
+	var llm output.LLMPort = openrouter.NewOpenRouterAdapter(llmCfg)
 
-	userInteraction := userinteraction.NewConsoleUserInteraction()
+	userInteraction := cfg.UserInteraction
+	if userInteraction == nil {
+		userInteraction = userinteraction.NewConsoleUserInteraction()
+	}
 
-	subAgentTools := service.NewToolRegistry()
-	registerBrowserTools(subAgentTools, browser, log)
-	registerUserInteractionTools(subAgentTools, userInteraction, log)
+	promptSet := prompts.Default()
+	if cfg.Prompts != nil {
+		promptSet = *cfg.Prompts
+	}
 
-	simpleAgents := service.NewSimpleAgentRegistry()
-	registerSimpleAgents(simpleAgents, llm, subAgentTools, log, userInteraction)
+	deps := pipelineDeps{
+		userInteraction:       userInteraction,
+		secrets:               cfg.Secrets,
+		artifacts:             artifacts.NewStore(strings.TrimSuffix(log.Path(), ".log")),
+		screenshot:            cfg.Screenshot,
+		approvalPolicy:        cfg.ApprovalPolicy,
+		prompts:               promptSet,
+		maxIterations:         cfg.MaxIterations,
+		subAgentMaxIterations: cfg.SubAgentMaxIterations,
+		log:                   log,
+	}
 
-	orchestratorTools := service.NewToolRegistry()
-	registerUserInteractionTools(orchestratorTools, userInteraction, log)
-	registerRunAgentTool(orchestratorTools, simpleAgents, log)
+	if cfg.BrowserPoolSize > 0 {
+		browserPool, shared, err := newBrowserPool(ctx, cfg, browserCfg, log)
+		if err != nil {
+			log.Close()
+			return nil, err
+		}
+		var recorder *transcript.Recorder
+		if cfg.RecordTranscripts {
+			recorder = newRecorder(llm, nil, cfg, redactor)
+			llm = recorder
+		}
+		deps.llm = llm
+		return &Container{
+			LLM:             llm,
+			Logger:          log,
+			UserInteraction: userInteraction,
+			TaskExecutor:    &pooledExecutor{pool: browserPool, recorder: recorder, deps: deps},
+			Transcripts:     recorder,
+			Screenshots:     poolScreenshots{pool: browserPool},
+			Pool:            browserPool,
+			shared:          shared,
+		}, nil
+	}
 
-	orchestratorUC := orchestrator.New(llm, orchestratorTools, simpleAgents, log, userInteraction, prompts.OrchestratorPrompt)
+	browser, err := rod.NewBrowserAdapter(ctx, browserCfg)
+	if err != nil {
+		log.Close()
+		return nil, fmt.Errorf("failed to create browser: %w", err)
+	}
+
+	var recorder *transcript.Recorder
+	if cfg.RecordTranscripts {
+		recorder = newRecorder(llm, browser, cfg, redactor)
+		llm = recorder
+	}
+	deps.llm = llm
+	p := newPipeline(browser, deps)
 
 	return &Container{
 		Browser:         browser,
 		LLM:             llm,
 		Logger:          log,
 		UserInteraction: userInteraction,
-		Tools:           subAgentTools,
-		SimpleAgents:    simpleAgents,
-		TaskExecutor:    orchestratorUC,
+		Tools:           p.tools,
+		SimpleAgents:    p.simpleAgents,
+		TaskExecutor:    p.orchestrator,
+		Transcripts:     recorder,
+		Screenshots:     browser,
 	}, nil
 }
 
+func newRecorder(llm output.LLMPort, page transcript.PageSource, cfg Config, redactor output.Redactor) *transcript.Recorder {
+	recorder := transcript.NewRecorder(llm, page)
+	recorder.SetPricing(cfg.LLMPricing)
+	if redactor != nil {
+		recorder.SetRedactor(redactor)
+	}
+	return recorder
+}
+
+// pipelineDeps is everything a pipeline needs besides its browser.
+type pipelineDeps struct {
+	llm                   output.LLMPort
+	userInteraction       output.UserInteractionPort
+	secrets               output.SecretsPort
+	artifacts             output.ArtifactPort
+	screenshot            entity.CaptureRequest
+	approvalPolicy        approval.Policy
+	prompts               prompts.Set
+	maxIterations         int
+	subAgentMaxIterations int
+	log                   output.LoggerPort
+}
+
+// pipeline is the tools, sub-agents and orchestrator working in one
+// browser.
+type pipeline struct {
+	tools        output.ToolRegistry
+	simpleAgents output.SimpleAgentRegistry
+	orchestrator input.TaskExecutor
+}
+
+func newPipeline(browser output.BrowserPort, deps pipelineDeps) pipeline {
+	subAgentTools := service.NewToolRegistry()
+	registerBrowserTools(subAgentTools, browser, deps.secrets, deps.artifacts, deps.screenshot, deps.log)
+	registerUserInteractionTools(subAgentTools, deps.userInteraction, deps.log)
+
+	approvalGate := approval.NewGate(deps.approvalPolicy, browser, deps.userInteraction, deps.log)
+	guardedTools := service.NewGuardedToolRegistry(subAgentTools, approvalGate)
+
+	simpleAgents := service.NewSimpleAgentRegistry()
+	registerSimpleAgents(simpleAgents, deps.llm, guardedTools, deps.log, deps.userInteraction, deps.prompts, deps.subAgentMaxIterations)
+
+	orchestratorTools := service.NewToolRegistry()
+	registerUserInteractionTools(orchestratorTools, deps.userInteraction, deps.log)
+	registerRunAgentTool(orchestratorTools, simpleAgents, deps.log)
+
+	orchestratorUC := orchestrator.New(deps.llm, orchestratorTools, simpleAgents, deps.log, deps.userInteraction, deps.prompts.Orchestrator)
+	orchestratorUC.SetMaxIterations(deps.maxIterations)
+
+	return pipeline{tools: guardedTools, simpleAgents: simpleAgents, orchestrator: orchestratorUC}
+}
+
 func (c *Container) Close() {
 	if c.Browser != nil {
 		c.Browser.Close()
 	}
+	if c.Pool != nil {
+		c.Pool.Close()
+	}
+	if c.shared != nil {
+		c.shared.Close()
+	}
 	if c.Logger != nil {
 		c.Logger.Close()
 	}
 }
 
-func registerBrowserTools(registry *service.ToolRegistryImpl, browser output.BrowserPort, log output.LoggerPort) {
+func registerBrowserTools(registry *service.ToolRegistryImpl, browser output.BrowserPort, secrets output.SecretsPort, artifacts output.ArtifactPort, screenshot entity.CaptureRequest, log output.LoggerPort) {
 	registry.Register(tool.NewNavigateTool(browser, log))
 	registry.Register(tool.NewClickTool(browser, log))
-	registry.Register(tool.NewFillTool(browser, log))
+	registry.Register(tool.NewFillTool(browser, secrets, log))
 	registry.Register(tool.NewScrollTool(browser, log))
-	registry.Register(tool.NewScreenshotTool(browser, log))
+	screenshotTool := tool.NewScreenshotTool(browser, artifacts, log)
+	screenshotTool.SetDefaults(screenshot)
+	registry.Register(screenshotTool)
 	registry.Register(tool.NewPressEnterTool(browser, log))
 	registry.Register(tool.NewObserveTool(browser, log))
 	registry.Register(tool.NewQueryElementsTool(browser, log))
 	registry.Register(tool.NewSearchTool(browser, log))
+	registry.Register(tool.NewWaitTool(browser, log))
+	registry.Register(tool.NewNetworkTool(browser, log))
+	registry.Register(tool.NewInterceptTool(browser, log))
+	registry.Register(tool.NewConsoleTool(browser, log))
+	registry.Register(tool.NewDialogTool(browser, log))
+	registry.Register(tool.NewEmulateTool(browser, log))
 }
 
 func registerUserInteractionTools(registry *service.ToolRegistryImpl, userInteraction output.UserInteractionPort, log output.LoggerPort) {
@@ -114,10 +333,18 @@ func registerUserInteractionTools(registry *service.ToolRegistryImpl, userIntera
 	registry.Register(tool.NewWaitUserActionTool(userInteraction, log))
 }
 
-func registerSimpleAgents(registry *service.SimpleAgentRegistryImpl, llm output.LLMPort, tools output.ToolRegistry, log output.LoggerPort, userInteraction output.UserInteractionPort) {
-	registry.Register(navigation.New(llm, tools, log, userInteraction, prompts.NavigationPrompt))
-	registry.Register(extraction.New(llm, tools, log, userInteraction, prompts.ExtractionPrompt))
-	registry.Register(form.New(llm, tools, log, userInteraction, prompts.FormPrompt))
+func registerSimpleAgents(registry *service.SimpleAgentRegistryImpl, llm output.LLMPort, tools output.ToolRegistry, log output.LoggerPort, userInteraction output.UserInteractionPort, promptSet prompts.Set, maxIterations int) {
+	navigationAgent := navigation.New(llm, tools, log, userInteraction, promptSet.Navigation)
+	navigationAgent.SetMaxIterations(maxIterations)
+	registry.Register(navigationAgent)
+
+	extractionAgent := extraction.New(llm, tools, log, userInteraction, promptSet.Extraction)
+	extractionAgent.SetMaxIterations(maxIterations)
+	registry.Register(extractionAgent)
+
+	formAgent := form.New(llm, tools, log, userInteraction, promptSet.Form)
+	formAgent.SetMaxIterations(maxIterations)
+	registry.Register(formAgent)
 }
 
 func registerRunAgentTool(registry *service.ToolRegistryImpl, agents output.SimpleAgentRegistry, log output.LoggerPort) {
@@ -0,0 +1,128 @@
+package di
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"browser-agent/internal/application/port/input"
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/application/runctx"
+	"browser-agent/internal/domain/entity"
+	"browser-agent/internal/infrastructure/browser/pool"
+	"browser-agent/internal/infrastructure/browser/rod"
+	"browser-agent/internal/infrastructure/transcript"
+	"browser-agent/internal/usecase/batch"
+)
+
+const (
+	PoolIsolationContext  = "context"
+	PoolIsolationInstance = "instance"
+)
+
+var errNoRunningTask = errors.New("no task is running")
+
+// newBrowserPool opens browsers for cfg.BrowserPoolSize tasks at once.
+// With context isolation they are incognito contexts of the returned
+// shared browser, one per task; otherwise separate browsers. An attached
+// browser is a single slot: its tab is handed to one task at a time, so
+// the user's session is kept as it is without a pool.
+func newBrowserPool(ctx context.Context, cfg Config, browserCfg rod.BrowserConfig, log output.LoggerPort) (*pool.Pool, *rod.SharedBrowser, error) {
+	if browserCfg.RemoteURL != "" {
+		if cfg.BrowserPoolSize > 1 {
+			log.Warn("Attached browser runs one task at a time", "concurrency", cfg.BrowserPoolSize)
+		}
+		factory := func(ctx context.Context) (pool.Browser, error) {
+			browser, err := rod.NewBrowserAdapter(context.WithoutCancel(ctx), browserCfg)
+			if err != nil {
+				return nil, fmt.Errorf("failed to attach to browser: %w", err)
+			}
+			return browser, nil
+		}
+		return pool.New(factory, pool.Config{MaxSize: 1}, log), nil, nil
+	}
+
+	// Every browser records its own HAR file next to the log.
+	harBase := strings.TrimSuffix(browserCfg.HARPath, ".har")
+	var opened atomic.Int64
+	configFor := func() rod.BrowserConfig {
+		leaseCfg := browserCfg
+		if leaseCfg.HARPath != "" {
+			leaseCfg.HARPath = fmt.Sprintf("%s_%d.har", harBase, opened.Add(1))
+		}
+		return leaseCfg
+	}
+
+	poolCfg := pool.Config{MaxSize: cfg.BrowserPoolSize, MaxUses: cfg.BrowserPoolMaxUses}
+	if cfg.BrowserPoolIsolation == PoolIsolationInstance {
+		factory := func(ctx context.Context) (pool.Browser, error) {
+			// The browser outlives the task that opened it.
+			browser, err := rod.NewBrowserAdapter(context.WithoutCancel(ctx), configFor())
+			if err != nil {
+				return nil, fmt.Errorf("failed to create browser: %w", err)
+			}
+			return browser, nil
+		}
+		return pool.New(factory, poolCfg, log), nil, nil
+	}
+
+	shared, err := rod.NewSharedBrowser(ctx, browserCfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create browser: %w", err)
+	}
+	factory := func(ctx context.Context) (pool.Browser, error) {
+		browser, err := shared.NewContext(ctx, configFor())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create browser context: %w", err)
+		}
+		return browser, nil
+	}
+	// A context is cheap to open, so no task sees another one's cookies.
+	// Config validation rejects any other max_uses with this isolation.
+	poolCfg.MaxUses = 1
+	return pool.New(factory, poolCfg, log), shared, nil
+}
+
+// pooledExecutor runs each task with a browser leased for it and the
+// tools and agents bound to that browser.
+type pooledExecutor struct {
+	pool     *pool.Pool
+	recorder *transcript.Recorder
+	deps     pipelineDeps
+}
+
+func (e *pooledExecutor) Execute(ctx context.Context, req input.TaskRequest) (*input.ExecuteResult, error) {
+	lease, err := e.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("no browser for the task: %w", err)
+	}
+	defer lease.Release()
+
+	browser := lease.Browser()
+	if e.recorder != nil {
+		taskID := runctx.TaskID(ctx)
+		e.recorder.SetPage(taskID, browser)
+		defer e.recorder.SetPage(taskID, nil)
+	}
+	if req.Task, err = batch.OpenStartURL(ctx, browser, req.Task, req.StartURL); err != nil {
+		return nil, err
+	}
+	req.StartURL = ""
+	return newPipeline(browser, e.deps).orchestrator.Execute(ctx, req)
+}
+
+// poolScreenshots captures the browser of the task on the context, or the
+// most recently leased one when the context has no task.
+type poolScreenshots struct {
+	pool *pool.Pool
+}
+
+func (s poolScreenshots) Screenshot(ctx context.Context) (*entity.Screenshot, error) {
+	browser, ok := s.pool.Browser(runctx.TaskID(ctx))
+	if !ok {
+		return nil, errNoRunningTask
+	}
+	return browser.Screenshot(ctx)
+}
@@ -0,0 +1,73 @@
+package di
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"browser-agent/internal/domain/entity"
+	"browser-agent/internal/infrastructure/browser/pool"
+	"browser-agent/internal/infrastructure/prompts"
+	"browser-agent/internal/testkit"
+	"browser-agent/internal/usecase/batch"
+)
+
+const shopSite = `
+start: blank
+pages:
+  blank:
+    url: about:blank
+  home:
+    url: https://shop.test/
+    title: Shop
+  cart:
+    url: https://shop.test/cart
+    title: Cart
+`
+
+func TestPooledExecutorOpensStartURL(t *testing.T) {
+	var mu sync.Mutex
+	var opened []*testkit.Browser
+	browsers := pool.New(func(context.Context) (pool.Browser, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		browser := testkit.NewBrowser(testkit.MustParseSite(shopSite))
+		opened = append(opened, browser)
+		return browser, nil
+	}, pool.Config{MaxSize: 2, MaxUses: 1}, testkit.NopLogger{})
+	defer browsers.Close()
+
+	llm := testkit.NewScriptedLLM().Script(testkit.Orchestrator, testkit.Answer("done"), testkit.Answer("done"))
+	executor := &pooledExecutor{pool: browsers, deps: pipelineDeps{
+		llm:             llm,
+		userInteraction: &testkit.User{},
+		prompts:         prompts.Default(),
+		maxIterations:   5,
+		log:             testkit.NopLogger{},
+	}}
+
+	workers := []batch.Worker{{Executor: executor}, {Executor: executor}}
+	results, err := batch.New(workers, nil, nil, testkit.NopLogger{}, batch.DefaultConfig()).Run(context.Background(), []entity.BatchTask{
+		{ID: "home", Task: "check the shop", StartURL: "https://shop.test/"},
+		{ID: "cart", Task: "check the cart", StartURL: "https://shop.test/cart"},
+	})
+	require.NoError(t, err)
+	for _, result := range results {
+		assert.Equal(t, entity.TaskStatusCompleted, result.Status, result.Error)
+	}
+
+	var pages []string
+	for _, browser := range opened {
+		pages = append(pages, browser.CurrentURL())
+		assert.Len(t, browser.Actions(), 1)
+	}
+	assert.ElementsMatch(t, []string{"https://shop.test/", "https://shop.test/cart"}, pages)
+
+	for _, call := range llm.CallsBy(testkit.Orchestrator) {
+		task := call.Request.Messages[len(call.Request.Messages)-1].Content
+		assert.Contains(t, task, "The browser is already open at https://shop.test/")
+	}
+}
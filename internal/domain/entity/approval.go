@@ -0,0 +1,24 @@
+package entity
+
+// ElementInfo describes a single page element for policy decisions.
+type ElementInfo struct {
+	Selector   string `json:"selector"`
+	TagName    string `json:"tagName"`
+	Text       string `json:"text"`
+	Role       string `json:"role"`
+	Type       string `json:"type"`
+	AriaLabel  string `json:"ariaLabel"`
+	Href       string `json:"href"`
+	InForm     bool   `json:"inForm"`
+	FormAction string `json:"formAction"`
+	IsSubmit   bool   `json:"isSubmit"`
+}
+
+type ApprovalRequest struct {
+	ToolName   ToolName     `json:"tool"`
+	Arguments  string       `json:"arguments"`
+	URL        string       `json:"url"`
+	Reasons    []string     `json:"reasons"`
+	Target     *ElementInfo `json:"target,omitempty"`
+	Screenshot *Screenshot  `json:"screenshot,omitempty"`
+}
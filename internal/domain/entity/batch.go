@@ -0,0 +1,51 @@
+package entity
+
+import "time"
+
+type ApprovalMode string
+
+const (
+	// ApprovalDeny rejects the action and lets the agent continue.
+	ApprovalDeny ApprovalMode = "deny"
+	// ApprovalApprove allows every action that asks for confirmation.
+	ApprovalApprove ApprovalMode = "approve"
+	// ApprovalFail aborts the task as soon as an approval is needed.
+	ApprovalFail ApprovalMode = "fail"
+)
+
+// ScriptedAnswer answers questions whose text matches the Match regexp
+// (case-insensitive). It also confirms manual actions with matching text.
+type ScriptedAnswer struct {
+	Match  string `yaml:"match" json:"match"`
+	Answer string `yaml:"answer" json:"answer"`
+}
+
+// InteractionScript replaces the user in non-interactive runs.
+type InteractionScript struct {
+	Answers   []ScriptedAnswer
+	Approvals ApprovalMode
+}
+
+// BatchTask is one entry of a batch task file.
+type BatchTask struct {
+	ID            string
+	Task          string
+	StartURL      string
+	MaxIterations int
+	Timeout       time.Duration
+	OutputSchema  map[string]any
+	Script        InteractionScript
+}
+
+type BatchResult struct {
+	ID          string     `json:"id"`
+	Task        string     `json:"task"`
+	Status      TaskStatus `json:"status"`
+	FinalAnswer string     `json:"final_answer,omitempty"`
+	// Output is the parsed final answer when the task has an output schema.
+	Output     any       `json:"output,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Iterations int       `json:"iterations"`
+	StartedAt  time.Time `json:"started_at"`
+	DurationMS int64     `json:"duration_ms"`
+}
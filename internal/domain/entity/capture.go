@@ -0,0 +1,114 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type ImageFormat string
+
+const (
+	ImageFormatPNG  ImageFormat = "png"
+	ImageFormatJPEG ImageFormat = "jpeg"
+)
+
+// DefaultJPEGQuality is used when a JPEG capture sets no quality.
+const DefaultJPEGQuality = 90
+
+// DefaultViewportMaxWidth is the width viewport screenshots are scaled down
+// to when they set no MaxWidth, the size agents observe pages at. Full-page
+// and element captures keep their size by default.
+const DefaultViewportMaxWidth = 1024
+
+// CaptureRequest describes a screenshot. Without FullPage or Selector it
+// captures the visible viewport.
+type CaptureRequest struct {
+	FullPage bool
+	// Selector is a CSS or XPath selector of the element to capture.
+	Selector string
+	// Format defaults to PNG.
+	Format ImageFormat
+	// Quality is 1..100 and only used for JPEG.
+	Quality int
+	// MaxWidth scales wider images down, keeping the aspect ratio. Zero
+	// means DefaultViewportMaxWidth for the viewport and the captured size
+	// for full-page and element captures.
+	MaxWidth int
+}
+
+// WithDefaults fills the zero format, quality and viewport max width.
+func (r CaptureRequest) WithDefaults() CaptureRequest {
+	if r.Format == "" {
+		r.Format = ImageFormatPNG
+	}
+	if r.Format == ImageFormatJPEG && r.Quality == 0 {
+		r.Quality = DefaultJPEGQuality
+	}
+	if r.MaxWidth == 0 && !r.FullPage && strings.TrimSpace(r.Selector) == "" {
+		r.MaxWidth = DefaultViewportMaxWidth
+	}
+	return r
+}
+
+func (r CaptureRequest) Validate() error {
+	var errs []error
+	if r.FullPage && strings.TrimSpace(r.Selector) != "" {
+		errs = append(errs, errors.New("full_page and selector cannot be combined"))
+	}
+	switch r.Format {
+	case "", ImageFormatPNG, ImageFormatJPEG:
+	default:
+		errs = append(errs, fmt.Errorf("invalid image format %q (must be png or jpeg)", r.Format))
+	}
+	if r.Quality < 0 || r.Quality > 100 {
+		errs = append(errs, fmt.Errorf("quality %d is out of range 1..100", r.Quality))
+	}
+	if r.MaxWidth < 0 {
+		errs = append(errs, errors.New("max_width must not be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+// Paper sizes in inches, as Page.printToPDF takes them.
+var PaperSizes = map[string][2]float64{
+	"a3":     {11.69, 16.54},
+	"a4":     {8.27, 11.69},
+	"letter": {8.5, 11},
+	"legal":  {8.5, 14},
+}
+
+// PDFRequest describes a PDF export of the page, as printed by Chrome.
+type PDFRequest struct {
+	Landscape bool
+	// Background prints background colors and images, which the print
+	// stylesheet of most sites drops.
+	Background bool
+	// Paper names one of PaperSizes; defaults to a4.
+	Paper string
+	// Scale is 0.1..2; zero means 1.
+	Scale float64
+	// PageRanges selects pages, e.g. "1-3, 5"; empty prints all.
+	PageRanges string
+}
+
+func (r PDFRequest) Validate() error {
+	var errs []error
+	if _, ok := PaperSizes[r.Paper]; r.Paper != "" && !ok {
+		errs = append(errs, fmt.Errorf("unknown paper %q (must be a3, a4, letter or legal)", r.Paper))
+	}
+	if r.Scale != 0 && (r.Scale < 0.1 || r.Scale > 2) {
+		errs = append(errs, fmt.Errorf("scale %g is out of range 0.1..2", r.Scale))
+	}
+	return errors.Join(errs...)
+}
+
+// PaperSize returns the portrait width and height in inches; Chrome turns
+// the page itself for Landscape.
+func (r PDFRequest) PaperSize() (width, height float64) {
+	size, ok := PaperSizes[r.Paper]
+	if !ok {
+		size = PaperSizes["a4"]
+	}
+	return size[0], size[1]
+}
@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCaptureRequest(t *testing.T) {
+	assert.Equal(t, CaptureRequest{Format: ImageFormatPNG, MaxWidth: DefaultViewportMaxWidth}, CaptureRequest{}.WithDefaults())
+	assert.Equal(t, CaptureRequest{Format: ImageFormatJPEG, Quality: DefaultJPEGQuality, MaxWidth: DefaultViewportMaxWidth}, CaptureRequest{Format: ImageFormatJPEG}.WithDefaults())
+	assert.Equal(t, CaptureRequest{FullPage: true, Format: ImageFormatPNG}, CaptureRequest{FullPage: true}.WithDefaults())
+	assert.Equal(t, CaptureRequest{Selector: "#invoice", Format: ImageFormatPNG}, CaptureRequest{Selector: "#invoice"}.WithDefaults())
+
+	assert.NoError(t, CaptureRequest{Selector: "#invoice", Format: ImageFormatJPEG, Quality: 70, MaxWidth: 800}.Validate())
+	err := CaptureRequest{FullPage: true, Selector: "#invoice", Format: "webp", Quality: 101}.Validate()
+	assert.ErrorContains(t, err, "full_page and selector cannot be combined")
+	assert.ErrorContains(t, err, `invalid image format "webp"`)
+	assert.ErrorContains(t, err, "quality 101 is out of range")
+}
+
+func TestPDFRequest(t *testing.T) {
+	width, height := PDFRequest{}.PaperSize()
+	assert.Equal(t, [2]float64{8.27, 11.69}, [2]float64{width, height})
+	width, height = PDFRequest{Paper: "letter", Landscape: true}.PaperSize()
+	assert.Equal(t, [2]float64{8.5, 11}, [2]float64{width, height})
+
+	assert.NoError(t, PDFRequest{Paper: "legal", Scale: 0.5}.Validate())
+	err := PDFRequest{Paper: "a5", Scale: 3}.Validate()
+	assert.ErrorContains(t, err, `unknown paper "a5"`)
+	assert.ErrorContains(t, err, "scale 3 is out of range")
+}
@@ -0,0 +1,98 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type ConsoleLevel string
+
+const (
+	ConsoleDebug   ConsoleLevel = "debug"
+	ConsoleInfo    ConsoleLevel = "info"
+	ConsoleWarning ConsoleLevel = "warning"
+	ConsoleError   ConsoleLevel = "error"
+	// ConsoleException is an uncaught JavaScript exception or rejection.
+	ConsoleException ConsoleLevel = "exception"
+)
+
+// IsError reports whether the level means something on the page broke.
+func (l ConsoleLevel) IsError() bool {
+	return l == ConsoleError || l == ConsoleException
+}
+
+func (l ConsoleLevel) severity() int {
+	switch l {
+	case ConsoleWarning:
+		return 1
+	case ConsoleError, ConsoleException:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// ConsoleMessage is a console call, an uncaught exception or a browser
+// message such as a failed resource load.
+type ConsoleMessage struct {
+	// ID grows with every message, so callers can ask for the messages
+	// after one they have seen.
+	ID    int          `json:"id"`
+	Level ConsoleLevel `json:"level"`
+	Text  string       `json:"text"`
+	// Source is the script location, e.g. https://shop.test/app.js:12.
+	Source  string    `json:"source,omitempty"`
+	PageURL string    `json:"page_url,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// String formats the message on one line for the agent.
+func (m ConsoleMessage) String() string {
+	text := m.Text
+	if line, _, found := strings.Cut(text, "\n"); found {
+		text = line + " ..."
+	}
+	if m.Source != "" {
+		return fmt.Sprintf("[%s] %s (%s)", m.Level, text, m.Source)
+	}
+	return fmt.Sprintf("[%s] %s", m.Level, text)
+}
+
+// ConsoleFilter selects messages of the console log.
+type ConsoleFilter struct {
+	// MinLevel drops less severe messages: warning keeps warnings and
+	// errors, error keeps errors and exceptions. Empty keeps all.
+	MinLevel ConsoleLevel
+	// AfterID keeps only messages newer than that ID.
+	AfterID int
+	// Limit keeps only the most recent messages; zero means all.
+	Limit int
+}
+
+func (f ConsoleFilter) Validate() error {
+	switch f.MinLevel {
+	case "", ConsoleDebug, ConsoleInfo, ConsoleWarning, ConsoleError:
+	default:
+		return fmt.Errorf("invalid level %q (must be warning or error)", f.MinLevel)
+	}
+	if f.Limit < 0 {
+		return fmt.Errorf("limit must not be negative")
+	}
+	return nil
+}
+
+// Apply returns the matching messages, oldest first.
+func (f ConsoleFilter) Apply(messages []ConsoleMessage) []ConsoleMessage {
+	var matched []ConsoleMessage
+	for _, m := range messages {
+		if m.ID <= f.AfterID || m.Level.severity() < f.MinLevel.severity() {
+			continue
+		}
+		matched = append(matched, m)
+	}
+	if f.Limit > 0 && len(matched) > f.Limit {
+		matched = matched[len(matched)-f.Limit:]
+	}
+	return matched
+}
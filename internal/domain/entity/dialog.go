@@ -0,0 +1,112 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrDialogOpen = errors.New("a JavaScript dialog is open")
+	ErrNoDialog   = errors.New("no JavaScript dialog is open")
+)
+
+type DialogType string
+
+const (
+	DialogAlert   DialogType = "alert"
+	DialogConfirm DialogType = "confirm"
+	DialogPrompt  DialogType = "prompt"
+	// DialogBeforeUnload asks whether to leave a page with unsaved changes.
+	DialogBeforeUnload DialogType = "beforeunload"
+)
+
+// DialogPolicy decides what happens to a dialog when it opens.
+type DialogPolicy string
+
+const (
+	// DialogPolicyAgent leaves the dialog open until the agent accepts or
+	// dismisses it with browser_dialog. Every other browser call fails
+	// while it is open.
+	DialogPolicyAgent DialogPolicy = "agent"
+	// DialogPolicyAccept accepts every dialog right away, entering the
+	// default text into prompts.
+	DialogPolicyAccept DialogPolicy = "accept"
+	// DialogPolicyDismiss dismisses every dialog right away.
+	DialogPolicyDismiss DialogPolicy = "dismiss"
+)
+
+func (p DialogPolicy) Validate() error {
+	switch p {
+	case DialogPolicyAgent, DialogPolicyAccept, DialogPolicyDismiss:
+		return nil
+	default:
+		return fmt.Errorf("invalid dialog policy %q (must be agent, accept or dismiss)", p)
+	}
+}
+
+// Dialog is a native alert, confirm, prompt or beforeunload dialog of the
+// page.
+type Dialog struct {
+	// ID grows with every dialog, so callers can ask for the dialogs after
+	// one they have seen.
+	ID            int        `json:"id"`
+	Type          DialogType `json:"type"`
+	Message       string     `json:"message"`
+	DefaultPrompt string     `json:"default_prompt,omitempty"`
+	URL           string     `json:"url,omitempty"`
+	OpenedAt      time.Time  `json:"opened_at"`
+	// Open is true until the dialog is accepted or dismissed; the fields
+	// below describe how it was closed.
+	Open       bool   `json:"open"`
+	Accepted   bool   `json:"accepted,omitempty"`
+	PromptText string `json:"prompt_text,omitempty"`
+	// ByPolicy is set when the dialog policy closed the dialog rather than
+	// the agent.
+	ByPolicy bool `json:"by_policy,omitempty"`
+}
+
+// String formats the dialog on one line for the agent, e.g.
+// confirm "Delete the item?" (dismissed by policy).
+func (d Dialog) String() string {
+	s := fmt.Sprintf("%s %q", d.Type, d.Message)
+	if d.Type == DialogPrompt && d.DefaultPrompt != "" {
+		s += fmt.Sprintf(" [default: %q]", d.DefaultPrompt)
+	}
+	if d.Open {
+		return s + " (open)"
+	}
+
+	outcome := "dismissed"
+	if d.Accepted {
+		outcome = "accepted"
+		if d.Type == DialogPrompt {
+			outcome += fmt.Sprintf(" with %q", d.PromptText)
+		}
+	}
+	if d.ByPolicy {
+		outcome += " by policy"
+	}
+	return fmt.Sprintf("%s (%s)", s, outcome)
+}
+
+// DialogResponse closes the open dialog.
+type DialogResponse struct {
+	Accept bool
+	// PromptText is entered into a prompt dialog before it is accepted.
+	PromptText string
+}
+
+// DialogOpenError is returned by browser calls made while a dialog blocks
+// the page.
+type DialogOpenError struct {
+	Dialog Dialog
+}
+
+func (e *DialogOpenError) Error() string {
+	return fmt.Sprintf("%s: %s; accept or dismiss it with %s first", ErrDialogOpen, e.Dialog, ToolBrowserDialog)
+}
+
+func (e *DialogOpenError) Unwrap() error {
+	return ErrDialogOpen
+}
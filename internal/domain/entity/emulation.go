@@ -0,0 +1,301 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ColorScheme string
+
+const (
+	ColorSchemeLight        ColorScheme = "light"
+	ColorSchemeDark         ColorScheme = "dark"
+	ColorSchemeNoPreference ColorScheme = "no-preference"
+)
+
+// Device is a preset of screen and browser identity.
+type Device struct {
+	Width             int
+	Height            int
+	DeviceScaleFactor float64
+	// Mobile makes the page use the mobile viewport meta tag and overlay
+	// scrollbars; Touch enables touch events.
+	Mobile    bool
+	Touch     bool
+	UserAgent string
+	// Platform is what navigator.platform reports.
+	Platform string
+}
+
+const (
+	uaDesktop = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36"
+	uaIPhone  = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Mobile/15E148 Safari/604.1"
+	uaIPad    = "Mozilla/5.0 (iPad; CPU OS 17_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Mobile/15E148 Safari/604.1"
+	uaPixel   = "Mozilla/5.0 (Linux; Android 14; Pixel 7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Mobile Safari/537.36"
+	uaGalaxy  = "Mozilla/5.0 (Linux; Android 14; SM-X700) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36"
+)
+
+// Devices are the presets Emulation.Device may name.
+var Devices = map[string]Device{
+	"desktop":    {Width: 1920, Height: 1080, DeviceScaleFactor: 1, UserAgent: uaDesktop, Platform: "Win32"},
+	"laptop":     {Width: 1366, Height: 768, DeviceScaleFactor: 1, UserAgent: uaDesktop, Platform: "Win32"},
+	"iphone-15":  {Width: 393, Height: 852, DeviceScaleFactor: 3, Mobile: true, Touch: true, UserAgent: uaIPhone, Platform: "iPhone"},
+	"iphone-se":  {Width: 375, Height: 667, DeviceScaleFactor: 2, Mobile: true, Touch: true, UserAgent: uaIPhone, Platform: "iPhone"},
+	"pixel-7":    {Width: 412, Height: 915, DeviceScaleFactor: 2.625, Mobile: true, Touch: true, UserAgent: uaPixel, Platform: "Linux armv8l"},
+	"ipad":       {Width: 820, Height: 1180, DeviceScaleFactor: 2, Mobile: true, Touch: true, UserAgent: uaIPad, Platform: "iPad"},
+	"galaxy-tab": {Width: 800, Height: 1280, DeviceScaleFactor: 2, Mobile: true, Touch: true, UserAgent: uaGalaxy, Platform: "Linux armv8l"},
+}
+
+// DeviceNames lists the presets in alphabetical order.
+func DeviceNames() []string {
+	names := make([]string, 0, len(Devices))
+	for name := range Devices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseViewport reads "WIDTHxHEIGHT", e.g. "1366x768".
+func ParseViewport(s string) (width, height int, err error) {
+	w, h, found := strings.Cut(strings.ToLower(strings.TrimSpace(s)), "x")
+	if found {
+		width, err = strconv.Atoi(w)
+		if err == nil {
+			height, err = strconv.Atoi(h)
+		}
+	}
+	if !found || err != nil {
+		return 0, 0, fmt.Errorf("invalid viewport %q (want WIDTHxHEIGHT, e.g. 1366x768)", s)
+	}
+	return width, height, nil
+}
+
+type Geolocation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	// Accuracy is in meters; zero means 50.
+	Accuracy float64 `json:"accuracy,omitempty"`
+}
+
+// ParseGeolocation reads "latitude,longitude[,accuracy]", e.g.
+// "52.52,13.405" for Berlin.
+func ParseGeolocation(s string) (*Geolocation, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, fmt.Errorf("invalid geolocation %q (want latitude,longitude[,accuracy])", s)
+	}
+	values := make([]float64, len(parts))
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid geolocation %q: %w", s, err)
+		}
+		values[i] = v
+	}
+	geo := &Geolocation{Latitude: values[0], Longitude: values[1]}
+	if len(values) == 3 {
+		geo.Accuracy = values[2]
+	}
+	return geo, geo.Validate()
+}
+
+func (g Geolocation) Validate() error {
+	if g.Latitude < -90 || g.Latitude > 90 {
+		return fmt.Errorf("latitude %g is out of range -90..90", g.Latitude)
+	}
+	if g.Longitude < -180 || g.Longitude > 180 {
+		return fmt.Errorf("longitude %g is out of range -180..180", g.Longitude)
+	}
+	if g.Accuracy < 0 {
+		return errors.New("accuracy must not be negative")
+	}
+	return nil
+}
+
+func (g Geolocation) String() string {
+	accuracy := g.Accuracy
+	if accuracy == 0 {
+		accuracy = 50
+	}
+	return fmt.Sprintf("%g,%g (±%gm)", g.Latitude, g.Longitude, accuracy)
+}
+
+var localePattern = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+
+// Emulation makes the page look like it runs on another device, in another
+// place or with other preferences. Zero fields keep the browser's own
+// values.
+type Emulation struct {
+	// Device names a preset from Devices; the viewport fields and UserAgent
+	// below override single values of it.
+	Device            string  `json:"device,omitempty"`
+	Width             int     `json:"width,omitempty"`
+	Height            int     `json:"height,omitempty"`
+	DeviceScaleFactor float64 `json:"device_scale_factor,omitempty"`
+	Mobile            bool    `json:"mobile,omitempty"`
+	Touch             bool    `json:"touch,omitempty"`
+	UserAgent         string  `json:"user_agent,omitempty"`
+	// Locale is a BCP 47 tag such as de-DE; it sets navigator.language,
+	// Intl formatting and the Accept-Language header.
+	Locale string `json:"locale,omitempty"`
+	// Timezone is an IANA name such as Europe/Berlin.
+	Timezone    string       `json:"timezone,omitempty"`
+	Geolocation *Geolocation `json:"geolocation,omitempty"`
+	ColorScheme ColorScheme  `json:"color_scheme,omitempty"`
+}
+
+func (e Emulation) IsZero() bool {
+	return e == Emulation{}
+}
+
+func (e Emulation) Validate() error {
+	var errs []error
+	if _, ok := Devices[e.Device]; e.Device != "" && !ok {
+		errs = append(errs, fmt.Errorf("unknown device %q (known: %s)", e.Device, strings.Join(DeviceNames(), ", ")))
+	}
+	if (e.Width == 0) != (e.Height == 0) {
+		errs = append(errs, errors.New("width and height must be set together"))
+	}
+	if e.Width != 0 && (e.Width < 100 || e.Width > 10000 || e.Height < 100 || e.Height > 10000) {
+		errs = append(errs, fmt.Errorf("viewport %dx%d is out of range 100..10000", e.Width, e.Height))
+	}
+	if e.DeviceScaleFactor < 0 || e.DeviceScaleFactor > 5 {
+		errs = append(errs, fmt.Errorf("device_scale_factor %g is out of range 0..5", e.DeviceScaleFactor))
+	}
+	if e.Locale != "" && !localePattern.MatchString(e.Locale) {
+		errs = append(errs, fmt.Errorf("invalid locale %q (want a tag like de-DE)", e.Locale))
+	}
+	if e.Timezone != "" {
+		if _, err := time.LoadLocation(e.Timezone); err != nil {
+			errs = append(errs, fmt.Errorf("unknown timezone %q (want an IANA name like Europe/Berlin)", e.Timezone))
+		}
+	}
+	if e.Geolocation != nil {
+		if err := e.Geolocation.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("geolocation: %w", err))
+		}
+	}
+	switch e.ColorScheme {
+	case "", ColorSchemeLight, ColorSchemeDark, ColorSchemeNoPreference:
+	default:
+		errs = append(errs, fmt.Errorf("invalid color scheme %q (must be light, dark or no-preference)", e.ColorScheme))
+	}
+	return errors.Join(errs...)
+}
+
+// Merge returns e with the non-zero fields of over applied. A new Device
+// drops the viewport and user agent of the previous one unless over sets
+// them as well.
+func (e Emulation) Merge(over Emulation) Emulation {
+	if over.Device != "" {
+		e.Device = over.Device
+		e.Width, e.Height, e.DeviceScaleFactor = 0, 0, 0
+		e.Mobile, e.Touch, e.UserAgent = false, false, ""
+	}
+	if over.Width != 0 {
+		e.Width, e.Height = over.Width, over.Height
+	}
+	if over.DeviceScaleFactor != 0 {
+		e.DeviceScaleFactor = over.DeviceScaleFactor
+	}
+	e.Mobile = e.Mobile || over.Mobile
+	e.Touch = e.Touch || over.Touch
+	if over.UserAgent != "" {
+		e.UserAgent = over.UserAgent
+	}
+	if over.Locale != "" {
+		e.Locale = over.Locale
+	}
+	if over.Timezone != "" {
+		e.Timezone = over.Timezone
+	}
+	if over.Geolocation != nil {
+		e.Geolocation = over.Geolocation
+	}
+	if over.ColorScheme != "" {
+		e.ColorScheme = over.ColorScheme
+	}
+	return e
+}
+
+// Resolve fills the zero viewport fields and the user agent from the
+// device preset, along with the platform to report.
+func (e Emulation) Resolve() (Emulation, string) {
+	device, ok := Devices[e.Device]
+	if !ok {
+		if e.Width != 0 && e.DeviceScaleFactor == 0 {
+			e.DeviceScaleFactor = 1
+		}
+		return e, ""
+	}
+	if e.Width == 0 {
+		e.Width, e.Height = device.Width, device.Height
+	}
+	if e.DeviceScaleFactor == 0 {
+		e.DeviceScaleFactor = device.DeviceScaleFactor
+	}
+	e.Mobile = e.Mobile || device.Mobile
+	e.Touch = e.Touch || device.Touch
+	if e.UserAgent == "" {
+		e.UserAgent = device.UserAgent
+	}
+	return e, device.Platform
+}
+
+// AcceptLanguage is the header value for Locale, e.g. "de-DE,de;q=0.9".
+func (e Emulation) AcceptLanguage() string {
+	if e.Locale == "" {
+		return ""
+	}
+	lang, _, found := strings.Cut(e.Locale, "-")
+	if !found {
+		return e.Locale
+	}
+	return e.Locale + "," + lang + ";q=0.9"
+}
+
+// String describes the settings on one line for the agent, e.g.
+// "iphone-15 393x852@3x mobile touch, locale de-DE, timezone Europe/Berlin".
+func (e Emulation) String() string {
+	if e.IsZero() {
+		return "browser defaults"
+	}
+	resolved, _ := e.Resolve()
+
+	var parts []string
+	if resolved.Width != 0 {
+		viewport := fmt.Sprintf("%dx%d@%gx", resolved.Width, resolved.Height, resolved.DeviceScaleFactor)
+		if e.Device != "" {
+			viewport = e.Device + " " + viewport
+		}
+		if resolved.Mobile {
+			viewport += " mobile"
+		}
+		if resolved.Touch {
+			viewport += " touch"
+		}
+		parts = append(parts, viewport)
+	}
+	if e.UserAgent != "" {
+		parts = append(parts, "custom user agent")
+	}
+	if e.Locale != "" {
+		parts = append(parts, "locale "+e.Locale)
+	}
+	if e.Timezone != "" {
+		parts = append(parts, "timezone "+e.Timezone)
+	}
+	if e.Geolocation != nil {
+		parts = append(parts, "geolocation "+e.Geolocation.String())
+	}
+	if e.ColorScheme != "" {
+		parts = append(parts, string(e.ColorScheme)+" color scheme")
+	}
+	return strings.Join(parts, ", ")
+}
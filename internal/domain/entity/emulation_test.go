@@ -0,0 +1,80 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmulationMergeAndResolve(t *testing.T) {
+	base := Emulation{Device: "iphone-15", UserAgent: "custom", Locale: "de-DE"}
+
+	next := base.Merge(Emulation{Timezone: "Europe/Berlin", ColorScheme: ColorSchemeDark})
+	assert.Equal(t, "iphone-15", next.Device)
+	assert.Equal(t, "custom", next.UserAgent)
+	assert.Equal(t, "Europe/Berlin", next.Timezone)
+
+	// A new device drops the user agent set for the previous one.
+	next = next.Merge(Emulation{Device: "pixel-7"})
+	assert.Empty(t, next.UserAgent)
+	assert.Equal(t, "de-DE", next.Locale)
+
+	resolved, platform := next.Resolve()
+	assert.Equal(t, 412, resolved.Width)
+	assert.Equal(t, 2.625, resolved.DeviceScaleFactor)
+	assert.True(t, resolved.Mobile)
+	assert.True(t, resolved.Touch)
+	assert.Contains(t, resolved.UserAgent, "Android")
+	assert.Equal(t, "Linux armv8l", platform)
+
+	resolved, _ = Emulation{Device: "ipad", Width: 1180, Height: 820}.Resolve()
+	assert.Equal(t, 1180, resolved.Width)
+	assert.Equal(t, 2.0, resolved.DeviceScaleFactor)
+
+	resolved, platform = Emulation{Width: 1280, Height: 720}.Resolve()
+	assert.Equal(t, 1.0, resolved.DeviceScaleFactor)
+	assert.Empty(t, platform)
+}
+
+func TestEmulationValidate(t *testing.T) {
+	require.NoError(t, Emulation{}.Validate())
+	require.NoError(t, Emulation{Device: "iphone-se", Locale: "pt-BR", Timezone: "America/Sao_Paulo", Geolocation: &Geolocation{Latitude: -23.55, Longitude: -46.63}}.Validate())
+
+	err := Emulation{
+		Device:      "nokia",
+		Width:       320,
+		Locale:      "german",
+		Timezone:    "Mars/Olympus",
+		Geolocation: &Geolocation{Latitude: 91},
+		ColorScheme: "sepia",
+	}.Validate()
+	require.Error(t, err)
+	for _, want := range []string{`unknown device "nokia"`, "width and height", `invalid locale "german"`, `unknown timezone "Mars/Olympus"`, "latitude 91", `invalid color scheme "sepia"`} {
+		assert.Contains(t, err.Error(), want)
+	}
+}
+
+func TestEmulationString(t *testing.T) {
+	assert.Equal(t, "browser defaults", Emulation{}.String())
+	assert.Equal(t, "iphone-15 393x852@3x mobile touch, locale de-DE, timezone Europe/Berlin, geolocation 52.52,13.405 (±50m), dark color scheme",
+		Emulation{Device: "iphone-15", Locale: "de-DE", Timezone: "Europe/Berlin", Geolocation: &Geolocation{Latitude: 52.52, Longitude: 13.405}, ColorScheme: ColorSchemeDark}.String())
+	assert.Equal(t, "de-DE,de;q=0.9", Emulation{Locale: "de-DE"}.AcceptLanguage())
+	assert.Equal(t, "fr", Emulation{Locale: "fr"}.AcceptLanguage())
+}
+
+func TestParseViewportAndGeolocation(t *testing.T) {
+	width, height, err := ParseViewport("1366x768")
+	require.NoError(t, err)
+	assert.Equal(t, []int{1366, 768}, []int{width, height})
+	_, _, err = ParseViewport("1366")
+	assert.Error(t, err)
+
+	geo, err := ParseGeolocation("52.52, 13.405, 10")
+	require.NoError(t, err)
+	assert.Equal(t, &Geolocation{Latitude: 52.52, Longitude: 13.405, Accuracy: 10}, geo)
+	_, err = ParseGeolocation("52.52")
+	assert.Error(t, err)
+	_, err = ParseGeolocation("52.52,200")
+	assert.ErrorContains(t, err, "longitude 200")
+}
@@ -0,0 +1,62 @@
+package entity
+
+import "time"
+
+// EvalCase is a batch task with the outcome it must reach.
+type EvalCase struct {
+	BatchTask
+	Expect EvalExpectation
+}
+
+// EvalExpectation lists the checks of a case; all of them must pass.
+type EvalExpectation struct {
+	// AnswerContains are substrings of the final answer, case-insensitive.
+	AnswerContains []string `yaml:"answer_contains" json:"answer_contains,omitempty"`
+	// AnswerMatches is a regexp the final answer must match.
+	AnswerMatches string `yaml:"answer_matches" json:"answer_matches,omitempty"`
+	// URLContains is a substring of the URL the browser ends on.
+	URLContains string `yaml:"url_contains" json:"url_contains,omitempty"`
+	// DOM checks the page the browser ends on.
+	DOM []DOMCheck `yaml:"dom" json:"dom,omitempty"`
+}
+
+// DOMCheck requires an element matching Selector, with TextContains in its
+// text when set, or no such element when Absent.
+type DOMCheck struct {
+	Selector     string `yaml:"selector" json:"selector"`
+	TextContains string `yaml:"text_contains" json:"text_contains,omitempty"`
+	Absent       bool   `yaml:"absent" json:"absent,omitempty"`
+}
+
+type EvalResult struct {
+	ID            string     `json:"id"`
+	Model         string     `json:"model"`
+	PromptVersion string     `json:"prompt_version"`
+	Passed        bool       `json:"passed"`
+	Failures      []string   `json:"failures,omitempty"`
+	Status        TaskStatus `json:"status"`
+	FinalAnswer   string     `json:"final_answer,omitempty"`
+	Error         string     `json:"error,omitempty"`
+	Iterations    int        `json:"iterations"`
+	Usage         Usage      `json:"usage"`
+	DurationMS    int64      `json:"duration_ms"`
+}
+
+// EvalSummary aggregates the results of one model and prompt version.
+type EvalSummary struct {
+	Model         string  `json:"model"`
+	PromptVersion string  `json:"prompt_version"`
+	Tasks         int     `json:"tasks"`
+	Passed        int     `json:"passed"`
+	SuccessRate   float64 `json:"success_rate"`
+	AvgIterations float64 `json:"avg_iterations"`
+	// Usage is the total over all tasks.
+	Usage         Usage `json:"usage"`
+	AvgDurationMS int64 `json:"avg_duration_ms"`
+}
+
+type EvalReport struct {
+	StartedAt time.Time     `json:"started_at"`
+	Summaries []EvalSummary `json:"summaries"`
+	Results   []EvalResult  `json:"results"`
+}
@@ -0,0 +1,25 @@
+package entity
+
+import "time"
+
+type EventType string
+
+const (
+	EventTaskStatus  EventType = "task_status"
+	EventIteration   EventType = "iteration"
+	EventToolStart   EventType = "tool_start"
+	EventToolResult  EventType = "tool_result"
+	EventThinking    EventType = "thinking"
+	EventInteraction EventType = "interaction"
+	EventAnswered    EventType = "answered"
+)
+
+// Event is a progress notification of a running task. ID grows monotonically
+// across all tasks so clients can resume a stream after reconnecting.
+type Event struct {
+	ID     int64          `json:"id"`
+	TaskID string         `json:"task_id"`
+	Type   EventType      `json:"type"`
+	Time   time.Time      `json:"time"`
+	Data   map[string]any `json:"data,omitempty"`
+}
@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+type InteractionKind string
+
+const (
+	InteractionQuestion InteractionKind = "question"
+	InteractionAction   InteractionKind = "action"
+	InteractionApproval InteractionKind = "approval"
+)
+
+// PendingInteraction is a question, manual action or approval an agent is
+// blocked on until a remote client answers it.
+type PendingInteraction struct {
+	ID        string           `json:"id"`
+	TaskID    string           `json:"task_id"`
+	Kind      InteractionKind  `json:"kind"`
+	Prompt    string           `json:"prompt"`
+	Approval  *ApprovalRequest `json:"approval,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+}
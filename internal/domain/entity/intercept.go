@@ -0,0 +1,201 @@
+package entity
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type InterceptAction string
+
+const (
+	// InterceptBlock fails matching requests as blocked by the client.
+	InterceptBlock InterceptAction = "block"
+	// InterceptMock answers matching requests without reaching the server.
+	InterceptMock InterceptAction = "mock"
+	// InterceptHeaders adds headers to matching requests.
+	InterceptHeaders InterceptAction = "headers"
+)
+
+// resourceTypes are the lowercase CDP resource types a rule can name.
+var resourceTypes = map[string]bool{
+	"document": true, "stylesheet": true, "image": true, "media": true,
+	"font": true, "script": true, "texttrack": true, "xhr": true,
+	"fetch": true, "prefetch": true, "eventsource": true, "websocket": true,
+	"manifest": true, "signedexchange": true, "ping": true,
+	"cspviolationreport": true, "preflight": true, "other": true,
+}
+
+// InterceptRule changes the requests of the page whose URL and resource type
+// match. The first matching block or mock rule decides the request; header
+// rules before it all apply.
+type InterceptRule struct {
+	// ID is assigned when the rule is added.
+	ID     string          `json:"id"`
+	Action InterceptAction `json:"action"`
+	// URLPattern is a regular expression matched against the URL; empty
+	// matches every URL.
+	URLPattern string `json:"url_pattern,omitempty"`
+	// ResourceTypes are lowercase CDP types such as image, font, stylesheet,
+	// media, script, xhr, fetch or document; empty matches every type.
+	ResourceTypes []string `json:"resource_types,omitempty"`
+
+	// Status, ContentType and Body form the response of a mock rule.
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        string `json:"body,omitempty"`
+	// Headers are added to the request by a headers rule and to the
+	// response by a mock rule.
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+func (r InterceptRule) Validate() error {
+	if _, err := regexp.Compile(r.URLPattern); err != nil {
+		return fmt.Errorf("invalid url_pattern: %w", err)
+	}
+	switch r.Action {
+	case InterceptBlock:
+	case InterceptMock:
+		if r.Status != 0 && http.StatusText(r.Status) == "" {
+			return fmt.Errorf("invalid mock status %d", r.Status)
+		}
+	case InterceptHeaders:
+		if len(r.Headers) == 0 {
+			return fmt.Errorf("headers are required for action %q", r.Action)
+		}
+	default:
+		return fmt.Errorf("unknown intercept action %q", r.Action)
+	}
+	for _, t := range r.ResourceTypes {
+		if !resourceTypes[t] {
+			return fmt.Errorf("unknown resource type %q", t)
+		}
+	}
+	for name := range r.Headers {
+		if name == "" || strings.ContainsAny(name, " :\r\n") {
+			return fmt.Errorf("invalid header name %q", name)
+		}
+	}
+	return nil
+}
+
+// Describe summarizes the rule for listings and logs, e.g.
+// `#2 mock 503 /api/cart`.
+func (r InterceptRule) Describe() string {
+	var b strings.Builder
+	if r.ID != "" {
+		fmt.Fprintf(&b, "#%s ", r.ID)
+	}
+	b.WriteString(string(r.Action))
+	if r.Action == InterceptMock {
+		fmt.Fprintf(&b, " %d", r.MockStatus())
+	}
+	if r.URLPattern != "" {
+		fmt.Fprintf(&b, " %s", r.URLPattern)
+	} else {
+		b.WriteString(" *")
+	}
+	if len(r.ResourceTypes) > 0 {
+		fmt.Fprintf(&b, " [%s]", strings.Join(r.ResourceTypes, ","))
+	}
+	if r.Action == InterceptHeaders {
+		names := make([]string, 0, len(r.Headers))
+		for name := range r.Headers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Fprintf(&b, " +%s", strings.Join(names, ",+"))
+	}
+	return b.String()
+}
+
+// MatchesType reports whether the rule applies to requests of resourceType,
+// given in lowercase.
+func (r InterceptRule) MatchesType(resourceType string) bool {
+	if len(r.ResourceTypes) == 0 {
+		return true
+	}
+	for _, t := range r.ResourceTypes {
+		if t == resourceType {
+			return true
+		}
+	}
+	return false
+}
+
+// MockStatus is the status of a mock response, 200 when not set.
+func (r InterceptRule) MockStatus() int {
+	if r.Status == 0 {
+		return http.StatusOK
+	}
+	return r.Status
+}
+
+// ParseInterceptRule reads a rule written on one line, as in the config:
+//
+//	block <url-regexp> [types]
+//	mock <url-regexp> <status> [body]
+//	header <url-regexp> <Name>: <value>
+//
+// types is a comma separated list in square brackets, e.g. [image,font];
+// "*" as the URL matches every URL. A mock body starting with { or [ is
+// served as JSON.
+func ParseInterceptRule(line string) (InterceptRule, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return InterceptRule{}, fmt.Errorf("intercept rule %q: expected <action> <url-regexp> ...", line)
+	}
+
+	rule := InterceptRule{URLPattern: fields[1]}
+	if rule.URLPattern == "*" {
+		rule.URLPattern = ""
+	}
+	rest := strings.TrimSpace(strings.TrimSpace(line)[len(fields[0]):])
+	rest = strings.TrimSpace(strings.TrimPrefix(rest, fields[1]))
+
+	switch fields[0] {
+	case "block":
+		rule.Action = InterceptBlock
+		if rest != "" {
+			types, hasPrefix := strings.CutPrefix(rest, "[")
+			types, hasSuffix := strings.CutSuffix(types, "]")
+			if !hasPrefix || !hasSuffix {
+				return InterceptRule{}, fmt.Errorf("intercept rule %q: expected resource types like [image,font]", line)
+			}
+			for _, t := range strings.Split(types, ",") {
+				if t = strings.TrimSpace(t); t != "" {
+					rule.ResourceTypes = append(rule.ResourceTypes, strings.ToLower(t))
+				}
+			}
+		}
+	case "mock":
+		rule.Action = InterceptMock
+		status, body, _ := strings.Cut(rest, " ")
+		code, err := strconv.Atoi(status)
+		if err != nil {
+			return InterceptRule{}, fmt.Errorf("intercept rule %q: expected a status code after the URL", line)
+		}
+		rule.Status = code
+		rule.Body = strings.TrimSpace(body)
+		if strings.HasPrefix(rule.Body, "{") || strings.HasPrefix(rule.Body, "[") {
+			rule.ContentType = "application/json"
+		}
+	case "header", "headers":
+		rule.Action = InterceptHeaders
+		name, value, ok := strings.Cut(rest, ":")
+		if !ok {
+			return InterceptRule{}, fmt.Errorf("intercept rule %q: expected <Name>: <value> after the URL", line)
+		}
+		rule.Headers = map[string]string{strings.TrimSpace(name): strings.TrimSpace(value)}
+	default:
+		return InterceptRule{}, fmt.Errorf("intercept rule %q: unknown action %q (block, mock or header)", line, fields[0])
+	}
+
+	if err := rule.Validate(); err != nil {
+		return InterceptRule{}, fmt.Errorf("intercept rule %q: %w", line, err)
+	}
+	return rule, nil
+}
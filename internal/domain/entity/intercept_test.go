@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInterceptRule(t *testing.T) {
+	tests := []struct {
+		line string
+		want InterceptRule
+	}{
+		{"block * [Image, font]", InterceptRule{Action: InterceptBlock, ResourceTypes: []string{"image", "font"}}},
+		{"block analytics", InterceptRule{Action: InterceptBlock, URLPattern: "analytics"}},
+		{"mock /api/cart 503", InterceptRule{Action: InterceptMock, URLPattern: "/api/cart", Status: 503}},
+		{
+			"mock /api/items 200 {\"items\": []}",
+			InterceptRule{Action: InterceptMock, URLPattern: "/api/items", Status: 200, Body: `{"items": []}`, ContentType: "application/json"},
+		},
+		{"header ^https://api\\. Authorization: Bearer x:y", InterceptRule{Action: InterceptHeaders, URLPattern: `^https://api\.`, Headers: map[string]string{"Authorization": "Bearer x:y"}}},
+	}
+	for _, tt := range tests {
+		rule, err := ParseInterceptRule(tt.line)
+		require.NoError(t, err, tt.line)
+		assert.Equal(t, tt.want, rule, tt.line)
+	}
+}
+
+func TestParseInterceptRuleErrors(t *testing.T) {
+	for line, want := range map[string]string{
+		"block":                   "expected <action> <url-regexp>",
+		"block * image":           "expected resource types like [image,font]",
+		"mock /api/cart":          "expected a status code",
+		"header /api/ X-Flag":     "expected <Name>: <value>",
+		"redirect /a /b":          `unknown action "redirect"`,
+		"block ( [image]":         "invalid url_pattern",
+		"block * [pictures]":      `unknown resource type "pictures"`,
+		"header * Bad Name: true": `invalid header name "Bad Name"`,
+	} {
+		_, err := ParseInterceptRule(line)
+		assert.ErrorContains(t, err, want, line)
+	}
+}
+
+func TestInterceptRuleDescribe(t *testing.T) {
+	rule := InterceptRule{ID: "4", Action: InterceptHeaders, URLPattern: "/api/", Headers: map[string]string{"X-B": "1", "X-A": "2"}}
+	assert.Equal(t, "#4 headers /api/ +X-A,+X-B", rule.Describe())
+	assert.Equal(t, "mock 200 * [xhr]", InterceptRule{Action: InterceptMock, ResourceTypes: []string{"xhr"}}.Describe())
+}
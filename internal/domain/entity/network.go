@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// NetworkEntry is a request made by the page and, once it arrived, its
+// response. Listings leave the bodies out; they come with a lookup by ID.
+type NetworkEntry struct {
+	ID string `json:"id"`
+	// Method is GET, POST and so on; ResourceType is the lowercase CDP
+	// type: xhr, fetch or document.
+	Method       string    `json:"method"`
+	URL          string    `json:"url"`
+	ResourceType string    `json:"resource_type"`
+	StartedAt    time.Time `json:"started_at"`
+	RequestBody  string    `json:"request_body,omitempty"`
+
+	// Status is zero until the response headers arrive.
+	Status   int    `json:"status,omitempty"`
+	MIMEType string `json:"mime_type,omitempty"`
+	// Finished is set once the response was received in full or failed.
+	Finished   bool  `json:"finished"`
+	DurationMS int64 `json:"duration_ms,omitempty"`
+	// Error is the reason a request failed, e.g. net::ERR_BLOCKED_BY_CLIENT.
+	Error string `json:"error,omitempty"`
+
+	// BodyPending is set between the end of the response and the moment its
+	// body has been fetched from the browser.
+	BodyPending bool `json:"body_pending,omitempty"`
+	// BodySize is the length of the captured response body, zero when the
+	// body was not captured.
+	BodySize      int    `json:"body_size,omitempty"`
+	ResponseBody  string `json:"response_body,omitempty"`
+	BodyTruncated bool   `json:"body_truncated,omitempty"`
+}
+
+// NetworkFilter selects entries of the network log.
+type NetworkFilter struct {
+	// URLPattern is a regular expression matched against the URL.
+	URLPattern string
+	// Types limits the resource types; empty means all.
+	Types []string
+	// Limit keeps only the most recent entries; zero means all.
+	Limit int
+}
+
+func (f NetworkFilter) Validate() error {
+	if _, err := regexp.Compile(f.URLPattern); err != nil {
+		return fmt.Errorf("invalid url_pattern: %w", err)
+	}
+	if f.Limit < 0 {
+		return fmt.Errorf("limit must not be negative")
+	}
+	return nil
+}
+
+// Apply returns the entries matching the filter, keeping their order. It
+// expects a filter that passed Validate.
+func (f NetworkFilter) Apply(entries []NetworkEntry) []NetworkEntry {
+	pattern := regexp.MustCompile(f.URLPattern)
+	var matched []NetworkEntry
+	for _, entry := range entries {
+		if !pattern.MatchString(entry.URL) || !f.hasType(entry.ResourceType) {
+			continue
+		}
+		matched = append(matched, entry)
+	}
+	if f.Limit > 0 && len(matched) > f.Limit {
+		matched = matched[len(matched)-f.Limit:]
+	}
+	return matched
+}
+
+func (f NetworkFilter) hasType(resourceType string) bool {
+	if len(f.Types) == 0 {
+		return true
+	}
+	for _, t := range f.Types {
+		if t == resourceType {
+			return true
+		}
+	}
+	return false
+}
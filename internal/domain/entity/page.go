@@ -16,11 +16,12 @@ type UIElement struct {
 	Selector  string
 }
 
+// Screenshot is an encoded image. In JSON, Data is base64.
 type Screenshot struct {
-	Data   []byte
-	Format string
-	Width  int
-	Height int
+	Data   []byte `json:"data"`
+	Format string `json:"format"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
 }
 
 type PageContext struct {
@@ -117,6 +118,8 @@ type PageChanges struct {
 	ModalOpened     bool
 	ModalClosed     bool
 	ElementsRemoved int
+	// ConsoleErrors are the errors and exceptions logged during the action.
+	ConsoleErrors []ConsoleMessage
 }
 
 type ClickResult struct {
@@ -0,0 +1,57 @@
+package entity
+
+type ReplayStepStatus string
+
+const (
+	ReplayStepMatched  ReplayStepStatus = "matched"
+	ReplayStepDiverged ReplayStepStatus = "diverged"
+	// ReplayStepSkipped steps could not be replayed, e.g. because their
+	// arguments were redacted in the transcript.
+	ReplayStepSkipped ReplayStepStatus = "skipped"
+	// ReplayStepNotRun steps come after a divergence that stopped the replay.
+	ReplayStepNotRun ReplayStepStatus = "not_run"
+)
+
+type DivergenceKind string
+
+const (
+	// DivergenceError: the step failed, e.g. the element was not found,
+	// while it succeeded in the recorded run.
+	DivergenceError DivergenceKind = "error"
+	// DivergenceSucceeded: the step succeeded while it failed in the
+	// recorded run, so the page is likely in a different state.
+	DivergenceSucceeded DivergenceKind = "unexpected_success"
+	DivergenceURL       DivergenceKind = "url"
+	// DivergenceData: a tool that reads the page returned different data.
+	DivergenceData DivergenceKind = "data"
+)
+
+type ReplayStep struct {
+	Index     int              `json:"index"`
+	Agent     string           `json:"agent"`
+	Tool      string           `json:"tool"`
+	Arguments string           `json:"arguments"`
+	Status    ReplayStepStatus `json:"status"`
+	Kind      DivergenceKind   `json:"kind,omitempty"`
+	// Expected and Actual describe the divergence, or why a step was
+	// skipped.
+	Expected   string `json:"expected,omitempty"`
+	Actual     string `json:"actual,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// ReplayReport compares a replay of the browser tool calls of a transcript
+// with the recorded run.
+type ReplayReport struct {
+	Task       string       `json:"task"`
+	Steps      []ReplayStep `json:"steps"`
+	Matched    int          `json:"matched"`
+	Diverged   int          `json:"diverged"`
+	Skipped    int          `json:"skipped"`
+	DurationMS int64        `json:"duration_ms"`
+}
+
+// Passed reports whether every replayed step behaved as recorded.
+func (r *ReplayReport) Passed() bool {
+	return r.Diverged == 0
+}
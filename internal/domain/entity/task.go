@@ -1,5 +1,7 @@
 package entity
 
+import "time"
+
 type TaskStatus string
 
 const (
@@ -7,12 +9,24 @@ const (
 	TaskStatusRunning   TaskStatus = "running"
 	TaskStatusCompleted TaskStatus = "completed"
 	TaskStatusFailed    TaskStatus = "failed"
+	TaskStatusCanceled  TaskStatus = "canceled"
 )
 
+// Finished reports whether the status is terminal.
+func (s TaskStatus) Finished() bool {
+	return s == TaskStatusCompleted || s == TaskStatusFailed || s == TaskStatusCanceled
+}
+
 type Task struct {
-	ID          string
-	Description string
-	Status      TaskStatus
+	ID          string     `json:"id"`
+	Description string     `json:"description"`
+	Status      TaskStatus `json:"status"`
+	FinalAnswer string     `json:"final_answer,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	Iterations  int        `json:"iterations,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
 }
 
 type TaskResult struct {
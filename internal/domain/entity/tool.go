@@ -12,6 +12,12 @@ const (
 	ToolBrowserObserve      ToolName = "browser_observe"
 	ToolBrowserQueryElements ToolName = "browser_query_elements"
 	ToolBrowserSearch       ToolName = "browser_search"
+	ToolBrowserWait         ToolName = "browser_wait"
+	ToolBrowserNetwork      ToolName = "browser_network"
+	ToolBrowserIntercept    ToolName = "browser_intercept"
+	ToolBrowserConsole      ToolName = "browser_console"
+	ToolBrowserDialog       ToolName = "browser_dialog"
+	ToolBrowserEmulate      ToolName = "browser_emulate"
 
 	ToolRunAgent ToolName = "run_agent"
 
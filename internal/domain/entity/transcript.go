@@ -0,0 +1,65 @@
+package entity
+
+import "time"
+
+// Usage counts the tokens of LLM calls. CostUSD is zero when the price of
+// the model is unknown.
+type Usage struct {
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	TotalTokens      int     `json:"total_tokens"`
+	CostUSD          float64 `json:"cost_usd,omitempty"`
+}
+
+func (u *Usage) Add(other Usage) {
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+	u.TotalTokens += other.TotalTokens
+	u.CostUSD += other.CostUSD
+}
+
+// Transcript is the report of one run: every LLM turn of the orchestrator
+// and its sub-agents with the tool calls they made and what came back.
+type Transcript struct {
+	TaskID string `json:"task_id,omitempty"`
+	Task   string `json:"task"`
+	// Plan is the reasoning of the orchestrator's first turn, before any
+	// tool was called.
+	Plan        string           `json:"plan,omitempty"`
+	Status      TaskStatus       `json:"status,omitempty"`
+	FinalAnswer string           `json:"final_answer,omitempty"`
+	Error       string           `json:"error,omitempty"`
+	StartedAt   time.Time        `json:"started_at"`
+	FinishedAt  time.Time        `json:"finished_at"`
+	Turns       []TranscriptTurn `json:"turns"`
+	Usage       Usage            `json:"usage"`
+}
+
+type TranscriptTurn struct {
+	// Agent is "orchestrator" or the sub-agent chain, e.g. "navigation".
+	Agent     string               `json:"agent"`
+	Time      time.Time            `json:"time"`
+	Thinking  string               `json:"thinking,omitempty"`
+	Content   string               `json:"content,omitempty"`
+	ToolCalls []TranscriptToolCall `json:"tool_calls,omitempty"`
+	// URL is the page the tool calls of the turn ended on.
+	URL   string `json:"url,omitempty"`
+	Usage Usage  `json:"usage"`
+	// Screenshot shows the page after the tool calls of the turn, or at the
+	// end of the run for the final turn. Only orchestrator turns have one.
+	Screenshot *TranscriptScreenshot `json:"screenshot,omitempty"`
+}
+
+// TranscriptScreenshot is an image of the page; Data is base64 in JSON.
+type TranscriptScreenshot struct {
+	Format string `json:"format"`
+	Data   []byte `json:"data"`
+}
+
+type TranscriptToolCall struct {
+	ID        string `json:"id,omitempty"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+	Result    string `json:"result,omitempty"`
+	IsError   bool   `json:"is_error,omitempty"`
+}
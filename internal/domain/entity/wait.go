@@ -0,0 +1,117 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// DefaultNetworkIdle is how long the network must stay quiet when IdleMS is
+// not set.
+const DefaultNetworkIdle = 500 * time.Millisecond
+
+type WaitCondition string
+
+const (
+	// WaitVisible waits until an element matching Selector is visible.
+	WaitVisible WaitCondition = "visible"
+	// WaitHidden waits until no element matching Selector is visible,
+	// including when there is none at all.
+	WaitHidden WaitCondition = "hidden"
+	// WaitText waits until the page text contains Text.
+	WaitText WaitCondition = "text"
+	// WaitURL waits until the current URL matches the regular expression
+	// URLPattern.
+	WaitURL WaitCondition = "url"
+	// WaitNetworkIdle waits until no request has been in flight for
+	// IdlePeriod.
+	WaitNetworkIdle WaitCondition = "network_idle"
+	// WaitScript waits until the JavaScript expression Script is truthy.
+	WaitScript WaitCondition = "script"
+)
+
+type WaitRequest struct {
+	Condition  WaitCondition `json:"condition"`
+	Selector   string        `json:"selector,omitempty"`
+	Text       string        `json:"text,omitempty"`
+	URLPattern string        `json:"url_pattern,omitempty"`
+	IdleMS     int           `json:"idle_ms,omitempty"`
+	Script     string        `json:"script,omitempty"`
+	// TimeoutMS bounds the wait; zero means the browser's default timeout.
+	TimeoutMS int `json:"timeout_ms,omitempty"`
+}
+
+// Validate checks that the field the condition needs is set.
+func (r WaitRequest) Validate() error {
+	switch r.Condition {
+	case WaitVisible, WaitHidden:
+		if r.Selector == "" {
+			return fmt.Errorf("selector is required for condition %q", r.Condition)
+		}
+	case WaitText:
+		if r.Text == "" {
+			return errors.New("text is required for condition \"text\"")
+		}
+	case WaitURL:
+		if r.URLPattern == "" {
+			return errors.New("url_pattern is required for condition \"url\"")
+		}
+		if _, err := regexp.Compile(r.URLPattern); err != nil {
+			return fmt.Errorf("invalid url_pattern: %w", err)
+		}
+	case WaitNetworkIdle:
+		if r.IdleMS < 0 {
+			return errors.New("idle_ms must not be negative")
+		}
+	case WaitScript:
+		if r.Script == "" {
+			return errors.New("script is required for condition \"script\"")
+		}
+	default:
+		return fmt.Errorf("unknown wait condition %q", r.Condition)
+	}
+	if r.TimeoutMS < 0 {
+		return errors.New("timeout_ms must not be negative")
+	}
+	return nil
+}
+
+// IdlePeriod is IdleMS, or DefaultNetworkIdle when it is not set.
+func (r WaitRequest) IdlePeriod() time.Duration {
+	if r.IdleMS > 0 {
+		return time.Duration(r.IdleMS) * time.Millisecond
+	}
+	return DefaultNetworkIdle
+}
+
+// Describe names what is waited for, e.g. `element "#list" visible`.
+func (r WaitRequest) Describe() string {
+	switch r.Condition {
+	case WaitVisible:
+		return fmt.Sprintf("element %q visible", r.Selector)
+	case WaitHidden:
+		return fmt.Sprintf("element %q hidden", r.Selector)
+	case WaitText:
+		return fmt.Sprintf("text %q present", r.Text)
+	case WaitURL:
+		return fmt.Sprintf("URL matching %q", r.URLPattern)
+	case WaitNetworkIdle:
+		return fmt.Sprintf("network idle for %dms", r.IdlePeriod().Milliseconds())
+	case WaitScript:
+		return fmt.Sprintf("script %q true", r.Script)
+	default:
+		return string(r.Condition)
+	}
+}
+
+type WaitResult struct {
+	// Met is false when the timeout expired first.
+	Met       bool
+	ElapsedMS int64
+	// URL is the page address when the wait ended.
+	URL string
+	// ScriptError is the last evaluation error of a condition that was not
+	// met, e.g. a syntax error in Script.
+	ScriptError string
+}
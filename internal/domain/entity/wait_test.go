@@ -0,0 +1,18 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitRequestIdlePeriod(t *testing.T) {
+	req := WaitRequest{Condition: WaitNetworkIdle}
+	assert.Equal(t, DefaultNetworkIdle, req.IdlePeriod())
+	assert.Equal(t, "network idle for 500ms", req.Describe())
+
+	req.IdleMS = 1200
+	assert.Equal(t, 1200*time.Millisecond, req.IdlePeriod())
+	assert.Equal(t, "network idle for 1200ms", req.Describe())
+}
@@ -0,0 +1,91 @@
+package entity
+
+import "time"
+
+type WorkflowAction string
+
+const (
+	WorkflowNavigate   WorkflowAction = "navigate"
+	WorkflowClick      WorkflowAction = "click"
+	WorkflowFill       WorkflowAction = "fill"
+	WorkflowPressEnter WorkflowAction = "press_enter"
+	WorkflowScroll     WorkflowAction = "scroll"
+	// WorkflowQuery reads elements of the page; its results make up the
+	// output of a workflow run.
+	WorkflowQuery WorkflowAction = "query"
+)
+
+// Workflow is the action sequence of a successful run, executed without the
+// LLM. Step values may contain {{var:name}} placeholders for Variables and
+// {{secret:name}} placeholders resolved from the secret store.
+type Workflow struct {
+	Name      string             `json:"name"`
+	Task      string             `json:"task,omitempty"`
+	CreatedAt time.Time          `json:"created_at"`
+	Variables []WorkflowVariable `json:"variables,omitempty"`
+	Steps     []WorkflowStep     `json:"steps"`
+}
+
+type WorkflowVariable struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Default is used when no value is given for the run.
+	Default string `json:"default,omitempty"`
+	// Secret variables come from the secret store and have no default.
+	Secret bool `json:"secret,omitempty"`
+}
+
+type WorkflowStep struct {
+	Action WorkflowAction `json:"action"`
+	// Agent is the sub-agent that made the call in the recorded run; it
+	// takes over when the step fails.
+	Agent     string            `json:"agent,omitempty"`
+	URL       string            `json:"url,omitempty"`
+	Selectors []string          `json:"selectors,omitempty"`
+	Selector  string            `json:"selector,omitempty"`
+	Text      string            `json:"text,omitempty"`
+	Fields    map[string]string `json:"fields,omitempty"`
+	Direction string            `json:"direction,omitempty"`
+	Extract   map[string]string `json:"extract,omitempty"`
+	Limit     int               `json:"limit,omitempty"`
+}
+
+type WorkflowStepStatus string
+
+const (
+	WorkflowStepDone WorkflowStepStatus = "done"
+	// WorkflowStepRecovered steps failed and were completed by the LLM.
+	WorkflowStepRecovered WorkflowStepStatus = "recovered"
+	WorkflowStepFailed    WorkflowStepStatus = "failed"
+	WorkflowStepNotRun    WorkflowStepStatus = "not_run"
+)
+
+type WorkflowStepResult struct {
+	Index  int                `json:"index"`
+	Action WorkflowAction     `json:"action"`
+	Status WorkflowStepStatus `json:"status"`
+	// Output holds the query results, or the sub-agent's answer for a
+	// recovered step.
+	Output     string `json:"output,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+type WorkflowRunResult struct {
+	Workflow  string               `json:"workflow"`
+	Steps     []WorkflowStepResult `json:"steps"`
+	Recovered int                  `json:"recovered"`
+	// Output joins the results of the query steps.
+	Output     string `json:"output,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// Succeeded reports whether every step was done, directly or by the LLM.
+func (r *WorkflowRunResult) Succeeded() bool {
+	for _, step := range r.Steps {
+		if step.Status == WorkflowStepFailed || step.Status == WorkflowStepNotRun {
+			return false
+		}
+	}
+	return true
+}
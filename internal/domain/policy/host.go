@@ -0,0 +1,50 @@
+package policy
+
+import "strings"
+
+// MatchHost reports whether host matches pattern. Patterns are case-insensitive
+// host names; "*.example.com" matches example.com and any of its subdomains,
+// and "*" matches every host. A port in host is ignored unless pattern has
+// one too, as in "localhost:8080".
+func MatchHost(pattern, host string) bool {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	host = strings.ToLower(strings.TrimSpace(host))
+
+	host, hostPort := splitPort(host)
+	pattern, patternPort := splitPort(pattern)
+	if patternPort != "" && patternPort != hostPort {
+		return false
+	}
+
+	if pattern == "" || host == "" {
+		return false
+	}
+	if pattern == "*" {
+		return true
+	}
+
+	if strings.HasPrefix(pattern, "*.") {
+		base := pattern[2:]
+		return host == base || strings.HasSuffix(host, "."+base)
+	}
+
+	return host == pattern
+}
+
+// MatchAnyHost reports whether host matches at least one of patterns.
+func MatchAnyHost(patterns []string, host string) bool {
+	for _, p := range patterns {
+		if MatchHost(p, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitPort cuts the port off host; IPv6 literals keep their colons.
+func splitPort(host string) (string, string) {
+	if i := strings.LastIndex(host, ":"); i != -1 && !strings.Contains(host[i:], "]") {
+		return host[:i], host[i+1:]
+	}
+	return host, ""
+}
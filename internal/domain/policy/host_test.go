@@ -0,0 +1,46 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchHost(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		host     string
+		expected bool
+	}{
+		{"Exact match", "example.com", "example.com", true},
+		{"Case insensitive", "Example.COM", "example.com", true},
+		{"Port ignored", "example.com", "example.com:8080", true},
+		{"Pattern port", "localhost:8080", "localhost:8080", true},
+		{"Pattern port differs", "localhost:8080", "localhost:3000", false},
+		{"Pattern port without host port", "localhost:8080", "localhost", false},
+		{"Different host", "example.com", "example.org", false},
+		{"Subdomain without wildcard", "example.com", "www.example.com", false},
+		{"Wildcard subdomain", "*.example.com", "mail.example.com", true},
+		{"Wildcard matches apex", "*.example.com", "example.com", true},
+		{"Wildcard suffix trick", "*.example.com", "badexample.com", false},
+		{"Match all", "*", "anything.test", true},
+		{"Empty pattern", "", "example.com", false},
+		{"Empty host", "example.com", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, MatchHost(tt.pattern, tt.host))
+		})
+	}
+}
+
+func TestMatchAnyHost(t *testing.T) {
+	patterns := []string{"localhost", "*.internal"}
+
+	assert.True(t, MatchAnyHost(patterns, "localhost:3000"))
+	assert.True(t, MatchAnyHost(patterns, "api.internal"))
+	assert.False(t, MatchAnyHost(patterns, "example.com"))
+	assert.False(t, MatchAnyHost(nil, "localhost"))
+}
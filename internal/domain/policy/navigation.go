@@ -0,0 +1,157 @@
+package policy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+var ErrNavigationBlocked = errors.New("navigation blocked by policy")
+
+// Navigation restricts which URLs the browser may load. The zero value allows
+// everything, matching the behaviour without a policy.
+type Navigation struct {
+	// AllowedHosts, when not empty, is the exhaustive list of host patterns
+	// that may be opened. See MatchHost for the pattern syntax.
+	AllowedHosts []string
+	DeniedHosts  []string
+	// BlockedPaths are path prefixes that may not be opened on any host. A
+	// pattern blocks the path it matches and everything below it; segments
+	// may be globs ("/*/delete") and "**" matches any number of segments
+	// ("/**/delete"). A trailing "/*" also blocks the bare prefix, so
+	// "/admin/*" blocks "/admin" as well.
+	BlockedPaths   []string
+	DenyFileAccess bool
+}
+
+// Violation describes why a URL was rejected. Its message is JSON so agents
+// can tell which rule fired.
+type Violation struct {
+	URL    string `json:"url"`
+	Rule   string `json:"rule"`
+	Reason string `json:"reason"`
+}
+
+func (v *Violation) Error() string {
+	data, _ := json.Marshal(v)
+	return fmt.Sprintf("%s: %s", ErrNavigationBlocked, data)
+}
+
+func (v *Violation) Unwrap() error {
+	return ErrNavigationBlocked
+}
+
+// Active reports whether the policy restricts anything at all.
+func (n Navigation) Active() bool {
+	return len(n.AllowedHosts) > 0 || len(n.DeniedHosts) > 0 || len(n.BlockedPaths) > 0 || n.DenyFileAccess
+}
+
+// Check returns a Violation if rawURL may not be opened, nil otherwise. A URL
+// that cannot be parsed is denied by any active policy.
+func (n Navigation) Check(rawURL string) *Violation {
+	if !n.Active() {
+		return nil
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return &Violation{URL: rawURL, Rule: "invalid_url", Reason: "URL could not be parsed"}
+	}
+
+	switch strings.ToLower(parsed.Scheme) {
+	case "about", "data", "blob", "chrome-error":
+		return nil
+	case "file":
+		if n.DenyFileAccess {
+			return &Violation{URL: rawURL, Rule: "deny_file_access", Reason: "file:// URLs are not allowed"}
+		}
+		return nil
+	}
+
+	host := parsed.Host
+
+	for _, pattern := range n.DeniedHosts {
+		if MatchHost(pattern, host) {
+			return &Violation{
+				URL:    rawURL,
+				Rule:   "denied_hosts:" + pattern,
+				Reason: fmt.Sprintf("host %q is denied", parsed.Hostname()),
+			}
+		}
+	}
+
+	if len(n.AllowedHosts) > 0 && !MatchAnyHost(n.AllowedHosts, host) {
+		return &Violation{
+			URL:    rawURL,
+			Rule:   "allowed_hosts",
+			Reason: fmt.Sprintf("host %q is not in the allowlist", parsed.Hostname()),
+		}
+	}
+
+	urlPath := normalizePath(parsed.Path)
+	for _, pattern := range n.BlockedPaths {
+		if matchPath(pattern, urlPath) {
+			return &Violation{
+				URL:    rawURL,
+				Rule:   "blocked_paths:" + pattern,
+				Reason: fmt.Sprintf("path %q is blocked", urlPath),
+			}
+		}
+	}
+
+	return nil
+}
+
+// normalizePath makes equivalent spellings of a path compare equal: it is
+// taken unescaped, cleaned of "." and ".." segments and repeated slashes, and
+// lowercased, so "/ADMIN", "/%61dmin" and "/x/../admin" all read "/admin".
+func normalizePath(urlPath string) string {
+	return strings.ToLower(path.Clean("/" + urlPath))
+}
+
+// matchPath reports whether a normalized path, or any path above it,
+// matches a blocked path pattern. Patterns are matched case-insensitively
+// segment by segment, so a "*" never spans a "/" but "**" does.
+func matchPath(pattern, urlPath string) bool {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	if pattern == "" {
+		return false
+	}
+	return matchSegments(splitPath(pattern), splitPath(urlPath))
+}
+
+func splitPath(p string) []string {
+	p = strings.Trim(p, "/")
+	if p == "" {
+		return nil
+	}
+	return strings.Split(p, "/")
+}
+
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		// Everything below a blocked path is blocked too.
+		return true
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		// Only wildcards are left: "/admin/*" covers "/admin" itself.
+		for _, p := range pattern {
+			if p != "*" && p != "**" {
+				return false
+			}
+		}
+		return true
+	}
+	ok, err := path.Match(pattern[0], segments[0])
+	return err == nil && ok && matchSegments(pattern[1:], segments[1:])
+}
@@ -0,0 +1,80 @@
+package policy
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNavigationCheck_ZeroValueAllowsEverything(t *testing.T) {
+	var n Navigation
+
+	assert.False(t, n.Active())
+	assert.Nil(t, n.Check("https://example.com/admin"))
+	assert.Nil(t, n.Check("file:///etc/passwd"))
+}
+
+func TestNavigationCheck(t *testing.T) {
+	n := Navigation{
+		AllowedHosts:   []string{"*.example.com", "localhost"},
+		DeniedHosts:    []string{"admin.example.com"},
+		BlockedPaths:   []string{"/account/delete", "/*/settings", "/admin/*", "/**/delete"},
+		DenyFileAccess: true,
+	}
+
+	tests := []struct {
+		name    string
+		url     string
+		blocked bool
+		rule    string
+	}{
+		{"Allowed host", "https://www.example.com/page", false, ""},
+		{"Allowed apex", "https://example.com/", false, ""},
+		{"Localhost with port", "http://localhost:8080/", false, ""},
+		{"Denied wins over allowed", "https://admin.example.com/", true, "denied_hosts:admin.example.com"},
+		{"Not in allowlist", "https://evil.test/", true, "allowed_hosts"},
+		{"Blocked prefix", "https://example.com/account/delete/confirm", true, "blocked_paths:/account/delete"},
+		{"Prefix respects segments", "https://example.com/account/deleted-items", false, ""},
+		{"Blocked glob", "https://example.com/user/settings", true, "blocked_paths:/*/settings"},
+		{"Glob blocks nested path", "https://example.com/user/settings/2fa", true, "blocked_paths:/*/settings"},
+		{"Glob star stays in its segment", "https://example.com/a/b/settings", false, ""},
+		{"Trailing star blocks child", "https://example.com/admin/users", true, "blocked_paths:/admin/*"},
+		{"Trailing star blocks nested path", "https://example.com/admin/users/1", true, "blocked_paths:/admin/*"},
+		{"Trailing star blocks bare prefix", "https://example.com/admin", true, "blocked_paths:/admin/*"},
+		{"Trailing star blocks prefix with slash", "https://example.com/admin/", true, "blocked_paths:/admin/*"},
+		{"Trailing star respects segments", "https://example.com/administrator", false, ""},
+		{"Double star spans segments", "https://example.com/x/y/delete", true, "blocked_paths:/**/delete"},
+		{"Double star blocks nested path", "https://example.com/x/delete/1", true, "blocked_paths:/**/delete"},
+		{"Double star matches zero segments", "https://example.com/delete", true, "blocked_paths:/**/delete"},
+		{"Double star respects segments", "https://example.com/x/undelete", false, ""},
+		{"File access denied", "file:///tmp/page.html", true, "deny_file_access"},
+		{"About blank allowed", "about:blank", false, ""},
+		{"Uppercase path", "https://example.com/ACCOUNT/Delete", true, "blocked_paths:/account/delete"},
+		{"Escaped path", "https://example.com/%61ccount/delete", true, "blocked_paths:/account/delete"},
+		{"Dot segments", "https://example.com/help/../account//delete", true, "blocked_paths:/account/delete"},
+		{"Unparseable URL", "https://example.com/%zz", true, "invalid_url"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := n.Check(tt.url)
+			if !tt.blocked {
+				assert.Nil(t, v)
+				return
+			}
+			require.NotNil(t, v)
+			assert.Equal(t, tt.rule, v.Rule)
+			assert.Equal(t, tt.url, v.URL)
+		})
+	}
+}
+
+func TestViolationError(t *testing.T) {
+	v := &Violation{URL: "https://evil.test/", Rule: "allowed_hosts", Reason: "host is not in the allowlist"}
+
+	assert.True(t, errors.Is(v, ErrNavigationBlocked))
+	assert.Contains(t, v.Error(), `"rule":"allowed_hosts"`)
+	assert.Contains(t, v.Error(), `"url":"https://evil.test/"`)
+}
@@ -0,0 +1,63 @@
+// Package artifacts keeps the files a run produces for the user in one
+// directory, next to the run log.
+package artifacts
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"browser-agent/internal/application/port/output"
+)
+
+var _ output.ArtifactPort = (*Store)(nil)
+
+var unsafeChars = regexp.MustCompile(`[^\p{L}\p{N}._-]+`)
+
+// Store writes artifacts into dir, which is created with the first one.
+type Store struct {
+	dir string
+}
+
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Save keeps the extension of name and turns the rest into a safe file
+// name, adding _1, _2... when the file exists.
+func (s *Store) Save(name string, data []byte) (string, error) {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return "", fmt.Errorf("create artifact directory: %w", err)
+	}
+
+	ext := filepath.Ext(name)
+	base := strings.Trim(unsafeChars.ReplaceAllString(strings.TrimSuffix(filepath.Base(name), ext), "_"), "_.")
+	if base == "" {
+		base = "artifact"
+	}
+	ext = unsafeChars.ReplaceAllString(ext, "")
+
+	path := filepath.Join(s.dir, base+ext)
+	for i := 1; ; i++ {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if errors.Is(err, os.ErrExist) {
+			path = filepath.Join(s.dir, fmt.Sprintf("%s_%d%s", base, i, ext))
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("create artifact: %w", err)
+		}
+		if _, err := file.Write(data); err != nil {
+			file.Close()
+			return "", fmt.Errorf("write artifact: %w", err)
+		}
+		return path, file.Close()
+	}
+}
@@ -0,0 +1,34 @@
+package artifacts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreSave(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "run")
+	store := NewStore(dir)
+
+	path, err := store.Save("invoice.pdf", []byte("first"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "invoice.pdf"), path)
+
+	path, err = store.Save("invoice.pdf", []byte("second"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "invoice_1.pdf"), path)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(data))
+
+	path, err = store.Save("../Счёт №42 (май).png", nil)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "Счёт_42_май.png"), path)
+
+	path, err = store.Save(".png", nil)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "artifact.png"), path)
+}
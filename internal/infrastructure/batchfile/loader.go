@@ -0,0 +1,149 @@
+package batchfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"browser-agent/internal/domain/entity"
+)
+
+// idPattern keeps task ids usable as file names: they name the transcript
+// written for each task.
+var idPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// File is the YAML layout of a task file. Settings in Defaults apply to every
+// task that does not override them; scripted answers are combined, with the
+// task's own answers tried first.
+type File struct {
+	Defaults taskSpec   `yaml:"defaults"`
+	Tasks    []taskSpec `yaml:"tasks"`
+}
+
+type taskSpec struct {
+	ID            string                  `yaml:"id"`
+	Task          string                  `yaml:"task"`
+	StartURL      string                  `yaml:"start_url"`
+	MaxIterations int                     `yaml:"max_iterations"`
+	Timeout       string                  `yaml:"timeout"`
+	OutputSchema  map[string]any          `yaml:"output_schema"`
+	Answers       []entity.ScriptedAnswer `yaml:"answers"`
+	Approvals     entity.ApprovalMode     `yaml:"approvals"`
+}
+
+func Load(path string) ([]entity.BatchTask, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read task file: %w", err)
+	}
+	return Parse(data)
+}
+
+func Parse(data []byte) ([]entity.BatchTask, error) {
+	var file File
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("parse task file: %w", err)
+	}
+	if len(file.Tasks) == 0 {
+		return nil, errors.New("task file has no tasks")
+	}
+
+	seen := make(map[string]bool, len(file.Tasks))
+	tasks := make([]entity.BatchTask, 0, len(file.Tasks))
+	for i, spec := range file.Tasks {
+		task, err := build(spec, file.Defaults)
+		if err != nil {
+			return nil, fmt.Errorf("task %d: %w", i+1, err)
+		}
+		if task.ID == "" {
+			task.ID = fmt.Sprintf("task-%d", i+1)
+		}
+		if seen[task.ID] {
+			return nil, fmt.Errorf("task %d: duplicate id %q", i+1, task.ID)
+		}
+		seen[task.ID] = true
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+func build(spec, defaults taskSpec) (entity.BatchTask, error) {
+	task := entity.BatchTask{
+		ID:            strings.TrimSpace(spec.ID),
+		Task:          strings.TrimSpace(spec.Task),
+		StartURL:      firstNonEmpty(spec.StartURL, defaults.StartURL),
+		MaxIterations: spec.MaxIterations,
+		Script: entity.InteractionScript{
+			Answers:   append(append([]entity.ScriptedAnswer{}, spec.Answers...), defaults.Answers...),
+			Approvals: entity.ApprovalMode(firstNonEmpty(string(spec.Approvals), string(defaults.Approvals))),
+		},
+	}
+	if task.ID != "" && (!idPattern.MatchString(task.ID) || task.ID == "." || task.ID == "..") {
+		return task, fmt.Errorf("invalid id %q (use letters, digits, '.', '_' and '-')", task.ID)
+	}
+	if task.Task == "" {
+		return task, errors.New("task text is empty")
+	}
+	if task.MaxIterations <= 0 {
+		task.MaxIterations = defaults.MaxIterations
+	}
+
+	switch task.Script.Approvals {
+	case "", entity.ApprovalDeny, entity.ApprovalApprove, entity.ApprovalFail:
+	default:
+		return task, fmt.Errorf("unknown approvals mode %q (want deny, approve or fail)", task.Script.Approvals)
+	}
+
+	if timeout := firstNonEmpty(spec.Timeout, defaults.Timeout); timeout != "" {
+		d, err := time.ParseDuration(timeout)
+		if err != nil {
+			return task, fmt.Errorf("invalid timeout %q: %w", timeout, err)
+		}
+		task.Timeout = d
+	}
+
+	schema := spec.OutputSchema
+	if schema == nil {
+		schema = defaults.OutputSchema
+	}
+	if schema != nil {
+		normalized, err := normalizeSchema(schema)
+		if err != nil {
+			return task, fmt.Errorf("invalid output_schema: %w", err)
+		}
+		task.OutputSchema = normalized
+	}
+	return task, nil
+}
+
+// normalizeSchema round-trips the schema through JSON so nested values have
+// the same types as a JSON-decoded answer.
+func normalizeSchema(schema map[string]any) (map[string]any, error) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	var normalized map[string]any
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
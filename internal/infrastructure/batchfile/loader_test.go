@@ -0,0 +1,132 @@
+package batchfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"browser-agent/internal/domain/entity"
+)
+
+const sampleFile = `
+defaults:
+  max_iterations: 20
+  timeout: 5m
+  answers:
+    - match: "confirm"
+      answer: "yes"
+tasks:
+  - id: price
+    task: Find the price of the first item
+    start_url: https://example.com/shop
+    max_iterations: 10
+    approvals: approve
+    output_schema:
+      type: object
+      required: [price]
+      properties:
+        price: {type: number}
+        currency: {enum: [EUR, USD]}
+    answers:
+      - match: "which city"
+        answer: Berlin
+  - task: Open example.com
+`
+
+func TestParse(t *testing.T) {
+	tasks, err := Parse([]byte(sampleFile))
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+
+	price := tasks[0]
+	assert.Equal(t, "price", price.ID)
+	assert.Equal(t, "https://example.com/shop", price.StartURL)
+	assert.Equal(t, 10, price.MaxIterations)
+	assert.Equal(t, 5*time.Minute, price.Timeout)
+	assert.Equal(t, entity.ApprovalApprove, price.Script.Approvals)
+	assert.Equal(t, []entity.ScriptedAnswer{
+		{Match: "which city", Answer: "Berlin"},
+		{Match: "confirm", Answer: "yes"},
+	}, price.Script.Answers)
+	assert.Equal(t, []any{"price"}, price.OutputSchema["required"])
+
+	second := tasks[1]
+	assert.Equal(t, "task-2", second.ID)
+	assert.Equal(t, 20, second.MaxIterations)
+	assert.Nil(t, second.OutputSchema)
+}
+
+func TestParse_Errors(t *testing.T) {
+	cases := map[string]string{
+		"no tasks":      "tasks: []",
+		"empty task":    "tasks:\n  - id: a",
+		"duplicate id":  "tasks:\n  - {id: a, task: x}\n  - {id: a, task: y}",
+		"bad timeout":   "tasks:\n  - {task: x, timeout: soon}",
+		"bad approvals": "tasks:\n  - {task: x, approvals: maybe}",
+		"unknown field": "tasks:\n  - {task: x, url: y}",
+	}
+	for name, data := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse([]byte(data))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParse_RejectsIDsThatAreNotFileNames(t *testing.T) {
+	for _, id := range []string{"../../x", "a/b", `a\b`, "..", ".", "two words"} {
+		t.Run(id, func(t *testing.T) {
+			_, err := Parse([]byte(fmt.Sprintf("tasks:\n  - {id: %q, task: x}", id)))
+			assert.ErrorContains(t, err, "invalid id")
+		})
+	}
+
+	tasks, err := Parse([]byte("tasks:\n  - {id: search_v2.1-eu, task: x}"))
+	require.NoError(t, err)
+	assert.Equal(t, "search_v2.1-eu", tasks[0].ID)
+}
+
+func TestResultWriter(t *testing.T) {
+	dir := t.TempDir()
+	result := entity.BatchResult{
+		ID:         "price",
+		Task:       "Find the price",
+		Status:     entity.TaskStatusCompleted,
+		Output:     map[string]any{"price": 12.5},
+		Iterations: 3,
+		DurationMS: 1500,
+	}
+
+	jsonlPath := filepath.Join(dir, "results.jsonl")
+	w, err := NewResultWriter(jsonlPath)
+	require.NoError(t, err)
+	require.NoError(t, w.Write(result))
+	require.NoError(t, w.Close())
+
+	data, err := os.ReadFile(jsonlPath)
+	require.NoError(t, err)
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "completed", decoded["status"])
+	assert.Equal(t, float64(1500), decoded["duration_ms"])
+
+	csvPath := filepath.Join(dir, "results.csv")
+	w, err = NewResultWriter(csvPath)
+	require.NoError(t, err)
+	require.NoError(t, w.Write(result))
+	require.NoError(t, w.Close())
+
+	data, err = os.ReadFile(csvPath)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	assert.True(t, strings.HasPrefix(lines[0], "id,task,status"))
+	assert.Contains(t, lines[1], `"{""price"":12.5}"`)
+}
@@ -0,0 +1,86 @@
+package batchfile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+
+	"browser-agent/internal/domain/entity"
+)
+
+// SuiteFile is the YAML layout of an eval suite: a task file whose tasks
+// carry an expect section.
+type SuiteFile struct {
+	Defaults taskSpec   `yaml:"defaults"`
+	Cases    []caseSpec `yaml:"tasks"`
+}
+
+type caseSpec struct {
+	taskSpec `yaml:",inline"`
+	Expect   entity.EvalExpectation `yaml:"expect"`
+}
+
+func LoadSuite(path string) ([]entity.EvalCase, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read eval suite: %w", err)
+	}
+	return ParseSuite(data)
+}
+
+// ParseSuite reads the cases of a suite. Every case must expect something,
+// otherwise any answer would pass.
+func ParseSuite(data []byte) ([]entity.EvalCase, error) {
+	var file SuiteFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("parse eval suite: %w", err)
+	}
+	if len(file.Cases) == 0 {
+		return nil, errors.New("eval suite has no tasks")
+	}
+
+	seen := make(map[string]bool, len(file.Cases))
+	cases := make([]entity.EvalCase, 0, len(file.Cases))
+	for i, spec := range file.Cases {
+		task, err := build(spec.taskSpec, file.Defaults)
+		if err != nil {
+			return nil, fmt.Errorf("task %d: %w", i+1, err)
+		}
+		if task.ID == "" {
+			return nil, fmt.Errorf("task %d: id is required in an eval suite", i+1)
+		}
+		if seen[task.ID] {
+			return nil, fmt.Errorf("task %d: duplicate id %q", i+1, task.ID)
+		}
+		seen[task.ID] = true
+
+		if err := validateExpectation(spec.Expect); err != nil {
+			return nil, fmt.Errorf("task %s: %w", task.ID, err)
+		}
+		cases = append(cases, entity.EvalCase{BatchTask: task, Expect: spec.Expect})
+	}
+	return cases, nil
+}
+
+func validateExpectation(expect entity.EvalExpectation) error {
+	if len(expect.AnswerContains) == 0 && expect.AnswerMatches == "" && expect.URLContains == "" && len(expect.DOM) == 0 {
+		return errors.New("expect has no checks")
+	}
+	if expect.AnswerMatches != "" {
+		if _, err := regexp.Compile(expect.AnswerMatches); err != nil {
+			return fmt.Errorf("invalid answer_matches: %w", err)
+		}
+	}
+	for _, check := range expect.DOM {
+		if check.Selector == "" {
+			return errors.New("dom check without a selector")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package batchfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"browser-agent/internal/domain/entity"
+)
+
+func TestParseSuite(t *testing.T) {
+	cases, err := ParseSuite([]byte(`
+defaults:
+  max_iterations: 15
+  approvals: approve
+tasks:
+  - id: cart
+    task: Add the toaster to the cart
+    start_url: /eval/shop.html
+    expect:
+      dom:
+        - selector: "#cart-items li"
+          text_contains: Toaster
+        - selector: .error
+          absent: true
+  - id: price
+    task: Find the price
+    expect:
+      answer_contains: ["1 190"]
+      url_contains: shop
+`))
+	require.NoError(t, err)
+	require.Len(t, cases, 2)
+
+	assert.Equal(t, "cart", cases[0].ID)
+	assert.Equal(t, "/eval/shop.html", cases[0].StartURL)
+	assert.Equal(t, 15, cases[0].MaxIterations)
+	assert.Equal(t, entity.ApprovalApprove, cases[0].Script.Approvals)
+	assert.Equal(t, []entity.DOMCheck{
+		{Selector: "#cart-items li", TextContains: "Toaster"},
+		{Selector: ".error", Absent: true},
+	}, cases[0].Expect.DOM)
+	assert.Equal(t, []string{"1 190"}, cases[1].Expect.AnswerContains)
+}
+
+func TestParseSuite_Errors(t *testing.T) {
+	tests := map[string]string{
+		"no checks":   "tasks:\n  - id: a\n    task: x\n",
+		"id required": "tasks:\n  - task: x\n    expect: {url_contains: y}\n",
+		"bad regexp":  "tasks:\n  - id: a\n    task: x\n    expect: {answer_matches: '('}\n",
+		"typo":        "tasks:\n  - id: a\n    task: x\n    expect: {answer_contain: [y]}\n",
+	}
+	for name, suite := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseSuite([]byte(suite))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadSuite_Bundled(t *testing.T) {
+	cases, err := LoadSuite("../../../test/integration/testdata/eval/suite.yaml")
+	require.NoError(t, err)
+	assert.NotEmpty(t, cases)
+}
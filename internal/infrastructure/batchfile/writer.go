@@ -0,0 +1,91 @@
+package batchfile
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/domain/entity"
+)
+
+var _ output.BatchResultWriter = (*ResultWriter)(nil)
+
+var csvHeader = []string{"id", "task", "status", "final_answer", "output", "error", "iterations", "started_at", "duration_ms"}
+
+// ResultWriter writes batch results as JSON lines, or as CSV when the file
+// name ends in .csv. Every result is flushed immediately so a crashed batch
+// keeps the results of finished tasks.
+type ResultWriter struct {
+	file *os.File
+	csv  *csv.Writer
+	json *json.Encoder
+}
+
+func NewResultWriter(path string) (*ResultWriter, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("create results file: %w", err)
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		w := &ResultWriter{file: file, csv: csv.NewWriter(file)}
+		if err := w.writeCSV(csvHeader); err != nil {
+			file.Close()
+			return nil, err
+		}
+		return w, nil
+	}
+
+	encoder := json.NewEncoder(file)
+	encoder.SetEscapeHTML(false)
+	return &ResultWriter{file: file, json: encoder}, nil
+}
+
+func (w *ResultWriter) Write(result entity.BatchResult) error {
+	if w.json != nil {
+		return w.json.Encode(result)
+	}
+
+	var outputJSON string
+	if result.Output != nil {
+		data, err := json.Marshal(result.Output)
+		if err != nil {
+			return fmt.Errorf("encode output: %w", err)
+		}
+		outputJSON = string(data)
+	}
+	var startedAt string
+	if !result.StartedAt.IsZero() {
+		startedAt = result.StartedAt.Format(time.RFC3339)
+	}
+
+	return w.writeCSV([]string{
+		result.ID,
+		result.Task,
+		string(result.Status),
+		result.FinalAnswer,
+		outputJSON,
+		result.Error,
+		strconv.Itoa(result.Iterations),
+		startedAt,
+		strconv.FormatInt(result.DurationMS, 10),
+	})
+}
+
+func (w *ResultWriter) Close() error {
+	return w.file.Close()
+}
+
+func (w *ResultWriter) writeCSV(record []string) error {
+	if err := w.csv.Write(record); err != nil {
+		return err
+	}
+	w.csv.Flush()
+	return w.csv.Error()
+}
@@ -0,0 +1,222 @@
+// Package pool leases browsers to tasks so several of them can run at once
+// without sharing a page.
+package pool
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/application/runctx"
+)
+
+var ErrClosed = errors.New("browser pool is closed")
+
+// Browser is a pooled browser; IsReady is its health check.
+type Browser interface {
+	output.BrowserPort
+	IsReady() bool
+}
+
+// Factory opens a new browser for the task on ctx.
+type Factory func(ctx context.Context) (Browser, error)
+
+type Config struct {
+	// MaxSize is how many browsers are leased at once; Acquire waits for
+	// a free one beyond it.
+	MaxSize int
+	// MaxUses closes a browser after that many leases, so the next task
+	// starts from a clean one; zero reuses it while it stays healthy.
+	MaxUses int
+}
+
+// Pool hands out at most MaxSize browsers, reusing the returned ones that
+// pass the health check and closing the others.
+type Pool struct {
+	factory Factory
+	config  Config
+	logger  output.LoggerPort
+	slots   chan struct{}
+	done    chan struct{}
+
+	mu     sync.Mutex
+	idle   []*entry
+	leased []*Lease
+	closed bool
+}
+
+type entry struct {
+	browser Browser
+	uses    int
+	// closed is set under Pool.mu by Close for browsers it closed while
+	// leased, so Release does not close them again.
+	closed bool
+}
+
+// Lease is a browser held by one task until Release.
+type Lease struct {
+	pool   *Pool
+	entry  *entry
+	taskID string
+	once   sync.Once
+}
+
+func New(factory Factory, config Config, logger output.LoggerPort) *Pool {
+	if config.MaxSize < 1 {
+		config.MaxSize = 1
+	}
+	return &Pool{
+		factory: factory,
+		config:  config,
+		logger:  logger,
+		slots:   make(chan struct{}, config.MaxSize),
+		done:    make(chan struct{}),
+	}
+}
+
+// Acquire blocks until a browser is free or ctx is done. The lease must be
+// released when the task ends.
+func (p *Pool) Acquire(ctx context.Context) (*Lease, error) {
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-p.done:
+		return nil, ErrClosed
+	}
+
+	e, err := p.take(ctx)
+	if err != nil {
+		<-p.slots
+		return nil, err
+	}
+
+	lease := &Lease{pool: p, entry: e, taskID: runctx.TaskID(ctx)}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		e.browser.Close()
+		<-p.slots
+		return nil, ErrClosed
+	}
+	p.leased = append(p.leased, lease)
+	return lease, nil
+}
+
+// take returns an idle browser that passes the health check or opens a
+// new one.
+func (p *Pool) take(ctx context.Context) (*entry, error) {
+	for {
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			return nil, ErrClosed
+		}
+		if len(p.idle) == 0 {
+			p.mu.Unlock()
+			break
+		}
+		e := p.idle[len(p.idle)-1]
+		p.idle = p.idle[:len(p.idle)-1]
+		p.mu.Unlock()
+
+		if e.browser.IsReady() {
+			return e, nil
+		}
+		p.logger.Warn("Pooled browser failed health check, replacing it", "uses", e.uses)
+		e.browser.Close()
+	}
+
+	browser, err := p.factory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &entry{browser: browser}, nil
+}
+
+func (l *Lease) Browser() output.BrowserPort {
+	return l.entry.browser
+}
+
+// Release returns the browser to the pool, or closes it when it is used
+// up or unhealthy. Only the first call has an effect.
+func (l *Lease) Release() {
+	l.once.Do(func() { l.pool.release(l) })
+}
+
+func (p *Pool) release(lease *Lease) {
+	e := lease.entry
+	e.uses++
+
+	p.mu.Lock()
+	for i, leased := range p.leased {
+		if leased == lease {
+			p.leased = append(p.leased[:i], p.leased[i+1:]...)
+			break
+		}
+	}
+	closed := e.closed
+	recycle := p.closed || (p.config.MaxUses > 0 && e.uses >= p.config.MaxUses) || !e.browser.IsReady()
+	if !recycle {
+		p.idle = append(p.idle, e)
+	}
+	p.mu.Unlock()
+
+	if recycle && !closed {
+		e.browser.Close()
+	}
+	<-p.slots
+}
+
+// Browser returns the browser leased to the task with taskID, or the most
+// recently leased one when taskID is empty.
+func (p *Pool) Browser(taskID string) (output.BrowserPort, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i := len(p.leased) - 1; i >= 0; i-- {
+		if taskID == "" || p.leased[i].taskID == taskID {
+			return p.leased[i].entry.browser, true
+		}
+	}
+	return nil, false
+}
+
+type Stats struct {
+	Leased int
+	Idle   int
+}
+
+func (p *Pool) Stats() Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return Stats{Leased: len(p.leased), Idle: len(p.idle)}
+}
+
+// Close closes every browser, including leased ones; their tasks fail on
+// the next browser call, and releasing them does not close them again.
+// Acquire fails with ErrClosed afterwards.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.closed = true
+	close(p.done)
+	browsers := make([]Browser, 0, len(p.idle)+len(p.leased))
+	for _, e := range p.idle {
+		browsers = append(browsers, e.browser)
+	}
+	for _, lease := range p.leased {
+		lease.entry.closed = true
+		browsers = append(browsers, lease.entry.browser)
+	}
+	p.idle = nil
+	p.mu.Unlock()
+
+	for _, browser := range browsers {
+		browser.Close()
+	}
+}
@@ -0,0 +1,167 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"browser-agent/internal/application/runctx"
+	"browser-agent/internal/testkit"
+)
+
+const blankSite = `
+start: blank
+pages:
+  blank:
+    url: about:blank
+`
+
+func newTestPool(t *testing.T, config Config) (*Pool, *[]*testkit.Browser) {
+	t.Helper()
+	var opened []*testkit.Browser
+	factory := func(context.Context) (Browser, error) {
+		browser := testkit.NewBrowser(testkit.MustParseSite(blankSite))
+		opened = append(opened, browser)
+		return browser, nil
+	}
+	return New(factory, config, testkit.NopLogger{}), &opened
+}
+
+func TestPoolReusesHealthyBrowsers(t *testing.T) {
+	ctx := context.Background()
+	pool, opened := newTestPool(t, Config{MaxSize: 2})
+
+	first, err := pool.Acquire(runctx.WithTaskID(ctx, "a"))
+	require.NoError(t, err)
+	second, err := pool.Acquire(runctx.WithTaskID(ctx, "b"))
+	require.NoError(t, err)
+	assert.NotSame(t, first.Browser(), second.Browser())
+	assert.Equal(t, Stats{Leased: 2}, pool.Stats())
+
+	browser, ok := pool.Browser("a")
+	require.True(t, ok)
+	assert.Same(t, first.Browser(), browser)
+	browser, ok = pool.Browser("")
+	require.True(t, ok)
+	assert.Same(t, second.Browser(), browser)
+
+	first.Release()
+	first.Release()
+	assert.Equal(t, Stats{Leased: 1, Idle: 1}, pool.Stats())
+
+	third, err := pool.Acquire(ctx)
+	require.NoError(t, err)
+	assert.Same(t, first.Browser(), third.Browser())
+	assert.Len(t, *opened, 2)
+
+	// A browser that died while idle is replaced.
+	third.Release()
+	(*opened)[0].Close()
+	fourth, err := pool.Acquire(ctx)
+	require.NoError(t, err)
+	assert.Len(t, *opened, 3)
+	assert.Same(t, (*opened)[2], fourth.Browser())
+
+	pool.Close()
+	assert.True(t, (*opened)[1].Closed())
+	assert.True(t, (*opened)[2].Closed())
+	_, err = pool.Acquire(ctx)
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func TestPoolRecyclesAfterMaxUses(t *testing.T) {
+	ctx := context.Background()
+	pool, opened := newTestPool(t, Config{MaxSize: 1, MaxUses: 1})
+
+	lease, err := pool.Acquire(ctx)
+	require.NoError(t, err)
+	lease.Release()
+	assert.True(t, (*opened)[0].Closed())
+
+	lease, err = pool.Acquire(ctx)
+	require.NoError(t, err)
+	assert.Same(t, (*opened)[1], lease.Browser())
+	lease.Release()
+}
+
+func TestPoolWaitsForFreeBrowser(t *testing.T) {
+	ctx := context.Background()
+	pool, _ := newTestPool(t, Config{MaxSize: 1})
+
+	lease, err := pool.Acquire(ctx)
+	require.NoError(t, err)
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	_, err = pool.Acquire(timeoutCtx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	var acquired atomic.Bool
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		next, err := pool.Acquire(ctx)
+		if err == nil {
+			acquired.Store(true)
+			next.Release()
+		}
+	}()
+	time.Sleep(10 * time.Millisecond)
+	assert.False(t, acquired.Load())
+	lease.Release()
+	<-done
+	assert.True(t, acquired.Load())
+}
+
+func TestPoolFactoryError(t *testing.T) {
+	failure := errors.New("chrome did not start")
+	pool := New(func(context.Context) (Browser, error) { return nil, failure }, Config{MaxSize: 1}, testkit.NopLogger{})
+
+	_, err := pool.Acquire(context.Background())
+	assert.ErrorIs(t, err, failure)
+	// The failed attempt gave its slot back.
+	_, err = pool.Acquire(context.Background())
+	assert.ErrorIs(t, err, failure)
+}
+
+// closeCounter counts Close calls, which must happen once per browser.
+type closeCounter struct {
+	*testkit.Browser
+	closes atomic.Int32
+}
+
+func (b *closeCounter) Close() {
+	b.closes.Add(1)
+	b.Browser.Close()
+}
+
+func TestPoolClosesLeasedBrowsersOnce(t *testing.T) {
+	ctx := context.Background()
+	var opened []*closeCounter
+	pool := New(func(context.Context) (Browser, error) {
+		browser := &closeCounter{Browser: testkit.NewBrowser(testkit.MustParseSite(blankSite))}
+		opened = append(opened, browser)
+		return browser, nil
+	}, Config{MaxSize: 2}, testkit.NopLogger{})
+
+	leased, err := pool.Acquire(ctx)
+	require.NoError(t, err)
+	idle, err := pool.Acquire(ctx)
+	require.NoError(t, err)
+	idle.Release()
+
+	pool.Close()
+	assert.True(t, opened[0].Closed(), "leased browsers are closed with the pool")
+	leased.Release()
+	pool.Close()
+
+	for _, browser := range opened {
+		assert.Equal(t, int32(1), browser.closes.Load())
+	}
+	assert.Equal(t, Stats{}, pool.Stats())
+}
@@ -15,6 +15,7 @@ import (
 
 	"browser-agent/internal/application/port/output"
 	"browser-agent/internal/domain/entity"
+	"browser-agent/internal/domain/policy"
 
 	"github.com/disintegration/imaging"
 	"github.com/go-rod/rod"
@@ -35,7 +36,7 @@ const (
 
 	maxUIElements = 100
 
-	screenshotMaxWidth      = 1024
+	screenshotMaxWidth      = entity.DefaultViewportMaxWidth
 	screenshotQuality       = 75
 	screenshotFormatQuality = 80
 
@@ -52,6 +53,7 @@ var (
 	ErrBrowserNotConnected    = errors.New("browser is not connected")
 	ErrContextCanceled        = errors.New("context was canceled")
 	ErrInvalidScrollDirection = errors.New("invalid scroll direction")
+	ErrNetworkCaptureDisabled = errors.New("network capture is disabled (browser.network.capture)")
 )
 
 type BrowserAdapter struct {
@@ -61,9 +63,43 @@ type BrowserAdapter struct {
 	timeout  time.Duration
 	mu       sync.RWMutex
 	closed   bool
+
+	navPolicy   policy.Navigation
+	intercept   interceptor
+	routerMu    sync.Mutex
+	router      *rod.HijackRouter
+	routerScope routerScope
+	violationMu sync.Mutex
+	violation   *policy.Violation
+	popups      *popupGuard
+
+	network *networkLog
+	har     *harRecorder
+	console *consoleLog
+	dialogs *dialogWatcher
+
+	emulationMu sync.Mutex
+	// baseEmulation is the configured emulation ResetEmulation returns to.
+	baseEmulation    entity.Emulation
+	emulation        entity.Emulation
+	defaultUserAgent string
+
+	onHARWritten func(path string, err error)
+
+	onSensitiveInput func(value string)
+
+	// disconnect is set for a browser attached with RemoteURL; closing the
+	// adapter then leaves that browser and its tabs running.
+	disconnect context.CancelFunc
 }
 
 type BrowserConfig struct {
+	// RemoteURL attaches to a running browser instead of launching one:
+	// a DevTools websocket URL (ws://...) or the host:port of its
+	// --remote-debugging-port. RemoteTab picks the tab to take over by a
+	// part of its URL or title; empty takes the first one.
+	RemoteURL               string
+	RemoteTab               string
 	Headless                bool
 	SlowMotion              time.Duration
 	Timeout                 time.Duration
@@ -71,6 +107,24 @@ type BrowserConfig struct {
 	DevTools                bool
 	DisableSecurityFeatures bool
 	EnableTrace             bool
+	NavigationPolicy        policy.Navigation
+	Network                 NetworkConfig
+	Intercept               InterceptConfig
+	// HARPath is where the traffic of the session is written as a HAR file
+	// when the browser closes; empty disables recording. Redact scrubs the
+	// URLs, header values and bodies written to it, and OnHARWritten
+	// reports the outcome.
+	HARPath      string
+	Redact       func(string) string
+	OnHARWritten func(path string, err error)
+	// DialogPolicy decides what happens to alert, confirm, prompt and
+	// beforeunload dialogs; empty means entity.DialogPolicyAgent.
+	DialogPolicy entity.DialogPolicy
+	// Emulation is applied to the page before the first navigation.
+	Emulation entity.Emulation
+	// OnSensitiveInput receives values typed into password-like fields so
+	// they can be redacted from logs.
+	OnSensitiveInput func(value string)
 }
 
 func DefaultConfig() BrowserConfig {
@@ -89,10 +143,31 @@ func NewBrowserAdapter(ctx context.Context, config BrowserConfig) (*BrowserAdapt
 		ctx = context.Background()
 	}
 
-	if config.Timeout <= 0 {
-		config.Timeout = defaultTimeout
+	var (
+		browser          *rod.Browser
+		page             *rod.Page
+		launcherInstance *launcher.Launcher
+		disconnect       context.CancelFunc
+		err              error
+	)
+	if config.RemoteURL != "" {
+		browser, page, disconnect, err = attachRemote(ctx, config)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		browser, launcherInstance, err = launch(ctx, config)
+		if err != nil {
+			return nil, err
+		}
+		page = browser.MustPage("about:blank")
 	}
 
+	return newAdapter(ctx, browser, page, launcherInstance, disconnect, config)
+}
+
+// launch starts a local browser and connects to it.
+func launch(ctx context.Context, config BrowserConfig) (*rod.Browser, *launcher.Launcher, error) {
 	launcherInstance := launcher.New().
 		Headless(config.Headless).
 		Devtools(config.DevTools).
@@ -107,7 +182,7 @@ func NewBrowserAdapter(ctx context.Context, config BrowserConfig) (*BrowserAdapt
 
 	launchURL, err := launcherInstance.Context(ctx).Launch()
 	if err != nil {
-		return nil, fmt.Errorf("failed to launch browser: %w", err)
+		return nil, nil, fmt.Errorf("failed to launch browser: %w", err)
 	}
 
 	browser := rod.New().
@@ -115,15 +190,75 @@ func NewBrowserAdapter(ctx context.Context, config BrowserConfig) (*BrowserAdapt
 		Trace(config.EnableTrace).
 		SlowMotion(config.SlowMotion).
 		MustConnect()
+	return browser, launcherInstance, nil
+}
 
-	page := browser.MustPage("about:blank")
+// newAdapter sets up the page of a connected browser. Closing the adapter
+// closes browser, which for an incognito context disposes only the context.
+func newAdapter(ctx context.Context, browser *rod.Browser, page *rod.Page, launcherInstance *launcher.Launcher, disconnect context.CancelFunc, config BrowserConfig) (*BrowserAdapter, error) {
+	if config.Timeout <= 0 {
+		config.Timeout = defaultTimeout
+	}
 
+	var err error
 	adapter := &BrowserAdapter{
-		browser:  browser,
-		launcher: launcherInstance,
-		page:     page,
-		timeout:  config.Timeout,
-		closed:   false,
+		browser:   browser,
+		launcher:  launcherInstance,
+		page:      page,
+		timeout:   config.Timeout,
+		closed:    false,
+		navPolicy: config.NavigationPolicy,
+
+		onSensitiveInput: config.OnSensitiveInput,
+		onHARWritten:     config.OnHARWritten,
+		disconnect:       disconnect,
+	}
+
+	adapter.console = newConsoleLog(page)
+	adapter.dialogs = newDialogWatcher(page, config.DialogPolicy)
+
+	if version, err := (proto.BrowserGetVersion{}).Call(browser); err == nil {
+		adapter.defaultUserAgent = version.UserAgent
+	}
+	if !config.Emulation.IsZero() {
+		if err := config.Emulation.Validate(); err != nil {
+			adapter.Close()
+			return nil, fmt.Errorf("invalid emulation: %w", err)
+		}
+		if err := adapter.applyEmulation(ctx, config.Emulation); err != nil {
+			adapter.Close()
+			return nil, err
+		}
+		adapter.baseEmulation = config.Emulation
+		adapter.emulation = config.Emulation
+	}
+
+	if config.HARPath != "" {
+		adapter.har = newHARRecorder(page, config.HARPath, config.Redact)
+	}
+
+	for _, rule := range config.Intercept.rules() {
+		if _, err := adapter.intercept.add(rule); err != nil {
+			adapter.Close()
+			return nil, fmt.Errorf("invalid intercept rule %s: %w", rule.Describe(), err)
+		}
+	}
+	if err := adapter.syncRouter(); err != nil {
+		adapter.Close()
+		return nil, err
+	}
+	if adapter.navPolicy.Active() {
+		if adapter.popups, err = newPopupGuard(browser, page, adapter.navPolicy, adapter.recordViolation); err != nil {
+			adapter.Close()
+			return nil, fmt.Errorf("failed to guard popups: %w", err)
+		}
+	}
+
+	if config.Network.Enabled {
+		if adapter.network, err = newNetworkLog(page, config.Network); err != nil {
+			adapter.Close()
+			return nil, err
+		}
 	}
 
 	return adapter, nil
@@ -141,20 +276,32 @@ func (b *BrowserAdapter) IsReady() bool {
 	return err == nil
 }
 
-func (b *BrowserAdapter) Navigate(ctx context.Context, targetURL string) error {
+func (b *BrowserAdapter) Navigate(ctx context.Context, targetURL string) (err error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
+	ctx, stopWatch := b.watchDialogs(ctx)
+	defer stopWatch()
+	defer func() { err = b.dialogError(err) }()
 
 	if err := b.validateURL(targetURL); err != nil {
 		return err
 	}
 
-	if err := b.checkState(); err != nil {
+	if v := b.navPolicy.Check(targetURL); v != nil {
+		return v
+	}
+
+	if err := b.checkPage(); err != nil {
 		return err
 	}
 
+	b.takeViolation()
+
 	if err := b.page.Context(ctx).Navigate(targetURL); err != nil {
+		if policyErr := b.checkNavigationResult(ctx); policyErr != nil {
+			return policyErr
+		}
 		if ctx.Err() != nil {
 			return fmt.Errorf("%w: %v", ErrContextCanceled, ctx.Err())
 		}
@@ -173,19 +320,22 @@ func (b *BrowserAdapter) Navigate(ctx context.Context, targetURL string) error {
 
 	_ = b.page.Context(ctx).WaitIdle(navigationWaitTime)
 
-	return nil
+	return b.checkNavigationResult(ctx)
 }
 
-func (b *BrowserAdapter) Click(ctx context.Context, selector string) error {
+func (b *BrowserAdapter) Click(ctx context.Context, selector string) (err error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
+	ctx, stopWatch := b.watchDialogs(ctx)
+	defer stopWatch()
+	defer func() { err = b.dialogError(err) }()
 
 	if err := b.validateSelector(selector); err != nil {
 		return err
 	}
 
-	if err := b.checkState(); err != nil {
+	if err := b.checkPage(); err != nil {
 		return err
 	}
 
@@ -194,6 +344,8 @@ func (b *BrowserAdapter) Click(ctx context.Context, selector string) error {
 		return fmt.Errorf("element not found for selector %q: %w", selector, err)
 	}
 
+	b.takeViolation()
+
 	if err := element.Context(ctx).Click(proto.InputMouseButtonLeft, 1); err != nil {
 		if ctx.Err() != nil {
 			return fmt.Errorf("%w: %v", ErrContextCanceled, ctx.Err())
@@ -205,25 +357,29 @@ func (b *BrowserAdapter) Click(ctx context.Context, selector string) error {
 	defer cancel()
 	_ = b.page.Context(waitCtx).WaitIdle(clickWaitTime)
 
-	return nil
+	return b.checkNavigationResult(ctx)
 }
 
-func (b *BrowserAdapter) ClickWithChanges(ctx context.Context, selector string) (*entity.ClickResult, error) {
+func (b *BrowserAdapter) ClickWithChanges(ctx context.Context, selector string) (result *entity.ClickResult, err error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
+	ctx, stopWatch := b.watchDialogs(ctx)
+	defer stopWatch()
+	defer func() { err = b.dialogError(err) }()
 
 	if err := b.validateSelector(selector); err != nil {
 		return &entity.ClickResult{Success: false, Error: err.Error()}, err
 	}
 
-	if err := b.checkState(); err != nil {
+	if err := b.checkPage(); err != nil {
 		return &entity.ClickResult{Success: false, Error: err.Error()}, err
 	}
 
 	beforeURL := b.CurrentURL()
 	beforeElements, _ := b.GetUIElements(ctx)
 	beforeCount := len(beforeElements)
+	beforeConsole := b.console.last()
 
 	element, err := b.findElement(ctx, selector)
 	if err != nil {
@@ -233,6 +389,8 @@ func (b *BrowserAdapter) ClickWithChanges(ctx context.Context, selector string)
 		}, err
 	}
 
+	b.takeViolation()
+
 	if err := element.Context(ctx).Click(proto.InputMouseButtonLeft, 1); err != nil {
 		if ctx.Err() != nil {
 			return &entity.ClickResult{Success: false, Error: "context canceled"}, ErrContextCanceled
@@ -244,13 +402,18 @@ func (b *BrowserAdapter) ClickWithChanges(ctx context.Context, selector string)
 	defer cancel()
 	_ = b.page.Context(waitCtx).WaitIdle(clickWaitTime)
 
+	if err := b.checkNavigationResult(ctx); err != nil {
+		return &entity.ClickResult{Success: false, Error: err.Error()}, err
+	}
+
 	afterURL := b.CurrentURL()
 	afterElements, _ := b.GetUIElements(ctx)
 	afterCount := len(afterElements)
 
 	changes := &entity.PageChanges{
-		URLChanged: beforeURL != afterURL,
-		NewURL:     afterURL,
+		URLChanged:    beforeURL != afterURL,
+		NewURL:        afterURL,
+		ConsoleErrors: b.console.list(entity.ConsoleFilter{MinLevel: entity.ConsoleError, AfterID: beforeConsole}),
 	}
 
 	if afterCount > beforeCount {
@@ -279,15 +442,20 @@ func (b *BrowserAdapter) ClickWithChanges(ctx context.Context, selector string)
 	}, nil
 }
 
-func (b *BrowserAdapter) BatchClick(ctx context.Context, selectors []string) error {
+func (b *BrowserAdapter) BatchClick(ctx context.Context, selectors []string) (err error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
+	ctx, stopWatch := b.watchDialogs(ctx)
+	defer stopWatch()
+	defer func() { err = b.dialogError(err) }()
 
-	if err := b.checkState(); err != nil {
+	if err := b.checkPage(); err != nil {
 		return err
 	}
 
+	b.takeViolation()
+
 	for i, selector := range selectors {
 		if err := b.validateSelector(selector); err != nil {
 			return fmt.Errorf("invalid selector at index %d (%q): %w", i, selector, err)
@@ -306,25 +474,32 @@ func (b *BrowserAdapter) BatchClick(ctx context.Context, selectors []string) err
 		}
 
 		time.Sleep(300 * time.Millisecond)
+
+		if err := b.checkNavigationResult(ctx); err != nil {
+			return fmt.Errorf("click %d/%d on selector %q: %w", i+1, len(selectors), selector, err)
+		}
 	}
 
 	waitCtx, cancel := context.WithTimeout(ctx, clickWaitTime)
 	defer cancel()
 	_ = b.page.Context(waitCtx).WaitIdle(clickWaitTime)
 
-	return nil
+	return b.checkNavigationResult(ctx)
 }
 
-func (b *BrowserAdapter) Fill(ctx context.Context, selector, text string) error {
+func (b *BrowserAdapter) Fill(ctx context.Context, selector, text string) (err error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
+	ctx, stopWatch := b.watchDialogs(ctx)
+	defer stopWatch()
+	defer func() { err = b.dialogError(err) }()
 
 	if err := b.validateSelector(selector); err != nil {
 		return err
 	}
 
-	if err := b.checkState(); err != nil {
+	if err := b.checkPage(); err != nil {
 		return err
 	}
 
@@ -345,6 +520,8 @@ func (b *BrowserAdapter) Fill(ctx context.Context, selector, text string) error
 		}
 	}
 
+	b.reportSensitiveInput(element, text)
+
 	if err := element.Context(ctx).Input(text); err != nil {
 		if ctx.Err() != nil {
 			return fmt.Errorf("%w: %v", ErrContextCanceled, ctx.Err())
@@ -355,12 +532,15 @@ func (b *BrowserAdapter) Fill(ctx context.Context, selector, text string) error
 	return nil
 }
 
-func (b *BrowserAdapter) BatchFill(ctx context.Context, fields map[string]string) error {
+func (b *BrowserAdapter) BatchFill(ctx context.Context, fields map[string]string) (err error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
+	ctx, stopWatch := b.watchDialogs(ctx)
+	defer stopWatch()
+	defer func() { err = b.dialogError(err) }()
 
-	if err := b.checkState(); err != nil {
+	if err := b.checkPage(); err != nil {
 		return err
 	}
 
@@ -383,6 +563,8 @@ func (b *BrowserAdapter) BatchFill(ctx context.Context, fields map[string]string
 			}
 		}
 
+		b.reportSensitiveInput(element, text)
+
 		if err := element.Context(ctx).Input(text); err != nil {
 			if ctx.Err() != nil {
 				return fmt.Errorf("input failed for %q: %w", selector, ErrContextCanceled)
@@ -394,12 +576,15 @@ func (b *BrowserAdapter) BatchFill(ctx context.Context, fields map[string]string
 	return nil
 }
 
-func (b *BrowserAdapter) PressEnter(ctx context.Context) error {
+func (b *BrowserAdapter) PressEnter(ctx context.Context) (err error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
+	ctx, stopWatch := b.watchDialogs(ctx)
+	defer stopWatch()
+	defer func() { err = b.dialogError(err) }()
 
-	if err := b.checkState(); err != nil {
+	if err := b.checkPage(); err != nil {
 		return err
 	}
 
@@ -408,6 +593,8 @@ func (b *BrowserAdapter) PressEnter(ctx context.Context) error {
 		return fmt.Errorf("failed to find body element: %w", err)
 	}
 
+	b.takeViolation()
+
 	if err := bodyElement.Context(ctx).Input("\n"); err != nil {
 		if ctx.Err() != nil {
 			return fmt.Errorf("%w: %v", ErrContextCanceled, ctx.Err())
@@ -419,7 +606,7 @@ func (b *BrowserAdapter) PressEnter(ctx context.Context) error {
 	defer cancel()
 	_ = b.page.Context(waitCtx).WaitIdle(enterWaitTime)
 
-	return nil
+	return b.checkNavigationResult(ctx)
 }
 
 type ScrollDirection string
@@ -431,12 +618,15 @@ const (
 	ScrollBottom ScrollDirection = "bottom"
 )
 
-func (b *BrowserAdapter) Scroll(ctx context.Context, direction string, amount int) error {
+func (b *BrowserAdapter) Scroll(ctx context.Context, direction string, amount int) (err error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
+	ctx, stopWatch := b.watchDialogs(ctx)
+	defer stopWatch()
+	defer func() { err = b.dialogError(err) }()
 
-	if err := b.checkState(); err != nil {
+	if err := b.checkPage(); err != nil {
 		return err
 	}
 
@@ -454,7 +644,7 @@ func (b *BrowserAdapter) Scroll(ctx context.Context, direction string, amount in
 		return fmt.Errorf("%w: %s (valid: down, up, top, bottom)", ErrInvalidScrollDirection, direction)
 	}
 
-	_, err := b.page.Context(ctx).Eval(script)
+	_, err = b.page.Context(ctx).Eval(script)
 	if err != nil {
 		if ctx.Err() != nil {
 			return fmt.Errorf("%w: %v", ErrContextCanceled, ctx.Err())
@@ -474,7 +664,7 @@ func (b *BrowserAdapter) GetPageContent(ctx context.Context) (*entity.PageConten
 		ctx = context.Background()
 	}
 
-	if err := b.checkState(); err != nil {
+	if err := b.checkPage(); err != nil {
 		return nil, err
 	}
 
@@ -514,7 +704,7 @@ func (b *BrowserAdapter) GetPageText(ctx context.Context) (string, error) {
 		ctx = context.Background()
 	}
 
-	if err := b.checkState(); err != nil {
+	if err := b.checkPage(); err != nil {
 		return "", err
 	}
 
@@ -539,7 +729,7 @@ func (b *BrowserAdapter) GetUIElements(ctx context.Context) ([]entity.UIElement,
 		ctx = context.Background()
 	}
 
-	if err := b.checkState(); err != nil {
+	if err := b.checkPage(); err != nil {
 		return nil, err
 	}
 
@@ -560,7 +750,7 @@ func (b *BrowserAdapter) GetPageContext(ctx context.Context) (*entity.PageContex
 		ctx = context.Background()
 	}
 
-	if err := b.checkState(); err != nil {
+	if err := b.checkPage(); err != nil {
 		return nil, err
 	}
 
@@ -598,7 +788,7 @@ func (b *BrowserAdapter) GetPageStructure(ctx context.Context) (*entity.PageStru
 		ctx = context.Background()
 	}
 
-	if err := b.checkState(); err != nil {
+	if err := b.checkPage(); err != nil {
 		return nil, err
 	}
 
@@ -831,7 +1021,7 @@ func (b *BrowserAdapter) QueryElements(ctx context.Context, req entity.QueryElem
 		ctx = context.Background()
 	}
 
-	if err := b.checkState(); err != nil {
+	if err := b.checkPage(); err != nil {
 		return nil, err
 	}
 
@@ -956,7 +1146,7 @@ func (b *BrowserAdapter) Search(ctx context.Context, req entity.SearchRequest) (
 		ctx = context.Background()
 	}
 
-	if err := b.checkState(); err != nil {
+	if err := b.checkPage(); err != nil {
 		return nil, err
 	}
 
@@ -1568,12 +1758,75 @@ func (b *BrowserAdapter) searchByAttribute(ctx context.Context, query string) (*
 	}, nil
 }
 
+const describeElementJS = `function describe(el) {
+	if (!el || !el.tagName) return null;
+	const form = el.closest('form');
+	const tag = el.tagName.toLowerCase();
+	const type = (el.getAttribute('type') || '').toLowerCase();
+	let text = (el.innerText || el.value || '').trim();
+	const isSubmit = !!form && (
+		(tag === 'button' && (type === '' || type === 'submit')) ||
+		(tag === 'input' && (type === 'submit' || type === 'image'))
+	);
+	return {
+		tagName: tag,
+		text: text.substring(0, 200),
+		role: el.getAttribute('role') || '',
+		type: type,
+		ariaLabel: el.getAttribute('aria-label') || '',
+		href: el.getAttribute('href') || '',
+		inForm: !!form,
+		formAction: form ? (form.getAttribute('action') || '') : '',
+		isSubmit: isSubmit
+	};
+}`
+
+func (b *BrowserAdapter) DescribeElement(ctx context.Context, selector string) (*entity.ElementInfo, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if err := b.checkPage(); err != nil {
+		return nil, err
+	}
+
+	var result *proto.RuntimeRemoteObject
+	var err error
+
+	if strings.TrimSpace(selector) == "" {
+		timeoutCtx, cancel := context.WithTimeout(ctx, b.timeout)
+		defer cancel()
+		result, err = b.page.Context(timeoutCtx).Eval(`() => { ` + describeElementJS + ` return describe(document.activeElement); }`)
+	} else {
+		element, findErr := b.findElement(ctx, selector)
+		if findErr != nil {
+			return nil, fmt.Errorf("element not found for selector %q: %w", selector, findErr)
+		}
+		result, err = element.Context(ctx).Eval(`function() { ` + describeElementJS + ` return describe(this); }`)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe element: %w", err)
+	}
+
+	if result.Value.Nil() {
+		return nil, fmt.Errorf("%w: no element to describe", ErrElementNotFound)
+	}
+
+	var info entity.ElementInfo
+	if err := result.Value.Unmarshal(&info); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal element info: %w", err)
+	}
+	info.Selector = selector
+
+	return &info, nil
+}
+
 func (b *BrowserAdapter) Screenshot(ctx context.Context) (*entity.Screenshot, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
-	if err := b.checkState(); err != nil {
+	if err := b.checkPage(); err != nil {
 		return nil, err
 	}
 
@@ -1632,10 +1885,43 @@ func (b *BrowserAdapter) Close() {
 
 	b.closed = true
 
-	if b.browser != nil {
+	b.routerMu.Lock()
+	if b.router != nil {
+		_ = b.router.Stop()
+		b.router = nil
+	}
+	b.routerMu.Unlock()
+
+	if b.popups != nil {
+		b.popups.close()
+	}
+
+	if b.network != nil {
+		b.network.close()
+	}
+
+	if b.console != nil {
+		b.console.close()
+	}
+
+	if b.dialogs != nil {
+		b.dialogs.close()
+	}
+
+	if b.har != nil {
+		err := b.har.close()
+		if b.onHARWritten != nil {
+			b.onHARWritten(b.har.path, err)
+		}
+	}
+
+	if b.disconnect != nil {
+		b.disconnect()
+		b.disconnect = nil
+	} else if b.browser != nil {
 		_ = b.browser.Close()
-		b.browser = nil
 	}
+	b.browser = nil
 
 	if b.launcher != nil {
 		b.launcher.Kill()
@@ -1665,6 +1951,15 @@ func (b *BrowserAdapter) checkState() error {
 	return nil
 }
 
+// checkPage is checkState for calls that need the page to respond, which
+// it does not while a JavaScript dialog is open.
+func (b *BrowserAdapter) checkPage() error {
+	if err := b.checkState(); err != nil {
+		return err
+	}
+	return b.dialogError(nil)
+}
+
 func (b *BrowserAdapter) validateURL(targetURL string) error {
 	if strings.TrimSpace(targetURL) == "" {
 		return fmt.Errorf("%w: URL cannot be empty", ErrInvalidURL)
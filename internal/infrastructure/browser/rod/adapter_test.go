@@ -1,9 +1,26 @@
 package rod
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ysmood/gson"
+
+	"browser-agent/internal/domain/entity"
+	"browser-agent/internal/infrastructure/har"
 )
 
 // Pure unit tests (fast, no browser required)
@@ -76,3 +93,309 @@ func TestIsXPathSelector(t *testing.T) {
 func stringPtr(s string) *string {
 	return &s
 }
+
+func TestInterceptConfigRules(t *testing.T) {
+	mock := entity.InterceptRule{Action: entity.InterceptMock, URLPattern: "/api/cart", Status: 503}
+	rules := InterceptConfig{BlockResources: []string{"image", "font"}, BlockAds: true, Rules: []entity.InterceptRule{mock}}.rules()
+
+	require.Len(t, rules, 3)
+	assert.Equal(t, []string{"image", "font"}, rules[0].ResourceTypes)
+	assert.Equal(t, adURLPattern, rules[1].URLPattern)
+	assert.Equal(t, mock, rules[2])
+	assert.Empty(t, InterceptConfig{}.rules())
+}
+
+func TestAdURLPattern(t *testing.T) {
+	ads := regexp.MustCompile(adURLPattern)
+
+	assert.True(t, ads.MatchString("https://securepubads.g.doubleclick.net/tag/js/gpt.js"))
+	assert.True(t, ads.MatchString("https://www.googletagmanager.com/gtm.js?id=GTM-X"))
+	assert.True(t, ads.MatchString("https://mc.yandex.ru/metrika/tag.js"))
+	assert.False(t, ads.MatchString("https://shop.test/ads/banner.png"))
+	assert.False(t, ads.MatchString("https://www.google.com/search?q=doubleclick.net/"))
+}
+
+func TestInterceptorAddRemove(t *testing.T) {
+	var i interceptor
+	assert.False(t, i.active())
+
+	_, err := i.add(entity.InterceptRule{Action: entity.InterceptBlock, URLPattern: "("})
+	require.Error(t, err)
+
+	first, err := i.add(entity.InterceptRule{Action: entity.InterceptBlock, ResourceTypes: []string{"image"}})
+	require.NoError(t, err)
+	second, err := i.add(entity.InterceptRule{Action: entity.InterceptHeaders, Headers: map[string]string{"X-Flag": "1"}})
+	require.NoError(t, err)
+	assert.Equal(t, "1", first.ID)
+	assert.Equal(t, "2", second.ID)
+	assert.True(t, i.active())
+
+	assert.True(t, i.remove("1"))
+	assert.False(t, i.remove("1"))
+	assert.Equal(t, []entity.InterceptRule{second}, i.list())
+}
+
+func TestHARRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.har")
+	r := &harRecorder{
+		page:    &rod.Page{FrameID: "main"},
+		stop:    func() {},
+		path:    path,
+		redact:  func(s string) string { return strings.ReplaceAll(s, "s3cret", "[SECRET]") },
+		file:    har.New(),
+		pending: make(map[proto.NetworkRequestID]*harEntry),
+	}
+
+	r.requestWillBeSent(&proto.NetworkRequestWillBeSent{
+		RequestID: "1", FrameID: "main", Type: proto.NetworkResourceTypeDocument, Timestamp: 10, WallTime: 1700000000,
+		Request: &proto.NetworkRequest{Method: "GET", URL: "http://shop.test/", Headers: networkHeaders(t, `{"Cookie": "sid=1"}`)},
+	})
+	r.requestWillBeSent(&proto.NetworkRequestWillBeSent{
+		RequestID: "1", FrameID: "main", Type: proto.NetworkResourceTypeDocument, Timestamp: 10.1, WallTime: 1700000000.1,
+		Request:          &proto.NetworkRequest{Method: "GET", URL: "https://shop.test/"},
+		RedirectResponse: &proto.NetworkResponse{Status: 301, StatusText: "Moved Permanently", Protocol: "http/1.1"},
+	})
+	r.responseReceived(&proto.NetworkResponseReceived{RequestID: "1", Response: &proto.NetworkResponse{Status: 200, MIMEType: "text/html", Protocol: "h2"}})
+	r.dataReceived(&proto.NetworkDataReceived{RequestID: "1", DataLength: 512})
+	r.loadingFinished(&proto.NetworkLoadingFinished{RequestID: "1", Timestamp: 10.3, EncodedDataLength: 300})
+
+	r.requestWillBeSent(&proto.NetworkRequestWillBeSent{
+		RequestID: "2", FrameID: "main", Type: proto.NetworkResourceTypeXHR, Timestamp: 11, WallTime: 1700000001,
+		Request: &proto.NetworkRequest{Method: "POST", URL: "https://shop.test/api/login?token=s3cret", PostData: `{"password":"s3cret"}`,
+			Headers: networkHeaders(t, `{"content-type": "application/json"}`)},
+	})
+	r.loadingFailed(&proto.NetworkLoadingFailed{RequestID: "2", Timestamp: 11.05, ErrorText: "net::ERR_BLOCKED_BY_CLIENT"})
+	r.requestWillBeSent(&proto.NetworkRequestWillBeSent{
+		RequestID: "3", FrameID: "main", Type: proto.NetworkResourceTypeFetch, Timestamp: 12, WallTime: 1700000002,
+		Request: &proto.NetworkRequest{Method: "GET", URL: "https://shop.test/api/poll"},
+	})
+
+	require.NoError(t, r.close())
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var file har.File
+	require.NoError(t, json.Unmarshal(data, &file))
+
+	require.Len(t, file.Log.Pages, 2)
+	require.Len(t, file.Log.Entries, 4)
+	redirect, page, login, poll := file.Log.Entries[0], file.Log.Entries[1], file.Log.Entries[2], file.Log.Entries[3]
+
+	assert.Equal(t, 301, redirect.Response.Status)
+	assert.Equal(t, "https://shop.test/", redirect.Response.RedirectURL)
+	assert.Equal(t, []har.NameValue{{Name: "Cookie", Value: "[REDACTED]"}}, redirect.Request.Headers)
+	assert.Equal(t, "page_1", redirect.PageRef)
+
+	assert.Equal(t, "page_2", page.PageRef)
+	assert.Equal(t, 200, page.Response.Status)
+	assert.Equal(t, "HTTP/2", page.Response.HTTPVersion)
+	assert.Equal(t, 512, page.Response.Content.Size)
+	assert.Equal(t, 300, page.Response.TransferSize)
+	assert.InDelta(t, 200, page.Time, 0.001)
+
+	assert.Equal(t, "https://shop.test/api/login?token=[SECRET]", login.Request.URL)
+	assert.Equal(t, []har.NameValue{{Name: "token", Value: "[SECRET]"}}, login.Request.QueryString)
+	assert.Equal(t, &har.PostData{MimeType: "application/json", Text: `{"password":"[SECRET]"}`}, login.Request.PostData)
+	assert.Equal(t, "net::ERR_BLOCKED_BY_CLIENT", login.Error)
+	assert.Equal(t, "xhr", login.ResourceType)
+
+	assert.Equal(t, "unfinished", poll.Error)
+}
+
+func networkHeaders(t *testing.T, raw string) proto.NetworkHeaders {
+	var headers proto.NetworkHeaders
+	require.NoError(t, json.Unmarshal([]byte(raw), &headers))
+	return headers
+}
+
+func TestHARTimings(t *testing.T) {
+	timing := &proto.NetworkResourceTiming{
+		RequestTime: 10.002, DNSStart: 1, DNSEnd: 5, ConnectStart: 5, ConnectEnd: 20, SslStart: 10, SslEnd: 20,
+		SendStart: 21, SendEnd: 22, ReceiveHeadersEnd: 72,
+	}
+	timings := harTimings(timing, 10, 100)
+
+	assert.InDelta(t, 3, timings.Blocked, 0.001)
+	assert.InDelta(t, 4, timings.DNS, 0.001)
+	assert.InDelta(t, 15, timings.Connect, 0.001)
+	assert.InDelta(t, 10, timings.SSL, 0.001)
+	assert.InDelta(t, 1, timings.Send, 0.001)
+	assert.InDelta(t, 50, timings.Wait, 0.001)
+	assert.InDelta(t, 26, timings.Receive, 0.001)
+
+	assert.Equal(t, har.Timings{Blocked: -1, DNS: -1, Connect: -1, SSL: -1, Wait: 40}, harTimings(nil, 10, 40))
+}
+
+func newTestNetworkLog() *networkLog {
+	return &networkLog{
+		maxEntries:   defaultNetworkMaxEntries,
+		maxBodyBytes: defaultNetworkMaxBodyBytes,
+		byRequest:    make(map[proto.NetworkRequestID]*entity.NetworkEntry),
+	}
+}
+
+func TestNetworkLogPending(t *testing.T) {
+	l := newTestNetworkLog()
+	for _, id := range []proto.NetworkRequestID{"1", "2", "3"} {
+		l.requestWillBeSent(&proto.NetworkRequestWillBeSent{
+			RequestID: id, Type: proto.NetworkResourceTypeXHR,
+			Request: &proto.NetworkRequest{Method: "GET", URL: "https://shop.test/api/" + string(id)},
+		})
+	}
+	l.requestWillBeSent(&proto.NetworkRequestWillBeSent{
+		RequestID: "img", Type: proto.NetworkResourceTypeImage,
+		Request: &proto.NetworkRequest{Method: "GET", URL: "https://shop.test/logo.png"},
+	})
+	assert.Equal(t, 3, l.pending())
+
+	l.responseReceived(&proto.NetworkResponseReceived{RequestID: "1", Response: &proto.NetworkResponse{Status: 204}})
+	l.loadingFinished(&proto.NetworkLoadingFinished{RequestID: "1"})
+	l.loadingFailed(&proto.NetworkLoadingFailed{RequestID: "2", ErrorText: "net::ERR_ABORTED"})
+	assert.Equal(t, 1, l.pending())
+
+	l.loadingFinished(&proto.NetworkLoadingFinished{RequestID: "3"})
+	assert.Zero(t, l.pending())
+}
+
+func TestNetworkLogBodyArrivesAfterFinish(t *testing.T) {
+	release := make(chan struct{})
+	l := newTestNetworkLog()
+	l.fetch = func(id proto.NetworkRequestID) (*proto.NetworkGetResponseBodyResult, error) {
+		<-release
+		return &proto.NetworkGetResponseBodyResult{Body: `{"items":[]}`}, nil
+	}
+
+	l.requestWillBeSent(&proto.NetworkRequestWillBeSent{
+		RequestID: "1", Type: proto.NetworkResourceTypeFetch,
+		Request: &proto.NetworkRequest{Method: "GET", URL: "https://shop.test/api/search"},
+	})
+	l.responseReceived(&proto.NetworkResponseReceived{RequestID: "1", Response: &proto.NetworkResponse{Status: 200, MIMEType: "application/json"}})
+	l.loadingFinished(&proto.NetworkLoadingFinished{RequestID: "1"})
+
+	entry, ok := l.get("1")
+	require.True(t, ok)
+	assert.True(t, entry.Finished)
+	assert.True(t, entry.BodyPending)
+	assert.Empty(t, entry.ResponseBody)
+
+	close(release)
+	require.Eventually(t, func() bool {
+		entry, _ = l.get("1")
+		return !entry.BodyPending
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, `{"items":[]}`, entry.ResponseBody)
+	assert.Equal(t, 12, entry.BodySize)
+}
+
+func TestConsoleLog(t *testing.T) {
+	l := &consoleLog{pageURL: "https://shop.test/cart"}
+	l.consoleAPICalled(&proto.RuntimeConsoleAPICalled{
+		Type: proto.RuntimeConsoleAPICalledTypeLog,
+		Args: []*proto.RuntimeRemoteObject{
+			{Type: proto.RuntimeRemoteObjectTypeString, Value: gson.New("total")},
+			{Type: proto.RuntimeRemoteObjectTypeNumber, Value: gson.New(42), Description: "42"},
+		},
+	})
+	l.exceptionThrown(&proto.RuntimeExceptionThrown{ExceptionDetails: &proto.RuntimeExceptionDetails{
+		Text:      "Uncaught",
+		Exception: &proto.RuntimeRemoteObject{Description: "TypeError: cart.total is undefined"},
+		StackTrace: &proto.RuntimeStackTrace{CallFrames: []*proto.RuntimeCallFrame{
+			{URL: "https://shop.test/app.js", LineNumber: 39},
+		}},
+	}})
+	line := 11
+	l.entryAdded(&proto.LogEntryAdded{Entry: &proto.LogLogEntry{
+		Level: proto.LogLogEntryLevelError, Text: "Failed to load resource", URL: "https://shop.test/api/cart", LineNumber: &line,
+	}})
+
+	all := l.list(entity.ConsoleFilter{})
+	require.Len(t, all, 3)
+	assert.Equal(t, "[info] total 42", all[0].String())
+	assert.Equal(t, "https://shop.test/cart", all[0].PageURL)
+	assert.Equal(t, "[exception] Uncaught TypeError: cart.total is undefined (https://shop.test/app.js:40)", all[1].String())
+	assert.Equal(t, "[error] Failed to load resource (https://shop.test/api/cart:12)", all[2].String())
+	assert.Equal(t, 3, l.last())
+
+	errors := l.list(entity.ConsoleFilter{MinLevel: entity.ConsoleError, AfterID: 2})
+	require.Len(t, errors, 1)
+	assert.Equal(t, 3, errors[0].ID)
+
+	l.add(entity.ConsoleWarning, strings.Repeat("x", maxConsoleTextLen+10), "")
+	assert.Len(t, l.list(entity.ConsoleFilter{Limit: 1})[0].Text, maxConsoleTextLen+3)
+}
+
+func TestDialogWatcherAgentPolicy(t *testing.T) {
+	w := &dialogWatcher{policy: entity.DialogPolicyAgent, opened: make(chan struct{})}
+	ctx, cancel := w.watch(context.Background())
+	defer cancel()
+
+	w.dialogOpening(&proto.PageJavascriptDialogOpening{Type: proto.PageDialogTypeConfirm, Message: "Leave?", URL: "https://shop.test/"})
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("watched context was not canceled when the dialog opened")
+	}
+	require.NotNil(t, w.current())
+	assert.Equal(t, `confirm "Leave?" (open)`, w.current().String())
+
+	w.dialogClosed(&proto.PageJavascriptDialogClosed{Result: true})
+	assert.Nil(t, w.current())
+	dialogs := w.list()
+	require.Len(t, dialogs, 1)
+	assert.True(t, dialogs[0].Accepted)
+	assert.False(t, dialogs[0].ByPolicy)
+
+	// Handled dialogs no longer cancel new actions.
+	ctx, cancel = w.watch(context.Background())
+	defer cancel()
+	assert.NoError(t, ctx.Err())
+	_, err := w.handle(entity.DialogResponse{Accept: true})
+	assert.ErrorIs(t, err, entity.ErrNoDialog)
+}
+
+func TestScaleCapture(t *testing.T) {
+	var buffer bytes.Buffer
+	require.NoError(t, png.Encode(&buffer, image.NewRGBA(image.Rect(0, 0, 2000, 1000))))
+
+	shot, err := scaleCapture(buffer.Bytes(), entity.CaptureRequest{Format: entity.ImageFormatPNG})
+	require.NoError(t, err)
+	assert.Equal(t, [2]int{2000, 1000}, [2]int{shot.Width, shot.Height})
+	assert.Equal(t, buffer.Bytes(), shot.Data)
+
+	shot, err = scaleCapture(buffer.Bytes(), entity.CaptureRequest{Format: entity.ImageFormatJPEG, Quality: 80, MaxWidth: 500})
+	require.NoError(t, err)
+	assert.Equal(t, [2]int{500, 250}, [2]int{shot.Width, shot.Height})
+	config, format, err := image.DecodeConfig(bytes.NewReader(shot.Data))
+	require.NoError(t, err)
+	assert.Equal(t, "jpeg", format)
+	assert.Equal(t, 500, config.Width)
+}
+
+func TestPickTab(t *testing.T) {
+	targets := []*proto.TargetTargetInfo{
+		{TargetID: "sw", Type: proto.TargetTargetInfoTypeServiceWorker, URL: "https://mail.test/sw.js"},
+		{TargetID: "devtools", Type: proto.TargetTargetInfoTypePage, URL: "devtools://devtools/bundled/inspector.html"},
+		{TargetID: "shop", Type: proto.TargetTargetInfoTypePage, URL: "https://shop.test/cart", Title: "Cart"},
+		{TargetID: "mail", Type: proto.TargetTargetInfoTypePage, URL: "https://mail.test/inbox", Title: "Inbox (3)"},
+	}
+
+	tab, err := pickTab(targets, "")
+	require.NoError(t, err)
+	assert.Equal(t, proto.TargetTargetID("shop"), tab.TargetID)
+
+	tab, err = pickTab(targets, "Inbox")
+	require.NoError(t, err)
+	assert.Equal(t, proto.TargetTargetID("mail"), tab.TargetID)
+
+	_, err = pickTab(targets, "bank.test")
+	assert.EqualError(t, err, `no tab matches "bank.test" (open tabs: https://shop.test/cart, https://mail.test/inbox)`)
+
+	tab, err = pickTab(targets[:2], "")
+	require.NoError(t, err)
+	assert.Nil(t, tab)
+}
+
+func TestResolveRemoteURL(t *testing.T) {
+	url, err := resolveRemoteURL("wss://chrome.example.com/devtools/browser/1?token=x")
+	require.NoError(t, err)
+	assert.Equal(t, "wss://chrome.example.com/devtools/browser/1?token=x", url)
+}
@@ -0,0 +1,156 @@
+package rod
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+
+	"browser-agent/internal/domain/entity"
+
+	"github.com/disintegration/imaging"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/ysmood/gson"
+)
+
+func (b *BrowserAdapter) Capture(ctx context.Context, req entity.CaptureRequest) (*entity.Screenshot, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if err := b.checkPage(); err != nil {
+		return nil, err
+	}
+	req = req.WithDefaults()
+
+	shot := &proto.PageCaptureScreenshot{Format: proto.PageCaptureScreenshotFormat(req.Format)}
+	if req.Format == entity.ImageFormatJPEG {
+		shot.Quality = gson.Int(req.Quality)
+	}
+
+	var data []byte
+	var err error
+	if req.Selector != "" {
+		data, err = b.captureElement(ctx, req.Selector, shot)
+	} else {
+		data, err = b.page.Context(ctx).Screenshot(req.FullPage, shot)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("%w: %v", ErrContextCanceled, ctx.Err())
+		}
+		return nil, fmt.Errorf("screenshot failed: %w", err)
+	}
+	return scaleCapture(data, req)
+}
+
+// captureElement clips the screenshot to the element's box in page
+// coordinates, so an element taller than the viewport is captured whole
+// and the clip matches at any device scale factor.
+func (b *BrowserAdapter) captureElement(ctx context.Context, selector string, shot *proto.PageCaptureScreenshot) ([]byte, error) {
+	element, err := b.findElement(ctx, selector)
+	if err != nil {
+		return nil, err
+	}
+	element = element.Context(ctx)
+	if err := element.ScrollIntoView(); err != nil {
+		return nil, err
+	}
+	shape, err := element.Shape()
+	if err != nil {
+		return nil, err
+	}
+	box := shape.Box()
+	if box == nil || box.Width == 0 || box.Height == 0 {
+		return nil, fmt.Errorf("element %s is not visible", selector)
+	}
+	metrics, err := proto.PageGetLayoutMetrics{}.Call(b.page.Context(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if metrics.CSSVisualViewport == nil {
+		return nil, fmt.Errorf("failed to get visual viewport")
+	}
+
+	shot.Clip = &proto.PageViewport{
+		X:      box.X + metrics.CSSVisualViewport.PageX,
+		Y:      box.Y + metrics.CSSVisualViewport.PageY,
+		Width:  box.Width,
+		Height: box.Height,
+		Scale:  1,
+	}
+	shot.CaptureBeyondViewport = true
+	res, err := shot.Call(b.page.Context(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return res.Data, nil
+}
+
+// scaleCapture applies req.MaxWidth and reads the image size.
+func scaleCapture(data []byte, req entity.CaptureRequest) (*entity.Screenshot, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("image decode failed: %w", err)
+	}
+	screenshot := &entity.Screenshot{Data: data, Format: string(req.Format), Width: config.Width, Height: config.Height}
+	if req.MaxWidth == 0 || config.Width <= req.MaxWidth {
+		return screenshot, nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("image decode failed: %w", err)
+	}
+	img = imaging.Resize(img, req.MaxWidth, 0, imaging.Lanczos)
+
+	buffer := new(bytes.Buffer)
+	if req.Format == entity.ImageFormatJPEG {
+		err = jpeg.Encode(buffer, img, &jpeg.Options{Quality: req.Quality})
+	} else {
+		err = png.Encode(buffer, img)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s encode failed: %w", req.Format, err)
+	}
+	screenshot.Data = buffer.Bytes()
+	screenshot.Width = img.Bounds().Dx()
+	screenshot.Height = img.Bounds().Dy()
+	return screenshot, nil
+}
+
+func (b *BrowserAdapter) PrintPDF(ctx context.Context, req entity.PDFRequest) ([]byte, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if err := b.checkPage(); err != nil {
+		return nil, err
+	}
+
+	width, height := req.PaperSize()
+	params := proto.PagePrintToPDF{
+		Landscape:       req.Landscape,
+		PrintBackground: req.Background,
+		PaperWidth:      &width,
+		PaperHeight:     &height,
+		PageRanges:      req.PageRanges,
+	}
+	if req.Scale != 0 {
+		params.Scale = &req.Scale
+	}
+	res, err := params.Call(b.page.Context(ctx))
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("%w: %v", ErrContextCanceled, ctx.Err())
+		}
+		return nil, fmt.Errorf("print to PDF failed (Chrome prints only in headless mode): %w", err)
+	}
+	return res.Data, nil
+}
@@ -0,0 +1,190 @@
+package rod
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"browser-agent/internal/domain/entity"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+const (
+	maxConsoleMessages = 500
+	// maxConsoleTextLen bounds one message; pages sometimes log whole
+	// payloads.
+	maxConsoleTextLen = 2000
+)
+
+// consoleLog records console calls, uncaught exceptions and browser log
+// entries (failed resource loads, CSP violations) of one page.
+type consoleLog struct {
+	page *rod.Page
+	stop func()
+
+	mu       sync.Mutex
+	messages []entity.ConsoleMessage
+	lastID   int
+	pageURL  string
+}
+
+func newConsoleLog(page *rod.Page) *consoleLog {
+	l := &consoleLog{}
+
+	var cancel func()
+	l.page, cancel = page.WithCancel()
+	disableRuntime := l.page.EnableDomain(&proto.RuntimeEnable{})
+	disableLog := l.page.EnableDomain(&proto.LogEnable{})
+	l.stop = func() {
+		cancel()
+		disableLog()
+		disableRuntime()
+	}
+
+	wait := l.page.EachEvent(l.frameNavigated, l.consoleAPICalled, l.exceptionThrown, l.entryAdded)
+	go wait()
+	return l
+}
+
+func (l *consoleLog) frameNavigated(e *proto.PageFrameNavigated) {
+	if e.Frame == nil || e.Frame.ParentID != "" {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.pageURL = e.Frame.URL
+}
+
+func (l *consoleLog) consoleAPICalled(e *proto.RuntimeConsoleAPICalled) {
+	args := make([]string, 0, len(e.Args))
+	for _, arg := range e.Args {
+		args = append(args, remoteObjectText(arg))
+	}
+	l.add(consoleAPILevel(e.Type), strings.Join(args, " "), stackSource(e.StackTrace))
+}
+
+func (l *consoleLog) exceptionThrown(e *proto.RuntimeExceptionThrown) {
+	details := e.ExceptionDetails
+	if details == nil {
+		return
+	}
+	// The description carries the error class, message and stack; the
+	// text alone is only "Uncaught".
+	text := details.Text
+	if details.Exception != nil && details.Exception.Description != "" {
+		text = strings.TrimSpace(details.Text + " " + details.Exception.Description)
+	}
+	source := stackSource(details.StackTrace)
+	if source == "" && details.URL != "" {
+		source = fmt.Sprintf("%s:%d", details.URL, details.LineNumber+1)
+	}
+	l.add(entity.ConsoleException, text, source)
+}
+
+func (l *consoleLog) entryAdded(e *proto.LogEntryAdded) {
+	if e.Entry == nil {
+		return
+	}
+	level := entity.ConsoleInfo
+	switch e.Entry.Level {
+	case proto.LogLogEntryLevelVerbose:
+		level = entity.ConsoleDebug
+	case proto.LogLogEntryLevelWarning:
+		level = entity.ConsoleWarning
+	case proto.LogLogEntryLevelError:
+		level = entity.ConsoleError
+	}
+	source := e.Entry.URL
+	if source != "" && e.Entry.LineNumber != nil {
+		source = fmt.Sprintf("%s:%d", source, *e.Entry.LineNumber+1)
+	}
+	l.add(level, e.Entry.Text, source)
+}
+
+func (l *consoleLog) add(level entity.ConsoleLevel, text, source string) {
+	if len(text) > maxConsoleTextLen {
+		text = strings.ToValidUTF8(text[:maxConsoleTextLen], "") + "..."
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lastID++
+	l.messages = append(l.messages, entity.ConsoleMessage{
+		ID:      l.lastID,
+		Level:   level,
+		Text:    text,
+		Source:  source,
+		PageURL: l.pageURL,
+		Time:    time.Now(),
+	})
+	if len(l.messages) > maxConsoleMessages {
+		l.messages = l.messages[len(l.messages)-maxConsoleMessages:]
+	}
+}
+
+func (l *consoleLog) list(filter entity.ConsoleFilter) []entity.ConsoleMessage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return filter.Apply(l.messages)
+}
+
+// last returns the ID of the newest message, zero when there is none.
+func (l *consoleLog) last() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.lastID
+}
+
+func (l *consoleLog) close() {
+	l.stop()
+}
+
+func consoleAPILevel(t proto.RuntimeConsoleAPICalledType) entity.ConsoleLevel {
+	switch t {
+	case proto.RuntimeConsoleAPICalledTypeError, proto.RuntimeConsoleAPICalledTypeAssert:
+		return entity.ConsoleError
+	case proto.RuntimeConsoleAPICalledTypeWarning:
+		return entity.ConsoleWarning
+	case proto.RuntimeConsoleAPICalledTypeDebug:
+		return entity.ConsoleDebug
+	default:
+		return entity.ConsoleInfo
+	}
+}
+
+func remoteObjectText(obj *proto.RuntimeRemoteObject) string {
+	switch {
+	case obj == nil:
+		return ""
+	case obj.Type == proto.RuntimeRemoteObjectTypeString:
+		return obj.Value.Str()
+	case obj.Description != "":
+		return obj.Description
+	case obj.UnserializableValue != "":
+		return string(obj.UnserializableValue)
+	default:
+		return obj.Value.String()
+	}
+}
+
+// stackSource is the location of the top frame, e.g. app.js:12.
+func stackSource(stack *proto.RuntimeStackTrace) string {
+	if stack == nil || len(stack.CallFrames) == 0 || stack.CallFrames[0].URL == "" {
+		return ""
+	}
+	frame := stack.CallFrames[0]
+	return fmt.Sprintf("%s:%d", frame.URL, frame.LineNumber+1)
+}
+
+func (b *BrowserAdapter) ConsoleMessages(ctx context.Context, filter entity.ConsoleFilter) ([]entity.ConsoleMessage, error) {
+	if err := b.checkState(); err != nil {
+		return nil, err
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	return b.console.list(filter), nil
+}
@@ -0,0 +1,194 @@
+package rod
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"browser-agent/internal/domain/entity"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+const maxDialogs = 50
+
+// dialogWatcher tracks the JavaScript dialogs of one page. A dialog blocks
+// every script and input of the page until it is closed, so depending on
+// the policy it is either closed right away or kept open for the agent.
+type dialogWatcher struct {
+	page   *rod.Page
+	stop   func()
+	policy entity.DialogPolicy
+
+	mu      sync.Mutex
+	dialogs []entity.Dialog
+	lastID  int
+	// opened is closed when a dialog opens under the agent policy and
+	// replaced once it is handled.
+	opened chan struct{}
+}
+
+func newDialogWatcher(page *rod.Page, policy entity.DialogPolicy) *dialogWatcher {
+	if policy == "" {
+		policy = entity.DialogPolicyAgent
+	}
+	w := &dialogWatcher{policy: policy, opened: make(chan struct{})}
+
+	var cancel func()
+	w.page, cancel = page.WithCancel()
+	w.stop = cancel
+
+	wait := w.page.EachEvent(w.dialogOpening, w.dialogClosed)
+	go wait()
+	return w
+}
+
+func (w *dialogWatcher) dialogOpening(e *proto.PageJavascriptDialogOpening) {
+	w.mu.Lock()
+	w.lastID++
+	dialog := entity.Dialog{
+		ID:            w.lastID,
+		Type:          entity.DialogType(e.Type),
+		Message:       e.Message,
+		DefaultPrompt: e.DefaultPrompt,
+		URL:           e.URL,
+		OpenedAt:      time.Now(),
+		Open:          true,
+	}
+	w.dialogs = append(w.dialogs, dialog)
+	if len(w.dialogs) > maxDialogs {
+		w.dialogs = w.dialogs[len(w.dialogs)-maxDialogs:]
+	}
+	if w.policy == entity.DialogPolicyAgent {
+		close(w.opened)
+		w.mu.Unlock()
+		return
+	}
+	w.mu.Unlock()
+
+	resp := entity.DialogResponse{Accept: w.policy == entity.DialogPolicyAccept, PromptText: e.DefaultPrompt}
+	if err := w.respond(resp); err == nil {
+		w.markClosed(resp)
+	}
+}
+
+// dialogClosed also covers dialogs closed without us, e.g. by a navigation
+// or by the user of a headed browser.
+func (w *dialogWatcher) dialogClosed(e *proto.PageJavascriptDialogClosed) {
+	w.markClosed(entity.DialogResponse{Accept: e.Result, PromptText: e.UserInput})
+}
+
+func (w *dialogWatcher) respond(resp entity.DialogResponse) error {
+	return proto.PageHandleJavaScriptDialog{Accept: resp.Accept, PromptText: resp.PromptText}.Call(w.page)
+}
+
+// markClosed records how the open dialog was closed and returns it; nil
+// when no dialog was open.
+func (w *dialogWatcher) markClosed(resp entity.DialogResponse) *entity.Dialog {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	i := len(w.dialogs) - 1
+	if i < 0 || !w.dialogs[i].Open {
+		return nil
+	}
+	d := &w.dialogs[i]
+	d.Open = false
+	d.Accepted = resp.Accept
+	d.ByPolicy = w.policy != entity.DialogPolicyAgent
+	if d.Accepted && d.Type == entity.DialogPrompt {
+		d.PromptText = resp.PromptText
+	}
+	if w.policy == entity.DialogPolicyAgent {
+		w.opened = make(chan struct{})
+	}
+	closed := *d
+	return &closed
+}
+
+// handle closes the open dialog on behalf of the agent.
+func (w *dialogWatcher) handle(resp entity.DialogResponse) (*entity.Dialog, error) {
+	if w.current() == nil {
+		return nil, entity.ErrNoDialog
+	}
+	if err := w.respond(resp); err != nil {
+		return nil, err
+	}
+	if d := w.markClosed(resp); d != nil {
+		return d, nil
+	}
+	// The closed event came first; report what it recorded.
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	d := w.dialogs[len(w.dialogs)-1]
+	return &d, nil
+}
+
+// current returns the open dialog, nil when there is none.
+func (w *dialogWatcher) current() *entity.Dialog {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.dialogs) == 0 || !w.dialogs[len(w.dialogs)-1].Open {
+		return nil
+	}
+	d := w.dialogs[len(w.dialogs)-1]
+	return &d
+}
+
+func (w *dialogWatcher) list() []entity.Dialog {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]entity.Dialog(nil), w.dialogs...)
+}
+
+// watch derives a context that is canceled when a dialog opens, so an
+// action blocked by the dialog returns instead of running into its timeout.
+func (w *dialogWatcher) watch(ctx context.Context) (context.Context, context.CancelFunc) {
+	w.mu.Lock()
+	opened := w.opened
+	w.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-opened:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+func (w *dialogWatcher) close() {
+	w.stop()
+}
+
+// watchDialogs wraps an action: its context ends when a dialog opens, and
+// dialogError reports the dialog in place of the action's own outcome.
+func (b *BrowserAdapter) watchDialogs(ctx context.Context) (context.Context, context.CancelFunc) {
+	return b.dialogs.watch(ctx)
+}
+
+// dialogError returns a *entity.DialogOpenError while a dialog is open and
+// err otherwise.
+func (b *BrowserAdapter) dialogError(err error) error {
+	if d := b.dialogs.current(); d != nil {
+		return &entity.DialogOpenError{Dialog: *d}
+	}
+	return err
+}
+
+func (b *BrowserAdapter) Dialogs(ctx context.Context) ([]entity.Dialog, error) {
+	if err := b.checkState(); err != nil {
+		return nil, err
+	}
+	return b.dialogs.list(), nil
+}
+
+func (b *BrowserAdapter) HandleDialog(ctx context.Context, resp entity.DialogResponse) (*entity.Dialog, error) {
+	if err := b.checkState(); err != nil {
+		return nil, err
+	}
+	return b.dialogs.handle(resp)
+}
@@ -0,0 +1,139 @@
+package rod
+
+import (
+	"context"
+	"fmt"
+
+	"browser-agent/internal/domain/entity"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+const maxTouchPoints = 5
+
+func (b *BrowserAdapter) Emulate(ctx context.Context, e entity.Emulation) (*entity.Emulation, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := b.checkPage(); err != nil {
+		return nil, err
+	}
+
+	b.emulationMu.Lock()
+	defer b.emulationMu.Unlock()
+
+	next := b.emulation.Merge(e)
+	if err := next.Validate(); err != nil {
+		return nil, err
+	}
+	if err := b.applyEmulation(ctx, next); err != nil {
+		return nil, err
+	}
+	b.emulation = next
+	return &next, nil
+}
+
+func (b *BrowserAdapter) ResetEmulation(ctx context.Context) (*entity.Emulation, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := b.checkPage(); err != nil {
+		return nil, err
+	}
+
+	b.emulationMu.Lock()
+	defer b.emulationMu.Unlock()
+
+	if err := b.applyEmulation(ctx, b.baseEmulation); err != nil {
+		return nil, err
+	}
+	b.emulation = b.baseEmulation
+	return &b.emulation, nil
+}
+
+// applyEmulation sets every override, clearing the ones e leaves zero, so
+// the page ends up in exactly the state e describes.
+func (b *BrowserAdapter) applyEmulation(ctx context.Context, e entity.Emulation) error {
+	page := b.page.Context(ctx)
+	resolved, platform := e.Resolve()
+
+	if resolved.Width != 0 {
+		err := proto.EmulationSetDeviceMetricsOverride{
+			Width:             resolved.Width,
+			Height:            resolved.Height,
+			DeviceScaleFactor: resolved.DeviceScaleFactor,
+			Mobile:            resolved.Mobile,
+		}.Call(page)
+		if err != nil {
+			return fmt.Errorf("set viewport: %w", err)
+		}
+	} else if err := (proto.EmulationClearDeviceMetricsOverride{}).Call(page); err != nil {
+		return fmt.Errorf("clear viewport: %w", err)
+	}
+
+	touch := proto.EmulationSetTouchEmulationEnabled{Enabled: resolved.Touch}
+	if resolved.Touch {
+		points := maxTouchPoints
+		touch.MaxTouchPoints = &points
+	}
+	if err := touch.Call(page); err != nil {
+		return fmt.Errorf("set touch: %w", err)
+	}
+
+	userAgent := resolved.UserAgent
+	if userAgent == "" {
+		userAgent = b.defaultUserAgent
+	}
+	err := proto.NetworkSetUserAgentOverride{
+		UserAgent:      userAgent,
+		AcceptLanguage: e.AcceptLanguage(),
+		Platform:       platform,
+	}.Call(page)
+	if err != nil {
+		return fmt.Errorf("set user agent: %w", err)
+	}
+
+	// Chrome refuses to replace an override that is in effect, so both are
+	// cleared before they are set.
+	_ = proto.EmulationSetLocaleOverride{}.Call(page)
+	if e.Locale != "" {
+		if err := (proto.EmulationSetLocaleOverride{Locale: e.Locale}).Call(page); err != nil {
+			return fmt.Errorf("set locale: %w", err)
+		}
+	}
+	_ = proto.EmulationSetTimezoneOverride{}.Call(page)
+	if e.Timezone != "" {
+		if err := (proto.EmulationSetTimezoneOverride{TimezoneID: e.Timezone}).Call(page); err != nil {
+			return fmt.Errorf("set timezone: %w", err)
+		}
+	}
+
+	if geo := e.Geolocation; geo != nil {
+		grant := proto.BrowserGrantPermissions{
+			Permissions:      []proto.BrowserPermissionType{proto.BrowserPermissionTypeGeolocation},
+			BrowserContextID: b.browser.BrowserContextID,
+		}
+		if err := grant.Call(b.browser.Context(ctx)); err != nil {
+			return fmt.Errorf("grant geolocation: %w", err)
+		}
+		accuracy := geo.Accuracy
+		if accuracy == 0 {
+			accuracy = 50
+		}
+		err := proto.EmulationSetGeolocationOverride{Latitude: &geo.Latitude, Longitude: &geo.Longitude, Accuracy: &accuracy}.Call(page)
+		if err != nil {
+			return fmt.Errorf("set geolocation: %w", err)
+		}
+	} else if err := (proto.EmulationClearGeolocationOverride{}).Call(page); err != nil {
+		return fmt.Errorf("clear geolocation: %w", err)
+	}
+
+	media := proto.EmulationSetEmulatedMedia{}
+	if e.ColorScheme != "" {
+		media.Features = []*proto.EmulationMediaFeature{{Name: "prefers-color-scheme", Value: string(e.ColorScheme)}}
+	}
+	if err := media.Call(page); err != nil {
+		return fmt.Errorf("set color scheme: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+package rod
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"browser-agent/internal/infrastructure/har"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// harRecorder records every request of the page as a HAR entry and writes
+// the archive when the browser closes.
+type harRecorder struct {
+	page   *rod.Page
+	stop   func()
+	path   string
+	redact func(string) string
+
+	mu      sync.Mutex
+	file    *har.File
+	entries []*harEntry
+	pending map[proto.NetworkRequestID]*harEntry
+}
+
+type harEntry struct {
+	har.Entry
+	// start is the monotonic time of the request, for the timings.
+	start  proto.MonotonicTime
+	timing *proto.NetworkResourceTiming
+	done   bool
+}
+
+func newHARRecorder(page *rod.Page, path string, redact func(string) string) *harRecorder {
+	if redact == nil {
+		redact = func(s string) string { return s }
+	}
+	r := &harRecorder{
+		path:    path,
+		redact:  redact,
+		file:    har.New(),
+		pending: make(map[proto.NetworkRequestID]*harEntry),
+	}
+
+	var cancel func()
+	r.page, cancel = page.WithCancel()
+	disable := r.page.EnableDomain(&proto.NetworkEnable{})
+	r.stop = func() {
+		cancel()
+		disable()
+	}
+
+	wait := r.page.EachEvent(r.requestWillBeSent, r.responseReceived, r.dataReceived, r.loadingFinished, r.loadingFailed)
+	go wait()
+	return r
+}
+
+func (r *harRecorder) requestWillBeSent(e *proto.NetworkRequestWillBeSent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// A redirect reuses the request ID: the previous hop ends with the
+	// redirect response and the new URL starts an entry of its own.
+	if prev, ok := r.pending[e.RequestID]; ok && e.RedirectResponse != nil {
+		r.setResponse(prev, e.RedirectResponse)
+		prev.Response.RedirectURL = r.redact(e.Request.URL)
+		r.finish(prev, e.Timestamp)
+	}
+
+	if e.Type == proto.NetworkResourceTypeDocument && e.FrameID == r.page.FrameID {
+		r.file.Log.Pages = append(r.file.Log.Pages, har.Page{
+			StartedDateTime: e.WallTime.Time(),
+			ID:              "page_" + strconv.Itoa(len(r.file.Log.Pages)+1),
+			Title:           r.redact(e.Request.URL),
+			PageTimings:     har.PageTimings{OnContentLoad: -1, OnLoad: -1},
+		})
+	}
+
+	entry := &harEntry{start: e.Timestamp}
+	entry.StartedDateTime = e.WallTime.Time()
+	entry.ResourceType = strings.ToLower(string(e.Type))
+	if n := len(r.file.Log.Pages); n > 0 {
+		entry.PageRef = r.file.Log.Pages[n-1].ID
+	}
+	entry.Request = har.Request{
+		Method:      e.Request.Method,
+		URL:         r.redact(e.Request.URL),
+		Cookies:     []har.NameValue{},
+		Headers:     r.headers(e.Request.Headers),
+		QueryString: r.queryString(e.Request.URL),
+		HeadersSize: -1,
+		BodySize:    len(e.Request.PostData),
+	}
+	if e.Request.HasPostData || e.Request.PostData != "" {
+		entry.Request.PostData = &har.PostData{
+			MimeType: headerValue(e.Request.Headers, "Content-Type"),
+			Text:     r.redact(e.Request.PostData),
+		}
+	}
+	entry.Response = har.Response{
+		Cookies:     []har.NameValue{},
+		Headers:     []har.NameValue{},
+		HeadersSize: -1,
+		BodySize:    -1,
+	}
+
+	r.entries = append(r.entries, entry)
+	r.pending[e.RequestID] = entry
+}
+
+func (r *harRecorder) responseReceived(e *proto.NetworkResponseReceived) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if entry, ok := r.pending[e.RequestID]; ok && e.Response != nil {
+		r.setResponse(entry, e.Response)
+	}
+}
+
+func (r *harRecorder) dataReceived(e *proto.NetworkDataReceived) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if entry, ok := r.pending[e.RequestID]; ok {
+		entry.Response.Content.Size += e.DataLength
+	}
+}
+
+func (r *harRecorder) loadingFinished(e *proto.NetworkLoadingFinished) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if entry, ok := r.pending[e.RequestID]; ok {
+		entry.Response.TransferSize = int(e.EncodedDataLength)
+		r.finish(entry, e.Timestamp)
+		delete(r.pending, e.RequestID)
+	}
+}
+
+func (r *harRecorder) loadingFailed(e *proto.NetworkLoadingFailed) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if entry, ok := r.pending[e.RequestID]; ok {
+		entry.Error = e.ErrorText
+		if e.BlockedReason != "" {
+			entry.Error += " (" + string(e.BlockedReason) + ")"
+		}
+		r.finish(entry, e.Timestamp)
+		delete(r.pending, e.RequestID)
+	}
+}
+
+// setResponse is called with r.mu held.
+func (r *harRecorder) setResponse(entry *harEntry, res *proto.NetworkResponse) {
+	entry.Response.Status = res.Status
+	entry.Response.StatusText = res.StatusText
+	entry.Response.HTTPVersion = har.HTTPVersion(res.Protocol)
+	entry.Response.Headers = r.headers(res.Headers)
+	entry.Response.Content.MimeType = res.MIMEType
+	entry.Request.HTTPVersion = entry.Response.HTTPVersion
+	if len(res.RequestHeaders) > 0 {
+		// The headers actually sent, cookies included.
+		entry.Request.Headers = r.headers(res.RequestHeaders)
+	}
+	entry.ServerIPAddress = res.RemoteIPAddress
+	entry.timing = res.Timing
+}
+
+// finish computes the timings of entry; it is called with r.mu held.
+func (r *harRecorder) finish(entry *harEntry, end proto.MonotonicTime) {
+	entry.done = true
+	entry.Time = float64(end-entry.start) * 1000
+	entry.Timings = harTimings(entry.timing, float64(entry.start), entry.Time)
+}
+
+// harTimings splits the total time of a request into HAR phases using the
+// CDP resource timing, whose offsets are milliseconds from RequestTime.
+func harTimings(t *proto.NetworkResourceTiming, start, total float64) har.Timings {
+	if t == nil {
+		// Served from cache or failed before a connection was made.
+		return har.Timings{Blocked: -1, DNS: -1, Connect: -1, SSL: -1, Wait: total}
+	}
+
+	span := func(from, to float64) float64 {
+		if from < 0 || to < 0 {
+			return -1
+		}
+		return to - from
+	}
+	queued := (t.RequestTime - start) * 1000
+	firstActivity := t.SendStart
+	for _, offset := range []float64{t.ConnectStart, t.DNSStart} {
+		if offset >= 0 {
+			firstActivity = offset
+		}
+	}
+
+	timings := har.Timings{
+		Blocked: queued + firstActivity,
+		DNS:     span(t.DNSStart, t.DNSEnd),
+		Connect: span(t.ConnectStart, t.ConnectEnd),
+		SSL:     span(t.SslStart, t.SslEnd),
+		Send:    t.SendEnd - t.SendStart,
+		Wait:    t.ReceiveHeadersEnd - t.SendEnd,
+		Receive: total - queued - t.ReceiveHeadersEnd,
+	}
+	if timings.Receive < 0 {
+		timings.Receive = 0
+	}
+	return timings
+}
+
+func (r *harRecorder) headers(headers proto.NetworkHeaders) []har.NameValue {
+	result := make([]har.NameValue, 0, len(headers))
+	for name, value := range headers {
+		header := har.Header(name, value.String())
+		header.Value = r.redact(header.Value)
+		result = append(result, header)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+func headerValue(headers proto.NetworkHeaders, name string) string {
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value.String()
+		}
+	}
+	return ""
+}
+
+func (r *harRecorder) queryString(rawURL string) []har.NameValue {
+	result := []har.NameValue{}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return result
+	}
+	for name, values := range u.Query() {
+		for _, value := range values {
+			result = append(result, har.NameValue{Name: name, Value: r.redact(value)})
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// close stops recording and writes the archive. Requests still running are
+// kept with the error "unfinished".
+func (r *harRecorder) close() error {
+	r.stop()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, entry := range r.entries {
+		if !entry.done {
+			entry.Error = "unfinished"
+			entry.Timings = harTimings(nil, 0, 0)
+		}
+		r.file.Log.Entries = append(r.file.Log.Entries, entry.Entry)
+	}
+	r.entries = nil
+	return har.WriteFile(r.path, r.file)
+}
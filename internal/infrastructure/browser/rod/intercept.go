@@ -0,0 +1,249 @@
+package rod
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"browser-agent/internal/domain/entity"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// adURLPattern matches the ad and tracking networks blocked by
+// InterceptConfig.BlockAds.
+const adURLPattern = `^https?://([^/]+\.)?(doubleclick\.net|googlesyndication\.com|googleadservices\.com|google-analytics\.com|googletagmanager\.com|adservice\.google\.[a-z.]+|connect\.facebook\.net|an\.yandex\.ru|mc\.yandex\.ru|ads\.yahoo\.com|amazon-adsystem\.com|adnxs\.com|criteo\.(com|net)|taboola\.com|outbrain\.com|scorecardresearch\.com|hotjar\.com)/`
+
+// InterceptConfig lists the rules applied from the start.
+type InterceptConfig struct {
+	// BlockResources are resource types never loaded, e.g. image, font,
+	// media.
+	BlockResources []string
+	BlockAds       bool
+	Rules          []entity.InterceptRule
+}
+
+// rules returns the configured rules, presets first.
+func (c InterceptConfig) rules() []entity.InterceptRule {
+	var rules []entity.InterceptRule
+	if len(c.BlockResources) > 0 {
+		rules = append(rules, entity.InterceptRule{Action: entity.InterceptBlock, ResourceTypes: c.BlockResources})
+	}
+	if c.BlockAds {
+		rules = append(rules, entity.InterceptRule{Action: entity.InterceptBlock, URLPattern: adURLPattern})
+	}
+	return append(rules, c.Rules...)
+}
+
+type compiledRule struct {
+	entity.InterceptRule
+	url *regexp.Regexp
+}
+
+// interceptor holds the rules applied to the requests of the page.
+type interceptor struct {
+	mu     sync.RWMutex
+	rules  []compiledRule
+	lastID int
+}
+
+func (i *interceptor) add(rule entity.InterceptRule) (entity.InterceptRule, error) {
+	if err := rule.Validate(); err != nil {
+		return entity.InterceptRule{}, err
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.lastID++
+	rule.ID = strconv.Itoa(i.lastID)
+	i.rules = append(i.rules, compiledRule{InterceptRule: rule, url: regexp.MustCompile(rule.URLPattern)})
+	return rule, nil
+}
+
+func (i *interceptor) remove(id string) bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for n, rule := range i.rules {
+		if rule.ID == id {
+			i.rules = append(i.rules[:n:n], i.rules[n+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (i *interceptor) list() []entity.InterceptRule {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	rules := make([]entity.InterceptRule, len(i.rules))
+	for n, rule := range i.rules {
+		rules[n] = rule.InterceptRule
+	}
+	return rules
+}
+
+func (i *interceptor) active() bool {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return len(i.rules) > 0
+}
+
+// handle applies the rules to a paused request: the first block or mock
+// rule answers it, otherwise it continues with the headers of the matching
+// header rules.
+func (i *interceptor) handle(h *rod.Hijack) {
+	url := h.Request.URL().String()
+	resourceType := strings.ToLower(string(h.Request.Type()))
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	extra := map[string]string{}
+	for _, rule := range i.rules {
+		if !rule.MatchesType(resourceType) || !rule.url.MatchString(url) {
+			continue
+		}
+		switch rule.Action {
+		case entity.InterceptBlock:
+			h.Response.Fail(proto.NetworkErrorReasonBlockedByClient)
+			return
+		case entity.InterceptMock:
+			mockResponse(h, rule.InterceptRule)
+			return
+		case entity.InterceptHeaders:
+			for name, value := range rule.Headers {
+				extra[name] = value
+			}
+		}
+	}
+
+	if len(extra) == 0 {
+		h.ContinueRequest(&proto.FetchContinueRequest{})
+		return
+	}
+	// Continuing with headers replaces all of them, so the page's own
+	// headers are sent along.
+	var headers []*proto.FetchHeaderEntry
+	for name, value := range h.Request.Headers() {
+		if _, ok := extra[name]; !ok {
+			headers = append(headers, &proto.FetchHeaderEntry{Name: name, Value: value.String()})
+		}
+	}
+	for name, value := range extra {
+		headers = append(headers, &proto.FetchHeaderEntry{Name: name, Value: value})
+	}
+	h.ContinueRequest(&proto.FetchContinueRequest{Headers: headers})
+}
+
+func mockResponse(h *rod.Hijack, rule entity.InterceptRule) {
+	contentType := rule.ContentType
+	if contentType == "" {
+		contentType = http.DetectContentType([]byte(rule.Body))
+	}
+	h.Response.Payload().ResponseCode = rule.MockStatus()
+	h.Response.SetHeader("Content-Type", contentType)
+	// Mocked API responses are usually read by scripts of another origin.
+	if _, ok := rule.Headers["Access-Control-Allow-Origin"]; !ok {
+		h.Response.SetHeader("Access-Control-Allow-Origin", "*")
+	}
+	for name, value := range rule.Headers {
+		h.Response.SetHeader(name, value)
+	}
+	h.Response.SetBody(rule.Body)
+}
+
+// routerScope is what the request router intercepts.
+type routerScope int
+
+const (
+	routerOff routerScope = iota
+	// routerDocuments is enough for the navigation guard alone.
+	routerDocuments
+	routerAll
+)
+
+// syncRouter starts, restarts or stops the request router shared by the
+// navigation guard and the intercept rules, so that it pauses only the
+// requests they need: pausing every request slows the page down.
+func (b *BrowserAdapter) syncRouter() error {
+	b.routerMu.Lock()
+	defer b.routerMu.Unlock()
+
+	scope := routerOff
+	switch {
+	case b.intercept.active():
+		scope = routerAll
+	case b.navPolicy.Active():
+		scope = routerDocuments
+	}
+	if scope == b.routerScope {
+		return nil
+	}
+
+	if b.router != nil {
+		_ = b.router.Stop()
+		b.router = nil
+	}
+	b.routerScope = routerOff
+	if scope == routerOff {
+		return nil
+	}
+
+	var resourceType proto.NetworkResourceType
+	if scope == routerDocuments {
+		resourceType = proto.NetworkResourceTypeDocument
+	}
+	router := b.page.HijackRequests()
+	if err := router.Add("*", resourceType, b.routeRequest); err != nil {
+		return fmt.Errorf("failed to intercept requests: %w", err)
+	}
+	go router.Run()
+
+	b.router = router
+	b.routerScope = scope
+	return nil
+}
+
+func (b *BrowserAdapter) routeRequest(h *rod.Hijack) {
+	if h.Request.IsNavigation() && b.guardDocumentRequest(h) {
+		return
+	}
+	b.intercept.handle(h)
+}
+
+func (b *BrowserAdapter) AddInterceptRule(ctx context.Context, rule entity.InterceptRule) (*entity.InterceptRule, error) {
+	if err := b.checkState(); err != nil {
+		return nil, err
+	}
+	added, err := b.intercept.add(rule)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.syncRouter(); err != nil {
+		b.intercept.remove(added.ID)
+		return nil, err
+	}
+	return &added, nil
+}
+
+func (b *BrowserAdapter) RemoveInterceptRule(ctx context.Context, id string) error {
+	if err := b.checkState(); err != nil {
+		return err
+	}
+	if !b.intercept.remove(id) {
+		return fmt.Errorf("intercept rule %q not found", id)
+	}
+	return b.syncRouter()
+}
+
+func (b *BrowserAdapter) InterceptRules(ctx context.Context) ([]entity.InterceptRule, error) {
+	if err := b.checkState(); err != nil {
+		return nil, err
+	}
+	return b.intercept.list(), nil
+}
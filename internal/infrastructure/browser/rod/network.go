@@ -0,0 +1,308 @@
+package rod
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"browser-agent/internal/domain/entity"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+const (
+	defaultNetworkMaxEntries   = 200
+	defaultNetworkMaxBodyBytes = 1 << 20
+)
+
+// NetworkConfig controls the capture of the page's traffic.
+type NetworkConfig struct {
+	Enabled bool
+	// BodyURLs are regular expressions; XHR and fetch responses whose URL
+	// matches one keep their body. Empty means every XHR and fetch response.
+	BodyURLs []string
+	// MaxEntries bounds the log; the oldest entries are dropped first.
+	MaxEntries   int
+	MaxBodyBytes int
+}
+
+// capturedTypes are the requests worth showing to the agent: API calls and
+// page loads, not images, styles or scripts.
+var capturedTypes = map[proto.NetworkResourceType]bool{
+	proto.NetworkResourceTypeXHR:      true,
+	proto.NetworkResourceTypeFetch:    true,
+	proto.NetworkResourceTypeDocument: true,
+}
+
+// networkLog records CDP Network events of one page.
+type networkLog struct {
+	page         *rod.Page
+	stop         func()
+	bodyURLs     []*regexp.Regexp
+	maxEntries   int
+	maxBodyBytes int
+	// fetch reads a response body from the browser.
+	fetch func(proto.NetworkRequestID) (*proto.NetworkGetResponseBodyResult, error)
+
+	mu        sync.Mutex
+	entries   []*entity.NetworkEntry
+	byRequest map[proto.NetworkRequestID]*entity.NetworkEntry
+	lastID    int
+}
+
+func newNetworkLog(page *rod.Page, cfg NetworkConfig) (*networkLog, error) {
+	l := &networkLog{
+		maxEntries:   cfg.MaxEntries,
+		maxBodyBytes: cfg.MaxBodyBytes,
+		byRequest:    make(map[proto.NetworkRequestID]*entity.NetworkEntry),
+	}
+	if l.maxEntries <= 0 {
+		l.maxEntries = defaultNetworkMaxEntries
+	}
+	if l.maxBodyBytes <= 0 {
+		l.maxBodyBytes = defaultNetworkMaxBodyBytes
+	}
+	for _, pattern := range cfg.BodyURLs {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid network body URL pattern %q: %w", pattern, err)
+		}
+		l.bodyURLs = append(l.bodyURLs, re)
+	}
+
+	// rod remembers the domain as enabled, so other event listeners, such
+	// as the network idle wait, leave it on when they finish.
+	var cancel func()
+	l.page, cancel = page.WithCancel()
+	l.fetch = func(id proto.NetworkRequestID) (*proto.NetworkGetResponseBodyResult, error) {
+		return proto.NetworkGetResponseBody{RequestID: id}.Call(l.page)
+	}
+	disable := l.page.EnableDomain(&proto.NetworkEnable{})
+	l.stop = func() {
+		cancel()
+		disable()
+	}
+
+	wait := l.page.EachEvent(l.requestWillBeSent, l.responseReceived, l.loadingFinished, l.loadingFailed)
+	go wait()
+	return l, nil
+}
+
+func (l *networkLog) requestWillBeSent(e *proto.NetworkRequestWillBeSent) {
+	if !capturedTypes[e.Type] {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// A redirect reuses the request ID; the entry follows it.
+	if entry, ok := l.byRequest[e.RequestID]; ok {
+		entry.URL = e.Request.URL
+		entry.Method = e.Request.Method
+		return
+	}
+
+	l.lastID++
+	entry := &entity.NetworkEntry{
+		ID:           strconv.Itoa(l.lastID),
+		Method:       e.Request.Method,
+		URL:          e.Request.URL,
+		ResourceType: strings.ToLower(string(e.Type)),
+		StartedAt:    time.Now(),
+		RequestBody:  truncateBody(e.Request.PostData, l.maxBodyBytes),
+	}
+	l.entries = append(l.entries, entry)
+	l.byRequest[e.RequestID] = entry
+
+	if len(l.entries) > l.maxEntries {
+		dropped := l.entries[0]
+		l.entries = l.entries[1:]
+		for id, e := range l.byRequest {
+			if e == dropped {
+				delete(l.byRequest, id)
+				break
+			}
+		}
+	}
+}
+
+func (l *networkLog) responseReceived(e *proto.NetworkResponseReceived) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if entry, ok := l.byRequest[e.RequestID]; ok && e.Response != nil {
+		entry.Status = e.Response.Status
+		entry.MIMEType = e.Response.MIMEType
+	}
+}
+
+func (l *networkLog) loadingFinished(e *proto.NetworkLoadingFinished) {
+	l.mu.Lock()
+	entry, ok := l.byRequest[e.RequestID]
+	if !ok {
+		l.mu.Unlock()
+		return
+	}
+	entry.Finished = true
+	entry.DurationMS = time.Since(entry.StartedAt).Milliseconds()
+	wantBody := l.wantsBody(entry)
+	entry.BodyPending = wantBody
+	l.mu.Unlock()
+
+	if wantBody {
+		// The body has to be fetched before the page discards it, but not
+		// from the event loop, which would stall the following events.
+		go l.fetchBody(e.RequestID, entry)
+	}
+}
+
+func (l *networkLog) loadingFailed(e *proto.NetworkLoadingFailed) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if entry, ok := l.byRequest[e.RequestID]; ok {
+		entry.Finished = true
+		entry.DurationMS = time.Since(entry.StartedAt).Milliseconds()
+		entry.Error = e.ErrorText
+	}
+}
+
+// wantsBody reports whether the response body of entry is kept. It is
+// called with l.mu held.
+func (l *networkLog) wantsBody(entry *entity.NetworkEntry) bool {
+	if entry.ResourceType == "document" || !isTextMIME(entry.MIMEType) {
+		return false
+	}
+	if len(l.bodyURLs) == 0 {
+		return true
+	}
+	for _, re := range l.bodyURLs {
+		if re.MatchString(entry.URL) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *networkLog) fetchBody(id proto.NetworkRequestID, entry *entity.NetworkEntry) {
+	body, ok := l.readBody(id)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entry.BodyPending = false
+	if !ok {
+		return
+	}
+	entry.BodySize = len(body)
+	entry.ResponseBody = truncateBody(body, l.maxBodyBytes)
+	entry.BodyTruncated = len(entry.ResponseBody) < len(body)
+}
+
+func (l *networkLog) readBody(id proto.NetworkRequestID) (string, bool) {
+	result, err := l.fetch(id)
+	if err != nil {
+		return "", false
+	}
+	if !result.Base64Encoded {
+		return result.Body, true
+	}
+	decoded, err := base64.StdEncoding.DecodeString(result.Body)
+	if err != nil {
+		return "", false
+	}
+	return string(decoded), true
+}
+
+// pending counts the requests still in flight.
+func (l *networkLog) pending() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	count := 0
+	for _, entry := range l.byRequest {
+		if !entry.Finished {
+			count++
+		}
+	}
+	return count
+}
+
+// list returns copies of the entries without bodies.
+func (l *networkLog) list() []entity.NetworkEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries := make([]entity.NetworkEntry, len(l.entries))
+	for i, entry := range l.entries {
+		entries[i] = *entry
+		entries[i].RequestBody = ""
+		entries[i].ResponseBody = ""
+	}
+	return entries
+}
+
+func (l *networkLog) get(id string) (entity.NetworkEntry, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, entry := range l.entries {
+		if entry.ID == id {
+			return *entry, true
+		}
+	}
+	return entity.NetworkEntry{}, false
+}
+
+func (l *networkLog) close() {
+	l.stop()
+}
+
+func isTextMIME(mime string) bool {
+	mime = strings.ToLower(mime)
+	return strings.HasPrefix(mime, "text/") ||
+		strings.Contains(mime, "json") ||
+		strings.Contains(mime, "xml") ||
+		strings.Contains(mime, "javascript") ||
+		strings.Contains(mime, "x-www-form-urlencoded")
+}
+
+func truncateBody(body string, limit int) string {
+	if len(body) <= limit {
+		return body
+	}
+	return strings.ToValidUTF8(body[:limit], "")
+}
+
+func (b *BrowserAdapter) NetworkRequests(ctx context.Context, filter entity.NetworkFilter) ([]entity.NetworkEntry, error) {
+	if err := b.checkState(); err != nil {
+		return nil, err
+	}
+	if b.network == nil {
+		return nil, ErrNetworkCaptureDisabled
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	return filter.Apply(b.network.list()), nil
+}
+
+func (b *BrowserAdapter) NetworkRequest(ctx context.Context, id string) (*entity.NetworkEntry, error) {
+	if err := b.checkState(); err != nil {
+		return nil, err
+	}
+	if b.network == nil {
+		return nil, ErrNetworkCaptureDisabled
+	}
+	entry, ok := b.network.get(id)
+	if !ok {
+		return nil, fmt.Errorf("network request %q not found (it may have been dropped from the log)", id)
+	}
+	return &entry, nil
+}
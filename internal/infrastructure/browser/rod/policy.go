@@ -0,0 +1,59 @@
+package rod
+
+import (
+	"context"
+
+	"browser-agent/internal/domain/policy"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// guardDocumentRequest fails document requests (top-level navigations,
+// redirects and frames) that violate the policy and reports whether it did.
+func (b *BrowserAdapter) guardDocumentRequest(h *rod.Hijack) bool {
+	v := b.navPolicy.Check(h.Request.URL().String())
+	if v == nil {
+		return false
+	}
+	b.recordViolation(v)
+	h.Response.Fail(proto.NetworkErrorReasonBlockedByClient)
+	return true
+}
+
+func (b *BrowserAdapter) recordViolation(v *policy.Violation) {
+	b.violationMu.Lock()
+	defer b.violationMu.Unlock()
+	b.violation = v
+}
+
+func (b *BrowserAdapter) takeViolation() *policy.Violation {
+	b.violationMu.Lock()
+	defer b.violationMu.Unlock()
+	v := b.violation
+	b.violation = nil
+	return v
+}
+
+// checkNavigationResult reports a violation caused by the last action. Besides
+// requests blocked by the guard it also catches client-side URL changes
+// (history.pushState) that never reach the network, and returns the page to
+// the previous entry in that case.
+func (b *BrowserAdapter) checkNavigationResult(ctx context.Context) error {
+	if !b.navPolicy.Active() {
+		return nil
+	}
+
+	violation := b.takeViolation()
+	if violation == nil {
+		violation = b.navPolicy.Check(b.CurrentURL())
+	}
+	if violation == nil {
+		return nil
+	}
+
+	if b.navPolicy.Check(b.CurrentURL()) != nil {
+		_ = b.page.Context(ctx).NavigateBack()
+	}
+	return violation
+}
@@ -0,0 +1,127 @@
+package rod
+
+import (
+	"sync"
+
+	"browser-agent/internal/domain/policy"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// popupGuard applies the navigation policy to the tabs a page opens with
+// window.open or target=_blank links. The adapter drives only its own
+// page, so they would otherwise load any URL: each one gets a router that
+// fails forbidden document requests, and a tab that still ends up on a
+// forbidden URL, e.g. because it navigated before its router was ready, is
+// closed.
+type popupGuard struct {
+	browser *rod.Browser
+	opener  proto.TargetTargetID
+	policy  policy.Navigation
+	report  func(*policy.Violation)
+	stop    func()
+
+	mu      sync.Mutex
+	routers map[proto.TargetTargetID]*rod.HijackRouter
+	closed  bool
+}
+
+func newPopupGuard(browser *rod.Browser, page *rod.Page, navPolicy policy.Navigation, report func(*policy.Violation)) (*popupGuard, error) {
+	if err := (proto.TargetSetDiscoverTargets{Discover: true}).Call(browser); err != nil {
+		return nil, err
+	}
+	g := &popupGuard{
+		opener:  page.TargetID,
+		policy:  navPolicy,
+		report:  report,
+		routers: make(map[proto.TargetTargetID]*rod.HijackRouter),
+	}
+	g.browser, g.stop = browser.WithCancel()
+
+	wait := g.browser.EachEvent(g.targetCreated, g.targetInfoChanged, g.targetDestroyed)
+	go wait()
+	return g, nil
+}
+
+func (g *popupGuard) targetCreated(e *proto.TargetTargetCreated) {
+	if !g.ownPopup(e.TargetInfo) || g.closeIfForbidden(e.TargetInfo) {
+		return
+	}
+	// Attaching calls the browser, which must not block the event loop.
+	go g.guard(e.TargetInfo.TargetID)
+}
+
+func (g *popupGuard) targetInfoChanged(e *proto.TargetTargetInfoChanged) {
+	if g.ownPopup(e.TargetInfo) {
+		g.closeIfForbidden(e.TargetInfo)
+	}
+}
+
+func (g *popupGuard) targetDestroyed(e *proto.TargetTargetDestroyed) {
+	g.mu.Lock()
+	router := g.routers[e.TargetID]
+	delete(g.routers, e.TargetID)
+	g.mu.Unlock()
+	if router != nil {
+		_ = router.Stop()
+	}
+}
+
+func (g *popupGuard) ownPopup(info *proto.TargetTargetInfo) bool {
+	return info != nil && info.Type == proto.TargetTargetInfoTypePage && info.OpenerID == g.opener
+}
+
+func (g *popupGuard) closeIfForbidden(info *proto.TargetTargetInfo) bool {
+	violation := g.policy.Check(info.URL)
+	if violation == nil {
+		return false
+	}
+	g.report(violation)
+	_, _ = proto.TargetCloseTarget{TargetID: info.TargetID}.Call(g.browser)
+	return true
+}
+
+func (g *popupGuard) guard(targetID proto.TargetTargetID) {
+	page, err := g.browser.PageFromTarget(targetID)
+	if err != nil {
+		return
+	}
+	router := page.HijackRequests()
+	err = router.Add("*", proto.NetworkResourceTypeDocument, func(h *rod.Hijack) {
+		if h.Request.IsNavigation() {
+			if violation := g.policy.Check(h.Request.URL().String()); violation != nil {
+				g.report(violation)
+				h.Response.Fail(proto.NetworkErrorReasonBlockedByClient)
+				return
+			}
+		}
+		h.ContinueRequest(&proto.FetchContinueRequest{})
+	})
+	if err != nil {
+		return
+	}
+
+	g.mu.Lock()
+	if g.closed {
+		g.mu.Unlock()
+		return
+	}
+	g.routers[targetID] = router
+	g.mu.Unlock()
+	go router.Run()
+}
+
+func (g *popupGuard) close() {
+	g.stop()
+
+	g.mu.Lock()
+	g.closed = true
+	routers := g.routers
+	g.routers = nil
+	g.mu.Unlock()
+
+	for _, router := range routers {
+		_ = router.Stop()
+	}
+}
@@ -0,0 +1,109 @@
+package rod
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// attachRemote connects to the browser at config.RemoteURL and takes over
+// one of its tabs, opening a new one only when it has none. The returned
+// function drops the connection without closing the browser.
+func attachRemote(ctx context.Context, config BrowserConfig) (*rod.Browser, *rod.Page, context.CancelFunc, error) {
+	browser, disconnect, err := connectRemote(config)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	targets, err := proto.TargetGetTargets{}.Call(browser.Context(ctx))
+	if err != nil {
+		disconnect()
+		return nil, nil, nil, fmt.Errorf("failed to list tabs: %w", err)
+	}
+	var page *rod.Page
+	target, err := pickTab(targets.TargetInfos, config.RemoteTab)
+	switch {
+	case err != nil:
+	case target != nil:
+		page, err = browser.PageFromTarget(target.TargetID)
+		if err == nil {
+			_, _ = page.Activate()
+		}
+	default:
+		page, err = browser.Page(proto.TargetCreateTarget{URL: "about:blank"})
+	}
+	if err != nil {
+		disconnect()
+		return nil, nil, nil, err
+	}
+	return browser, page, disconnect, nil
+}
+
+// connectRemote connects to the browser at config.RemoteURL. The
+// connection lives until disconnect, not until a task's context is done.
+func connectRemote(config BrowserConfig) (*rod.Browser, context.CancelFunc, error) {
+	wsURL, err := resolveRemoteURL(config.RemoteURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to reach browser at %s: %w", config.RemoteURL, err)
+	}
+
+	connCtx, disconnect := context.WithCancel(context.Background())
+	browser := rod.New().
+		Context(connCtx).
+		ControlURL(wsURL).
+		Trace(config.EnableTrace).
+		SlowMotion(config.SlowMotion)
+	if err := browser.Connect(); err != nil {
+		disconnect()
+		return nil, nil, fmt.Errorf("failed to connect to browser at %s: %w", config.RemoteURL, err)
+	}
+	return browser, disconnect, nil
+}
+
+// resolveRemoteURL takes a websocket URL as is, since hosted browsers
+// often serve no /json/version, and asks host:port for its websocket URL.
+func resolveRemoteURL(remoteURL string) (string, error) {
+	if strings.HasPrefix(remoteURL, "ws://") || strings.HasPrefix(remoteURL, "wss://") {
+		return remoteURL, nil
+	}
+	return launcher.ResolveURL(remoteURL)
+}
+
+// pickTab returns the first regular tab whose URL or title contains match,
+// or the first regular tab when match is empty. It returns nil without an
+// error when there is no tab at all.
+func pickTab(targets []*proto.TargetTargetInfo, match string) (*proto.TargetTargetInfo, error) {
+	var tabs []*proto.TargetTargetInfo
+	for _, target := range targets {
+		if target.Type != proto.TargetTargetInfoTypePage || isInternalTab(target.URL) {
+			continue
+		}
+		if match == "" || strings.Contains(target.URL, match) || strings.Contains(target.Title, match) {
+			return target, nil
+		}
+		tabs = append(tabs, target)
+	}
+	if match != "" {
+		urls := make([]string, len(tabs))
+		for i, tab := range tabs {
+			urls[i] = tab.URL
+		}
+		return nil, fmt.Errorf("no tab matches %q (open tabs: %s)", match, strings.Join(urls, ", "))
+	}
+	return nil, nil
+}
+
+// isInternalTab reports the devtools and extension pages a browser lists
+// as tabs, which the agent must not take over.
+func isInternalTab(url string) bool {
+	for _, prefix := range []string{"devtools://", "chrome-extension://"} {
+		if strings.HasPrefix(url, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package rod
+
+import (
+	"strings"
+
+	"github.com/go-rod/rod"
+)
+
+// sensitiveAutocomplete lists autocomplete hints of fields whose values must
+// not leak into logs even when the field is not type=password.
+var sensitiveAutocomplete = map[string]bool{
+	"current-password": true,
+	"new-password":     true,
+	"one-time-code":    true,
+	"cc-number":        true,
+	"cc-csc":           true,
+}
+
+// reportSensitiveInput passes text to the OnSensitiveInput callback when the
+// element it is typed into holds a password or a similar secret.
+func (b *BrowserAdapter) reportSensitiveInput(element *rod.Element, text string) {
+	if b.onSensitiveInput == nil || text == "" {
+		return
+	}
+	if isSensitiveField(element) {
+		b.onSensitiveInput(text)
+	}
+}
+
+func isSensitiveField(element *rod.Element) bool {
+	if inputType, err := element.Attribute("type"); err == nil && inputType != nil &&
+		strings.EqualFold(*inputType, "password") {
+		return true
+	}
+
+	autocomplete, err := element.Attribute("autocomplete")
+	if err != nil || autocomplete == nil {
+		return false
+	}
+	for _, token := range strings.Fields(strings.ToLower(*autocomplete)) {
+		if sensitiveAutocomplete[token] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package rod
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// SharedBrowser is one launched browser process whose incognito contexts
+// serve as isolated browsers: they share no cookies, storage or cache with
+// each other.
+type SharedBrowser struct {
+	browser  *rod.Browser
+	launcher *launcher.Launcher
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewSharedBrowser launches a browser without opening a page in it. An
+// attached browser (config.RemoteURL) is not shared this way: its tab is
+// used as it is, see NewBrowserAdapter.
+func NewSharedBrowser(ctx context.Context, config BrowserConfig) (*SharedBrowser, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if config.RemoteURL != "" {
+		return nil, errors.New("a remote browser cannot be shared by incognito contexts")
+	}
+
+	browser, launcherInstance, err := launch(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	return &SharedBrowser{browser: browser, launcher: launcherInstance}, nil
+}
+
+// NewContext opens a page in a new incognito context and sets it up like
+// NewBrowserAdapter does; config's launch and remote options are ignored.
+// Closing the adapter disposes the context and leaves the browser running.
+func (s *SharedBrowser) NewContext(ctx context.Context, config BrowserConfig) (*BrowserAdapter, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	s.mu.Lock()
+	closed := s.closed
+	s.mu.Unlock()
+	if closed {
+		return nil, ErrBrowserNotConnected
+	}
+
+	incognito, err := s.browser.Incognito()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create browser context: %w", err)
+	}
+	page, err := incognito.Page(proto.TargetCreateTarget{URL: "about:blank"})
+	if err != nil {
+		_ = incognito.Close()
+		return nil, fmt.Errorf("failed to open page: %w", err)
+	}
+	return newAdapter(ctx, incognito, page, nil, nil, config)
+}
+
+// Close closes the browser. Adapters made by NewContext stop working.
+func (s *SharedBrowser) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+	s.closed = true
+
+	_ = s.browser.Close()
+	if s.launcher != nil {
+		s.launcher.Kill()
+		s.launcher.Cleanup()
+	}
+}
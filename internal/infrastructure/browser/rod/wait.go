@@ -0,0 +1,177 @@
+package rod
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"browser-agent/internal/domain/entity"
+)
+
+const waitPollInterval = 100 * time.Millisecond
+
+// visibleJS reports whether an element matching the selector is rendered
+// with a non-empty box.
+const visibleJS = `(selector, xpath) => {
+	const el = xpath
+		? document.evaluate(selector, document, null, XPathResult.FIRST_ORDERED_NODE_TYPE, null).singleNodeValue
+		: document.querySelector(selector);
+	if (!el) return false;
+	const style = getComputedStyle(el);
+	if (style.display === 'none' || style.visibility === 'hidden') return false;
+	const rect = el.getBoundingClientRect();
+	return rect.width > 0 && rect.height > 0;
+}`
+
+const textJS = `(text) => !!document.body && document.body.innerText.includes(text)`
+
+func (b *BrowserAdapter) WaitFor(ctx context.Context, req entity.WaitRequest) (*entity.WaitResult, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stopWatch := b.watchDialogs(ctx)
+	defer stopWatch()
+
+	result, err := b.waitFor(ctx, req)
+	if dialogErr := b.dialogError(nil); dialogErr != nil {
+		return nil, dialogErr
+	}
+	return result, err
+}
+
+func (b *BrowserAdapter) waitFor(ctx context.Context, req entity.WaitRequest) (*entity.WaitResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := b.checkPage(); err != nil {
+		return nil, err
+	}
+
+	timeout := b.GetTimeout()
+	if req.TimeoutMS > 0 {
+		timeout = time.Duration(req.TimeoutMS) * time.Millisecond
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	var err, scriptErr error
+	if req.Condition == entity.WaitNetworkIdle {
+		err = b.waitNetworkIdle(waitCtx, req)
+	} else {
+		scriptErr, err = b.poll(waitCtx, req)
+	}
+
+	result := &entity.WaitResult{Met: err == nil, ElapsedMS: time.Since(start).Milliseconds(), URL: b.CurrentURL()}
+	if scriptErr != nil && err != nil {
+		result.ScriptError = scriptErr.Error()
+	}
+	if err != nil && waitCtx.Err() != nil && ctx.Err() == nil {
+		// The timeout expired: a result, not a failure.
+		return result, nil
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("%w: %v", ErrContextCanceled, ctx.Err())
+		}
+		return nil, fmt.Errorf("wait for %s failed: %w", req.Describe(), err)
+	}
+	return result, nil
+}
+
+// poll checks the condition every waitPollInterval until it holds or ctx
+// expires. Evaluation errors are retried, since the page may be navigating
+// or a script may refer to objects that do not exist yet; the last one is
+// returned alongside the timeout.
+func (b *BrowserAdapter) poll(ctx context.Context, req entity.WaitRequest) (lastErr, err error) {
+	var pattern *regexp.Regexp
+	if req.Condition == entity.WaitURL {
+		pattern = regexp.MustCompile(req.URLPattern)
+	}
+
+	ticker := time.NewTicker(waitPollInterval)
+	defer ticker.Stop()
+
+	for {
+		var met bool
+		if pattern != nil {
+			met = pattern.MatchString(b.CurrentURL())
+		} else {
+			met, lastErr = b.check(ctx, req)
+		}
+		if met {
+			return nil, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return lastErr, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (b *BrowserAdapter) check(ctx context.Context, req entity.WaitRequest) (bool, error) {
+	page := b.page.Context(ctx)
+
+	var (
+		js   string
+		args []interface{}
+	)
+	switch req.Condition {
+	case entity.WaitVisible, entity.WaitHidden:
+		selector := strings.TrimPrefix(strings.TrimSpace(req.Selector), "xpath=")
+		js, args = visibleJS, []interface{}{selector, isXPathSelector(req.Selector)}
+	case entity.WaitText:
+		js, args = textJS, []interface{}{req.Text}
+	case entity.WaitScript:
+		js = "() => !!(" + req.Script + ")"
+	}
+
+	result, err := page.Eval(js, args...)
+	if err != nil {
+		return false, err
+	}
+	met := result.Value.Bool()
+	if req.Condition == entity.WaitHidden {
+		met = !met
+	}
+	return met, nil
+}
+
+// waitNetworkIdle returns once no request has been in flight for the idle
+// period. rod's idle wait only sees requests that start after it subscribes,
+// so the XHR a previous click started is taken from the network log: the
+// wait does not begin while it is pending and starts over if one still is.
+func (b *BrowserAdapter) waitNetworkIdle(ctx context.Context, req entity.WaitRequest) error {
+	for {
+		if b.inFlightRequests() > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(waitPollInterval):
+			}
+			continue
+		}
+
+		b.page.Context(ctx).WaitRequestIdle(req.IdlePeriod(), nil, nil, nil)()
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if b.inFlightRequests() == 0 {
+			return nil
+		}
+	}
+}
+
+// inFlightRequests counts the captured requests that have not finished;
+// without network capture nothing is known about earlier requests.
+func (b *BrowserAdapter) inFlightRequests() int {
+	if b.network == nil {
+		return 0
+	}
+	return b.network.pending()
+}
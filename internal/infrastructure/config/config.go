@@ -0,0 +1,348 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"browser-agent/internal/domain/entity"
+)
+
+// Config is the complete agent configuration. Every field can be set in the
+// config file under its yaml path and overridden by the environment variable
+// in its env tag.
+type Config struct {
+	LLM      LLM      `yaml:"llm"`
+	Browser  Browser  `yaml:"browser"`
+	Agents   Agents   `yaml:"agents"`
+	Budgets  Budgets  `yaml:"budgets"`
+	Policies Policies `yaml:"policies"`
+	Secrets  Secrets  `yaml:"secrets"`
+	Logging  Logging  `yaml:"logging"`
+	Output   Output   `yaml:"output"`
+	Server   Server   `yaml:"server"`
+}
+
+type LLM struct {
+	APIKey         string `yaml:"api_key" env:"OPENROUTER_API_KEY"`
+	Model          string `yaml:"model" env:"OPENROUTER_MODEL_NAME"`
+	ThinkingMode   bool   `yaml:"thinking_mode" env:"THINKING_MODE"`
+	ThinkingBudget int    `yaml:"thinking_budget" env:"THINKING_BUDGET"`
+	// Redact scrubs messages sent to the model with the redaction policy.
+	Redact bool `yaml:"redact" env:"REDACT_LLM"`
+	// PromptPrice and CompletionPrice are USD per million tokens; they give
+	// the cost totals of run transcripts.
+	PromptPrice     float64 `yaml:"prompt_price" env:"LLM_PROMPT_PRICE"`
+	CompletionPrice float64 `yaml:"completion_price" env:"LLM_COMPLETION_PRICE"`
+}
+
+type Browser struct {
+	// Headless is nil when not configured; each command has its own default.
+	Headless *bool `yaml:"headless" env:"BROWSER_HEADLESS"`
+	Trace    bool  `yaml:"trace" env:"BROWSER_TRACE"`
+	// RemoteURL attaches to a running Chrome instead of launching one: a
+	// DevTools websocket URL or the host:port of --remote-debugging-port.
+	// RemoteTab picks its tab by a part of the URL or title.
+	RemoteURL string `yaml:"remote_url" env:"BROWSER_REMOTE_URL"`
+	RemoteTab string `yaml:"remote_tab" env:"BROWSER_REMOTE_TAB"`
+	// HAR records the traffic of each run to a .har file next to its log.
+	HAR      bool   `yaml:"har" env:"BROWSER_HAR"`
+	StartURL string `yaml:"start_url" env:"START_URL"`
+	// DialogPolicy is agent, accept or dismiss, see entity.DialogPolicy.
+	DialogPolicy string     `yaml:"dialog_policy" env:"BROWSER_DIALOG_POLICY"`
+	Emulation    Emulation  `yaml:"emulation"`
+	Screenshot   Screenshot `yaml:"screenshot"`
+	Network      Network    `yaml:"network"`
+	Intercept    Intercept  `yaml:"intercept"`
+	Pool         Pool       `yaml:"pool"`
+}
+
+// Pool configures the browsers of tasks run at once by serve and batch.
+type Pool struct {
+	// Isolation is context for an incognito context of one browser per
+	// task or instance for a separate browser per task.
+	Isolation string `yaml:"isolation" env:"BROWSER_POOL_ISOLATION"`
+	// MaxUses closes an instance after that many tasks; zero reuses it
+	// while it stays healthy. It is an error with context isolation, whose
+	// contexts are never reused.
+	MaxUses int `yaml:"max_uses" env:"BROWSER_POOL_MAX_USES"`
+}
+
+// Emulation is applied to the page before the first navigation; empty
+// fields keep the browser's own values.
+type Emulation struct {
+	// Device is a preset such as iphone-15 or pixel-7, see entity.Devices.
+	Device string `yaml:"device" env:"BROWSER_DEVICE"`
+	// Viewport is WIDTHxHEIGHT, e.g. 1366x768, and overrides the device's.
+	Viewport  string `yaml:"viewport" env:"BROWSER_VIEWPORT"`
+	UserAgent string `yaml:"user_agent" env:"BROWSER_USER_AGENT"`
+	Locale    string `yaml:"locale" env:"BROWSER_LOCALE"`
+	Timezone  string `yaml:"timezone" env:"BROWSER_TIMEZONE"`
+	// Geolocation is "latitude,longitude[,accuracy]".
+	Geolocation string `yaml:"geolocation" env:"BROWSER_GEOLOCATION"`
+	ColorScheme string `yaml:"color_scheme" env:"BROWSER_COLOR_SCHEME"`
+}
+
+// Screenshot holds the defaults of browser_screenshot; the agent can
+// override them per call.
+type Screenshot struct {
+	// Format is png or jpeg.
+	Format  string `yaml:"format" env:"SCREENSHOT_FORMAT"`
+	Quality int    `yaml:"quality" env:"SCREENSHOT_QUALITY"`
+	// MaxWidth scales wider screenshots down; zero scales the viewport to
+	// entity.DefaultViewportMaxWidth and keeps full-page and element
+	// captures as captured.
+	MaxWidth int `yaml:"max_width" env:"SCREENSHOT_MAX_WIDTH"`
+}
+
+type Network struct {
+	Capture bool `yaml:"capture" env:"NETWORK_CAPTURE"`
+	// BodyURLs are regular expressions; empty keeps every XHR/fetch body.
+	BodyURLs     []string `yaml:"body_urls" env:"NETWORK_BODY_URLS"`
+	MaxEntries   int      `yaml:"max_entries" env:"NETWORK_MAX_ENTRIES"`
+	MaxBodyBytes int      `yaml:"max_body_bytes" env:"NETWORK_MAX_BODY_BYTES"`
+}
+
+type Intercept struct {
+	// BlockResources are resource types never loaded, e.g. image,font,media.
+	BlockResources []string `yaml:"block_resources" env:"INTERCEPT_BLOCK_RESOURCES"`
+	BlockAds       bool     `yaml:"block_ads" env:"INTERCEPT_BLOCK_ADS"`
+	// Rules are written one per line, see entity.ParseInterceptRule.
+	Rules []string `yaml:"rules" env:"INTERCEPT_RULES" sep:";"`
+}
+
+type Agents struct {
+	// MaxIterations limits orchestrator turns; SubAgentMaxIterations limits
+	// each run_agent call. Zero keeps the built-in limits.
+	MaxIterations         int `yaml:"max_iterations" env:"MAX_ITERATIONS"`
+	SubAgentMaxIterations int `yaml:"sub_agent_max_iterations" env:"SUB_AGENT_MAX_ITERATIONS"`
+}
+
+type Budgets struct {
+	TaskTimeout      time.Duration `yaml:"task_timeout" env:"TASK_TIMEOUT"`
+	BatchConcurrency int           `yaml:"batch_concurrency" env:"BATCH_CONCURRENCY"`
+}
+
+type Policies struct {
+	Approval   Approval   `yaml:"approval"`
+	Navigation Navigation `yaml:"navigation"`
+	Redaction  Redaction  `yaml:"redaction"`
+}
+
+type Approval struct {
+	Enabled        bool     `yaml:"enabled" env:"APPROVAL_ENABLED"`
+	ConfirmSubmits bool     `yaml:"confirm_submits" env:"APPROVAL_CONFIRM_SUBMITS"`
+	Keywords       []string `yaml:"keywords" env:"APPROVAL_KEYWORDS"`
+	// Rules use the APPROVAL_RULES syntax: "decision:host[:tools]" items.
+	Rules []string `yaml:"rules" env:"APPROVAL_RULES" sep:";"`
+}
+
+type Navigation struct {
+	AllowedHosts   []string `yaml:"allowed_hosts" env:"NAV_ALLOWED_HOSTS"`
+	DeniedHosts    []string `yaml:"denied_hosts" env:"NAV_DENIED_HOSTS"`
+	BlockedPaths   []string `yaml:"blocked_paths" env:"NAV_BLOCKED_PATHS"`
+	DenyFileAccess bool     `yaml:"deny_file_access" env:"NAV_DENY_FILE_ACCESS"`
+}
+
+type Redaction struct {
+	Enabled bool `yaml:"enabled" env:"REDACT_ENABLED"`
+	// Patterns are built-in pattern names; empty means the defaults.
+	Patterns       []string `yaml:"patterns" env:"REDACT_PATTERNS"`
+	CustomPatterns []string `yaml:"custom_patterns" env:"REDACT_CUSTOM_PATTERNS" sep:";"`
+}
+
+type Secrets struct {
+	File       string `yaml:"file" env:"SECRETS_FILE"`
+	Passphrase string `yaml:"passphrase" env:"SECRETS_PASSPHRASE"`
+}
+
+type Logging struct {
+	Level string `yaml:"level" env:"LOG_LEVEL"`
+}
+
+type Output struct {
+	Format string `yaml:"format" env:"OUTPUT_FORMAT"`
+}
+
+type Server struct {
+	Addr  string `yaml:"addr" env:"SERVE_ADDR"`
+	Token string `yaml:"token" env:"SERVE_TOKEN"`
+	// Workers is how many tasks run at once, each in its own browser.
+	Workers int `yaml:"workers" env:"SERVE_WORKERS"`
+}
+
+func Default() Config {
+	return Config{
+		LLM: LLM{
+			ThinkingMode:   true,
+			ThinkingBudget: 10000,
+		},
+		Browser: Browser{
+			DialogPolicy: string(entity.DialogPolicyAgent),
+			Screenshot:   Screenshot{Format: string(entity.ImageFormatPNG)},
+			Network:      Network{Capture: true, MaxEntries: 200, MaxBodyBytes: 1 << 20},
+			Pool:         Pool{Isolation: "context"},
+		},
+		Budgets: Budgets{
+			TaskTimeout:      30 * time.Minute,
+			BatchConcurrency: 1,
+		},
+		Policies: Policies{
+			Approval:  Approval{Enabled: true, ConfirmSubmits: true},
+			Redaction: Redaction{Enabled: true},
+		},
+		Logging: Logging{Level: "debug"},
+		Output:  Output{Format: "text"},
+		Server:  Server{Addr: "127.0.0.1:8080", Workers: 1},
+	}
+}
+
+// HeadlessOr returns the configured headless mode or def when unset.
+func (c *Config) HeadlessOr(def bool) bool {
+	if c.Browser.Headless == nil {
+		return def
+	}
+	return *c.Browser.Headless
+}
+
+// Validate reports every invalid setting at once.
+func (c *Config) Validate() error {
+	return errors.Join(c.validateLLM(), c.ValidateWithoutLLM())
+}
+
+func (c *Config) validateLLM() error {
+	var errs []error
+	if c.LLM.APIKey == "" {
+		errs = append(errs, errors.New("llm.api_key is not set (OPENROUTER_API_KEY)"))
+	}
+	if c.LLM.Model == "" {
+		errs = append(errs, errors.New("llm.model is not set (OPENROUTER_MODEL_NAME or --model)"))
+	}
+	return errors.Join(errs...)
+}
+
+// ValidateWithoutLLM skips the model credentials, for commands that never
+// call the LLM.
+func (c *Config) ValidateWithoutLLM() error {
+	var errs []error
+	if c.LLM.ThinkingBudget < 0 {
+		errs = append(errs, fmt.Errorf("llm.thinking_budget must not be negative, got %d", c.LLM.ThinkingBudget))
+	}
+	if c.LLM.PromptPrice < 0 || c.LLM.CompletionPrice < 0 {
+		errs = append(errs, errors.New("llm: prices must not be negative"))
+	}
+	if c.Agents.MaxIterations < 0 || c.Agents.SubAgentMaxIterations < 0 {
+		errs = append(errs, errors.New("agents: iteration limits must not be negative"))
+	}
+	if c.Budgets.TaskTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("budgets.task_timeout must be positive, got %s", c.Budgets.TaskTimeout))
+	}
+	if c.Budgets.BatchConcurrency < 1 {
+		errs = append(errs, fmt.Errorf("budgets.batch_concurrency must be at least 1, got %d", c.Budgets.BatchConcurrency))
+	}
+	for _, pattern := range c.Browser.Network.BodyURLs {
+		if _, err := regexp.Compile(pattern); err != nil {
+			errs = append(errs, fmt.Errorf("browser.network.body_urls: invalid pattern %q: %w", pattern, err))
+		}
+	}
+	if len(c.Browser.Intercept.BlockResources) > 0 {
+		rule := entity.InterceptRule{Action: entity.InterceptBlock, ResourceTypes: c.Browser.Intercept.BlockResources}
+		if err := rule.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("browser.intercept.block_resources: %w", err))
+		}
+	}
+	for _, line := range c.Browser.Intercept.Rules {
+		if _, err := entity.ParseInterceptRule(line); err != nil {
+			errs = append(errs, fmt.Errorf("browser.intercept.rules: %w", err))
+		}
+	}
+	if err := validateRemoteURL(c.Browser.RemoteURL); err != nil {
+		errs = append(errs, fmt.Errorf("browser.remote_url: %w", err))
+	}
+	if c.Browser.RemoteTab != "" && c.Browser.RemoteURL == "" {
+		errs = append(errs, errors.New("browser.remote_tab needs browser.remote_url"))
+	}
+	if err := entity.DialogPolicy(c.Browser.DialogPolicy).Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("browser.dialog_policy: %w", err))
+	}
+	errs = append(errs, c.Browser.Emulation.validate())
+	if err := c.Browser.Screenshot.Request().Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("browser.screenshot: %w", err))
+	}
+	if c.Browser.Network.MaxEntries < 0 || c.Browser.Network.MaxBodyBytes < 0 {
+		errs = append(errs, errors.New("browser.network: limits must not be negative"))
+	}
+	switch c.Browser.Pool.Isolation {
+	case "context":
+		if c.Browser.Pool.MaxUses > 0 {
+			errs = append(errs, errors.New("browser.pool.max_uses only applies to isolation instance: a context is never reused"))
+		}
+	case "instance":
+		if c.Browser.RemoteURL != "" {
+			errs = append(errs, errors.New("browser.pool.isolation instance cannot be used with browser.remote_url, which is a single browser"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("browser.pool.isolation %q is not one of context, instance", c.Browser.Pool.Isolation))
+	}
+	if c.Browser.Pool.MaxUses < 0 {
+		errs = append(errs, fmt.Errorf("browser.pool.max_uses must not be negative, got %d", c.Browser.Pool.MaxUses))
+	}
+	if c.Server.Workers < 1 {
+		errs = append(errs, fmt.Errorf("server.workers must be at least 1, got %d", c.Server.Workers))
+	}
+	switch strings.ToLower(c.Logging.Level) {
+	case "debug", "info", "warn", "warning", "error":
+	default:
+		errs = append(errs, fmt.Errorf("logging.level %q is not one of debug, info, warn, error", c.Logging.Level))
+	}
+	if c.Output.Format != "text" && c.Output.Format != "json" {
+		errs = append(errs, fmt.Errorf("output.format %q is not one of text, json", c.Output.Format))
+	}
+	return errors.Join(errs...)
+}
+
+func validateRemoteURL(remoteURL string) error {
+	scheme, _, found := strings.Cut(remoteURL, "://")
+	if !found {
+		return nil
+	}
+	switch scheme {
+	case "ws", "wss", "http", "https":
+		return nil
+	}
+	return fmt.Errorf("unsupported scheme %q in %q (want ws://, wss://, http:// or host:port)", scheme, remoteURL)
+}
+
+// Request returns the defaults as a capture request.
+func (s Screenshot) Request() entity.CaptureRequest {
+	return entity.CaptureRequest{Format: entity.ImageFormat(s.Format), Quality: s.Quality, MaxWidth: s.MaxWidth}
+}
+
+func (e Emulation) validate() error {
+	emulation := entity.Emulation{
+		Device:      e.Device,
+		UserAgent:   e.UserAgent,
+		Locale:      e.Locale,
+		Timezone:    e.Timezone,
+		ColorScheme: entity.ColorScheme(e.ColorScheme),
+	}
+	var errs []error
+	if e.Viewport != "" {
+		var err error
+		if emulation.Width, emulation.Height, err = entity.ParseViewport(e.Viewport); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if e.Geolocation != "" {
+		if _, err := entity.ParseGeolocation(e.Geolocation); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	errs = append(errs, emulation.Validate())
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("browser.emulation: %w", err)
+	}
+	return nil
+}
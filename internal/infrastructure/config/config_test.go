@@ -0,0 +1,211 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeEnv struct {
+	vars  map[string]string
+	files map[string][]string
+}
+
+func (e fakeEnv) Lookup(key string) (string, bool) {
+	v, ok := e.vars[key]
+	return v, ok
+}
+
+func (e fakeEnv) FileKeys() map[string][]string { return e.files }
+
+const sampleFile = `
+llm:
+  model: file-model
+  thinking_budget: 500
+browser:
+  start_url: https://example.com
+agents:
+  max_iterations: 20
+policies:
+  navigation:
+    denied_hosts: ["*.bank.com"]
+profiles:
+  prod:
+    browser:
+      headless: true
+    logging:
+      level: info
+`
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "agent.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad_Layers(t *testing.T) {
+	path := writeFile(t, sampleFile)
+	env := fakeEnv{vars: map[string]string{
+		"OPENROUTER_API_KEY": "key",
+		"MAX_ITERATIONS":     "40",
+		"APPROVAL_RULES":     "require:*.bank.com;allow:localhost",
+		"START_URL":          "",
+	}}
+
+	cfg, warnings, err := Load(env, Options{File: path, Profile: "prod"})
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+
+	assert.Equal(t, "file-model", cfg.LLM.Model)
+	assert.Equal(t, 500, cfg.LLM.ThinkingBudget)
+	assert.True(t, cfg.LLM.ThinkingMode, "defaults survive")
+	assert.Equal(t, "https://example.com", cfg.Browser.StartURL, "empty env values are ignored")
+	assert.True(t, cfg.HeadlessOr(false), "profile section applies")
+	assert.Equal(t, "info", cfg.Logging.Level)
+	assert.Equal(t, 40, cfg.Agents.MaxIterations, "env overrides the file")
+	assert.Equal(t, []string{"*.bank.com"}, cfg.Policies.Navigation.DeniedHosts)
+	assert.Equal(t, []string{"require:*.bank.com", "allow:localhost"}, cfg.Policies.Approval.Rules)
+	assert.Equal(t, 30*time.Minute, cfg.Budgets.TaskTimeout)
+
+	require.NoError(t, cfg.Set("budgets.task_timeout", "5m"))
+	require.NoError(t, cfg.Set("NAV_DENY_FILE_ACCESS", "true"))
+	assert.Equal(t, 5*time.Minute, cfg.Budgets.TaskTimeout)
+	assert.True(t, cfg.Policies.Navigation.DenyFileAccess)
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestLoad_WithoutProfile(t *testing.T) {
+	path := writeFile(t, sampleFile)
+
+	cfg, _, err := Load(fakeEnv{}, Options{File: path})
+	require.NoError(t, err)
+	assert.False(t, cfg.HeadlessOr(false))
+	assert.Equal(t, "debug", cfg.Logging.Level)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		profile string
+		vars    map[string]string
+		want    string
+	}{
+		{name: "unknown key", file: "llm:\n  modle: x\n", want: "modle"},
+		{name: "unknown profile key", file: "profiles:\n  prod:\n    browser:\n      headles: true\n", profile: "prod", want: "headles"},
+		{name: "invalid file value", file: "budgets:\n  task_timeout: soon\n", want: "soon"},
+		{name: "invalid env value", vars: map[string]string{"MAX_ITERATIONS": "many", "BROWSER_HEADLESS": "maybe"}, want: "BROWSER_HEADLESS"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Options{Profile: tt.profile}
+			if tt.file != "" {
+				opts.File = writeFile(t, tt.file)
+			}
+			_, _, err := Load(fakeEnv{vars: tt.vars}, opts)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestLoad_RequireProfile(t *testing.T) {
+	path := writeFile(t, sampleFile)
+	envFile := fakeEnv{files: map[string][]string{".env.stage": {"OPENROUTER_API_KEY"}}}
+
+	_, _, err := Load(fakeEnv{}, Options{File: path, Profile: "prod", RequireProfile: true})
+	assert.NoError(t, err, "profile section in the file")
+	_, _, err = Load(envFile, Options{File: path, Profile: "stage", RequireProfile: true})
+	assert.NoError(t, err, ".env.stage file")
+	_, _, err = Load(fakeEnv{}, Options{File: path, Profile: "qa"})
+	assert.NoError(t, err, "implicit profiles may be missing")
+
+	_, _, err = Load(envFile, Options{File: path, Profile: "qa", RequireProfile: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown profile "qa"`)
+}
+
+func TestFileProfiles(t *testing.T) {
+	profiles, err := FileProfiles(writeFile(t, sampleFile+"  dev: {}\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dev", "prod"}, profiles)
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	_, _, err := Load(fakeEnv{}, Options{File: filepath.Join(t.TempDir(), "missing.yaml")})
+	assert.Error(t, err)
+}
+
+func TestLoad_UnknownEnvFileKeys(t *testing.T) {
+	env := fakeEnv{files: map[string][]string{
+		".env": {"OPENROUTER_API_KEY", "APP_ENV", "SECRET_GITHUB", "OPENROUTER_MODEL"},
+	}}
+
+	_, warnings, err := Load(env, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{".env: unknown setting OPENROUTER_MODEL"}, warnings)
+}
+
+func TestConfig_GetSet(t *testing.T) {
+	cfg := Default()
+
+	assert.Equal(t, "30m0s", cfg.Get("budgets.task_timeout"))
+	assert.Equal(t, "30m0s", cfg.Get("TASK_TIMEOUT"))
+	assert.Equal(t, "", cfg.Get("BROWSER_HEADLESS"), "unset pointer")
+	assert.Equal(t, "", cfg.Get("unknown"))
+	assert.Equal(t, "fallback", cfg.GetWithDefault("llm.model", "fallback"))
+
+	require.NoError(t, cfg.Set("REDACT_CUSTOM_PATTERNS", "ticket=TCK-[0-9]+; order=ORD-[0-9]+"))
+	assert.Equal(t, "ticket=TCK-[0-9]+;order=ORD-[0-9]+", cfg.Get("policies.redaction.custom_patterns"))
+
+	require.NoError(t, cfg.Set("NETWORK_BODY_URLS", "/api/,graphql"))
+	assert.Equal(t, []string{"/api/", "graphql"}, cfg.Browser.Network.BodyURLs)
+	assert.True(t, cfg.Browser.Network.Capture)
+
+	require.NoError(t, cfg.Set("INTERCEPT_RULES", "block * [image, font]; header /api/ X-Flag: on"))
+	assert.Equal(t, []string{"block * [image, font]", "header /api/ X-Flag: on"}, cfg.Browser.Intercept.Rules)
+
+	require.NoError(t, cfg.Set("LLM_PROMPT_PRICE", "0.35"))
+	assert.Equal(t, 0.35, cfg.LLM.PromptPrice)
+
+	assert.Error(t, cfg.Set("llm.unknown", "x"))
+	assert.Error(t, cfg.Set("agents.max_iterations", "x"))
+}
+
+func TestConfig_Validate(t *testing.T) {
+	cfg := Default()
+	cfg.Budgets.BatchConcurrency = 0
+	cfg.Logging.Level = "verbose"
+	cfg.Browser.Network.BodyURLs = []string{"("}
+	cfg.Browser.Intercept.BlockResources = []string{"images"}
+	cfg.Browser.Intercept.Rules = []string{"mock /api/cart 503 {}", "rewrite /api/cart"}
+	cfg.Browser.DialogPolicy = "ignore"
+	cfg.Browser.Emulation.Device = "nokia"
+	cfg.Browser.Emulation.Viewport = "wide"
+	cfg.Browser.Screenshot.Format = "gif"
+	cfg.Browser.RemoteTab = "mail"
+	cfg.Browser.Pool.Isolation = "process"
+	cfg.Server.Workers = 0
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	for _, want := range []string{"llm.api_key", "llm.model", "batch_concurrency", "logging.level", "browser.network.body_urls", `unknown resource type "images"`, `unknown action "rewrite"`, `invalid dialog policy "ignore"`, `browser.emulation: invalid viewport "wide"`, `unknown device "nokia"`, `browser.screenshot: invalid image format "gif"`, "browser.remote_tab needs browser.remote_url", `browser.pool.isolation "process"`, "server.workers"} {
+		assert.Contains(t, err.Error(), want)
+	}
+
+	cfg = Default()
+	cfg.Browser.RemoteURL = "localhost:9222"
+	cfg.Browser.Pool.Isolation = "instance"
+	assert.ErrorContains(t, cfg.ValidateWithoutLLM(), "browser.pool.isolation instance cannot be used with browser.remote_url")
+
+	cfg = Default()
+	cfg.Browser.Pool.MaxUses = 5
+	assert.ErrorContains(t, cfg.ValidateWithoutLLM(), "browser.pool.max_uses only applies to isolation instance")
+	cfg.Browser.Pool.Isolation = "instance"
+	assert.NoError(t, cfg.ValidateWithoutLLM())
+}
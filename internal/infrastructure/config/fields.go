@@ -0,0 +1,130 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// field is one leaf setting of Config, addressable by its yaml path
+// ("llm.model") and its environment variable (OPENROUTER_MODEL_NAME).
+type field struct {
+	path  string
+	env   string
+	sep   string
+	index []int
+}
+
+var fields = collectFields(reflect.TypeOf(Config{}), "", nil)
+
+func collectFields(t reflect.Type, prefix string, index []int) []field {
+	var result []field
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		path := prefix + name
+		idx := append(append([]int{}, index...), i)
+
+		if f.Type.Kind() == reflect.Struct && f.Type != reflect.TypeOf(time.Duration(0)) {
+			result = append(result, collectFields(f.Type, path+".", idx)...)
+			continue
+		}
+
+		sep := f.Tag.Get("sep")
+		if sep == "" {
+			sep = ","
+		}
+		result = append(result, field{path: path, env: f.Tag.Get("env"), sep: sep, index: idx})
+	}
+	return result
+}
+
+// lookupField finds a field by yaml path or environment variable name.
+func lookupField(key string) (field, bool) {
+	for _, f := range fields {
+		if f.path == key || f.env == key {
+			return f, true
+		}
+	}
+	return field{}, false
+}
+
+// set parses raw into the field of c.
+func (f field) set(c *Config, raw string) error {
+	v := reflect.ValueOf(c).Elem().FieldByIndex(f.index)
+	raw = strings.TrimSpace(raw)
+
+	if v.Kind() == reflect.Pointer {
+		target := reflect.New(v.Type().Elem())
+		if err := parseInto(target.Elem(), raw, f.sep); err != nil {
+			return err
+		}
+		v.Set(target)
+		return nil
+	}
+	return parseInto(v, raw, f.sep)
+}
+
+func parseInto(v reflect.Value, raw, sep string) error {
+	switch {
+	case v.Type() == reflect.TypeOf(time.Duration(0)):
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		v.SetInt(int64(d))
+	case v.Kind() == reflect.String:
+		v.SetString(raw)
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		v.SetBool(b)
+	case v.Kind() == reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		v.SetInt(int64(n))
+	case v.Kind() == reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		v.SetFloat(f)
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String:
+		var items []string
+		for _, item := range strings.Split(raw, sep) {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported setting type %s", v.Type())
+	}
+	return nil
+}
+
+// get formats the field of c the way it would be written in the environment.
+func (f field) get(c *Config) string {
+	v := reflect.ValueOf(c).Elem().FieldByIndex(f.index)
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	switch {
+	case v.Type() == reflect.TypeOf(time.Duration(0)):
+		return time.Duration(v.Int()).String()
+	case v.Kind() == reflect.Slice:
+		return strings.Join(v.Interface().([]string), f.sep)
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
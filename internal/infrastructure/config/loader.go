@@ -0,0 +1,221 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"browser-agent/internal/application/port/output"
+)
+
+var _ output.ConfigPort = (*Config)(nil)
+
+// DefaultFile is read when no config file is given and it exists.
+const DefaultFile = "agent.yaml"
+
+// Environment is the process environment after the .env files are loaded.
+type Environment interface {
+	Lookup(key string) (string, bool)
+	// FileKeys lists the keys each loaded .env file defined.
+	FileKeys() map[string][]string
+}
+
+type Options struct {
+	// File is the YAML config file; CONFIG_FILE and DefaultFile are used
+	// when empty.
+	File string
+	// Profile selects the profiles.<name> section of the file.
+	Profile string
+	// RequireProfile makes a Profile that has neither a .env.<profile>
+	// file nor a profiles.<profile> section an error, for profiles the
+	// user named explicitly.
+	RequireProfile bool
+}
+
+// FindFile returns the config file to read: file if given, else
+// CONFIG_FILE, else DefaultFile if it exists. It is empty when there is none.
+func FindFile(file string, lookup func(key string) (string, bool)) string {
+	if file != "" {
+		return file
+	}
+	if path, _ := lookup("CONFIG_FILE"); path != "" {
+		return path
+	}
+	if _, err := os.Stat(DefaultFile); err == nil {
+		return DefaultFile
+	}
+	return ""
+}
+
+// FileProfiles lists the sections under profiles: in the config file at path.
+func FileProfiles(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config file: %w", err)
+	}
+	var layout struct {
+		Profiles map[string]yaml.Node `yaml:"profiles"`
+	}
+	if err := yaml.Unmarshal(data, &layout); err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	names := make([]string, 0, len(layout.Profiles))
+	for name := range layout.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Load builds the configuration from defaults, the config file with its
+// profile section, and the environment, in increasing precedence. Command
+// line flags are applied by the caller, which then calls Validate.
+//
+// Unknown keys and unparsable values in the file or the environment are
+// errors; unknown keys in .env files are returned as warnings, since those
+// files may be shared with other tools.
+func Load(env Environment, opts Options) (*Config, []string, error) {
+	cfg := Default()
+
+	path := FindFile(opts.File, env.Lookup)
+	profileFound := false
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("read config file: %w", err)
+		}
+		if profileFound, err = decodeFile(data, opts.Profile, &cfg); err != nil {
+			return nil, nil, fmt.Errorf("config file %s: %w", path, err)
+		}
+	}
+	if _, ok := env.FileKeys()[".env."+opts.Profile]; ok {
+		profileFound = true
+	}
+	if opts.RequireProfile && !profileFound {
+		return nil, nil, fmt.Errorf("unknown profile %q: there is no .env.%s file and no profiles.%s section in the config file",
+			opts.Profile, opts.Profile, opts.Profile)
+	}
+
+	var errs []error
+	for _, f := range fields {
+		if f.env == "" {
+			continue
+		}
+		raw, ok := env.Lookup(f.env)
+		if !ok || strings.TrimSpace(raw) == "" {
+			continue
+		}
+		if err := f.set(&cfg, raw); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f.env, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, nil, err
+	}
+
+	return &cfg, unknownEnvKeys(env.FileKeys()), nil
+}
+
+// fileLayout is the config file: Config at the top level plus per-profile
+// overrides with the same layout.
+type fileLayout struct {
+	Config   `yaml:",inline"`
+	Profiles map[string]yaml.Node `yaml:"profiles"`
+}
+
+// decodeFile applies the config file to cfg and reports whether it has a
+// section for profile.
+func decodeFile(data []byte, profile string, cfg *Config) (bool, error) {
+	layout := fileLayout{Config: *cfg}
+	if err := decodeStrict(data, &layout); err != nil {
+		return false, err
+	}
+
+	node, found := layout.Profiles[profile]
+	if found {
+		// yaml.Node.Decode cannot reject unknown fields, so the profile
+		// section goes through a strict decoder as well.
+		section, err := yaml.Marshal(&node)
+		if err != nil {
+			return false, err
+		}
+		if err := decodeStrict(section, &layout.Config); err != nil {
+			return false, fmt.Errorf("profile %s: %w", profile, err)
+		}
+	}
+
+	*cfg = layout.Config
+	return found, nil
+}
+
+func decodeStrict(data []byte, target any) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(target); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+// otherEnvKeys are read outside Config.
+var otherEnvKeys = map[string]bool{"APP_ENV": true, "CONFIG_FILE": true}
+
+func unknownEnvKeys(files map[string][]string) []string {
+	var warnings []string
+	for file, keys := range files {
+		for _, key := range keys {
+			if _, known := lookupField(key); known || otherEnvKeys[key] || strings.HasPrefix(key, "SECRET_") {
+				continue
+			}
+			warnings = append(warnings, fmt.Sprintf("%s: unknown setting %s", file, key))
+		}
+	}
+	sort.Strings(warnings)
+	return warnings
+}
+
+// Get returns a setting by yaml path ("browser.start_url") or environment
+// variable name, formatted as it would appear in the environment.
+func (c *Config) Get(key string) string {
+	f, ok := lookupField(key)
+	if !ok {
+		return ""
+	}
+	return f.get(c)
+}
+
+func (c *Config) MustGet(key string) string {
+	val := c.Get(key)
+	if val == "" {
+		log.Fatalf("config %s is missing", key)
+	}
+	return val
+}
+
+func (c *Config) GetWithDefault(key string, defaultValue string) string {
+	if val := c.Get(key); val != "" {
+		return val
+	}
+	return defaultValue
+}
+
+// Set assigns a setting by yaml path or environment variable name. It is
+// used for command line overrides.
+func (c *Config) Set(key, value string) error {
+	f, ok := lookupField(key)
+	if !ok {
+		return fmt.Errorf("unknown setting %s", key)
+	}
+	if err := f.set(c, value); err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	return nil
+}
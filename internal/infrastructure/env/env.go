@@ -4,31 +4,74 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"strconv"
+	"sort"
 
 	"github.com/joho/godotenv"
 )
 
-type EnvService struct{}
+type EnvService struct {
+	appEnv string
+	files  map[string][]string
+}
 
+// NewEnvService loads .env.<APP_ENV> and .env into the process environment.
+// Variables that are already set win over both files, and the profile file
+// wins over .env.
 func NewEnvService() *EnvService {
 	appEnv := os.Getenv("APP_ENV")
 	if appEnv == "" {
 		appEnv = "dev"
 	}
 
-	if err := godotenv.Load(".env"); err != nil {
-		log.Printf("Info: no .env file with secrets found (this is OK for CI/CD)")
+	e := &EnvService{
+		appEnv: appEnv,
+		files:  make(map[string][]string),
 	}
 
 	envFile := fmt.Sprintf(".env.%s", appEnv)
-	if err := godotenv.Overload(envFile); err != nil {
+	if err := e.load(envFile); err != nil {
 		log.Printf("Warning: could not load %s: %v", envFile, err)
 	}
 
+	if err := e.load(".env"); err != nil {
+		log.Printf("Info: no .env file with secrets found (this is OK for CI/CD)")
+	}
+
 	log.Printf("Environment loaded: APP_ENV=%s", appEnv)
 
-	return &EnvService{}
+	return e
+}
+
+func (e *EnvService) load(path string) error {
+	values, err := godotenv.Read(path)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(values))
+	for key, value := range values {
+		keys = append(keys, key)
+		if _, set := os.LookupEnv(key); !set {
+			os.Setenv(key, value)
+		}
+	}
+	sort.Strings(keys)
+	e.files[path] = keys
+	return nil
+}
+
+// Profile is the APP_ENV the service was loaded for; it selects .env.<profile>.
+func (e *EnvService) Profile() string {
+	return e.appEnv
+}
+
+// FileKeys lists the keys defined by each .env file that was loaded.
+func (e *EnvService) FileKeys() map[string][]string {
+	return e.files
+}
+
+func (e *EnvService) Lookup(key string) (string, bool) {
+	return os.LookupEnv(key)
 }
 
 func (e *EnvService) Get(key string) string {
@@ -42,27 +85,3 @@ func (e *EnvService) MustGet(key string) string {
 	}
 	return val
 }
-
-func (e *EnvService) GetBool(key string, defaultValue bool) bool {
-	val := os.Getenv(key)
-	if val == "" {
-		return defaultValue
-	}
-	parsed, err := strconv.ParseBool(val)
-	if err != nil {
-		return defaultValue
-	}
-	return parsed
-}
-
-func (e *EnvService) GetInt(key string, defaultValue int) int {
-	val := os.Getenv(key)
-	if val == "" {
-		return defaultValue
-	}
-	parsed, err := strconv.Atoi(val)
-	if err != nil {
-		return defaultValue
-	}
-	return parsed
-}
@@ -0,0 +1,106 @@
+package eventbus
+
+import (
+	"sync"
+	"time"
+
+	"browser-agent/internal/application/port/input"
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/domain/entity"
+)
+
+var (
+	_ output.EventPublisher = (*Bus)(nil)
+	_ input.EventSource     = (*Bus)(nil)
+)
+
+const (
+	defaultHistoryLimit = 1000
+	subscriberBuffer    = 256
+)
+
+// Bus fans task events out to subscribers and keeps a bounded history per
+// task so late subscribers see what already happened.
+type Bus struct {
+	mu           sync.Mutex
+	nextID       int64
+	historyLimit int
+	history      map[string][]entity.Event
+	subscribers  map[string]map[chan entity.Event]struct{}
+}
+
+func New() *Bus {
+	return &Bus{
+		historyLimit: defaultHistoryLimit,
+		history:      make(map[string][]entity.Event),
+		subscribers:  make(map[string]map[chan entity.Event]struct{}),
+	}
+}
+
+// Publish records event and delivers it to subscribers of its task. A
+// subscriber that does not keep up is disconnected rather than blocking the
+// agent or silently missing events: its channel is closed, and it can
+// subscribe again and skip the history it has already seen.
+func (b *Bus) Publish(event entity.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	event.ID = b.nextID
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	history := append(b.history[event.TaskID], event)
+	if len(history) > b.historyLimit {
+		history = history[len(history)-b.historyLimit:]
+	}
+	b.history[event.TaskID] = history
+
+	for ch := range b.subscribers[event.TaskID] {
+		select {
+		case ch <- event:
+		default:
+			delete(b.subscribers[event.TaskID], ch)
+			close(ch)
+		}
+	}
+	if len(b.subscribers[event.TaskID]) == 0 {
+		delete(b.subscribers, event.TaskID)
+	}
+}
+
+func (b *Bus) Subscribe(taskID string) ([]entity.Event, <-chan entity.Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan entity.Event, subscriberBuffer)
+	if b.subscribers[taskID] == nil {
+		b.subscribers[taskID] = make(map[chan entity.Event]struct{})
+	}
+	b.subscribers[taskID][ch] = struct{}{}
+
+	history := make([]entity.Event, len(b.history[taskID]))
+	copy(history, b.history[taskID])
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subscribers[taskID], ch)
+			if len(b.subscribers[taskID]) == 0 {
+				delete(b.subscribers, taskID)
+			}
+		})
+	}
+
+	return history, ch, unsubscribe
+}
+
+// Forget drops the history of a task that is no longer tracked.
+func (b *Bus) Forget(taskID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.history, taskID)
+}
@@ -0,0 +1,80 @@
+package eventbus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"browser-agent/internal/domain/entity"
+)
+
+func TestBus_HistoryAndLiveEvents(t *testing.T) {
+	bus := New()
+	bus.Publish(entity.Event{TaskID: "a", Type: entity.EventIteration})
+	bus.Publish(entity.Event{TaskID: "b", Type: entity.EventIteration})
+
+	history, events, unsubscribe := bus.Subscribe("a")
+	defer unsubscribe()
+
+	require.Len(t, history, 1)
+	assert.Equal(t, int64(1), history[0].ID)
+	assert.False(t, history[0].Time.IsZero())
+
+	bus.Publish(entity.Event{TaskID: "b", Type: entity.EventToolStart})
+	bus.Publish(entity.Event{TaskID: "a", Type: entity.EventToolStart})
+
+	event := <-events
+	assert.Equal(t, "a", event.TaskID)
+	assert.Equal(t, int64(4), event.ID)
+	assert.Empty(t, events)
+}
+
+func TestBus_HistoryLimit(t *testing.T) {
+	bus := New()
+	bus.historyLimit = 2
+	for i := 0; i < 5; i++ {
+		bus.Publish(entity.Event{TaskID: "a"})
+	}
+
+	history, _, unsubscribe := bus.Subscribe("a")
+	unsubscribe()
+
+	require.Len(t, history, 2)
+	assert.Equal(t, int64(5), history[1].ID)
+
+	bus.Forget("a")
+	history, _, unsubscribe = bus.Subscribe("a")
+	unsubscribe()
+	assert.Empty(t, history)
+}
+
+func TestBus_UnsubscribeStopsDelivery(t *testing.T) {
+	bus := New()
+	_, events, unsubscribe := bus.Subscribe("a")
+	unsubscribe()
+	unsubscribe()
+
+	bus.Publish(entity.Event{TaskID: "a"})
+	assert.Empty(t, events)
+}
+
+func TestBus_DisconnectsLaggingSubscriber(t *testing.T) {
+	bus := New()
+	_, events, unsubscribe := bus.Subscribe("a")
+	defer unsubscribe()
+
+	for i := 0; i < subscriberBuffer+1; i++ {
+		bus.Publish(entity.Event{TaskID: "a"})
+	}
+
+	received := 0
+	for range events {
+		received++
+	}
+	assert.Equal(t, subscriberBuffer, received)
+
+	history, _, unsubscribe := bus.Subscribe("a")
+	unsubscribe()
+	assert.Len(t, history, subscriberBuffer+1)
+}
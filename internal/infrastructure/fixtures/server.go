@@ -0,0 +1,56 @@
+// Package fixtures serves local HTML fixture sites over HTTP, so that eval
+// tasks run against pages that never change.
+package fixtures
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+)
+
+type Server struct {
+	// URL is the base address, e.g. http://127.0.0.1:41234, without a
+	// trailing slash.
+	URL    string
+	server *http.Server
+}
+
+// Serve starts serving the files of dir on a free loopback port.
+func Serve(dir string) (*Server, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("fixtures: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("fixtures: %s is not a directory", dir)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("fixtures: %w", err)
+	}
+
+	s := &Server{
+		URL: "http://" + listener.Addr().String(),
+		server: &http.Server{
+			Handler:           http.FileServer(http.Dir(dir)),
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+	}
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "fixtures: %v\n", err)
+		}
+	}()
+	return s, nil
+}
+
+func (s *Server) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return s.server.Shutdown(ctx)
+}
@@ -0,0 +1,31 @@
+package fixtures
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServe(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "page.html"), []byte("<h1>fixture</h1>"), 0o644))
+
+	server, err := Serve(dir)
+	require.NoError(t, err)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/page.html")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "<h1>fixture</h1>", string(body))
+
+	_, err = Serve(filepath.Join(dir, "page.html"))
+	assert.ErrorContains(t, err, "not a directory")
+}
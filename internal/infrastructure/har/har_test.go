@@ -0,0 +1,42 @@
+package har
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeaderMasksCredentials(t *testing.T) {
+	assert.Equal(t, NameValue{Name: "Cookie", Value: "[REDACTED]"}, Header("Cookie", "sid=1"))
+	assert.Equal(t, NameValue{Name: "authorization", Value: "[REDACTED]"}, Header("authorization", "Bearer x"))
+	assert.Equal(t, NameValue{Name: "Accept", Value: "*/*"}, Header("Accept", "*/*"))
+}
+
+func TestHTTPVersion(t *testing.T) {
+	assert.Equal(t, "HTTP/2", HTTPVersion("h2"))
+	assert.Equal(t, "HTTP/3", HTTPVersion("h3"))
+	assert.Equal(t, "HTTP/1.1", HTTPVersion("http/1.1"))
+	assert.Equal(t, "", HTTPVersion(""))
+}
+
+func TestWriteFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.har")
+	file := New()
+	file.Log.Entries = append(file.Log.Entries, Entry{Request: Request{Method: "GET", URL: "https://shop.test/"}, Error: "net::ERR_FAILED"})
+	require.NoError(t, WriteFile(path, file))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	log := decoded["log"].(map[string]any)
+	assert.Equal(t, "1.2", log["version"])
+	assert.Equal(t, []any{}, log["pages"])
+	entry := log["entries"].([]any)[0].(map[string]any)
+	assert.Equal(t, "net::ERR_FAILED", entry["_error"])
+	assert.Equal(t, map[string]any{}, entry["cache"])
+}
@@ -23,6 +23,7 @@ type OpenRouterAdapter struct {
 	logger         output.LoggerPort
 	thinkingMode   bool
 	thinkingBudget int
+	redactor       output.Redactor
 }
 
 type Config struct {
@@ -32,6 +33,9 @@ type Config struct {
 	Logger         output.LoggerPort
 	ThinkingMode   bool
 	ThinkingBudget int
+	// Redactor, when set, scrubs message content and tool arguments before
+	// they are sent to the provider.
+	Redactor output.Redactor
 }
 
 func DefaultConfig(apiKey, model string) Config {
@@ -106,11 +110,15 @@ func NewOpenRouterAdapter(cfg Config) *OpenRouterAdapter {
 		logger:         cfg.Logger,
 		thinkingMode:   cfg.ThinkingMode,
 		thinkingBudget: cfg.ThinkingBudget,
+		redactor:       cfg.Redactor,
 	}
 }
 
 func (a *OpenRouterAdapter) Chat(ctx context.Context, req output.ChatRequest) (*output.ChatResponse, error) {
 	messages := convertMessages(req.Messages)
+	if a.redactor != nil {
+		redactMessages(messages, a.redactor)
+	}
 	tools := convertTools(req.Tools)
 
 	if a.logger != nil {
@@ -178,6 +186,11 @@ func (a *OpenRouterAdapter) Chat(ctx context.Context, req output.ChatRequest) (*
 
 	return &output.ChatResponse{
 		Message: message,
+		Usage: entity.Usage{
+			PromptTokens:     resp.Usage.PromptTokens,
+			CompletionTokens: resp.Usage.CompletionTokens,
+			TotalTokens:      resp.Usage.TotalTokens,
+		},
 	}, nil
 }
 
@@ -226,6 +239,16 @@ func convertMessages(messages []entity.Message) []openai.ChatCompletionMessage {
 	return result
 }
 
+func redactMessages(messages []openai.ChatCompletionMessage, redactor output.Redactor) {
+	for i := range messages {
+		messages[i].Content = redactor.Redact(messages[i].Content)
+		for j := range messages[i].ToolCalls {
+			call := &messages[i].ToolCalls[j].Function
+			call.Arguments = redactor.Redact(call.Arguments)
+		}
+	}
+}
+
 func convertTools(tools []entity.ToolDefinition) []openai.Tool {
 	result := make([]openai.Tool, 0, len(tools))
 	for _, t := range tools {
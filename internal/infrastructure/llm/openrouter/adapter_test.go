@@ -1,6 +1,7 @@
 package openrouter
 
 import (
+	"strings"
 	"testing"
 
 	"browser-agent/internal/domain/entity"
@@ -101,3 +102,23 @@ func TestConvertMessages_EmptyContentWithBlocks(t *testing.T) {
 	assert.Len(t, result, 1)
 	assert.Equal(t, "Response text", result[0].Content)
 }
+
+type wordRedactor struct{}
+
+func (wordRedactor) Redact(text string) string {
+	return strings.ReplaceAll(text, "secret", "[REDACTED]")
+}
+
+func TestRedactMessages(t *testing.T) {
+	messages := []openai.ChatCompletionMessage{
+		{Role: "user", Content: "my secret is here"},
+		{Role: "assistant", ToolCalls: []openai.ToolCall{
+			{Function: openai.FunctionCall{Name: "fill", Arguments: `{"text":"secret"}`}},
+		}},
+	}
+
+	redactMessages(messages, wordRedactor{})
+
+	assert.Equal(t, "my [REDACTED] is here", messages[0].Content)
+	assert.Equal(t, `{"text":"[REDACTED]"}`, messages[1].ToolCalls[0].Function.Arguments)
+}
@@ -2,9 +2,12 @@ package logger
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	"browser-agent/internal/application/port/output"
@@ -12,33 +15,78 @@ import (
 
 var _ output.LoggerPort = (*LoggerAdapter)(nil)
 
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return LevelDebug, nil
+	case "", "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q (want debug, info, warn or error)", s)
+}
+
 type LoggerAdapter struct {
-	file   *os.File
-	fields map[string]any
+	file *os.File
+	// mu is shared with every derived logger so concurrent tasks writing to
+	// the same file never interleave within a line.
+	mu       *sync.Mutex
+	fields   map[string]any
+	redactor output.Redactor
+	level    Level
 }
 
 func NewLoggerAdapter() (*LoggerAdapter, error) {
-	filename := fmt.Sprintf("%s.log", time.Now().Format("2006-01-02_15-04-05"))
-
 	if err := os.MkdirAll("log", 0755); err != nil {
 		return nil, fmt.Errorf("create log dir: %w", err)
 	}
 
-	file, err := os.Create(filepath.Join("log", filename))
+	file, err := createLogFile(time.Now().Format("2006-01-02_15-04-05"))
 	if err != nil {
 		return nil, fmt.Errorf("create log file: %w", err)
 	}
 
 	return &LoggerAdapter{
 		file:   file,
+		mu:     &sync.Mutex{},
 		fields: make(map[string]any),
+		level:  LevelDebug,
 	}, nil
 }
 
-func (l *LoggerAdapter) log(level, msg string, args ...any) {
+// createLogFile never reuses an existing file: containers started within the
+// same second (e.g. batch workers) get a numbered suffix.
+func createLogFile(base string) (*os.File, error) {
+	name := base
+	for i := 1; ; i++ {
+		file, err := os.OpenFile(filepath.Join("log", name+".log"), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if !errors.Is(err, os.ErrExist) {
+			return file, err
+		}
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+}
+
+func (l *LoggerAdapter) log(level Level, name, msg string, args ...any) {
+	if level < l.level {
+		return
+	}
+
 	entry := map[string]any{
 		"timestamp": time.Now().Format(time.RFC3339),
-		"level":     level,
+		"level":     name,
 		"message":   msg,
 	}
 
@@ -53,30 +101,79 @@ func (l *LoggerAdapter) log(level, msg string, args ...any) {
 	}
 
 	data, err := json.Marshal(entry)
+	if err == nil && l.redactor != nil {
+		data, err = redactJSON(data, l.redactor)
+	}
 	if err != nil {
-		fmt.Fprintf(l.file, `{"timestamp":"%s","level":"ERROR","message":"marshal error: %v"}`+"\n",
+		data = fmt.Appendf(nil, `{"timestamp":"%s","level":"ERROR","message":"marshal error: %v"}`,
 			time.Now().Format(time.RFC3339), err)
-		return
 	}
 
-	l.file.Write(data)
-	l.file.WriteString("\n")
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.file.Write(append(data, '\n'))
+}
+
+// Path is the log file, e.g. log/2024-05-01_10-00-00.log.
+func (l *LoggerAdapter) Path() string {
+	return l.file.Name()
+}
+
+// SetLevel drops entries below level. Loggers derived with WithField keep
+// the level they were created with.
+func (l *LoggerAdapter) SetLevel(level Level) {
+	l.level = level
+}
+
+// SetRedactor makes the logger scrub every string value in an entry before
+// it is written. Loggers derived with WithField share the redactor.
+func (l *LoggerAdapter) SetRedactor(redactor output.Redactor) {
+	l.redactor = redactor
+}
+
+// redactJSON walks a marshaled entry so values are redacted in their decoded
+// form; replacing in the encoded text would miss values that JSON escapes.
+func redactJSON(data []byte, redactor output.Redactor) ([]byte, error) {
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+	return json.Marshal(redactValue(decoded, redactor))
+}
+
+func redactValue(value any, redactor output.Redactor) any {
+	switch v := value.(type) {
+	case string:
+		return redactor.Redact(v)
+	case map[string]any:
+		for key, item := range v {
+			v[key] = redactValue(item, redactor)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = redactValue(item, redactor)
+		}
+		return v
+	default:
+		return v
+	}
 }
 
 func (l *LoggerAdapter) Debug(msg string, args ...any) {
-	l.log("DEBUG", msg, args...)
+	l.log(LevelDebug, "DEBUG", msg, args...)
 }
 
 func (l *LoggerAdapter) Info(msg string, args ...any) {
-	l.log("INFO", msg, args...)
+	l.log(LevelInfo, "INFO", msg, args...)
 }
 
 func (l *LoggerAdapter) Warn(msg string, args ...any) {
-	l.log("WARN", msg, args...)
+	l.log(LevelWarn, "WARN", msg, args...)
 }
 
 func (l *LoggerAdapter) Error(msg string, args ...any) {
-	l.log("ERROR", msg, args...)
+	l.log(LevelError, "ERROR", msg, args...)
 }
 
 func (l *LoggerAdapter) WithField(key string, value any) output.LoggerPort {
@@ -87,8 +184,11 @@ func (l *LoggerAdapter) WithField(key string, value any) output.LoggerPort {
 	newFields[key] = value
 
 	return &LoggerAdapter{
-		file:   l.file,
-		fields: newFields,
+		file:     l.file,
+		mu:       l.mu,
+		fields:   newFields,
+		redactor: l.redactor,
+		level:    l.level,
 	}
 }
 
@@ -102,8 +202,11 @@ func (l *LoggerAdapter) WithFields(fields map[string]any) output.LoggerPort {
 	}
 
 	return &LoggerAdapter{
-		file:   l.file,
-		fields: newFields,
+		file:     l.file,
+		mu:       l.mu,
+		fields:   newFields,
+		redactor: l.redactor,
+		level:    l.level,
 	}
 }
 
@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerAdapter_ConcurrentDerivedLoggersWriteWholeLines(t *testing.T) {
+	file, err := os.Create(filepath.Join(t.TempDir(), "run.log"))
+	require.NoError(t, err)
+
+	root := &LoggerAdapter{
+		file:   file,
+		mu:     &sync.Mutex{},
+		fields: make(map[string]any),
+		level:  LevelDebug,
+	}
+
+	const workers, perWorker = 8, 200
+	payload := strings.Repeat("x", 4096)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			log := root.WithField("worker", w).WithFields(map[string]any{"task": "t"})
+			for i := 0; i < perWorker; i++ {
+				log.Info("step", "i", i, "payload", payload)
+			}
+		}(w)
+	}
+	wg.Wait()
+	require.NoError(t, root.Close())
+
+	data, err := os.Open(file.Name())
+	require.NoError(t, err)
+	defer data.Close()
+
+	scanner := bufio.NewScanner(data)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lines := 0
+	for scanner.Scan() {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry), "line %d: %q", lines+1, scanner.Text())
+		assert.Equal(t, "step", entry["message"])
+		lines++
+	}
+	require.NoError(t, scanner.Err())
+	assert.Equal(t, workers*perWorker, lines)
+}
@@ -0,0 +1,69 @@
+package prompts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Set holds the system prompts of the orchestrator and the sub-agents.
+type Set struct {
+	Orchestrator string
+	Navigation   string
+	Extraction   string
+	Form         string
+}
+
+// Default returns the prompts embedded in the binary.
+func Default() Set {
+	return Set{
+		Orchestrator: OrchestratorPrompt,
+		Navigation:   NavigationPrompt,
+		Extraction:   ExtractionPrompt,
+		Form:         FormPrompt,
+	}
+}
+
+// LoadDir reads orchestrator.txt, navigation.txt, extraction.txt and
+// form.txt from dir. Missing files keep the embedded prompt, so a directory
+// may hold only the prompts being changed.
+func LoadDir(dir string) (Set, error) {
+	set := Default()
+	files := map[string]*string{
+		"orchestrator.txt": &set.Orchestrator,
+		"navigation.txt":   &set.Navigation,
+		"extraction.txt":   &set.Extraction,
+		"form.txt":         &set.Form,
+	}
+
+	found := 0
+	for name, prompt := range files {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return Set{}, err
+		}
+		*prompt = string(data)
+		found++
+	}
+	if found == 0 {
+		return Set{}, fmt.Errorf("%s: no prompt files found", dir)
+	}
+	return set, nil
+}
+
+// Version identifies the prompt texts: equal sets have equal versions.
+func (s Set) Version() string {
+	hash := sha256.New()
+	for _, prompt := range []string{s.Orchestrator, s.Navigation, s.Extraction, s.Form} {
+		hash.Write([]byte(prompt))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:8]
+}
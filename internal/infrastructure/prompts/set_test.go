@@ -0,0 +1,27 @@
+package prompts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "form.txt"), []byte("Fill forms carefully."), 0o644))
+
+	set, err := LoadDir(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "Fill forms carefully.", set.Form)
+	assert.Equal(t, OrchestratorPrompt, set.Orchestrator, "missing files keep the embedded prompt")
+
+	assert.NotEqual(t, Default().Version(), set.Version())
+	assert.Equal(t, Default().Version(), Default().Version())
+	assert.Len(t, set.Version(), 8)
+
+	_, err = LoadDir(t.TempDir())
+	assert.ErrorContains(t, err, "no prompt files")
+}
@@ -0,0 +1,93 @@
+package redaction
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"browser-agent/internal/application/port/output"
+)
+
+var _ output.Redactor = (*Pipeline)(nil)
+
+// SensitiveValueName is the mask name used for values registered at runtime,
+// e.g. text typed into password fields.
+const SensitiveValueName = "sensitive"
+
+// minSensitiveLength keeps very short inputs from turning every matching
+// letter in the log into a mask.
+const minSensitiveLength = 4
+
+// Pipeline runs text through a chain of redactors. Exact values (secrets,
+// typed passwords) are scrubbed before patterns so they keep their own names.
+type Pipeline struct {
+	stages   []output.Redactor
+	patterns []Pattern
+
+	mu        sync.RWMutex
+	sensitive map[string]struct{}
+}
+
+func NewPipeline(patterns []Pattern, stages ...output.Redactor) *Pipeline {
+	p := &Pipeline{
+		patterns:  patterns,
+		sensitive: make(map[string]struct{}),
+	}
+	for _, stage := range stages {
+		if stage != nil {
+			p.stages = append(p.stages, stage)
+		}
+	}
+	return p
+}
+
+// AddSensitive registers a value that must never show up in output again.
+func (p *Pipeline) AddSensitive(value string) {
+	value = strings.TrimSpace(value)
+	if len(value) < minSensitiveLength {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sensitive[value] = struct{}{}
+}
+
+func (p *Pipeline) Redact(text string) string {
+	if text == "" {
+		return text
+	}
+
+	for _, stage := range p.stages {
+		text = stage.Redact(text)
+	}
+
+	text = p.redactSensitive(text)
+
+	for _, pattern := range p.patterns {
+		text = pattern.Redact(text)
+	}
+	return text
+}
+
+func (p *Pipeline) redactSensitive(text string) string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if len(p.sensitive) == 0 {
+		return text
+	}
+
+	values := make([]string, 0, len(p.sensitive))
+	for value := range p.sensitive {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		return len(values[i]) > len(values[j])
+	})
+
+	for _, value := range values {
+		text = strings.ReplaceAll(text, value, Mask(SensitiveValueName))
+	}
+	return text
+}
@@ -0,0 +1,112 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const (
+	fileVersion      = 1
+	kdfIterations    = 600000
+	// maxKDFIterations bounds the work a tampered file can demand at startup.
+	maxKDFIterations = 10_000_000
+	keyLength        = 32
+	saltLength       = 16
+	minPassphraseLen = 8
+)
+
+var ErrWrongPassphrase = errors.New("wrong passphrase or corrupted secrets file")
+
+type encryptedFile struct {
+	Version    int    `json:"version"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// Encrypt seals name/value pairs with AES-256-GCM using a key derived from
+// passphrase with PBKDF2-SHA256.
+func Encrypt(values map[string]string, passphrase string) ([]byte, error) {
+	if len(passphrase) < minPassphraseLen {
+		return nil, fmt.Errorf("passphrase must be at least %d characters", minPassphraseLen)
+	}
+
+	plaintext, err := json.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("marshal secrets: %w", err)
+	}
+
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("generate salt: %w", err)
+	}
+
+	gcm, err := newGCM(passphrase, salt, kdfIterations)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+
+	return json.MarshalIndent(encryptedFile{
+		Version:    fileVersion,
+		Iterations: kdfIterations,
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plaintext, nil),
+	}, "", "  ")
+}
+
+func Decrypt(data []byte, passphrase string) (map[string]string, error) {
+	var file encryptedFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse secrets file: %w", err)
+	}
+	if file.Version != fileVersion {
+		return nil, fmt.Errorf("unsupported secrets file version %d", file.Version)
+	}
+	if file.Iterations < kdfIterations || file.Iterations > maxKDFIterations {
+		return nil, fmt.Errorf("secrets file iterations %d out of range [%d, %d]",
+			file.Iterations, kdfIterations, maxKDFIterations)
+	}
+
+	gcm, err := newGCM(passphrase, file.Salt, file.Iterations)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := gcm.Open(nil, file.Nonce, file.Ciphertext, nil)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+
+	var values map[string]string
+	if err := json.Unmarshal(plaintext, &values); err != nil {
+		return nil, fmt.Errorf("parse decrypted secrets: %w", err)
+	}
+	return values, nil
+}
+
+func newGCM(passphrase string, salt []byte, iterations int) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, iterations, keyLength)
+	if err != nil {
+		return nil, fmt.Errorf("derive key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,163 @@
+package secrets
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"browser-agent/internal/application/port/output"
+)
+
+var _ output.SecretsPort = (*Store)(nil)
+
+const DefaultEnvPrefix = "SECRET_"
+
+// MinRedactLength matches the redaction pipeline: shorter values (a one-digit
+// PIN) can still be typed but are not masked, or every occurrence of that
+// character in the output would turn into a placeholder.
+const MinRedactLength = 4
+
+var placeholderPattern = regexp.MustCompile(`\{\{\s*secret:([A-Za-z0-9_.-]+)\s*\}\}`)
+
+type Store struct {
+	mu     sync.RWMutex
+	values map[string]string
+}
+
+func NewStore() *Store {
+	return &Store{
+		values: make(map[string]string),
+	}
+}
+
+// Placeholder returns the placeholder agents use to reference a secret.
+func Placeholder(name string) string {
+	return "{{secret:" + name + "}}"
+}
+
+func (s *Store) Set(name, value string) {
+	name = normalizeName(name)
+	if name == "" || value == "" {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[name] = value
+}
+
+// LoadEnv imports every environment variable starting with prefix, so
+// SECRET_GITHUB_PASSWORD becomes the secret "github_password".
+func (s *Store) LoadEnv(prefix string) int {
+	count := 0
+	for _, kv := range os.Environ() {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || !strings.HasPrefix(key, prefix) || value == "" {
+			continue
+		}
+		s.Set(strings.TrimPrefix(key, prefix), value)
+		count++
+	}
+	return count
+}
+
+// LoadFile imports secrets from a file created by Encrypt.
+func (s *Store) LoadFile(path, passphrase string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("read secrets file: %w", err)
+	}
+
+	values, err := Decrypt(data, passphrase)
+	if err != nil {
+		return 0, fmt.Errorf("decrypt secrets file %s: %w", path, err)
+	}
+
+	for name, value := range values {
+		s.Set(name, value)
+	}
+	return len(values), nil
+}
+
+func (s *Store) Names() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	names := make([]string, 0, len(s.values))
+	for name := range s.values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Unredacted lists the secrets shorter than MinRedactLength, which Redact
+// leaves visible.
+func (s *Store) Unredacted() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var names []string
+	for name, value := range s.values {
+		if len(value) < MinRedactLength {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *Store) Resolve(text string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var missing []string
+	resolved := placeholderPattern.ReplaceAllStringFunc(text, func(match string) string {
+		name := normalizeName(placeholderPattern.FindStringSubmatch(match)[1])
+		value, ok := s.values[name]
+		if !ok {
+			missing = append(missing, name)
+			return match
+		}
+		return value
+	})
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("unknown secret(s) %s", strings.Join(missing, ", "))
+	}
+	return resolved, nil
+}
+
+// Redact replaces every known secret value of at least MinRedactLength bytes
+// in text with its placeholder. Longer values are replaced first so a secret
+// that contains another one is not left partially visible.
+func (s *Store) Redact(text string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.values) == 0 || text == "" {
+		return text
+	}
+
+	names := make([]string, 0, len(s.values))
+	for name, value := range s.values {
+		if len(value) >= MinRedactLength {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return len(s.values[names[i]]) > len(s.values[names[j]])
+	})
+
+	for _, name := range names {
+		text = strings.ReplaceAll(text, s.values[name], Placeholder(name))
+	}
+	return text
+}
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
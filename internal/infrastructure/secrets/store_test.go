@@ -0,0 +1,105 @@
+package secrets
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreResolve(t *testing.T) {
+	store := NewStore()
+	store.Set("github_password", "hunter2")
+	store.Set("GitHub_User", "octocat")
+
+	resolved, err := store.Resolve("{{secret:github_user}} / {{ secret:GITHUB_PASSWORD }}")
+	require.NoError(t, err)
+	assert.Equal(t, "octocat / hunter2", resolved)
+
+	plain, err := store.Resolve("no placeholders here")
+	require.NoError(t, err)
+	assert.Equal(t, "no placeholders here", plain)
+
+	_, err = store.Resolve("{{secret:missing}}")
+	assert.ErrorContains(t, err, "missing")
+}
+
+func TestStoreRedact(t *testing.T) {
+	store := NewStore()
+	store.Set("token", "abcd")
+	store.Set("long_token", "abcdefgh")
+
+	assert.Equal(t, "key={{secret:long_token}} short={{secret:token}}", store.Redact("key=abcdefgh short=abcd"))
+	assert.Equal(t, "", store.Redact(""))
+	assert.Equal(t, "nothing", NewStore().Redact("nothing"))
+}
+
+func TestStoreRedactSkipsShortValues(t *testing.T) {
+	store := NewStore()
+	store.Set("pin", "1")
+	store.Set("code", "abc")
+
+	assert.Equal(t, "step 1 of 10: abc", store.Redact("step 1 of 10: abc"))
+	assert.Equal(t, []string{"code", "pin"}, store.Unredacted())
+
+	resolved, err := store.Resolve("{{secret:pin}}")
+	require.NoError(t, err)
+	assert.Equal(t, "1", resolved)
+}
+
+func TestStoreLoadEnv(t *testing.T) {
+	t.Setenv("SECRET_TEST_API_KEY", "sk-123")
+	t.Setenv("SECRET_EMPTY", "")
+
+	store := NewStore()
+	store.LoadEnv(DefaultEnvPrefix)
+
+	assert.Contains(t, store.Names(), "test_api_key")
+	assert.NotContains(t, store.Names(), "empty")
+
+	resolved, err := store.Resolve("{{secret:test_api_key}}")
+	require.NoError(t, err)
+	assert.Equal(t, "sk-123", resolved)
+}
+
+func TestEncryptedFileRoundTrip(t *testing.T) {
+	data, err := Encrypt(map[string]string{"mail_password": "p@ss"}, "correct horse")
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "p@ss")
+
+	path := filepath.Join(t.TempDir(), "secrets.enc")
+	require.NoError(t, os.WriteFile(path, data, 0600))
+
+	store := NewStore()
+	count, err := store.LoadFile(path, "correct horse")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []string{"mail_password"}, store.Names())
+
+	_, err = NewStore().LoadFile(path, "wrong passphrase")
+	assert.ErrorIs(t, err, ErrWrongPassphrase)
+}
+
+func TestEncryptRejectsShortPassphrase(t *testing.T) {
+	_, err := Encrypt(map[string]string{"a": "b"}, "short")
+	assert.Error(t, err)
+}
+
+func TestDecryptRejectsIterationsOutOfRange(t *testing.T) {
+	data, err := Encrypt(map[string]string{"a": "b"}, "correct horse")
+	require.NoError(t, err)
+
+	for _, iterations := range []int{-1, 0, 1, kdfIterations - 1, maxKDFIterations + 1} {
+		var file encryptedFile
+		require.NoError(t, json.Unmarshal(data, &file))
+		file.Iterations = iterations
+		tampered, err := json.Marshal(file)
+		require.NoError(t, err)
+
+		_, err = Decrypt(tampered, "correct horse")
+		assert.ErrorContains(t, err, "iterations", "iterations=%d", iterations)
+	}
+}
@@ -0,0 +1,342 @@
+// Package transcript records the LLM turns of each run and exports them as
+// a self-contained report in Markdown, HTML or JSON.
+package transcript
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/application/runctx"
+	"browser-agent/internal/domain/entity"
+)
+
+var _ output.LLMPort = (*Recorder)(nil)
+
+const orchestratorAgent = "orchestrator"
+
+var ErrNoTranscript = errors.New("no transcript recorded for the task")
+
+// PageSource is the browser the agent is working in.
+type PageSource interface {
+	Screenshot(ctx context.Context) (*entity.Screenshot, error)
+	CurrentURL() string
+}
+
+// Pricing is the model price in USD per million tokens, used when the
+// provider does not report the cost itself.
+type Pricing struct {
+	PromptPerMillion     float64
+	CompletionPerMillion float64
+}
+
+func (p Pricing) cost(u entity.Usage) float64 {
+	return (float64(u.PromptTokens)*p.PromptPerMillion + float64(u.CompletionTokens)*p.CompletionPerMillion) / 1e6
+}
+
+// Recorder wraps an LLMPort and keeps a transcript per task, keyed by the
+// task ID on the request context. Tool results are taken from the next
+// request of the same agent, which carries them as tool messages.
+type Recorder struct {
+	llm      output.LLMPort
+	page     PageSource
+	redactor output.Redactor
+	pricing  Pricing
+
+	mu   sync.Mutex
+	runs map[string]*run
+	// pages holds the browsers of tasks that do not use page.
+	pages map[string]PageSource
+}
+
+type run struct {
+	transcript entity.Transcript
+	// pending maps agent and tool call ID to the call awaiting its result.
+	pending map[string]callRef
+	// shotTurn is the orchestrator turn whose tool calls are running and
+	// that gets a screenshot once they are done; -1 when there is none.
+	shotTurn int
+}
+
+type callRef struct {
+	turn, call int
+}
+
+// NewRecorder records the calls made through llm. page may be nil; without
+// it the transcript has no screenshots and URLs.
+func NewRecorder(llm output.LLMPort, page PageSource) *Recorder {
+	return &Recorder{
+		llm:   llm,
+		page:  page,
+		runs:  make(map[string]*run),
+		pages: make(map[string]PageSource),
+	}
+}
+
+// SetPage makes the task with taskID record page instead of the browser
+// given to NewRecorder, for tasks run in a browser of their own. A nil
+// page goes back to the shared one.
+func (r *Recorder) SetPage(taskID string, page PageSource) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if page == nil {
+		delete(r.pages, taskID)
+		return
+	}
+	r.pages[taskID] = page
+}
+
+// pageFor returns the browser of taskID, or nil when there is none.
+func (r *Recorder) pageFor(taskID string) PageSource {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if page, ok := r.pages[taskID]; ok {
+		return page
+	}
+	return r.page
+}
+
+// SetRedactor scrubs everything recorded from now on. Screenshots are kept
+// as they are.
+func (r *Recorder) SetRedactor(redactor output.Redactor) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.redactor = redactor
+}
+
+func (r *Recorder) SetPricing(pricing Pricing) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pricing = pricing
+}
+
+func (r *Recorder) Chat(ctx context.Context, req output.ChatRequest) (*output.ChatResponse, error) {
+	taskID := runctx.TaskID(ctx)
+	agent := agentName(ctx)
+
+	url := ""
+	if page := r.pageFor(taskID); page != nil {
+		url = page.CurrentURL()
+	}
+
+	r.mu.Lock()
+	state := r.runFor(taskID)
+	if agent == orchestratorAgent && state.transcript.Task == "" {
+		state.transcript.Task = r.redact(firstUserMessage(req.Messages))
+	}
+	r.collectResults(state, agent, req.Messages, url)
+	shotTurn := -1
+	if agent == orchestratorAgent {
+		shotTurn, state.shotTurn = state.shotTurn, -1
+	}
+	r.mu.Unlock()
+
+	if shotTurn >= 0 {
+		r.attachScreenshot(ctx, taskID, shotTurn)
+	}
+
+	resp, err := r.llm.Chat(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	usage := resp.Usage
+	if usage.CostUSD == 0 {
+		usage.CostUSD = r.pricing.cost(usage)
+	}
+	turn := r.newTurn(agent, resp.Message, usage)
+
+	state = r.runFor(taskID)
+	index := len(state.transcript.Turns)
+	state.transcript.Turns = append(state.transcript.Turns, turn)
+	state.transcript.Usage.Add(usage)
+	state.transcript.FinishedAt = turn.Time
+	if state.transcript.StartedAt.IsZero() {
+		state.transcript.StartedAt = turn.Time
+	}
+	if agent == orchestratorAgent && state.transcript.Plan == "" {
+		state.transcript.Plan = turn.Thinking
+		if state.transcript.Plan == "" && len(turn.ToolCalls) > 0 {
+			state.transcript.Plan = turn.Content
+		}
+	}
+	for i, call := range turn.ToolCalls {
+		state.pending[agent+"\x00"+call.ID] = callRef{turn: index, call: i}
+	}
+	final := agent == orchestratorAgent && len(turn.ToolCalls) == 0
+	if agent == orchestratorAgent && !final {
+		state.shotTurn = index
+	}
+	r.mu.Unlock()
+
+	if final {
+		r.attachScreenshot(ctx, taskID, index)
+	}
+	return resp, nil
+}
+
+// Complete records how the run ended.
+func (r *Recorder) Complete(taskID string, status entity.TaskStatus, finalAnswer, errMsg string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	state := r.runFor(taskID)
+	state.transcript.TaskID = taskID
+	state.transcript.Status = status
+	state.transcript.FinalAnswer = r.redact(finalAnswer)
+	state.transcript.Error = r.redact(errMsg)
+	state.transcript.FinishedAt = time.Now()
+	if state.transcript.StartedAt.IsZero() {
+		state.transcript.StartedAt = state.transcript.FinishedAt
+	}
+}
+
+// Transcript returns a copy of the transcript recorded for taskID.
+func (r *Recorder) Transcript(taskID string) (*entity.Transcript, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	state, ok := r.runs[taskID]
+	if !ok {
+		return nil, false
+	}
+	transcript := state.transcript
+	transcript.Turns = make([]entity.TranscriptTurn, len(state.transcript.Turns))
+	for i, turn := range state.transcript.Turns {
+		turn.ToolCalls = append([]entity.TranscriptToolCall(nil), turn.ToolCalls...)
+		transcript.Turns[i] = turn
+	}
+	return &transcript, true
+}
+
+// Forget drops the transcript of a task.
+func (r *Recorder) Forget(taskID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.runs, taskID)
+}
+
+// Export renders the transcript of taskID in the named format.
+func (r *Recorder) Export(taskID, format string) ([]byte, string, error) {
+	f, err := ParseFormat(format)
+	if err != nil {
+		return nil, "", err
+	}
+	transcript, ok := r.Transcript(taskID)
+	if !ok {
+		return nil, "", ErrNoTranscript
+	}
+	data, err := Render(transcript, f)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, f.ContentType(), nil
+}
+
+// runFor returns the run of taskID, creating it. r.mu must be held.
+func (r *Recorder) runFor(taskID string) *run {
+	state, ok := r.runs[taskID]
+	if !ok {
+		state = &run{pending: make(map[string]callRef), shotTurn: -1}
+		r.runs[taskID] = state
+	}
+	return state
+}
+
+// collectResults fills in the results of pending tool calls from the tool
+// messages of a request and notes url as the page the calls ended on. r.mu
+// must be held.
+func (r *Recorder) collectResults(state *run, agent string, messages []entity.Message, url string) {
+	for _, msg := range messages {
+		if msg.Role != entity.RoleTool {
+			continue
+		}
+		key := agent + "\x00" + msg.ToolCallID
+		ref, ok := state.pending[key]
+		if !ok {
+			continue
+		}
+		delete(state.pending, key)
+
+		turn := &state.transcript.Turns[ref.turn]
+		turn.URL = url
+		call := &turn.ToolCalls[ref.call]
+		call.Result = r.redact(msg.Content)
+		call.IsError = strings.HasPrefix(msg.Content, "Error: ")
+	}
+}
+
+// newTurn converts an LLM response. r.mu must be held.
+func (r *Recorder) newTurn(agent string, msg entity.Message, usage entity.Usage) entity.TranscriptTurn {
+	turn := entity.TranscriptTurn{
+		Agent:   agent,
+		Time:    time.Now(),
+		Content: r.redact(msg.Content),
+		Usage:   usage,
+	}
+
+	var thinking []string
+	for _, block := range msg.ContentBlocks {
+		if block.Type == entity.ContentTypeThinking && block.Thinking != "" {
+			thinking = append(thinking, block.Thinking)
+		}
+	}
+	turn.Thinking = r.redact(strings.Join(thinking, "\n\n"))
+
+	for _, tc := range msg.ToolCalls {
+		turn.ToolCalls = append(turn.ToolCalls, entity.TranscriptToolCall{
+			ID:        tc.ID,
+			Name:      tc.Name,
+			Arguments: r.redact(tc.Arguments),
+		})
+	}
+	return turn
+}
+
+func (r *Recorder) attachScreenshot(ctx context.Context, taskID string, turn int) {
+	page := r.pageFor(taskID)
+	if page == nil {
+		return
+	}
+	screenshot, err := page.Screenshot(ctx)
+	if err != nil || screenshot == nil {
+		// No page yet or the browser is gone: the report simply has no image.
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if state, ok := r.runs[taskID]; ok && turn < len(state.transcript.Turns) {
+		state.transcript.Turns[turn].Screenshot = &entity.TranscriptScreenshot{
+			Format: screenshot.Format,
+			Data:   screenshot.Data,
+		}
+	}
+}
+
+func (r *Recorder) redact(text string) string {
+	if r.redactor == nil || text == "" {
+		return text
+	}
+	return r.redactor.Redact(text)
+}
+
+func agentName(ctx context.Context) string {
+	if agents := runctx.Agents(ctx); len(agents) > 0 {
+		return strings.Join(agents, "/")
+	}
+	return orchestratorAgent
+}
+
+func firstUserMessage(messages []entity.Message) string {
+	for _, msg := range messages {
+		if msg.Role == entity.RoleUser {
+			return msg.Content
+		}
+	}
+	return ""
+}
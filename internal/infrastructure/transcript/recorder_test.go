@@ -0,0 +1,187 @@
+package transcript
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/application/runctx"
+	"browser-agent/internal/domain/entity"
+)
+
+// scriptedLLM answers each call with the next response.
+type scriptedLLM struct {
+	responses []output.ChatResponse
+}
+
+func (l *scriptedLLM) Chat(ctx context.Context, req output.ChatRequest) (*output.ChatResponse, error) {
+	if len(l.responses) == 0 {
+		return nil, errors.New("no more responses")
+	}
+	resp := l.responses[0]
+	l.responses = l.responses[1:]
+	return &resp, nil
+}
+
+type fakePage struct {
+	url   string
+	taken int
+}
+
+func (p *fakePage) Screenshot(context.Context) (*entity.Screenshot, error) {
+	p.taken++
+	return &entity.Screenshot{Data: []byte{byte(p.taken)}, Format: "jpeg"}, nil
+}
+
+func (p *fakePage) CurrentURL() string { return p.url }
+
+type secretRedactor struct{}
+
+func (secretRedactor) Redact(text string) string {
+	return strings.ReplaceAll(text, "hunter2", "[REDACTED]")
+}
+
+func toolCall(id, name, args string) entity.Message {
+	return entity.Message{
+		Role:      entity.RoleAssistant,
+		ToolCalls: []entity.ToolCall{{ID: id, Name: name, Arguments: args}},
+	}
+}
+
+func TestRecorder_RecordsRun(t *testing.T) {
+	llm := &scriptedLLM{responses: []output.ChatResponse{
+		{
+			Message: entity.Message{
+				Role:          entity.RoleAssistant,
+				ContentBlocks: []entity.ContentBlock{{Type: entity.ContentTypeThinking, Thinking: "open the login form first"}},
+				ToolCalls:     []entity.ToolCall{{ID: "o1", Name: "run_agent", Arguments: `{"agent":"form"}`}},
+			},
+			Usage: entity.Usage{PromptTokens: 100, CompletionTokens: 20, TotalTokens: 120},
+		},
+		{
+			Message: toolCall("s1", "fill", `{"text":"hunter2"}`),
+			Usage:   entity.Usage{PromptTokens: 50, CompletionTokens: 10, TotalTokens: 60},
+		},
+		{Message: entity.Message{Role: entity.RoleAssistant, Content: "form submitted"}},
+		{Message: entity.Message{Role: entity.RoleAssistant, Content: "Logged in"}},
+	}}
+	page := &fakePage{url: "about:blank"}
+	recorder := NewRecorder(llm, page)
+	recorder.SetRedactor(secretRedactor{})
+	recorder.SetPricing(Pricing{PromptPerMillion: 1, CompletionPerMillion: 2})
+
+	ctx := runctx.WithTaskID(context.Background(), "t1")
+	agentCtx := runctx.WithAgent(ctx, "form")
+	task := []entity.Message{{Role: entity.RoleSystem, Content: "system"}, {Role: entity.RoleUser, Content: "log in"}}
+
+	_, err := recorder.Chat(ctx, output.ChatRequest{Messages: task})
+	require.NoError(t, err)
+	_, err = recorder.Chat(agentCtx, output.ChatRequest{Messages: []entity.Message{{Role: entity.RoleUser, Content: "fill the form"}}})
+	require.NoError(t, err)
+	page.url = "https://example.com/login"
+	_, err = recorder.Chat(agentCtx, output.ChatRequest{Messages: []entity.Message{
+		{Role: entity.RoleTool, ToolCallID: "s1", Content: "Error: field not found"},
+	}})
+	require.NoError(t, err)
+	_, err = recorder.Chat(ctx, output.ChatRequest{Messages: append(task,
+		entity.Message{Role: entity.RoleTool, ToolCallID: "o1", Content: "form submitted"},
+	)})
+	require.NoError(t, err)
+
+	recorder.Complete("t1", entity.TaskStatusCompleted, "Logged in", "")
+
+	transcript, ok := recorder.Transcript("t1")
+	require.True(t, ok)
+	assert.Equal(t, "t1", transcript.TaskID)
+	assert.Equal(t, "log in", transcript.Task)
+	assert.Equal(t, "open the login form first", transcript.Plan)
+	assert.Equal(t, entity.TaskStatusCompleted, transcript.Status)
+	require.Len(t, transcript.Turns, 4)
+
+	orchestratorTurn := transcript.Turns[0]
+	assert.Equal(t, "orchestrator", orchestratorTurn.Agent)
+	assert.Equal(t, "form submitted", orchestratorTurn.ToolCalls[0].Result)
+	require.NotNil(t, orchestratorTurn.Screenshot, "screenshot after the sub-agent finished")
+	assert.Equal(t, []byte{1}, orchestratorTurn.Screenshot.Data)
+
+	subTurn := transcript.Turns[1]
+	assert.Equal(t, "form", subTurn.Agent)
+	assert.Equal(t, `{"text":"[REDACTED]"}`, subTurn.ToolCalls[0].Arguments)
+	assert.True(t, subTurn.ToolCalls[0].IsError)
+	assert.Equal(t, "https://example.com/login", subTurn.URL)
+	assert.Nil(t, subTurn.Screenshot)
+
+	final := transcript.Turns[3]
+	require.NotNil(t, final.Screenshot, "final screenshot")
+	assert.Equal(t, []byte{2}, final.Screenshot.Data)
+
+	assert.Equal(t, 180, transcript.Usage.TotalTokens)
+	assert.InDelta(t, (150*1+30*2)/1e6, transcript.Usage.CostUSD, 1e-12)
+}
+
+func TestRecorder_SeparatesTasks(t *testing.T) {
+	llm := &scriptedLLM{responses: []output.ChatResponse{
+		{Message: entity.Message{Role: entity.RoleAssistant, Content: "a"}},
+		{Message: entity.Message{Role: entity.RoleAssistant, Content: "b"}},
+	}}
+	recorder := NewRecorder(llm, nil)
+
+	_, err := recorder.Chat(runctx.WithTaskID(context.Background(), "a"), output.ChatRequest{})
+	require.NoError(t, err)
+	_, err = recorder.Chat(runctx.WithTaskID(context.Background(), "b"), output.ChatRequest{})
+	require.NoError(t, err)
+
+	a, ok := recorder.Transcript("a")
+	require.True(t, ok)
+	require.Len(t, a.Turns, 1)
+	assert.Equal(t, "a", a.Turns[0].Content)
+
+	recorder.Forget("a")
+	_, ok = recorder.Transcript("a")
+	assert.False(t, ok)
+
+	_, _, err = recorder.Export("a", "md")
+	assert.ErrorIs(t, err, ErrNoTranscript)
+	data, contentType, err := recorder.Export("b", "json")
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", contentType)
+	assert.Contains(t, string(data), `"content": "b"`)
+}
+
+func TestRecorder_TaskPages(t *testing.T) {
+	llm := &scriptedLLM{responses: []output.ChatResponse{
+		{Message: entity.Message{Role: entity.RoleAssistant, Content: "a"}},
+		{Message: entity.Message{Role: entity.RoleAssistant, Content: "b"}},
+	}}
+	shared := &fakePage{url: "https://shared.test"}
+	own := &fakePage{url: "https://own.test"}
+	recorder := NewRecorder(llm, shared)
+	recorder.SetPage("a", own)
+
+	_, err := recorder.Chat(runctx.WithTaskID(context.Background(), "a"), output.ChatRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, own.taken)
+	assert.Equal(t, 0, shared.taken)
+
+	recorder.SetPage("a", nil)
+	_, err = recorder.Chat(runctx.WithTaskID(context.Background(), "a"), output.ChatRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, own.taken)
+	assert.Equal(t, 1, shared.taken)
+}
+
+func TestRecorder_PassesErrorsThrough(t *testing.T) {
+	recorder := NewRecorder(&scriptedLLM{}, nil)
+
+	_, err := recorder.Chat(context.Background(), output.ChatRequest{})
+	assert.Error(t, err)
+
+	transcript, ok := recorder.Transcript("")
+	require.True(t, ok)
+	assert.Empty(t, transcript.Turns)
+}
@@ -0,0 +1,221 @@
+package transcript
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"browser-agent/internal/domain/entity"
+)
+
+type Format string
+
+const (
+	FormatMarkdown Format = "md"
+	FormatHTML     Format = "html"
+	FormatJSON     Format = "json"
+)
+
+// ParseFormat accepts md, markdown, html and json.
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "md", "markdown":
+		return FormatMarkdown, nil
+	case "html", "htm":
+		return FormatHTML, nil
+	case "json":
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("unknown transcript format %q (md, html or json)", s)
+	}
+}
+
+// FormatFromPath picks the format by file extension.
+func FormatFromPath(path string) (Format, error) {
+	return ParseFormat(strings.TrimPrefix(filepath.Ext(path), "."))
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatHTML:
+		return "text/html; charset=utf-8"
+	case FormatJSON:
+		return "application/json"
+	default:
+		return "text/markdown; charset=utf-8"
+	}
+}
+
+// Render exports a transcript. Screenshots are embedded, so the result is
+// a single self-contained document.
+func Render(transcript *entity.Transcript, format Format) ([]byte, error) {
+	switch format {
+	case FormatMarkdown:
+		return renderMarkdown(transcript), nil
+	case FormatHTML:
+		return renderHTML(transcript)
+	case FormatJSON:
+		return json.MarshalIndent(transcript, "", "  ")
+	default:
+		return nil, fmt.Errorf("unknown transcript format %q", format)
+	}
+}
+
+// WriteFile renders the transcript in the format given by the extension of
+// path.
+func WriteFile(path string, transcript *entity.Transcript) error {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return err
+	}
+	data, err := Render(transcript, format)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Load reads a transcript exported as JSON; the Markdown and HTML exports
+// cannot be read back.
+func Load(path string) (*entity.Transcript, error) {
+	if format, err := FormatFromPath(path); err == nil && format != FormatJSON {
+		return nil, fmt.Errorf("%s: only JSON transcripts can be loaded", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var transcript entity.Transcript
+	if err := json.Unmarshal(data, &transcript); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &transcript, nil
+}
+
+func renderMarkdown(t *entity.Transcript) []byte {
+	var b strings.Builder
+
+	b.WriteString("# Отчёт о запуске\n\n")
+	b.WriteString("| | |\n|---|---|\n")
+	if t.TaskID != "" {
+		fmt.Fprintf(&b, "| Задача | `%s` |\n", t.TaskID)
+	}
+	if t.Status != "" {
+		fmt.Fprintf(&b, "| Статус | %s |\n", t.Status)
+	}
+	fmt.Fprintf(&b, "| Начало | %s |\n", formatTime(t.StartedAt))
+	fmt.Fprintf(&b, "| Длительность | %s |\n", duration(t))
+	fmt.Fprintf(&b, "| Ходов LLM | %d |\n", len(t.Turns))
+	fmt.Fprintf(&b, "| Токены | %s |\n", usageLine(t.Usage))
+	b.WriteString("\n")
+
+	b.WriteString("## Задача\n\n")
+	b.WriteString(codeBlock(t.Task))
+
+	if t.Plan != "" {
+		b.WriteString("## План\n\n")
+		b.WriteString(codeBlock(t.Plan))
+	}
+
+	b.WriteString("## Ходы\n\n")
+	for i, turn := range t.Turns {
+		fmt.Fprintf(&b, "### %d. %s — %s\n\n", i+1, turn.Agent, turn.Time.Format("15:04:05"))
+		fmt.Fprintf(&b, "Токены: %s\n\n", usageLine(turn.Usage))
+		if turn.Thinking != "" {
+			b.WriteString("**Размышления**\n\n")
+			b.WriteString(codeBlock(turn.Thinking))
+		}
+		if turn.Content != "" {
+			b.WriteString("**Ответ модели**\n\n")
+			b.WriteString(codeBlock(turn.Content))
+		}
+		for _, call := range turn.ToolCalls {
+			fmt.Fprintf(&b, "**Вызов `%s`**\n\n", call.Name)
+			b.WriteString(codeBlock(call.Arguments))
+			if call.IsError {
+				b.WriteString("Ошибка:\n\n")
+			} else {
+				b.WriteString("Результат:\n\n")
+			}
+			b.WriteString(codeBlock(call.Result))
+		}
+		if turn.Screenshot != nil {
+			fmt.Fprintf(&b, "![Скриншот после хода %d](%s)\n\n", i+1, dataURI(turn.Screenshot))
+		}
+	}
+
+	b.WriteString("## Итог\n\n")
+	if t.FinalAnswer != "" {
+		b.WriteString(codeBlock(t.FinalAnswer))
+	}
+	if t.Error != "" {
+		b.WriteString("Ошибка:\n\n")
+		b.WriteString(codeBlock(t.Error))
+	}
+	if t.FinalAnswer == "" && t.Error == "" {
+		b.WriteString("Нет ответа.\n")
+	}
+
+	return []byte(b.String())
+}
+
+// codeBlock fences text with more backticks than it contains in a row.
+func codeBlock(text string) string {
+	fence := "```"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	return fence + "\n" + strings.TrimRight(text, "\n") + "\n" + fence + "\n\n"
+}
+
+//go:embed report.html.tmpl
+var reportTemplate string
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"add":      func(a, b int) int { return a + b },
+	"dataURI":  func(s *entity.TranscriptScreenshot) template.URL { return template.URL(dataURI(s)) },
+	"duration": duration,
+	"time":     formatTime,
+	"usage":    usageLine,
+}).Parse(reportTemplate))
+
+func renderHTML(t *entity.Transcript) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, t); err != nil {
+		return nil, fmt.Errorf("render html: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func dataURI(s *entity.TranscriptScreenshot) string {
+	return "data:image/" + s.Format + ";base64," + base64.StdEncoding.EncodeToString(s.Data)
+}
+
+func usageLine(u entity.Usage) string {
+	line := fmt.Sprintf("%d (запрос %d, ответ %d)", u.TotalTokens, u.PromptTokens, u.CompletionTokens)
+	if u.CostUSD > 0 {
+		line += fmt.Sprintf(", $%.4f", u.CostUSD)
+	}
+	return line
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "—"
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
+
+func duration(t *entity.Transcript) string {
+	if t.StartedAt.IsZero() || t.FinishedAt.IsZero() {
+		return "—"
+	}
+	return t.FinishedAt.Sub(t.StartedAt).Round(time.Second).String()
+}
@@ -0,0 +1,104 @@
+package transcript
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"browser-agent/internal/domain/entity"
+)
+
+func sampleTranscript() *entity.Transcript {
+	started := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	return &entity.Transcript{
+		TaskID:      "t1",
+		Task:        "Find the <b>price</b>",
+		Plan:        "Open the shop",
+		Status:      entity.TaskStatusCompleted,
+		FinalAnswer: "42 EUR",
+		StartedAt:   started,
+		FinishedAt:  started.Add(90 * time.Second),
+		Turns: []entity.TranscriptTurn{{
+			Agent:    "orchestrator",
+			Time:     started,
+			Thinking: "Open the shop",
+			ToolCalls: []entity.TranscriptToolCall{{
+				Name:      "run_agent",
+				Arguments: "```json\n{}\n```",
+				Result:    "done",
+			}},
+			Usage:      entity.Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15, CostUSD: 0.001},
+			Screenshot: &entity.TranscriptScreenshot{Format: "jpeg", Data: []byte("img")},
+		}},
+		Usage: entity.Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15, CostUSD: 0.001},
+	}
+}
+
+func TestRender_Markdown(t *testing.T) {
+	data, err := Render(sampleTranscript(), FormatMarkdown)
+	require.NoError(t, err)
+	md := string(data)
+
+	assert.Contains(t, md, "## План")
+	assert.Contains(t, md, "### 1. orchestrator")
+	assert.Contains(t, md, "15 (запрос 10, ответ 5), $0.0010")
+	assert.Contains(t, md, "| Длительность | 1m30s |")
+	assert.Contains(t, md, "````\n```json\n{}\n```\n````", "fence longer than the content's")
+	assert.Contains(t, md, "](data:image/jpeg;base64,aW1n)")
+	assert.Contains(t, md, "42 EUR")
+}
+
+func TestRender_HTML(t *testing.T) {
+	data, err := Render(sampleTranscript(), FormatHTML)
+	require.NoError(t, err)
+	html := string(data)
+
+	assert.Contains(t, html, "Find the &lt;b&gt;price&lt;/b&gt;")
+	assert.Contains(t, html, `src="data:image/jpeg;base64,aW1n"`)
+	assert.Contains(t, html, "status-completed")
+	assert.False(t, strings.Contains(html, "<b>price"))
+}
+
+func TestRender_JSON(t *testing.T) {
+	data, err := Render(sampleTranscript(), FormatJSON)
+	require.NoError(t, err)
+
+	var decoded entity.Transcript
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, *sampleTranscript(), decoded)
+}
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, WriteFile(filepath.Join(dir, "run.md"), sampleTranscript()))
+	data, err := os.ReadFile(filepath.Join(dir, "run.md"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "# Отчёт о запуске"))
+
+	assert.Error(t, WriteFile(filepath.Join(dir, "run.pdf"), sampleTranscript()))
+
+	require.NoError(t, WriteFile(filepath.Join(dir, "run.json"), sampleTranscript()))
+	loaded, err := Load(filepath.Join(dir, "run.json"))
+	require.NoError(t, err)
+	assert.Equal(t, sampleTranscript(), loaded)
+
+	_, err = Load(filepath.Join(dir, "run.md"))
+	assert.ErrorContains(t, err, "only JSON")
+}
+
+func TestParseFormat(t *testing.T) {
+	for input, want := range map[string]Format{"md": FormatMarkdown, "Markdown": FormatMarkdown, "htm": FormatHTML, "json": FormatJSON} {
+		got, err := ParseFormat(input)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	_, err := ParseFormat("pdf")
+	assert.Error(t, err)
+}
@@ -5,48 +5,243 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/domain/entity"
 	"github.com/fatih/color"
 )
 
 var _ output.UserInteractionPort = (*ConsoleUserInteraction)(nil)
 
+const approvalScreenshotDir = "log/approvals"
+
 type ConsoleUserInteraction struct {
-	reader *bufio.Reader
+	reader    *bufio.Reader
+	lines     chan string
+	readErr   error
+	startOnce sync.Once
+
+	mu             sync.Mutex
+	pauseRequested bool
+	aborted        bool
+
+	redactor output.Redactor
 }
 
 func NewConsoleUserInteraction() *ConsoleUserInteraction {
+	return newConsoleUserInteraction(os.Stdin)
+}
+
+func newConsoleUserInteraction(in io.Reader) *ConsoleUserInteraction {
 	return &ConsoleUserInteraction{
-		reader: bufio.NewReader(os.Stdin),
+		reader: bufio.NewReader(in),
+		lines:  make(chan string),
+	}
+}
+
+// ReadLine reads one line from stdin. Unlike a plain bufio read it returns as
+// soon as ctx is canceled, so a second Ctrl+C is never stuck behind a prompt.
+func (u *ConsoleUserInteraction) ReadLine(ctx context.Context) (string, error) {
+	u.startOnce.Do(u.startReader)
+
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case line, ok := <-u.lines:
+		if !ok {
+			if u.readErr != nil {
+				return "", u.readErr
+			}
+			return "", io.EOF
+		}
+		return strings.TrimSpace(line), nil
+	}
+}
+
+func (u *ConsoleUserInteraction) startReader() {
+	go func() {
+		defer close(u.lines)
+		for {
+			line, err := u.reader.ReadString('\n')
+			if line != "" {
+				u.lines <- line
+			}
+			if err != nil {
+				u.readErr = err
+				return
+			}
+		}
+	}()
+}
+
+// SetRedactor makes every piece of agent output pass through redactor
+// before it is printed.
+func (u *ConsoleUserInteraction) SetRedactor(redactor output.Redactor) {
+	u.redactor = redactor
+}
+
+func (u *ConsoleUserInteraction) redact(text string) string {
+	if u.redactor == nil {
+		return text
 	}
+	return u.redactor.Redact(text)
+}
+
+// RequestPause asks the running agent to stop at the next checkpoint.
+// It returns false if a pause is already pending, so the caller can escalate
+// to cancellation.
+func (u *ConsoleUserInteraction) RequestPause() bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.pauseRequested {
+		return false
+	}
+	u.pauseRequested = true
+	return true
 }
 
 func (u *ConsoleUserInteraction) AskQuestion(ctx context.Context, question string) (string, error) {
-	fmt.Printf("\n[USER INPUT REQUIRED] %s\n> ", question)
+	fmt.Printf("\n[USER INPUT REQUIRED] %s\n> ", u.redact(question))
 
-	answer, err := u.reader.ReadString('\n')
+	answer, err := u.ReadLine(ctx)
 	if err != nil {
 		return "", fmt.Errorf("failed to read user input: %w", err)
 	}
 
-	return strings.TrimSpace(answer), nil
+	return answer, nil
 }
 
 func (u *ConsoleUserInteraction) WaitForUserAction(ctx context.Context, message string) error {
-	fmt.Printf("\n[USER ACTION REQUIRED] %s\n", message)
+	fmt.Printf("\n[USER ACTION REQUIRED] %s\n", u.redact(message))
 	fmt.Print("Press Enter when done...")
 
-	_, err := u.reader.ReadString('\n')
-	if err != nil {
+	if _, err := u.ReadLine(ctx); err != nil {
 		return fmt.Errorf("failed to wait for user: %w", err)
 	}
 
 	return nil
 }
 
+func (u *ConsoleUserInteraction) RequestApproval(ctx context.Context, req entity.ApprovalRequest) (bool, error) {
+	_, name := getToolDisplay(string(req.ToolName))
+
+	red := color.New(color.FgRed, color.Bold)
+	red.Printf("\n⚠️  [ТРЕБУЕТСЯ ПОДТВЕРЖДЕНИЕ] %s\n", name)
+
+	if req.URL != "" {
+		fmt.Printf("   Страница: %s\n", u.redact(req.URL))
+	}
+	if req.Target != nil {
+		fmt.Printf("   Элемент: %s\n", u.redact(formatElementInfo(req.Target)))
+	} else if summary := formatToolArguments(string(req.ToolName), req.Arguments); summary != "" {
+		fmt.Printf("   Действие: %s\n", u.redact(summary))
+	}
+	for _, reason := range req.Reasons {
+		fmt.Printf("   Причина: %s\n", u.redact(reason))
+	}
+	if req.Screenshot != nil {
+		if path, err := saveApprovalScreenshot(req.Screenshot); err == nil {
+			fmt.Printf("   Скриншот: %s\n", path)
+		}
+	}
+
+	fmt.Print("Разрешить действие? [y/N] > ")
+
+	answer, err := u.ReadLine(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to read approval: %w", err)
+	}
+
+	switch strings.ToLower(answer) {
+	case "y", "yes", "д", "да":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+func formatElementInfo(info *entity.ElementInfo) string {
+	label := info.Text
+	if label == "" {
+		label = info.AriaLabel
+	}
+
+	result := fmt.Sprintf("<%s>", info.TagName)
+	if label != "" {
+		result += fmt.Sprintf(" \"%s\"", truncate(label, 80))
+	}
+	if info.Selector != "" {
+		result += fmt.Sprintf(" (selector: %s)", truncate(info.Selector, 60))
+	}
+	if info.InForm && info.FormAction != "" {
+		result += fmt.Sprintf(" → form action: %s", info.FormAction)
+	}
+	return result
+}
+
+func saveApprovalScreenshot(screenshot *entity.Screenshot) (string, error) {
+	if err := os.MkdirAll(approvalScreenshotDir, 0755); err != nil {
+		return "", err
+	}
+
+	filename := fmt.Sprintf("%s.%s", time.Now().Format("2006-01-02_15-04-05.000"), screenshot.Format)
+	path := filepath.Join(approvalScreenshotDir, filename)
+	if err := os.WriteFile(path, screenshot.Data, 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func (u *ConsoleUserInteraction) Checkpoint(ctx context.Context) (string, error) {
+	u.mu.Lock()
+	aborted, paused := u.aborted, u.pauseRequested
+	u.mu.Unlock()
+
+	if aborted {
+		return "", output.ErrTaskAborted
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if !paused {
+		return "", nil
+	}
+
+	yellow := color.New(color.FgYellow, color.Bold)
+	yellow.Println("\n⏸  Выполнение приостановлено")
+	fmt.Println("   Enter — продолжить")
+	fmt.Println("   текст — добавить указание агенту и продолжить")
+	fmt.Println("   abort — прервать задачу")
+	fmt.Println("   Ctrl+C — отменить немедленно")
+	fmt.Print("> ")
+
+	line, err := u.ReadLine(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.pauseRequested = false
+
+	switch strings.ToLower(line) {
+	case "":
+		return "", nil
+	case "abort", "stop", "стоп":
+		u.aborted = true
+		return "", output.ErrTaskAborted
+	default:
+		return line, nil
+	}
+}
+
 func (u *ConsoleUserInteraction) ShowIteration(ctx context.Context, iteration, maxIterations int) {
 	cyan := color.New(color.FgCyan, color.Bold)
 	cyan.Printf("\n━━━ Итерация %d/%d ━━━\n", iteration, maxIterations)
@@ -61,7 +256,7 @@ func (u *ConsoleUserInteraction) ShowThinking(ctx context.Context, content strin
 	blue.Print("\n💭 Размышление: ")
 
 	dim := color.New(color.Faint)
-	truncated := truncate(content, 500)
+	truncated := truncate(u.redact(content), 500)
 	dim.Println(truncated)
 }
 
@@ -71,7 +266,7 @@ func (u *ConsoleUserInteraction) ShowToolStart(ctx context.Context, toolName, ar
 	yellow := color.New(color.FgYellow, color.Bold)
 	yellow.Printf("\n%s %s\n", icon, name)
 
-	summary := formatToolArguments(toolName, arguments)
+	summary := formatToolArguments(toolName, u.redact(arguments))
 	if summary != "" {
 		dim := color.New(color.Faint)
 		dim.Printf("   %s\n", summary)
@@ -84,11 +279,11 @@ func (u *ConsoleUserInteraction) ShowToolResult(ctx context.Context, toolName, r
 		red.Print("❌ Ошибка: ")
 
 		dim := color.New(color.Faint)
-		dim.Println(truncate(result, 300))
+		dim.Println(truncate(u.redact(result), 300))
 		return
 	}
 
-	summary := formatToolResult(toolName, result)
+	summary := formatToolResult(toolName, u.redact(result))
 	green := color.New(color.FgGreen)
 	green.Printf("✓ %s\n", summary)
 }
@@ -104,6 +299,12 @@ func getToolDisplay(toolName string) (string, string) {
 		"browser_observe":        {"👁️", "Наблюдение"},
 		"browser_query_elements": {"🔍", "Извлечение данных"},
 		"browser_search":         {"🔎", "Поиск"},
+		"browser_wait":           {"⏳", "Ожидание"},
+		"browser_network":        {"📡", "Сетевые запросы"},
+		"browser_intercept":      {"🚧", "Перехват запросов"},
+		"browser_console":        {"🧾", "Консоль страницы"},
+		"browser_dialog":         {"💬", "Диалог страницы"},
+		"browser_emulate":        {"📱", "Эмуляция устройства"},
 		"run_agent":              {"🤖", "Запуск агента"},
 		"user_ask_question":      {"❓", "Вопрос пользователю"},
 		"user_wait_action":       {"⏸️", "Ожидание действия"},
@@ -184,6 +385,66 @@ func formatToolArguments(toolName, arguments string) string {
 			return fmt.Sprintf("Тип: %s, Запрос: %s", t, truncate(query, 50))
 		}
 
+	case "browser_wait":
+		condition, _ := args["condition"].(string)
+		for _, key := range []string{"selector", "text", "url_pattern", "script"} {
+			if value, ok := args[key].(string); ok && value != "" {
+				return fmt.Sprintf("%s: %s", condition, truncate(value, 60))
+			}
+		}
+		return condition
+
+	case "browser_network":
+		if id, ok := args["id"].(string); ok && id != "" {
+			return "Запрос #" + id
+		}
+		if pattern, ok := args["url_pattern"].(string); ok && pattern != "" {
+			return "URL: " + truncate(pattern, 60)
+		}
+
+	case "browser_intercept":
+		action, _ := args["action"].(string)
+		if rule, ok := args["rule"].(string); ok && action == "add" {
+			pattern, _ := args["url_pattern"].(string)
+			return strings.TrimSpace(rule + " " + truncate(pattern, 60))
+		}
+		return action
+
+	case "browser_emulate":
+		if reset, _ := args["reset"].(bool); reset {
+			return "reset"
+		}
+		var parts []string
+		for _, key := range []string{"device", "locale", "timezone", "color_scheme"} {
+			if value, ok := args[key].(string); ok && value != "" {
+				parts = append(parts, value)
+			}
+		}
+		if width, ok := args["width"].(float64); ok {
+			height, _ := args["height"].(float64)
+			parts = append(parts, fmt.Sprintf("%gx%g", width, height))
+		}
+		return strings.Join(parts, ", ")
+
+	case "browser_screenshot":
+		if format, _ := args["format"].(string); format == "pdf" {
+			return "PDF"
+		}
+		if selector, ok := args["selector"].(string); ok && selector != "" {
+			return "Элемент: " + truncate(selector, 60)
+		}
+		if fullPage, _ := args["full_page"].(bool); fullPage {
+			return "Вся страница"
+		}
+		return "Видимая область"
+
+	case "browser_dialog":
+		action, _ := args["action"].(string)
+		if text, ok := args["prompt_text"].(string); ok && action == "accept" {
+			return fmt.Sprintf("accept %q", truncate(text, 60))
+		}
+		return action
+
 	case "run_agent":
 		agentType, _ := args["agent_type"].(string)
 		task, _ := args["task"].(string)
@@ -252,7 +513,10 @@ func formatToolResult(toolName, result string) string {
 		return result
 
 	case "browser_screenshot":
-		return "Скриншот сделан"
+		if i := strings.LastIndex(result, " to "); i >= 0 {
+			return "Сохранено: " + result[i+len(" to "):]
+		}
+		return result
 
 	case "browser_press_enter":
 		return "Enter нажат"
@@ -0,0 +1,85 @@
+package userinteraction
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"browser-agent/internal/application/port/output"
+)
+
+func TestConsoleCheckpoint_NothingRequested(t *testing.T) {
+	u := newConsoleUserInteraction(strings.NewReader(""))
+
+	guidance, err := u.Checkpoint(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, guidance)
+}
+
+func TestConsoleCheckpoint_EnterResumes(t *testing.T) {
+	u := newConsoleUserInteraction(strings.NewReader("\n"))
+	require.True(t, u.RequestPause())
+
+	guidance, err := u.Checkpoint(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, guidance)
+
+	// The pause is consumed, so the next checkpoint does not read again.
+	guidance, err = u.Checkpoint(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, guidance)
+}
+
+func TestConsoleCheckpoint_TextIsGuidance(t *testing.T) {
+	u := newConsoleUserInteraction(strings.NewReader("  open the second result \n"))
+	require.True(t, u.RequestPause())
+
+	guidance, err := u.Checkpoint(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "open the second result", guidance)
+}
+
+func TestConsoleCheckpoint_Abort(t *testing.T) {
+	u := newConsoleUserInteraction(strings.NewReader("abort\n"))
+	require.True(t, u.RequestPause())
+
+	_, err := u.Checkpoint(context.Background())
+	assert.ErrorIs(t, err, output.ErrTaskAborted)
+
+	// An aborted task stays aborted at every later checkpoint.
+	_, err = u.Checkpoint(context.Background())
+	assert.ErrorIs(t, err, output.ErrTaskAborted)
+}
+
+func TestConsoleRequestPause_SecondRequestEscalates(t *testing.T) {
+	u := newConsoleUserInteraction(strings.NewReader(""))
+
+	assert.True(t, u.RequestPause())
+	assert.False(t, u.RequestPause())
+}
+
+func TestConsoleReadLine_CanceledContextUnblocks(t *testing.T) {
+	in, out := io.Pipe()
+	defer out.Close()
+	u := newConsoleUserInteraction(in)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := u.ReadLine(ctx)
+		done <- err
+	}()
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("ReadLine did not return after cancel")
+	}
+}
@@ -0,0 +1,211 @@
+package userinteraction
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"browser-agent/internal/application/port/input"
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/application/runctx"
+	"browser-agent/internal/domain/entity"
+)
+
+var (
+	_ output.UserInteractionPort = (*RemoteUserInteraction)(nil)
+	_ input.InteractionResponder = (*RemoteUserInteraction)(nil)
+)
+
+const maxEventResultLen = 4000
+
+// RemoteUserInteraction turns agent output into task events and blocks
+// questions and approvals until a remote client answers them. The task is
+// taken from the call's context, so one instance serves all tasks.
+type RemoteUserInteraction struct {
+	events   output.EventPublisher
+	redactor output.Redactor
+
+	mu      sync.Mutex
+	pending map[string]*pendingInteraction
+}
+
+type pendingInteraction struct {
+	info   entity.PendingInteraction
+	answer chan string
+}
+
+func NewRemoteUserInteraction(events output.EventPublisher) *RemoteUserInteraction {
+	return &RemoteUserInteraction{
+		events:  events,
+		pending: make(map[string]*pendingInteraction),
+	}
+}
+
+// SetRedactor makes every published text pass through redactor.
+func (u *RemoteUserInteraction) SetRedactor(redactor output.Redactor) {
+	u.redactor = redactor
+}
+
+func (u *RemoteUserInteraction) redact(text string) string {
+	if u.redactor == nil {
+		return text
+	}
+	return u.redactor.Redact(text)
+}
+
+func (u *RemoteUserInteraction) AskQuestion(ctx context.Context, question string) (string, error) {
+	return u.wait(ctx, entity.PendingInteraction{
+		Kind:   entity.InteractionQuestion,
+		Prompt: u.redact(question),
+	})
+}
+
+func (u *RemoteUserInteraction) WaitForUserAction(ctx context.Context, message string) error {
+	_, err := u.wait(ctx, entity.PendingInteraction{
+		Kind:   entity.InteractionAction,
+		Prompt: u.redact(message),
+	})
+	return err
+}
+
+func (u *RemoteUserInteraction) RequestApproval(ctx context.Context, req entity.ApprovalRequest) (bool, error) {
+	req.Arguments = u.redact(req.Arguments)
+	req.URL = u.redact(req.URL)
+
+	answer, err := u.wait(ctx, entity.PendingInteraction{
+		Kind:     entity.InteractionApproval,
+		Prompt:   "Approve " + string(req.ToolName) + "?",
+		Approval: &req,
+	})
+	if err != nil {
+		return false, err
+	}
+	return isApproval(answer), nil
+}
+
+// Checkpoint never pauses: remote clients stop a task by canceling it.
+func (u *RemoteUserInteraction) Checkpoint(ctx context.Context) (string, error) {
+	return "", ctx.Err()
+}
+
+func (u *RemoteUserInteraction) ShowIteration(ctx context.Context, iteration, maxIterations int) {
+	u.publish(ctx, entity.EventIteration, map[string]any{
+		"iteration":      iteration,
+		"max_iterations": maxIterations,
+	})
+}
+
+func (u *RemoteUserInteraction) ShowThinking(ctx context.Context, content string) {
+	u.publish(ctx, entity.EventThinking, map[string]any{
+		"content": u.redact(content),
+	})
+}
+
+func (u *RemoteUserInteraction) ShowToolStart(ctx context.Context, toolName, arguments string) {
+	u.publish(ctx, entity.EventToolStart, map[string]any{
+		"tool":      toolName,
+		"arguments": u.redact(arguments),
+	})
+}
+
+func (u *RemoteUserInteraction) ShowToolResult(ctx context.Context, toolName, result string, isError bool) {
+	u.publish(ctx, entity.EventToolResult, map[string]any{
+		"tool":     toolName,
+		"result":   truncate(u.redact(result), maxEventResultLen),
+		"is_error": isError,
+	})
+}
+
+func (u *RemoteUserInteraction) Pending(taskID string) []entity.PendingInteraction {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	result := make([]entity.PendingInteraction, 0)
+	for _, p := range u.pending {
+		if taskID == "" || p.info.TaskID == taskID {
+			result = append(result, p.info)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result
+}
+
+func (u *RemoteUserInteraction) Respond(taskID, interactionID, answer string) error {
+	u.mu.Lock()
+	p, ok := u.pending[interactionID]
+	if ok && p.info.TaskID == taskID {
+		delete(u.pending, interactionID)
+	}
+	u.mu.Unlock()
+
+	if !ok || p.info.TaskID != taskID {
+		return input.ErrInteractionNotFound
+	}
+
+	p.answer <- answer
+	return nil
+}
+
+func (u *RemoteUserInteraction) wait(ctx context.Context, info entity.PendingInteraction) (string, error) {
+	info.ID = newInteractionID()
+	info.TaskID = runctx.TaskID(ctx)
+	info.CreatedAt = time.Now()
+
+	p := &pendingInteraction{info: info, answer: make(chan string, 1)}
+
+	u.mu.Lock()
+	u.pending[info.ID] = p
+	u.mu.Unlock()
+
+	u.publish(ctx, entity.EventInteraction, map[string]any{"interaction": info})
+
+	select {
+	case answer := <-p.answer:
+		u.publish(ctx, entity.EventAnswered, map[string]any{"interaction_id": info.ID})
+		return answer, nil
+	case <-ctx.Done():
+		u.mu.Lock()
+		delete(u.pending, info.ID)
+		u.mu.Unlock()
+		return "", ctx.Err()
+	}
+}
+
+// publish tags data with the agent that produced it so clients can render
+// sub-agent calls nested under the run_agent call that started them.
+func (u *RemoteUserInteraction) publish(ctx context.Context, eventType entity.EventType, data map[string]any) {
+	agents := runctx.Agents(ctx)
+	data["depth"] = len(agents)
+	if len(agents) > 0 {
+		data["agent"] = agents[len(agents)-1]
+	} else {
+		data["agent"] = string(entity.AgentTypeOrchestrator)
+	}
+
+	u.events.Publish(entity.Event{
+		TaskID: runctx.TaskID(ctx),
+		Type:   eventType,
+		Data:   data,
+	})
+}
+
+func isApproval(answer string) bool {
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes", "approve", "approved", "true", "да", "д":
+		return true
+	default:
+		return false
+	}
+}
+
+func newInteractionID() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,96 @@
+package userinteraction
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"browser-agent/internal/application/port/input"
+	"browser-agent/internal/application/runctx"
+	"browser-agent/internal/domain/entity"
+	"browser-agent/internal/infrastructure/eventbus"
+)
+
+func waitPending(t *testing.T, u *RemoteUserInteraction, taskID string) entity.PendingInteraction {
+	t.Helper()
+	var pending []entity.PendingInteraction
+	require.Eventually(t, func() bool {
+		pending = u.Pending(taskID)
+		return len(pending) == 1
+	}, time.Second, 5*time.Millisecond)
+	return pending[0]
+}
+
+func TestRemoteUserInteraction_AskQuestion(t *testing.T) {
+	bus := eventbus.New()
+	u := NewRemoteUserInteraction(bus)
+	ctx := runctx.WithTaskID(context.Background(), "task-1")
+
+	answers := make(chan string, 1)
+	go func() {
+		answer, err := u.AskQuestion(ctx, "Which account?")
+		assert.NoError(t, err)
+		answers <- answer
+	}()
+
+	pending := waitPending(t, u, "task-1")
+	assert.Equal(t, entity.InteractionQuestion, pending.Kind)
+	assert.Equal(t, "Which account?", pending.Prompt)
+
+	assert.ErrorIs(t, u.Respond("other-task", pending.ID, "x"), input.ErrInteractionNotFound)
+	require.NoError(t, u.Respond("task-1", pending.ID, "work"))
+	assert.Equal(t, "work", <-answers)
+	assert.Empty(t, u.Pending("task-1"))
+
+	history, _, unsubscribe := bus.Subscribe("task-1")
+	unsubscribe()
+	require.Len(t, history, 2)
+	assert.Equal(t, entity.EventInteraction, history[0].Type)
+	assert.Equal(t, entity.EventAnswered, history[1].Type)
+}
+
+func TestRemoteUserInteraction_RequestApproval(t *testing.T) {
+	u := NewRemoteUserInteraction(eventbus.New())
+	ctx := runctx.WithTaskID(context.Background(), "task-1")
+
+	results := make(chan bool, 1)
+	go func() {
+		approved, err := u.RequestApproval(ctx, entity.ApprovalRequest{
+			ToolName:   "click",
+			Screenshot: &entity.Screenshot{Data: []byte("jpeg"), Format: "jpeg"},
+		})
+		assert.NoError(t, err)
+		results <- approved
+	}()
+
+	pending := waitPending(t, u, "task-1")
+	require.NotNil(t, pending.Approval)
+
+	// Remote clients get the screenshot inline, base64 encoded.
+	data, err := json.Marshal(pending)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"screenshot":{"data":"anBlZw==","format":"jpeg"`)
+
+	require.NoError(t, u.Respond("task-1", pending.ID, "Yes"))
+	assert.True(t, <-results)
+}
+
+func TestRemoteUserInteraction_CanceledWhileWaiting(t *testing.T) {
+	u := NewRemoteUserInteraction(eventbus.New())
+	ctx, cancel := context.WithCancel(runctx.WithTaskID(context.Background(), "task-1"))
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- u.WaitForUserAction(ctx, "Solve the CAPTCHA")
+	}()
+
+	waitPending(t, u, "task-1")
+	cancel()
+
+	assert.ErrorIs(t, <-errs, context.Canceled)
+	assert.Empty(t, u.Pending(""))
+}
@@ -0,0 +1,164 @@
+package userinteraction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/application/runctx"
+	"browser-agent/internal/domain/entity"
+)
+
+var _ output.UserInteractionPort = (*ScriptedUserInteraction)(nil)
+
+var ErrNoScriptedAnswer = errors.New("no scripted answer in non-interactive mode")
+
+// ScriptedUserInteraction answers agents from per-task scripts instead of a
+// person. A request the script cannot answer fails the tool call and aborts
+// the task at the next checkpoint, so a batch never waits on stdin.
+type ScriptedUserInteraction struct {
+	logger output.LoggerPort
+
+	mu      sync.Mutex
+	scripts map[string]*scriptState
+}
+
+type scriptState struct {
+	answers   []compiledAnswer
+	approvals entity.ApprovalMode
+	failure   error
+}
+
+type compiledAnswer struct {
+	match  *regexp.Regexp
+	answer string
+}
+
+func NewScriptedUserInteraction(logger output.LoggerPort) *ScriptedUserInteraction {
+	return &ScriptedUserInteraction{
+		logger:  logger,
+		scripts: make(map[string]*scriptState),
+	}
+}
+
+// Load installs the script used for calls made on behalf of taskID.
+func (u *ScriptedUserInteraction) Load(taskID string, script entity.InteractionScript) error {
+	state := &scriptState{approvals: script.Approvals}
+	for _, a := range script.Answers {
+		re, err := regexp.Compile("(?i)" + a.Match)
+		if err != nil {
+			return fmt.Errorf("invalid answer pattern %q: %w", a.Match, err)
+		}
+		state.answers = append(state.answers, compiledAnswer{match: re, answer: a.Answer})
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.scripts[taskID] = state
+	return nil
+}
+
+func (u *ScriptedUserInteraction) Forget(taskID string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	delete(u.scripts, taskID)
+}
+
+func (u *ScriptedUserInteraction) AskQuestion(ctx context.Context, question string) (string, error) {
+	if answer, ok := u.lookup(ctx, question); ok {
+		u.logger.Info("Scripted answer used", "question", question)
+		return answer, nil
+	}
+	return "", u.fail(ctx, fmt.Errorf("%w: question %q", ErrNoScriptedAnswer, question))
+}
+
+func (u *ScriptedUserInteraction) WaitForUserAction(ctx context.Context, message string) error {
+	if _, ok := u.lookup(ctx, message); ok {
+		u.logger.Info("Scripted user action confirmed", "message", message)
+		return nil
+	}
+	return u.fail(ctx, fmt.Errorf("%w: user action %q", ErrNoScriptedAnswer, message))
+}
+
+func (u *ScriptedUserInteraction) RequestApproval(ctx context.Context, req entity.ApprovalRequest) (bool, error) {
+	switch u.approvalMode(ctx) {
+	case entity.ApprovalApprove:
+		u.logger.Info("Scripted approval granted", "tool", req.ToolName)
+		return true, nil
+	case entity.ApprovalFail:
+		return false, u.fail(ctx, fmt.Errorf("%w: approval for %s", ErrNoScriptedAnswer, req.ToolName))
+	default:
+		u.logger.Info("Scripted approval denied", "tool", req.ToolName)
+		return false, nil
+	}
+}
+
+// Checkpoint aborts a task once one of its requests went unanswered.
+func (u *ScriptedUserInteraction) Checkpoint(ctx context.Context) (string, error) {
+	u.mu.Lock()
+	state := u.scripts[runctx.TaskID(ctx)]
+	var failure error
+	if state != nil {
+		failure = state.failure
+	}
+	u.mu.Unlock()
+
+	if failure != nil {
+		return "", fmt.Errorf("%w: %v", output.ErrTaskAborted, failure)
+	}
+	return "", ctx.Err()
+}
+
+func (u *ScriptedUserInteraction) ShowIteration(ctx context.Context, iteration, maxIterations int) {
+	u.logger.Debug("Iteration", "taskId", runctx.TaskID(ctx), "iteration", iteration, "max", maxIterations)
+}
+
+func (u *ScriptedUserInteraction) ShowThinking(ctx context.Context, content string) {}
+
+func (u *ScriptedUserInteraction) ShowToolStart(ctx context.Context, toolName, arguments string) {
+	u.logger.Debug("Tool started", "taskId", runctx.TaskID(ctx), "tool", toolName)
+}
+
+func (u *ScriptedUserInteraction) ShowToolResult(ctx context.Context, toolName, result string, isError bool) {
+	u.logger.Debug("Tool finished", "taskId", runctx.TaskID(ctx), "tool", toolName, "isError", isError)
+}
+
+func (u *ScriptedUserInteraction) lookup(ctx context.Context, text string) (string, bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	state := u.scripts[runctx.TaskID(ctx)]
+	if state == nil {
+		return "", false
+	}
+	for _, a := range state.answers {
+		if a.match.MatchString(text) {
+			return a.answer, true
+		}
+	}
+	return "", false
+}
+
+func (u *ScriptedUserInteraction) approvalMode(ctx context.Context) entity.ApprovalMode {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if state := u.scripts[runctx.TaskID(ctx)]; state != nil && state.approvals != "" {
+		return state.approvals
+	}
+	return entity.ApprovalDeny
+}
+
+func (u *ScriptedUserInteraction) fail(ctx context.Context, err error) error {
+	u.logger.Warn("Non-interactive request failed", "taskId", runctx.TaskID(ctx), "error", err)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if state := u.scripts[runctx.TaskID(ctx)]; state != nil && state.failure == nil {
+		state.failure = err
+	}
+	return err
+}
@@ -0,0 +1,76 @@
+package userinteraction
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/application/runctx"
+	"browser-agent/internal/domain/entity"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...any)                          {}
+func (nopLogger) Info(string, ...any)                           {}
+func (nopLogger) Warn(string, ...any)                           {}
+func (nopLogger) Error(string, ...any)                          {}
+func (l nopLogger) WithField(string, any) output.LoggerPort     { return l }
+func (l nopLogger) WithFields(map[string]any) output.LoggerPort { return l }
+func (nopLogger) Close() error                                  { return nil }
+
+func TestScriptedUserInteraction_Answers(t *testing.T) {
+	u := NewScriptedUserInteraction(nopLogger{})
+	require.NoError(t, u.Load("task-1", entity.InteractionScript{
+		Answers: []entity.ScriptedAnswer{
+			{Match: "which city", Answer: "Berlin"},
+			{Match: "log in", Answer: "done"},
+		},
+		Approvals: entity.ApprovalApprove,
+	}))
+	ctx := runctx.WithTaskID(context.Background(), "task-1")
+
+	answer, err := u.AskQuestion(ctx, "Which city should I search?")
+	require.NoError(t, err)
+	assert.Equal(t, "Berlin", answer)
+
+	require.NoError(t, u.WaitForUserAction(ctx, "Please log in to continue"))
+
+	approved, err := u.RequestApproval(ctx, entity.ApprovalRequest{ToolName: "click"})
+	require.NoError(t, err)
+	assert.True(t, approved)
+
+	_, err = u.Checkpoint(ctx)
+	assert.NoError(t, err)
+}
+
+func TestScriptedUserInteraction_FailsFast(t *testing.T) {
+	u := NewScriptedUserInteraction(nopLogger{})
+	require.NoError(t, u.Load("task-1", entity.InteractionScript{}))
+	ctx := runctx.WithTaskID(context.Background(), "task-1")
+
+	approved, err := u.RequestApproval(ctx, entity.ApprovalRequest{ToolName: "click"})
+	require.NoError(t, err)
+	assert.False(t, approved, "approvals are denied by default")
+
+	_, err = u.AskQuestion(ctx, "What is your password?")
+	assert.ErrorIs(t, err, ErrNoScriptedAnswer)
+
+	_, err = u.Checkpoint(ctx)
+	assert.ErrorIs(t, err, output.ErrTaskAborted)
+
+	other := runctx.WithTaskID(context.Background(), "task-2")
+	_, err = u.Checkpoint(other)
+	assert.NoError(t, err)
+}
+
+func TestScriptedUserInteraction_InvalidPattern(t *testing.T) {
+	u := NewScriptedUserInteraction(nopLogger{})
+	err := u.Load("task-1", entity.InteractionScript{
+		Answers: []entity.ScriptedAnswer{{Match: "(", Answer: "x"}},
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,81 @@
+// Package workflowfile stores compiled workflows as JSON files.
+package workflowfile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+
+	"browser-agent/internal/domain/entity"
+)
+
+var variablePattern = regexp.MustCompile(`\{\{var:([^}]+)\}\}`)
+
+var actions = map[entity.WorkflowAction]bool{
+	entity.WorkflowNavigate:   true,
+	entity.WorkflowClick:      true,
+	entity.WorkflowFill:       true,
+	entity.WorkflowPressEnter: true,
+	entity.WorkflowScroll:     true,
+	entity.WorkflowQuery:      true,
+}
+
+func Write(path string, workflow *entity.Workflow) error {
+	data, err := json.MarshalIndent(workflow, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Load reads a workflow and checks that its steps are known actions and
+// that every {{var:name}} placeholder is declared.
+func Load(path string) (*entity.Workflow, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read workflow: %w", err)
+	}
+	var workflow entity.Workflow
+	if err := json.Unmarshal(data, &workflow); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := validate(&workflow); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &workflow, nil
+}
+
+func validate(workflow *entity.Workflow) error {
+	if len(workflow.Steps) == 0 {
+		return errors.New("workflow has no steps")
+	}
+
+	declared := make(map[string]bool, len(workflow.Variables))
+	for _, variable := range workflow.Variables {
+		if variable.Name == "" {
+			return errors.New("variable without a name")
+		}
+		declared[variable.Name] = !variable.Secret
+	}
+
+	var errs []error
+	for i, step := range workflow.Steps {
+		if !actions[step.Action] {
+			errs = append(errs, fmt.Errorf("step %d: unknown action %q", i+1, step.Action))
+		}
+		values := []string{step.URL, step.Text}
+		for _, text := range step.Fields {
+			values = append(values, text)
+		}
+		for _, value := range values {
+			for _, match := range variablePattern.FindAllStringSubmatch(value, -1) {
+				if !declared[match[1]] {
+					errs = append(errs, fmt.Errorf("step %d: undeclared variable %q", i+1, match[1]))
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
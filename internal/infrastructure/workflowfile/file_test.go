@@ -0,0 +1,49 @@
+package workflowfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"browser-agent/internal/domain/entity"
+)
+
+func TestWriteAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "login.json")
+	workflow := &entity.Workflow{
+		Name:      "login",
+		Variables: []entity.WorkflowVariable{{Name: "user", Default: "alice"}},
+		Steps: []entity.WorkflowStep{
+			{Action: entity.WorkflowFill, Selector: "#user", Text: "{{var:user}}"},
+			{Action: entity.WorkflowPressEnter},
+		},
+	}
+
+	require.NoError(t, Write(path, workflow))
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, workflow, loaded)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"name": "bad",
+		"variables": [{"name": "password", "secret": true}],
+		"steps": [
+			{"action": "hover"},
+			{"action": "fill", "selector": "#p", "text": "{{var:password}}"}
+		]
+	}`), 0o644))
+
+	_, err := Load(path)
+	assert.ErrorContains(t, err, `step 1: unknown action "hover"`)
+	assert.ErrorContains(t, err, `step 2: undeclared variable "password"`)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"name": "empty", "steps": []}`), 0o644))
+	_, err = Load(path)
+	assert.ErrorContains(t, err, "no steps")
+}
@@ -0,0 +1,857 @@
+package testkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/domain/entity"
+)
+
+var _ output.BrowserPort = (*Browser)(nil)
+
+// fillableTags are the elements Fill accepts.
+var fillableTags = map[string]bool{"input": true, "textarea": true, "select": true}
+
+// Browser is an in-memory BrowserPort showing the pages of a Site. Actions
+// lists what was done to it, e.g. "navigate https://shop.test/",
+// "click #buy", "fill #q=kettle", "press_enter", "scroll down",
+// `wait element ".list" visible`, "dialog accept".
+type Browser struct {
+	mu      sync.Mutex
+	site    *Site
+	page    string
+	values  map[string]string
+	focused string
+	actions []string
+	network []entity.NetworkEntry
+	rules   []entity.InterceptRule
+	console []entity.ConsoleMessage
+	ruleID  int
+	closed  bool
+
+	dialogPolicy entity.DialogPolicy
+	dialogs      []entity.Dialog
+	// dialogElement is the clicked element whose dialog is open.
+	dialogElement Element
+
+	emulation entity.Emulation
+}
+
+func NewBrowser(site *Site) *Browser {
+	b := &Browser{site: site, dialogPolicy: entity.DialogPolicyAgent}
+	b.show(site.Start)
+	return b
+}
+
+// Actions returns the actions so far.
+func (b *Browser) Actions() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string(nil), b.actions...)
+}
+
+// Page returns the name of the page shown.
+func (b *Browser) Page() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.page
+}
+
+// Value returns what was filled into selector on the current page.
+func (b *Browser) Value(selector string) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.values[selector]
+}
+
+// SetDialogPolicy decides what happens to the dialogs opened later; the
+// default leaves them open for HandleDialog.
+func (b *Browser) SetDialogPolicy(policy entity.DialogPolicy) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.dialogPolicy = policy
+}
+
+func (b *Browser) Closed() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.closed
+}
+
+// IsReady reports whether the browser is still open, like the health check
+// of the real adapter.
+func (b *Browser) IsReady() bool {
+	return !b.Closed()
+}
+
+func (b *Browser) Navigate(_ context.Context, url string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.dialogErrorLocked(); err != nil {
+		return err
+	}
+	b.actions = append(b.actions, "navigate "+url)
+	return b.navigateLocked(url)
+}
+
+func (b *Browser) navigateLocked(url string) error {
+	for name, page := range b.site.Pages {
+		if page.url(name) == url {
+			b.show(name)
+			return nil
+		}
+	}
+	return fmt.Errorf("navigate %s: net::ERR_NAME_NOT_RESOLVED", url)
+}
+
+// show switches to page; filled values and focus belong to the old page,
+// while the network log keeps growing as in a real tab.
+func (b *Browser) show(page string) {
+	b.page = page
+	b.values = make(map[string]string)
+	b.focused = ""
+	for _, req := range b.site.Pages[page].Requests {
+		b.network = append(b.network, b.intercept(req.entry(len(b.network)+1)))
+	}
+	for _, msg := range b.site.Pages[page].Console {
+		level := msg.Level
+		if level == "" {
+			level = entity.ConsoleInfo
+		}
+		b.console = append(b.console, entity.ConsoleMessage{
+			ID:      len(b.console) + 1,
+			Level:   level,
+			Text:    msg.Text,
+			Source:  msg.Source,
+			PageURL: b.currentURLLocked(),
+			Time:    time.Now(),
+		})
+	}
+}
+
+// intercept applies the first matching block or mock rule to entry. Header
+// rules have nothing to act on in the fake.
+func (b *Browser) intercept(entry entity.NetworkEntry) entity.NetworkEntry {
+	for _, rule := range b.rules {
+		if !rule.MatchesType(entry.ResourceType) || !regexp.MustCompile(rule.URLPattern).MatchString(entry.URL) {
+			continue
+		}
+		switch rule.Action {
+		case entity.InterceptBlock:
+			entry.Status, entry.MIMEType, entry.ResponseBody, entry.BodySize = 0, "", "", 0
+			entry.Error = "net::ERR_BLOCKED_BY_CLIENT"
+			return entry
+		case entity.InterceptMock:
+			entry.Status, entry.MIMEType, entry.Error = rule.MockStatus(), rule.ContentType, ""
+			entry.ResponseBody, entry.BodySize = rule.Body, len(rule.Body)
+			return entry
+		}
+	}
+	return entry
+}
+
+func (b *Browser) current() (Page, error) {
+	if err := b.dialogErrorLocked(); err != nil {
+		return Page{}, err
+	}
+	if b.page == "" {
+		return Page{}, fmt.Errorf("no page is open")
+	}
+	return b.site.Pages[b.page], nil
+}
+
+func (b *Browser) element(selector string) (Element, error) {
+	page, err := b.current()
+	if err != nil {
+		return Element{}, err
+	}
+	element, ok := page.find(selector)
+	if !ok {
+		return Element{}, fmt.Errorf("element not found: %s", selector)
+	}
+	return element, nil
+}
+
+func (b *Browser) Click(_ context.Context, selector string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.clickLocked(selector)
+}
+
+func (b *Browser) clickLocked(selector string) error {
+	element, err := b.element(selector)
+	if err != nil {
+		return err
+	}
+	b.actions = append(b.actions, "click "+selector)
+	b.focused = selector
+
+	if element.Dialog != nil {
+		return b.openDialogLocked(element)
+	}
+	return b.followLocked(element)
+}
+
+func (b *Browser) followLocked(element Element) error {
+	switch {
+	case element.Goto != "":
+		b.show(element.Goto)
+	case element.Href != "":
+		return b.navigateLocked(element.Href)
+	}
+	return nil
+}
+
+// openDialogLocked opens the dialog of a clicked element and, unless the
+// policy leaves it to HandleDialog, closes it right away.
+func (b *Browser) openDialogLocked(element Element) error {
+	dialog := entity.Dialog{
+		ID:            len(b.dialogs) + 1,
+		Type:          element.Dialog.Type,
+		Message:       element.Dialog.Message,
+		DefaultPrompt: element.Dialog.Default,
+		URL:           b.currentURLLocked(),
+		OpenedAt:      time.Now(),
+		Open:          true,
+	}
+	if dialog.Type == "" {
+		dialog.Type = entity.DialogAlert
+	}
+	b.dialogs = append(b.dialogs, dialog)
+	b.dialogElement = element
+
+	if b.dialogPolicy == entity.DialogPolicyAgent {
+		return &entity.DialogOpenError{Dialog: dialog}
+	}
+	resp := entity.DialogResponse{Accept: b.dialogPolicy == entity.DialogPolicyAccept, PromptText: dialog.DefaultPrompt}
+	_, err := b.closeDialogLocked(resp, true)
+	return err
+}
+
+func (b *Browser) closeDialogLocked(resp entity.DialogResponse, byPolicy bool) (*entity.Dialog, error) {
+	dialog := &b.dialogs[len(b.dialogs)-1]
+	dialog.Open = false
+	dialog.Accepted = resp.Accept
+	dialog.ByPolicy = byPolicy
+	if resp.Accept && dialog.Type == entity.DialogPrompt {
+		dialog.PromptText = resp.PromptText
+	}
+	closed := *dialog
+
+	if closed.Accepted || closed.Type == entity.DialogAlert {
+		return &closed, b.followLocked(b.dialogElement)
+	}
+	return &closed, nil
+}
+
+func (b *Browser) dialogErrorLocked() error {
+	if len(b.dialogs) == 0 || !b.dialogs[len(b.dialogs)-1].Open {
+		return nil
+	}
+	return &entity.DialogOpenError{Dialog: b.dialogs[len(b.dialogs)-1]}
+}
+
+func (b *Browser) ClickWithChanges(_ context.Context, selector string) (*entity.ClickResult, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	before, _ := b.current()
+	beforeURL := b.currentURLLocked()
+	beforeConsole := len(b.console)
+	if err := b.clickLocked(selector); err != nil {
+		if errors.Is(err, entity.ErrDialogOpen) {
+			return nil, err
+		}
+		return &entity.ClickResult{Success: false, Error: err.Error()}, nil
+	}
+	after, _ := b.current()
+
+	changes := &entity.PageChanges{NewURL: b.currentURLLocked()}
+	changes.URLChanged = changes.NewURL != beforeURL
+	changes.ConsoleErrors = entity.ConsoleFilter{MinLevel: entity.ConsoleError, AfterID: beforeConsole}.Apply(b.console)
+	for _, element := range after.Elements {
+		if _, existed := before.find(element.Selector); !existed && !element.Hidden {
+			changes.NewElements = append(changes.NewElements, uiElement(len(changes.NewElements), element))
+		}
+	}
+	for _, element := range before.Elements {
+		if _, exists := after.find(element.Selector); !exists && !element.Hidden {
+			changes.ElementsRemoved++
+		}
+	}
+	return &entity.ClickResult{Success: true, Changes: changes}, nil
+}
+
+func (b *Browser) BatchClick(ctx context.Context, selectors []string) error {
+	for _, selector := range selectors {
+		if err := b.Click(ctx, selector); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *Browser) Fill(_ context.Context, selector, text string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	element, err := b.element(selector)
+	if err != nil {
+		return err
+	}
+	if !fillableTags[element.Tag] {
+		return fmt.Errorf("element %s is not an input", selector)
+	}
+	b.actions = append(b.actions, "fill "+selector+"="+text)
+	b.values[selector] = text
+	b.focused = selector
+	return nil
+}
+
+func (b *Browser) BatchFill(ctx context.Context, fields map[string]string) error {
+	selectors := make([]string, 0, len(fields))
+	for selector := range fields {
+		selectors = append(selectors, selector)
+	}
+	// Sorted, so Actions does not depend on map order.
+	sort.Strings(selectors)
+	for _, selector := range selectors {
+		if err := b.Fill(ctx, selector, fields[selector]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *Browser) PressEnter(_ context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.dialogErrorLocked(); err != nil {
+		return err
+	}
+	b.actions = append(b.actions, "press_enter")
+	if b.focused == "" {
+		return nil
+	}
+	if element, err := b.element(b.focused); err == nil && element.Submit != "" {
+		b.show(element.Submit)
+	}
+	return nil
+}
+
+func (b *Browser) Scroll(_ context.Context, direction string, _ int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.dialogErrorLocked(); err != nil {
+		return err
+	}
+	switch direction {
+	case "up", "down", "top", "bottom":
+	default:
+		return fmt.Errorf("invalid scroll direction: %s", direction)
+	}
+	b.actions = append(b.actions, "scroll "+direction)
+	return nil
+}
+
+func (b *Browser) GetPageContent(_ context.Context) (*entity.PageContent, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	page, err := b.current()
+	if err != nil {
+		return nil, err
+	}
+	return &entity.PageContent{
+		URL:        b.currentURLLocked(),
+		Title:      page.Title,
+		HTML:       renderHTML(page),
+		UIElements: uiElements(page),
+	}, nil
+}
+
+func (b *Browser) GetPageText(_ context.Context) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	page, err := b.current()
+	if err != nil {
+		return "", err
+	}
+	return pageText(page), nil
+}
+
+func (b *Browser) GetUIElements(_ context.Context) ([]entity.UIElement, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	page, err := b.current()
+	if err != nil {
+		return nil, err
+	}
+	return uiElements(page), nil
+}
+
+func (b *Browser) GetPageContext(_ context.Context) (*entity.PageContext, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	page, err := b.current()
+	if err != nil {
+		return nil, err
+	}
+	elements := uiElements(page)
+	return &entity.PageContext{
+		URL:             b.currentURLLocked(),
+		Title:           page.Title,
+		VisibleElements: elements,
+		TextContent:     pageText(page),
+		ElementCount:    len(elements),
+	}, nil
+}
+
+func (b *Browser) GetPageStructure(_ context.Context) (*entity.PageStructure, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	page, err := b.current()
+	if err != nil {
+		return nil, err
+	}
+	structure := &entity.PageStructure{URL: b.currentURLLocked(), Title: page.Title}
+	for _, element := range page.Elements {
+		if element.Hidden {
+			continue
+		}
+		structure.Elements = append(structure.Elements, entity.StructureElement{
+			TagName:    element.Tag,
+			Selector:   element.Selector,
+			ID:         element.Attrs["id"],
+			Text:       element.Text,
+			Attributes: element.Attrs,
+		})
+	}
+	return structure, nil
+}
+
+// Screenshot returns a placeholder image naming the page.
+func (b *Browser) Screenshot(_ context.Context) (*entity.Screenshot, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, err := b.current(); err != nil {
+		return nil, err
+	}
+	return &entity.Screenshot{Data: []byte("screenshot of " + b.page), Format: "png", Width: 1, Height: 1}, nil
+}
+
+// Capture returns a placeholder image naming the page and what was
+// captured of it.
+func (b *Browser) Capture(_ context.Context, req entity.CaptureRequest) (*entity.Screenshot, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, err := b.current(); err != nil {
+		return nil, err
+	}
+	target := b.page
+	if req.Selector != "" {
+		if _, err := b.element(req.Selector); err != nil {
+			return nil, err
+		}
+		target += " " + req.Selector
+	} else if req.FullPage {
+		target += " (full page)"
+	}
+	req = req.WithDefaults()
+	return &entity.Screenshot{Data: []byte("capture of " + target), Format: string(req.Format), Width: 1, Height: 1}, nil
+}
+
+// PrintPDF returns a placeholder document naming the page.
+func (b *Browser) PrintPDF(_ context.Context, req entity.PDFRequest) ([]byte, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, err := b.current(); err != nil {
+		return nil, err
+	}
+	return []byte("%PDF-1.4 " + b.page), nil
+}
+
+// QueryElements supports the "text", "html", "selector" and "attr:name"
+// extractions of the element itself ("_self"); sub-selectors extract "".
+func (b *Browser) QueryElements(_ context.Context, req entity.QueryElementsRequest) (*entity.QueryElementsResult, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	page, err := b.current()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &entity.QueryElementsResult{}
+	for _, element := range page.Elements {
+		if element.Hidden || !element.matches(req.Selector) {
+			continue
+		}
+		if req.Limit > 0 && result.Count == req.Limit {
+			break
+		}
+		data := make(map[string]string, len(req.Extract))
+		for key, kind := range req.Extract {
+			if key == "_self" {
+				data[key] = b.extract(element, kind)
+			} else {
+				data[key] = ""
+			}
+		}
+		result.Elements = append(result.Elements, entity.ElementData{Index: result.Count, Selector: element.Selector, Data: data})
+		result.Count++
+	}
+	return result, nil
+}
+
+func (b *Browser) extract(element Element, kind string) string {
+	switch {
+	case kind == "text" || kind == "html":
+		return element.Text
+	case kind == "selector":
+		return element.Selector
+	case kind == "attr:value":
+		if value, ok := b.values[element.Selector]; ok {
+			return value
+		}
+		return element.Attrs["value"]
+	case strings.HasPrefix(kind, "attr:"):
+		return element.Attrs[strings.TrimPrefix(kind, "attr:")]
+	default:
+		return ""
+	}
+}
+
+// Search supports the text, contains, selector and id search types.
+func (b *Browser) Search(_ context.Context, req entity.SearchRequest) (*entity.SearchResult, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	page, err := b.current()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &entity.SearchResult{Type: req.Type, Query: req.Query}
+	for _, element := range page.Elements {
+		if element.Hidden {
+			continue
+		}
+		var found bool
+		switch req.Type {
+		case "text":
+			found = strings.TrimSpace(element.Text) == req.Query
+		case "contains":
+			found = strings.Contains(strings.ToLower(element.Text), strings.ToLower(req.Query))
+		case "selector":
+			found = element.matches(req.Query)
+		case "id":
+			found = element.Attrs["id"] == req.Query || element.Selector == "#"+req.Query
+		default:
+			return nil, fmt.Errorf("unknown search type: %s", req.Type)
+		}
+		if !found {
+			continue
+		}
+		if req.Limit > 0 && result.Count == req.Limit {
+			break
+		}
+		result.Results = append(result.Results, entity.SearchResultItem{
+			Element:    element.Tag,
+			Text:       element.Text,
+			Selector:   element.Selector,
+			ID:         element.Attrs["id"],
+			Attributes: element.Attrs,
+			Match:      req.Query,
+		})
+		result.Count++
+	}
+	result.Found = result.Count > 0
+	return result, nil
+}
+
+// DescribeElement describes selector, or the element last clicked or
+// filled when selector is empty.
+func (b *Browser) DescribeElement(_ context.Context, selector string) (*entity.ElementInfo, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if selector == "" {
+		selector = b.focused
+	}
+	element, err := b.element(selector)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.ElementInfo{
+		Selector:  element.Selector,
+		TagName:   element.Tag,
+		Text:      element.Text,
+		Role:      element.Attrs["role"],
+		Type:      element.Attrs["type"],
+		AriaLabel: element.Attrs["aria-label"],
+		Href:      element.Href,
+		IsSubmit:  element.Attrs["type"] == "submit" || element.Submit != "",
+	}, nil
+}
+
+// WaitFor checks the condition once: the fake page only changes through
+// actions, so a condition that does not hold now never will. Scripts are not
+// supported and the network is always idle.
+func (b *Browser) WaitFor(_ context.Context, req entity.WaitRequest) (*entity.WaitResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	page, err := b.current()
+	if err != nil {
+		return nil, err
+	}
+
+	var met bool
+	switch req.Condition {
+	case entity.WaitVisible, entity.WaitHidden:
+		_, met = page.find(req.Selector)
+		if req.Condition == entity.WaitHidden {
+			met = !met
+		}
+	case entity.WaitText:
+		met = strings.Contains(pageText(page), req.Text)
+	case entity.WaitURL:
+		met = regexp.MustCompile(req.URLPattern).MatchString(b.currentURLLocked())
+	case entity.WaitNetworkIdle:
+		met = true
+	case entity.WaitScript:
+		return nil, fmt.Errorf("testkit: script waits are not supported")
+	}
+
+	b.actions = append(b.actions, "wait "+req.Describe())
+	result := &entity.WaitResult{Met: met, URL: b.currentURLLocked()}
+	if !met {
+		result.ElapsedMS = int64(req.TimeoutMS)
+	}
+	return result, nil
+}
+
+func (b *Browser) NetworkRequests(_ context.Context, filter entity.NetworkFilter) ([]entity.NetworkEntry, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	entries := filter.Apply(b.network)
+	for i := range entries {
+		entries[i].RequestBody = ""
+		entries[i].ResponseBody = ""
+	}
+	return entries, nil
+}
+
+func (b *Browser) NetworkRequest(_ context.Context, id string) (*entity.NetworkEntry, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, entry := range b.network {
+		if entry.ID == id {
+			return &entry, nil
+		}
+	}
+	return nil, fmt.Errorf("network request %q not found", id)
+}
+
+func (b *Browser) ConsoleMessages(_ context.Context, filter entity.ConsoleFilter) ([]entity.ConsoleMessage, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return filter.Apply(b.console), nil
+}
+
+// AddInterceptRule applies rule to the requests of the pages shown from now
+// on, as listed by NetworkRequests.
+func (b *Browser) Dialogs(_ context.Context) ([]entity.Dialog, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]entity.Dialog(nil), b.dialogs...), nil
+}
+
+func (b *Browser) HandleDialog(_ context.Context, resp entity.DialogResponse) (*entity.Dialog, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.dialogErrorLocked() == nil {
+		return nil, entity.ErrNoDialog
+	}
+	action := "dialog dismiss"
+	if resp.Accept {
+		action = strings.TrimSpace("dialog accept " + resp.PromptText)
+	}
+	b.actions = append(b.actions, action)
+	return b.closeDialogLocked(resp, false)
+}
+
+// Emulation returns the emulation settings in effect.
+func (b *Browser) Emulation() entity.Emulation {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.emulation
+}
+
+func (b *Browser) Emulate(_ context.Context, e entity.Emulation) (*entity.Emulation, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	next := b.emulation.Merge(e)
+	if err := next.Validate(); err != nil {
+		return nil, err
+	}
+	b.actions = append(b.actions, "emulate "+next.String())
+	b.emulation = next
+	return &next, nil
+}
+
+func (b *Browser) ResetEmulation(_ context.Context) (*entity.Emulation, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.actions = append(b.actions, "emulate reset")
+	b.emulation = entity.Emulation{}
+	return &b.emulation, nil
+}
+
+func (b *Browser) AddInterceptRule(_ context.Context, rule entity.InterceptRule) (*entity.InterceptRule, error) {
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.ruleID++
+	rule.ID = strconv.Itoa(b.ruleID)
+	b.rules = append(b.rules, rule)
+	return &rule, nil
+}
+
+func (b *Browser) RemoveInterceptRule(_ context.Context, id string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for i, rule := range b.rules {
+		if rule.ID == id {
+			b.rules = append(b.rules[:i:i], b.rules[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("intercept rule %q not found", id)
+}
+
+func (b *Browser) InterceptRules(_ context.Context) ([]entity.InterceptRule, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]entity.InterceptRule(nil), b.rules...), nil
+}
+
+func (b *Browser) CurrentURL() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.currentURLLocked()
+}
+
+func (b *Browser) currentURLLocked() string {
+	if b.page == "" {
+		return "about:blank"
+	}
+	return b.site.Pages[b.page].url(b.page)
+}
+
+func (b *Browser) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+}
+
+func uiElements(page Page) []entity.UIElement {
+	var elements []entity.UIElement
+	for _, element := range page.Elements {
+		if !element.Hidden {
+			elements = append(elements, uiElement(len(elements), element))
+		}
+	}
+	return elements
+}
+
+func uiElement(index int, element Element) entity.UIElement {
+	return entity.UIElement{
+		ID:        strconv.Itoa(index + 1),
+		Type:      element.Tag,
+		Text:      element.Text,
+		AriaLabel: element.Attrs["aria-label"],
+		Role:      element.Attrs["role"],
+		Selector:  element.Selector,
+	}
+}
+
+func pageText(page Page) string {
+	parts := []string{page.Text}
+	for _, element := range page.Elements {
+		if !element.Hidden && element.Text != "" {
+			parts = append(parts, element.Text)
+		}
+	}
+	return strings.TrimSpace(strings.Join(parts, "\n"))
+}
+
+func renderHTML(page Page) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<html><head><title>%s</title></head><body>", html.EscapeString(page.Title))
+	if page.Text != "" {
+		fmt.Fprintf(&b, "<p>%s</p>", html.EscapeString(page.Text))
+	}
+	for _, element := range page.Elements {
+		if element.Hidden {
+			continue
+		}
+		tag := element.Tag
+		if tag == "" {
+			tag = "div"
+		}
+		fmt.Fprintf(&b, "<%s data-selector=%q>%s</%s>", tag, element.Selector, html.EscapeString(element.Text), tag)
+	}
+	b.WriteString("</body></html>")
+	return b.String()
+}
@@ -0,0 +1,153 @@
+package testkit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"browser-agent/internal/domain/entity"
+)
+
+const shop = `
+start: search
+pages:
+  search:
+    url: https://shop.test/
+    title: Shop
+    text: Find anything
+    elements:
+      - {selector: "#q", tag: input, submit: results}
+      - {selector: "#help", tag: button, text: Help, goto: help}
+  help:
+    url: https://shop.test/
+    title: Shop
+    elements:
+      - {selector: "#close", tag: button, text: Close, goto: search}
+      - {selector: "#faq", tag: div, text: Returns are free}
+  results:
+    url: https://shop.test/?q=kettle
+    title: Results
+    elements:
+      - {selector: ".item:nth-child(1)", match: [.item], tag: a, text: Kettle, href: https://shop.test/kettle, attrs: {data-price: "30"}}
+      - {selector: ".item:nth-child(2)", match: [.item], tag: a, text: Teapot, attrs: {data-price: "20"}}
+      - {selector: ".item:nth-child(3)", match: [.item], tag: a, text: Sold out, hidden: true}
+  kettle:
+    url: https://shop.test/kettle
+    title: Kettle
+    text: A kettle for 30 EUR
+`
+
+func TestParseSite(t *testing.T) {
+	_, err := ParseSite("pages: {a: {elements: [{tag: div}]}}")
+	assert.ErrorContains(t, err, "without a selector")
+
+	_, err = ParseSite("pages: {a: {elements: [{selector: '#x', goto: b}]}}")
+	assert.ErrorContains(t, err, `undefined page "b"`)
+
+	_, err = ParseSite("start: b\npages: {a: {}}")
+	assert.ErrorContains(t, err, `start page "b"`)
+
+	_, err = ParseSite("pages: {a: {colour: red}}")
+	assert.Error(t, err)
+}
+
+func TestBrowserFollowsStates(t *testing.T) {
+	ctx := context.Background()
+	b := NewBrowser(MustParseSite(shop))
+	assert.Equal(t, "https://shop.test/", b.CurrentURL())
+
+	result, err := b.ClickWithChanges(ctx, "#help")
+	require.NoError(t, err)
+	require.True(t, result.Success)
+	assert.False(t, result.Changes.URLChanged)
+	assert.Equal(t, 2, result.Changes.ElementsRemoved)
+	require.Len(t, result.Changes.NewElements, 2)
+	assert.Equal(t, "#close", result.Changes.NewElements[0].Selector)
+	assert.Equal(t, "help", b.Page())
+
+	require.NoError(t, b.Click(ctx, "#close"))
+	require.NoError(t, b.Fill(ctx, "#q", "kettle"))
+	assert.Equal(t, "kettle", b.Value("#q"))
+	require.NoError(t, b.PressEnter(ctx))
+	assert.Equal(t, "https://shop.test/?q=kettle", b.CurrentURL())
+	assert.Empty(t, b.Value("#q"))
+
+	require.NoError(t, b.Click(ctx, ".item:nth-child(1)"))
+	text, err := b.GetPageText(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "A kettle for 30 EUR", text)
+
+	assert.Equal(t, []string{
+		"click #help", "click #close", "fill #q=kettle", "press_enter", "click .item:nth-child(1)",
+	}, b.Actions())
+}
+
+func TestBrowserErrors(t *testing.T) {
+	ctx := context.Background()
+	b := NewBrowser(MustParseSite(shop))
+
+	assert.EqualError(t, b.Click(ctx, "#missing"), "element not found: #missing")
+	assert.ErrorContains(t, b.Fill(ctx, "#help", "x"), "not an input")
+	assert.Error(t, b.Navigate(ctx, "https://elsewhere.test/"))
+
+	result, err := b.ClickWithChanges(ctx, "#missing")
+	require.NoError(t, err)
+	assert.False(t, result.Success)
+
+	empty := NewBrowser(MustParseSite("pages: {a: {}}"))
+	assert.Equal(t, "about:blank", empty.CurrentURL())
+	_, err = empty.GetPageText(ctx)
+	assert.Error(t, err)
+	require.NoError(t, empty.Navigate(ctx, "a"))
+	assert.Equal(t, "a", empty.CurrentURL())
+}
+
+func TestBrowserQueryAndSearch(t *testing.T) {
+	ctx := context.Background()
+	b := NewBrowser(MustParseSite(shop))
+	require.NoError(t, b.Navigate(ctx, "https://shop.test/?q=kettle"))
+
+	result, err := b.QueryElements(ctx, entity.QueryElementsRequest{
+		Selector: ".item",
+		Extract:  map[string]string{"name": "_self", "_self": "text", "price": "attr:data-price"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, result.Count)
+	assert.Equal(t, "Kettle", result.Elements[0].Data["_self"])
+	assert.Equal(t, "Teapot", result.Elements[1].Data["_self"])
+
+	limited, err := b.QueryElements(ctx, entity.QueryElementsRequest{Selector: ".item", Extract: map[string]string{"_self": "attr:data-price"}, Limit: 1})
+	require.NoError(t, err)
+	require.Equal(t, 1, limited.Count)
+	assert.Equal(t, "30", limited.Elements[0].Data["_self"])
+
+	found, err := b.Search(ctx, entity.SearchRequest{Type: "contains", Query: "tea"})
+	require.NoError(t, err)
+	require.True(t, found.Found)
+	assert.Equal(t, ".item:nth-child(2)", found.Results[0].Selector)
+
+	missing, err := b.Search(ctx, entity.SearchRequest{Type: "text", Query: "Sold out"})
+	require.NoError(t, err)
+	assert.False(t, missing.Found)
+}
+
+func TestBrowserWaitFor(t *testing.T) {
+	ctx := context.Background()
+	b := NewBrowser(MustParseSite(shop))
+	require.NoError(t, b.Navigate(ctx, "https://shop.test/?q=kettle"))
+
+	result, err := b.WaitFor(ctx, entity.WaitRequest{Condition: entity.WaitHidden, Selector: ".item:nth-child(3)"})
+	require.NoError(t, err)
+	assert.True(t, result.Met)
+
+	result, err = b.WaitFor(ctx, entity.WaitRequest{Condition: entity.WaitText, Text: "Sold out", TimeoutMS: 500})
+	require.NoError(t, err)
+	assert.False(t, result.Met)
+	assert.EqualValues(t, 500, result.ElapsedMS)
+
+	_, err = b.WaitFor(ctx, entity.WaitRequest{Condition: entity.WaitScript, Script: "true"})
+	assert.Error(t, err)
+	assert.Equal(t, `wait text "Sold out" present`, b.Actions()[2])
+}
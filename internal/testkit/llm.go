@@ -0,0 +1,159 @@
+package testkit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/application/runctx"
+	"browser-agent/internal/domain/entity"
+)
+
+var _ output.LLMPort = (*ScriptedLLM)(nil)
+
+// Orchestrator is the agent name of calls made outside any sub-agent.
+const Orchestrator = "orchestrator"
+
+// Reply is one scripted LLM response.
+type Reply struct {
+	Message entity.Message
+	Usage   entity.Usage
+	Err     error
+}
+
+// Answer is a reply without tool calls: the agent's final answer.
+func Answer(text string) Reply {
+	return Reply{Message: entity.Message{Role: entity.RoleAssistant, Content: text}}
+}
+
+// CallTool is a reply calling a single tool. args is marshalled to JSON
+// unless it already is a string.
+func CallTool(name entity.ToolName, args any) Reply {
+	return CallTools(Call(name, args))
+}
+
+// CallTools is a reply calling several tools at once.
+func CallTools(calls ...entity.ToolCall) Reply {
+	return Reply{Message: entity.Message{Role: entity.RoleAssistant, ToolCalls: calls}}
+}
+
+// Call builds a tool call for CallTools; the ID is filled in when replied.
+func Call(name entity.ToolName, args any) entity.ToolCall {
+	arguments, ok := args.(string)
+	if !ok {
+		data, err := json.Marshal(args)
+		if err != nil {
+			panic(fmt.Sprintf("testkit: marshal %s arguments: %v", name, err))
+		}
+		arguments = string(data)
+	}
+	return entity.ToolCall{Name: string(name), Arguments: arguments}
+}
+
+// Fail is a reply failing the LLM request.
+func Fail(err error) Reply {
+	return Reply{Err: err}
+}
+
+// LLMCall is a request the ScriptedLLM received.
+type LLMCall struct {
+	Agent   string
+	Request output.ChatRequest
+}
+
+// ScriptedLLM answers each agent from its own queue of replies. The agent of
+// a request is the innermost runctx agent, or Orchestrator. A request to an
+// agent whose queue is empty fails.
+type ScriptedLLM struct {
+	mu     sync.Mutex
+	queues map[string][]Reply
+	calls  []LLMCall
+	lastID int
+}
+
+func NewScriptedLLM() *ScriptedLLM {
+	return &ScriptedLLM{queues: make(map[string][]Reply)}
+}
+
+// Script queues replies for agent, after any already queued.
+func (l *ScriptedLLM) Script(agent string, replies ...Reply) *ScriptedLLM {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.queues[agent] = append(l.queues[agent], replies...)
+	return l
+}
+
+func (l *ScriptedLLM) Chat(ctx context.Context, req output.ChatRequest) (*output.ChatResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	agent := Orchestrator
+	if agents := runctx.Agents(ctx); len(agents) > 0 {
+		agent = agents[len(agents)-1]
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Agents append to their message slice; keep our own copy of it.
+	req.Messages = append([]entity.Message(nil), req.Messages...)
+	l.calls = append(l.calls, LLMCall{Agent: agent, Request: req})
+
+	queue := l.queues[agent]
+	if len(queue) == 0 {
+		return nil, fmt.Errorf("testkit: no scripted reply left for %s", agent)
+	}
+	reply := queue[0]
+	l.queues[agent] = queue[1:]
+	if reply.Err != nil {
+		return nil, reply.Err
+	}
+
+	message := reply.Message
+	message.ToolCalls = append([]entity.ToolCall(nil), message.ToolCalls...)
+	for i := range message.ToolCalls {
+		if message.ToolCalls[i].ID == "" {
+			l.lastID++
+			message.ToolCalls[i].ID = fmt.Sprintf("call_%d", l.lastID)
+		}
+	}
+	return &output.ChatResponse{Message: message, Usage: reply.Usage}, nil
+}
+
+// Calls returns the requests received so far.
+func (l *ScriptedLLM) Calls() []LLMCall {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]LLMCall(nil), l.calls...)
+}
+
+// CallsBy returns the requests made by agent.
+func (l *ScriptedLLM) CallsBy(agent string) []LLMCall {
+	var calls []LLMCall
+	for _, call := range l.Calls() {
+		if call.Agent == agent {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// AssertExhausted fails t if any scripted reply was not requested.
+func (l *ScriptedLLM) AssertExhausted(t testing.TB) bool {
+	t.Helper()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	ok := true
+	for agent, queue := range l.queues {
+		if len(queue) > 0 {
+			t.Errorf("testkit: %d scripted replies left for %s", len(queue), agent)
+			ok = false
+		}
+	}
+	return ok
+}
@@ -0,0 +1,88 @@
+package testkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/application/runctx"
+	"browser-agent/internal/application/service"
+	"browser-agent/internal/domain/entity"
+)
+
+func TestScriptedLLMQueuesPerAgent(t *testing.T) {
+	ctx := context.Background()
+	nav := runctx.WithAgent(ctx, "navigation")
+	llm := NewScriptedLLM().
+		Script(Orchestrator, CallTool(entity.ToolRunAgent, map[string]string{"agent_type": "navigation"}), Answer("done")).
+		Script("navigation", Fail(errors.New("rate limited")))
+
+	resp, err := llm.Chat(ctx, output.ChatRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.Message.ToolCalls, 1)
+	assert.Equal(t, "call_1", resp.Message.ToolCalls[0].ID)
+	assert.JSONEq(t, `{"agent_type":"navigation"}`, resp.Message.ToolCalls[0].Arguments)
+
+	_, err = llm.Chat(nav, output.ChatRequest{})
+	assert.EqualError(t, err, "rate limited")
+	_, err = llm.Chat(nav, output.ChatRequest{})
+	assert.ErrorContains(t, err, "no scripted reply left for navigation")
+
+	resp, err = llm.Chat(ctx, output.ChatRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "done", resp.Message.Content)
+
+	assert.Len(t, llm.CallsBy("navigation"), 2)
+	assert.Len(t, llm.Calls(), 4)
+	llm.AssertExhausted(t)
+}
+
+func TestScriptedLLMReportsUnusedReplies(t *testing.T) {
+	llm := NewScriptedLLM().Script(Orchestrator, Answer("never asked"))
+	inner := &testing.T{}
+	assert.False(t, llm.AssertExhausted(inner))
+	assert.True(t, inner.Failed())
+}
+
+type echoTool struct{ name entity.ToolName }
+
+func (e echoTool) Name() entity.ToolName              { return e.name }
+func (e echoTool) Description() string                { return "" }
+func (e echoTool) Parameters() map[string]interface{} { return nil }
+func (e echoTool) Execute(_ context.Context, args string) (string, error) {
+	if args == "fail" {
+		return "", errors.New("failed")
+	}
+	return "echo " + args, nil
+}
+
+func TestToolRecorder(t *testing.T) {
+	registry := service.NewToolRegistry()
+	registry.Register(echoTool{entity.ToolBrowserClick})
+	registry.Register(echoTool{entity.ToolBrowserFill})
+	recorder := NewToolRecorder(registry)
+
+	ctx := runctx.WithAgent(runctx.WithAgent(context.Background(), "navigation"), "form")
+	click, _ := recorder.Get(entity.ToolBrowserClick)
+	fill, _ := recorder.Get(entity.ToolBrowserFill)
+	_, _ = click.Execute(context.Background(), `{"selector":"#a"}`)
+	_, _ = fill.Execute(ctx, "fail")
+	_, _ = click.Execute(ctx, `{ "selector": "#b" }`)
+
+	calls := recorder.Calls()
+	AssertToolSequence(t, calls, entity.ToolBrowserClick, entity.ToolBrowserFill, entity.ToolBrowserClick)
+	AssertToolSubsequence(t, calls, entity.ToolBrowserClick, entity.ToolBrowserClick)
+	AssertArguments(t, calls[2], map[string]string{"selector": "#b"})
+	assert.Equal(t, Orchestrator, calls[0].Agent)
+	assert.Equal(t, "navigation/form", calls[1].Agent)
+	assert.EqualError(t, calls[1].Err, "failed")
+	assert.Equal(t, `echo {"selector":"#a"}`, calls[0].Result)
+
+	inner := &testing.T{}
+	assert.False(t, AssertToolSubsequence(inner, calls, entity.ToolBrowserFill, entity.ToolBrowserFill))
+	assert.True(t, inner.Failed())
+}
@@ -0,0 +1,203 @@
+// Package testkit provides in-memory stand-ins for the browser, the LLM and
+// the user, so agent loops can be tested without Chrome or an API key.
+//
+// A Site describes the pages the fake Browser shows. Each page is a state;
+// clicking a link or a button with goto, or pressing Enter in a field with
+// submit, moves the browser to another state. Sites can be written in Go or
+// parsed from YAML:
+//
+//	start: search
+//	pages:
+//	  search:
+//	    url: https://shop.test/
+//	    title: Shop
+//	    elements:
+//	      - {selector: "#q", tag: input, submit: results}
+//	  results:
+//	    url: https://shop.test/?q=kettle
+//	    elements:
+//	      - {selector: ".item:nth-child(1)", tag: a, text: Kettle, match: [.item], href: https://shop.test/kettle}
+package testkit
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"browser-agent/internal/domain/entity"
+)
+
+type Site struct {
+	// Start is the page shown before the first navigation. Optional.
+	Start string          `yaml:"start"`
+	Pages map[string]Page `yaml:"pages"`
+}
+
+type Page struct {
+	// URL defaults to the page name. Several states may share a URL, e.g.
+	// a page before and after a modal opens.
+	URL      string    `yaml:"url"`
+	Title    string    `yaml:"title"`
+	Text     string    `yaml:"text"`
+	Elements []Element `yaml:"elements"`
+	// Requests are added to the network log and Console to the console log
+	// each time the page is shown.
+	Requests []Request `yaml:"requests"`
+	Console  []Console `yaml:"console"`
+}
+
+// Console is a console message of a page.
+type Console struct {
+	// Level defaults to info; error and exception count as errors.
+	Level  entity.ConsoleLevel `yaml:"level"`
+	Text   string              `yaml:"text"`
+	Source string              `yaml:"source"`
+}
+
+// Request is a captured request of a page, as listed by NetworkRequests.
+type Request struct {
+	// Method defaults to GET, Type to xhr and Status to 200.
+	Method string `yaml:"method"`
+	URL    string `yaml:"url"`
+	Type   string `yaml:"type"`
+	Status int    `yaml:"status"`
+	MIME   string `yaml:"mime"`
+	Body   string `yaml:"body"`
+	// Error marks a failed request, e.g. net::ERR_BLOCKED_BY_CLIENT.
+	Error string `yaml:"error"`
+}
+
+func (r Request) entry(id int) entity.NetworkEntry {
+	entry := entity.NetworkEntry{
+		ID:           strconv.Itoa(id),
+		Method:       r.Method,
+		URL:          r.URL,
+		ResourceType: r.Type,
+		StartedAt:    time.Now(),
+		Status:       r.Status,
+		MIMEType:     r.MIME,
+		Finished:     true,
+		Error:        r.Error,
+		BodySize:     len(r.Body),
+		ResponseBody: r.Body,
+	}
+	if entry.Method == "" {
+		entry.Method = "GET"
+	}
+	if entry.ResourceType == "" {
+		entry.ResourceType = "xhr"
+	}
+	if entry.Status == 0 && entry.Error == "" {
+		entry.Status = 200
+	}
+	return entry
+}
+
+type Element struct {
+	// Selector identifies the element and is what the agent sees.
+	Selector string `yaml:"selector"`
+	// Match lists further selectors the element matches in QueryElements
+	// and Search, e.g. a class shared with its siblings.
+	Match []string          `yaml:"match"`
+	Tag   string            `yaml:"tag"`
+	Text  string            `yaml:"text"`
+	Attrs map[string]string `yaml:"attrs"`
+	// Href is navigated to when the element is clicked.
+	Href string `yaml:"href"`
+	// Goto names the page shown after a click, without a navigation.
+	Goto string `yaml:"goto"`
+	// Submit names the page shown when Enter is pressed in this field.
+	Submit string `yaml:"submit"`
+	Hidden bool   `yaml:"hidden"`
+	// Dialog opens when the element is clicked; Goto and Href are then
+	// followed only once it is accepted, or closed in case of an alert.
+	Dialog *Dialog `yaml:"dialog"`
+}
+
+// Dialog is a native JavaScript dialog.
+type Dialog struct {
+	// Type defaults to alert.
+	Type    entity.DialogType `yaml:"type"`
+	Message string            `yaml:"message"`
+	Default string            `yaml:"default"`
+}
+
+// ParseSite reads a site description in YAML.
+func ParseSite(data string) (*Site, error) {
+	var site Site
+	decoder := yaml.NewDecoder(bytes.NewReader([]byte(data)))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&site); err != nil {
+		return nil, fmt.Errorf("parse site: %w", err)
+	}
+	if err := site.validate(); err != nil {
+		return nil, err
+	}
+	return &site, nil
+}
+
+// MustParseSite is ParseSite for site descriptions written in tests.
+func MustParseSite(data string) *Site {
+	site, err := ParseSite(data)
+	if err != nil {
+		panic(err)
+	}
+	return site
+}
+
+func (s *Site) validate() error {
+	if len(s.Pages) == 0 {
+		return fmt.Errorf("site has no pages")
+	}
+	if s.Start != "" {
+		if _, ok := s.Pages[s.Start]; !ok {
+			return fmt.Errorf("start page %q is not defined", s.Start)
+		}
+	}
+	for name, page := range s.Pages {
+		for _, element := range page.Elements {
+			if element.Selector == "" {
+				return fmt.Errorf("page %q: element without a selector", name)
+			}
+			for _, target := range []string{element.Goto, element.Submit} {
+				if _, ok := s.Pages[target]; target != "" && !ok {
+					return fmt.Errorf("page %q: element %s leads to undefined page %q", name, element.Selector, target)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (p Page) url(name string) string {
+	if p.URL != "" {
+		return p.URL
+	}
+	return name
+}
+
+// find returns the visible element with the given selector.
+func (p Page) find(selector string) (Element, bool) {
+	for _, element := range p.Elements {
+		if element.Selector == selector && !element.Hidden {
+			return element, true
+		}
+	}
+	return Element{}, false
+}
+
+// matches reports whether the element is selected by selector.
+func (e Element) matches(selector string) bool {
+	if e.Selector == selector {
+		return true
+	}
+	for _, match := range e.Match {
+		if match == selector {
+			return true
+		}
+	}
+	return false
+}
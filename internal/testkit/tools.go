@@ -0,0 +1,135 @@
+package testkit
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/application/runctx"
+	"browser-agent/internal/domain/entity"
+)
+
+var _ output.ToolRegistry = (*ToolRecorder)(nil)
+
+// ToolCallRecord is a tool execution seen by a ToolRecorder.
+type ToolCallRecord struct {
+	// Agent is the runctx agent chain joined with "/", or Orchestrator.
+	Agent     string
+	Name      entity.ToolName
+	Arguments string
+	Result    string
+	Err       error
+}
+
+// ToolRecorder wraps a ToolRegistry and records every tool executed through
+// it, in order.
+type ToolRecorder struct {
+	inner output.ToolRegistry
+
+	mu    sync.Mutex
+	calls []ToolCallRecord
+}
+
+func NewToolRecorder(inner output.ToolRegistry) *ToolRecorder {
+	return &ToolRecorder{inner: inner}
+}
+
+func (r *ToolRecorder) Register(tool output.ToolPort) {
+	r.inner.Register(tool)
+}
+
+func (r *ToolRecorder) Get(name entity.ToolName) (output.ToolPort, bool) {
+	tool, ok := r.inner.Get(name)
+	if !ok {
+		return nil, false
+	}
+	return &recordedTool{ToolPort: tool, recorder: r}, true
+}
+
+func (r *ToolRecorder) All() []output.ToolPort {
+	tools := r.inner.All()
+	wrapped := make([]output.ToolPort, len(tools))
+	for i, tool := range tools {
+		wrapped[i] = &recordedTool{ToolPort: tool, recorder: r}
+	}
+	return wrapped
+}
+
+func (r *ToolRecorder) Definitions() []entity.ToolDefinition {
+	return r.inner.Definitions()
+}
+
+// Calls returns the tool executions so far.
+func (r *ToolRecorder) Calls() []ToolCallRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]ToolCallRecord(nil), r.calls...)
+}
+
+func (r *ToolRecorder) record(call ToolCallRecord) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, call)
+}
+
+type recordedTool struct {
+	output.ToolPort
+	recorder *ToolRecorder
+}
+
+func (t *recordedTool) Execute(ctx context.Context, arguments string) (string, error) {
+	result, err := t.ToolPort.Execute(ctx, arguments)
+	agent := Orchestrator
+	if agents := runctx.Agents(ctx); len(agents) > 0 {
+		agent = strings.Join(agents, "/")
+	}
+	t.recorder.record(ToolCallRecord{Agent: agent, Name: t.Name(), Arguments: arguments, Result: result, Err: err})
+	return result, err
+}
+
+// ToolNames lists the names of calls, in order.
+func ToolNames(calls []ToolCallRecord) []entity.ToolName {
+	names := make([]entity.ToolName, len(calls))
+	for i, call := range calls {
+		names[i] = call.Name
+	}
+	return names
+}
+
+// AssertToolSequence checks that exactly the named tools were called, in
+// this order.
+func AssertToolSequence(t testing.TB, calls []ToolCallRecord, names ...entity.ToolName) bool {
+	t.Helper()
+	if names == nil {
+		names = []entity.ToolName{}
+	}
+	return assert.Equal(t, names, ToolNames(calls), "tool call sequence")
+}
+
+// AssertToolSubsequence checks that the named tools were called in this
+// order, possibly with other calls in between.
+func AssertToolSubsequence(t testing.TB, calls []ToolCallRecord, names ...entity.ToolName) bool {
+	t.Helper()
+	next := 0
+	for _, call := range calls {
+		if next < len(names) && call.Name == names[next] {
+			next++
+		}
+	}
+	if next < len(names) {
+		return assert.Fail(t, "tool call subsequence",
+			"%s not called in order after %v\ncalls: %v", names[next], names[:next], ToolNames(calls))
+	}
+	return true
+}
+
+// AssertArguments checks the JSON arguments of a call, ignoring formatting
+// and key order. args is marshalled like in Call.
+func AssertArguments(t testing.TB, call ToolCallRecord, args any) bool {
+	t.Helper()
+	return assert.JSONEq(t, Call(call.Name, args).Arguments, call.Arguments, "arguments of %s", call.Name)
+}
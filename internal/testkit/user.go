@@ -0,0 +1,113 @@
+package testkit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/domain/entity"
+)
+
+var (
+	_ output.UserInteractionPort = (*User)(nil)
+	_ output.LoggerPort          = NopLogger{}
+)
+
+// User is a silent UserInteractionPort. Questions are answered from Answers
+// in order, approvals are granted when Approve is set, checkpoints return
+// Guidance in order, and everything asked is recorded.
+type User struct {
+	Answers  []string
+	Approve  bool
+	Guidance []string
+
+	mu        sync.Mutex
+	questions []string
+	approvals []entity.ApprovalRequest
+	shown     []string
+}
+
+func (u *User) AskQuestion(_ context.Context, question string) (string, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.questions = append(u.questions, question)
+	if len(u.Answers) == 0 {
+		return "", fmt.Errorf("testkit: no answer left for %q", question)
+	}
+	answer := u.Answers[0]
+	u.Answers = u.Answers[1:]
+	return answer, nil
+}
+
+func (u *User) WaitForUserAction(_ context.Context, _ string) error {
+	return nil
+}
+
+func (u *User) RequestApproval(_ context.Context, req entity.ApprovalRequest) (bool, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.approvals = append(u.approvals, req)
+	return u.Approve, nil
+}
+
+func (u *User) Checkpoint(ctx context.Context) (string, error) {
+	if ctx.Err() != nil {
+		return "", output.ErrTaskAborted
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if len(u.Guidance) == 0 {
+		return "", nil
+	}
+	guidance := u.Guidance[0]
+	u.Guidance = u.Guidance[1:]
+	return guidance, nil
+}
+
+func (u *User) ShowIteration(_ context.Context, iteration, maxIterations int) {
+	u.show(fmt.Sprintf("iteration %d/%d", iteration, maxIterations))
+}
+
+func (u *User) ShowToolStart(context.Context, string, string)        {}
+func (u *User) ShowToolResult(context.Context, string, string, bool) {}
+func (u *User) ShowThinking(context.Context, string)                 {}
+
+func (u *User) show(line string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.shown = append(u.shown, line)
+}
+
+// Questions returns the questions asked so far.
+func (u *User) Questions() []string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return append([]string(nil), u.questions...)
+}
+
+// Approvals returns the approval requests so far.
+func (u *User) Approvals() []entity.ApprovalRequest {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return append([]entity.ApprovalRequest(nil), u.approvals...)
+}
+
+// Iterations returns the iterations shown, e.g. "iteration 2/10".
+func (u *User) Iterations() []string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return append([]string(nil), u.shown...)
+}
+
+// NopLogger discards everything.
+type NopLogger struct{}
+
+func (NopLogger) Debug(string, ...any)                          {}
+func (NopLogger) Info(string, ...any)                           {}
+func (NopLogger) Warn(string, ...any)                           {}
+func (NopLogger) Error(string, ...any)                          {}
+func (l NopLogger) WithField(string, any) output.LoggerPort     { return l }
+func (l NopLogger) WithFields(map[string]any) output.LoggerPort { return l }
+func (NopLogger) Close() error                                  { return nil }
@@ -6,11 +6,12 @@ import (
 
 	"browser-agent/internal/application/port/output"
 	"browser-agent/internal/domain/entity"
+	"browser-agent/internal/usecase/checkpoint"
 )
 
 const (
-	maxIterations     = 10
-	maxObservationLen = 20000
+	defaultMaxIterations = 10
+	maxObservationLen    = 20000
 )
 
 var _ output.SimpleAgent = (*Agent)(nil)
@@ -21,6 +22,7 @@ type Agent struct {
 	logger          output.LoggerPort
 	userInteraction output.UserInteractionPort
 	systemPrompt    string
+	maxIterations   int
 }
 
 func New(
@@ -36,6 +38,14 @@ func New(
 		logger:          logger,
 		userInteraction: userInteraction,
 		systemPrompt:    systemPrompt,
+		maxIterations:   defaultMaxIterations,
+	}
+}
+
+// SetMaxIterations overrides the iteration limit; values below 1 are ignored.
+func (a *Agent) SetMaxIterations(n int) {
+	if n > 0 {
+		a.maxIterations = n
 	}
 }
 
@@ -64,8 +74,8 @@ func (a *Agent) executeWithIterations(ctx context.Context, task string) (string,
 
 	toolDefs := a.filterTools()
 
-	for iter := 1; iter <= maxIterations; iter++ {
-		a.userInteraction.ShowIteration(ctx, iter, maxIterations)
+	for iter := 1; iter <= a.maxIterations; iter++ {
+		a.userInteraction.ShowIteration(ctx, iter, a.maxIterations)
 		a.logger.Debug("Form agent iteration", "iteration", iter)
 
 		resp, err := a.llm.Chat(ctx, output.ChatRequest{
@@ -87,6 +97,7 @@ func (a *Agent) executeWithIterations(ctx context.Context, task string) (string,
 			return resp.Message.Content, nil
 		}
 
+		var guidance checkpoint.Guidance
 		for _, tc := range resp.Message.ToolCalls {
 			a.userInteraction.ShowToolStart(ctx, tc.Name, tc.Arguments)
 			observation := a.executeTool(ctx, tc)
@@ -104,7 +115,12 @@ func (a *Agent) executeWithIterations(ctx context.Context, task string) (string,
 				Name:       tc.Name,
 				Content:    observation,
 			})
+
+			if err := guidance.Wait(ctx, a.userInteraction); err != nil {
+				return "", err
+			}
 		}
+		messages = append(messages, guidance.Messages()...)
 	}
 
 	// Summary iteration: force agent to provide final report
@@ -164,6 +180,9 @@ func (a *Agent) filterTools() []entity.ToolDefinition {
 		entity.ToolBrowserPressEnter,
 		entity.ToolBrowserObserve,
 		entity.ToolBrowserSearch,
+		entity.ToolBrowserWait,
+		entity.ToolBrowserConsole,
+		entity.ToolBrowserDialog,
 		entity.ToolUserWaitAction,
 		entity.ToolUserAskQuestion,
 	}
@@ -0,0 +1,89 @@
+package navigation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tool "browser-agent/internal/adapter/tools"
+	"browser-agent/internal/application/runctx"
+	"browser-agent/internal/application/service"
+	"browser-agent/internal/domain/entity"
+	"browser-agent/internal/testkit"
+)
+
+const site = `
+pages:
+  home:
+    url: https://docs.test/
+    title: Docs
+    elements:
+      - {selector: "#install", tag: a, text: Install, href: https://docs.test/install}
+  install:
+    url: https://docs.test/install
+    title: Install
+`
+
+func newAgent(t *testing.T, llm *testkit.ScriptedLLM) (*Agent, *testkit.ToolRecorder) {
+	t.Helper()
+	logger := testkit.NopLogger{}
+	browser := testkit.NewBrowser(testkit.MustParseSite(site))
+
+	registry := service.NewToolRegistry()
+	registry.Register(tool.NewNavigateTool(browser, logger))
+	registry.Register(tool.NewClickTool(browser, logger))
+	registry.Register(tool.NewScrollTool(browser, logger))
+	tools := testkit.NewToolRecorder(registry)
+
+	t.Cleanup(func() { llm.AssertExhausted(t) })
+	return New(llm, tools, logger, &testkit.User{}, "navigate"), tools
+}
+
+func TestExecuteOffersOnlyNavigationTools(t *testing.T) {
+	llm := testkit.NewScriptedLLM().Script("navigation",
+		testkit.CallTool(entity.ToolBrowserNavigate, map[string]string{"url": "https://docs.test/install"}),
+		testkit.Answer("Install page loaded"),
+	)
+	agent, tools := newAgent(t, llm)
+
+	answer, err := agent.Execute(runctx.WithAgent(context.Background(), "navigation"), "open install")
+	require.NoError(t, err)
+	assert.Equal(t, "Install page loaded", answer)
+	testkit.AssertToolSequence(t, tools.Calls(), entity.ToolBrowserNavigate)
+
+	var offered []entity.ToolName
+	for _, def := range llm.Calls()[0].Request.Tools {
+		offered = append(offered, def.Name)
+	}
+	assert.ElementsMatch(t, []entity.ToolName{entity.ToolBrowserNavigate, entity.ToolBrowserScroll}, offered)
+}
+
+func TestExecuteRequestsSummaryAfterMaxIterations(t *testing.T) {
+	llm := testkit.NewScriptedLLM().Script("navigation",
+		testkit.CallTool(entity.ToolBrowserScroll, map[string]string{"direction": "down"}),
+		testkit.CallTool(entity.ToolBrowserNavigate, map[string]string{"url": "https://nowhere.test/"}),
+		testkit.Answer("FAILED: could not find the page"),
+	)
+	agent, tools := newAgent(t, llm)
+	agent.SetMaxIterations(2)
+
+	answer, err := agent.Execute(runctx.WithAgent(context.Background(), "navigation"), "find the changelog")
+	require.NoError(t, err)
+	assert.Equal(t, "FAILED: could not find the page", answer)
+
+	calls := tools.Calls()
+	testkit.AssertToolSequence(t, calls, entity.ToolBrowserScroll, entity.ToolBrowserNavigate)
+	assert.Error(t, calls[1].Err)
+
+	requests := llm.Calls()
+	require.Len(t, requests, 3)
+	summary := requests[2].Request
+	assert.Nil(t, summary.Tools)
+	last := summary.Messages[len(summary.Messages)-1]
+	assert.Equal(t, entity.RoleUser, last.Role)
+	assert.Contains(t, last.Content, "Maximum iterations reached")
+	failed := summary.Messages[len(summary.Messages)-2]
+	assert.Contains(t, failed.Content, "Error: navigate https://nowhere.test/")
+}
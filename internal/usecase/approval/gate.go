@@ -0,0 +1,212 @@
+package approval
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/domain/entity"
+)
+
+var (
+	ErrRejected = errors.New("action rejected by user")
+	ErrDenied   = errors.New("action denied by approval policy")
+)
+
+var _ output.ToolGuard = (*Gate)(nil)
+
+// Gate asks the user to confirm risky browser actions before they run.
+type Gate struct {
+	policy          Policy
+	browser         output.BrowserPort
+	userInteraction output.UserInteractionPort
+	logger          output.LoggerPort
+}
+
+func NewGate(
+	policy Policy,
+	browser output.BrowserPort,
+	userInteraction output.UserInteractionPort,
+	logger output.LoggerPort,
+) *Gate {
+	return &Gate{
+		policy:          policy,
+		browser:         browser,
+		userInteraction: userInteraction,
+		logger:          logger,
+	}
+}
+
+func (g *Gate) Check(ctx context.Context, name entity.ToolName, arguments string) error {
+	if !g.policy.Enabled {
+		return nil
+	}
+
+	actions := g.actions(ctx, name, arguments)
+	if len(actions) == 0 {
+		return nil
+	}
+
+	var (
+		reasons []string
+		target  *entity.ElementInfo
+		require bool
+	)
+	for _, a := range actions {
+		decision, why := g.policy.assess(a)
+		switch decision {
+		case DecisionDeny:
+			g.logger.Warn("Action denied by approval policy", "tool", name, "args", arguments, "reasons", why)
+			return fmt.Errorf("%w: %s", ErrDenied, strings.Join(why, "; "))
+		case DecisionRequire:
+			require = true
+			reasons = append(reasons, why...)
+			if target == nil {
+				target = a.target
+			}
+		}
+	}
+
+	if !require {
+		return nil
+	}
+
+	req := entity.ApprovalRequest{
+		ToolName:  name,
+		Arguments: arguments,
+		URL:       g.browser.CurrentURL(),
+		Reasons:   reasons,
+		Target:    target,
+	}
+	if screenshot, err := g.browser.Screenshot(ctx); err == nil {
+		req.Screenshot = screenshot
+	}
+
+	approved, err := g.userInteraction.RequestApproval(ctx, req)
+	if err != nil {
+		return fmt.Errorf("approval request failed: %w", err)
+	}
+
+	g.logger.Info("Approval decision", "tool", name, "args", arguments, "approved", approved, "reasons", reasons)
+
+	if !approved {
+		return fmt.Errorf("%w: %s. Do not retry this action unless the user asks for it", ErrRejected, strings.Join(reasons, "; "))
+	}
+	return nil
+}
+
+// actions returns what a call of the tool would do; nil means the tool is
+// not guarded. Arguments that cannot be read and targets that cannot be
+// inspected make an action risky, so the gate fails closed.
+func (g *Gate) actions(ctx context.Context, name entity.ToolName, arguments string) []action {
+	host := hostOf(g.browser.CurrentURL())
+
+	switch name {
+	case entity.ToolBrowserClick:
+		var input struct {
+			Selectors []string `json:"selectors"`
+		}
+		if err := json.Unmarshal([]byte(arguments), &input); err != nil {
+			return []action{unreadable(name, err)}
+		}
+		actions := make([]action, 0, len(input.Selectors))
+		for _, selector := range input.Selectors {
+			actions = append(actions, g.describe(ctx, action{tool: name, host: host}, selector))
+		}
+		return actions
+
+	case entity.ToolBrowserPressEnter:
+		return []action{g.describe(ctx, action{tool: name, host: host}, "")}
+
+	case entity.ToolBrowserFill:
+		return []action{{tool: name, host: host}}
+
+	case entity.ToolBrowserNavigate:
+		var input struct {
+			URL string `json:"url"`
+		}
+		if err := json.Unmarshal([]byte(arguments), &input); err != nil {
+			return []action{unreadable(name, err)}
+		}
+		return []action{{tool: name, host: hostOf(input.URL)}}
+
+	case entity.ToolBrowserDialog:
+		var input struct {
+			Action string `json:"action"`
+		}
+		if err := json.Unmarshal([]byte(arguments), &input); err != nil {
+			return []action{unreadable(name, err)}
+		}
+		if input.Action != "accept" {
+			return nil
+		}
+		return []action{g.describeDialog(ctx, action{tool: name, host: host})}
+
+	case entity.ToolBrowserIntercept:
+		var input struct {
+			Action     string `json:"action"`
+			Rule       string `json:"rule"`
+			URLPattern string `json:"url_pattern"`
+		}
+		if err := json.Unmarshal([]byte(arguments), &input); err != nil {
+			return []action{unreadable(name, err)}
+		}
+		if input.Action != "add" {
+			return nil
+		}
+		risk := fmt.Sprintf("intercept rule %q changes requests matching %q", input.Rule, input.URLPattern)
+		return []action{{tool: name, host: host, risks: []string{risk}}}
+	}
+	return nil
+}
+
+func unreadable(name entity.ToolName, err error) action {
+	return action{tool: name, risks: []string{fmt.Sprintf("arguments could not be read: %v", err)}}
+}
+
+// describe adds the element the action targets, or marks the action risky
+// when the element cannot be inspected.
+func (g *Gate) describe(ctx context.Context, a action, selector string) action {
+	info, err := g.browser.DescribeElement(ctx, selector)
+	if err != nil {
+		g.logger.Debug("Failed to describe element for approval", "selector", selector, "error", err)
+		a.risks = append(a.risks, "target element could not be inspected")
+		return a
+	}
+	a.target = info
+	return a
+}
+
+// describeDialog marks accepting a confirm or beforeunload dialog risky:
+// it is how pages ask before deleting, paying or leaving unsaved work.
+func (g *Gate) describeDialog(ctx context.Context, a action) action {
+	dialogs, err := g.browser.Dialogs(ctx)
+	if err != nil {
+		g.logger.Debug("Failed to list dialogs for approval", "error", err)
+		a.risks = append(a.risks, "open dialog could not be inspected")
+		return a
+	}
+	for i := len(dialogs) - 1; i >= 0; i-- {
+		dialog := dialogs[i]
+		if !dialog.Open {
+			continue
+		}
+		if dialog.Type == entity.DialogConfirm || dialog.Type == entity.DialogBeforeUnload {
+			a.risks = append(a.risks, fmt.Sprintf("accepting %s dialog %q", dialog.Type, dialog.Message))
+		}
+		break
+	}
+	return a
+}
+
+func hostOf(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return parsed.Host
+}
@@ -0,0 +1,70 @@
+package approval
+
+import (
+	"context"
+	"testing"
+
+	"browser-agent/internal/domain/entity"
+	"browser-agent/internal/testkit"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const shopSite = `
+start: cart
+pages:
+  cart:
+    url: https://shop.test/cart
+    elements:
+      - selector: "#remove"
+        tag: button
+        text: Remove
+        goto: cart
+        dialog: {type: confirm, message: "Remove the kettle from the cart?"}
+      - selector: "#details"
+        tag: button
+        text: Details
+`
+
+func TestGateFailsClosed(t *testing.T) {
+	ctx := context.Background()
+	user := &testkit.User{}
+	gate := NewGate(DefaultPolicy(), testkit.NewBrowser(testkit.MustParseSite(shopSite)), user, testkit.NopLogger{})
+
+	assert.NoError(t, gate.Check(ctx, entity.ToolBrowserClick, `{"selectors":["#details"]}`))
+
+	err := gate.Check(ctx, entity.ToolBrowserClick, `{"selectors":`)
+	assert.ErrorIs(t, err, ErrRejected)
+	assert.ErrorContains(t, err, "arguments could not be read")
+
+	err = gate.Check(ctx, entity.ToolBrowserClick, `{"selectors":["#missing"]}`)
+	assert.ErrorIs(t, err, ErrRejected)
+	assert.ErrorContains(t, err, "target element could not be inspected")
+
+	assert.Len(t, user.Approvals(), 2)
+}
+
+func TestGateGuardsDialogsAndIntercepts(t *testing.T) {
+	ctx := context.Background()
+	user := &testkit.User{}
+	browser := testkit.NewBrowser(testkit.MustParseSite(shopSite))
+	gate := NewGate(DefaultPolicy(), browser, user, testkit.NopLogger{})
+
+	var dialogErr *entity.DialogOpenError
+	require.ErrorAs(t, browser.Click(ctx, "#remove"), &dialogErr)
+	assert.NoError(t, gate.Check(ctx, entity.ToolBrowserDialog, `{"action":"dismiss"}`))
+	err := gate.Check(ctx, entity.ToolBrowserDialog, `{"action":"accept"}`)
+	assert.ErrorIs(t, err, ErrRejected)
+	assert.ErrorContains(t, err, `accepting confirm dialog "Remove the kettle from the cart?"`)
+
+	assert.NoError(t, gate.Check(ctx, entity.ToolBrowserIntercept, `{"action":"list"}`))
+	err = gate.Check(ctx, entity.ToolBrowserIntercept, `{"action":"add","rule":"mock","url_pattern":"/api/cart"}`)
+	assert.ErrorIs(t, err, ErrRejected)
+	assert.ErrorContains(t, err, `intercept rule "mock" changes requests matching "/api/cart"`)
+
+	policy := DefaultPolicy()
+	policy.Rules = []Rule{{Decision: DecisionAllow, Domain: "shop.test", Tools: []entity.ToolName{entity.ToolBrowserIntercept}}}
+	gate = NewGate(policy, browser, user, testkit.NopLogger{})
+	assert.NoError(t, gate.Check(ctx, entity.ToolBrowserIntercept, `{"action":"add","rule":"mock","url_pattern":"/api/cart"}`))
+}
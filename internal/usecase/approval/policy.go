@@ -0,0 +1,184 @@
+package approval
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"browser-agent/internal/domain/entity"
+	"browser-agent/internal/domain/policy"
+)
+
+type Decision string
+
+const (
+	DecisionAllow   Decision = "allow"
+	DecisionRequire Decision = "require"
+	DecisionDeny    Decision = "deny"
+)
+
+// Rule overrides the heuristics for pages on Domain. An empty Tools list
+// applies the rule to every guarded action.
+type Rule struct {
+	Decision Decision
+	Domain   string
+	Tools    []entity.ToolName
+}
+
+type Policy struct {
+	Enabled        bool
+	Keywords       []string
+	ConfirmSubmits bool
+	Rules          []Rule
+}
+
+var defaultKeywords = []string{
+	"buy", "buy now", "purchase", "order", "place order", "checkout", "pay", "pay now",
+	"delete", "remove", "send", "submit", "confirm", "transfer", "unsubscribe",
+	"купить", "оплатить", "заказать", "оформить заказ", "удалить", "отправить",
+	"подтвердить", "перевести",
+}
+
+func DefaultPolicy() Policy {
+	return Policy{
+		Enabled:        true,
+		Keywords:       append([]string(nil), defaultKeywords...),
+		ConfirmSubmits: true,
+	}
+}
+
+// ParseRules parses rules in the form "decision:domain[:tool1,tool2]"
+// separated by ";", e.g. "require:*.bank.com;allow:localhost:8080:browser_click".
+// The domain may carry a port, since tool names are never numbers.
+func ParseRules(spec string) ([]Rule, error) {
+	var rules []Rule
+
+	for _, raw := range strings.Split(spec, ";") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		decisionPart, domain, found := strings.Cut(raw, ":")
+		if !found {
+			return nil, fmt.Errorf("invalid approval rule %q: expected decision:domain[:tools]", raw)
+		}
+		var tools string
+		if i := strings.LastIndex(domain, ":"); i != -1 && !isPort(domain[i+1:]) {
+			domain, tools = domain[:i], domain[i+1:]
+		}
+
+		decision := Decision(strings.ToLower(strings.TrimSpace(decisionPart)))
+		switch decision {
+		case DecisionAllow, DecisionRequire, DecisionDeny:
+		default:
+			return nil, fmt.Errorf("invalid approval rule %q: unknown decision %q", raw, decision)
+		}
+
+		rule := Rule{
+			Decision: decision,
+			Domain:   strings.TrimSpace(domain),
+		}
+		if rule.Domain == "" {
+			return nil, fmt.Errorf("invalid approval rule %q: empty domain", raw)
+		}
+
+		for _, name := range strings.Split(tools, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				rule.Tools = append(rule.Tools, entity.ToolName(name))
+			}
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// action is a single guarded operation as seen by the policy. risks are
+// reasons to ask that hold whatever the target is.
+type action struct {
+	tool   entity.ToolName
+	host   string
+	target *entity.ElementInfo
+	risks  []string
+}
+
+// assess returns the decision for an action together with human readable
+// reasons. Explicit rules win over heuristics; the first matching rule is used.
+func (p Policy) assess(a action) (Decision, []string) {
+	for _, rule := range p.Rules {
+		if !rule.matches(a) {
+			continue
+		}
+		reason := fmt.Sprintf("rule %s:%s", rule.Decision, rule.Domain)
+		return rule.Decision, []string{reason}
+	}
+
+	reasons := append([]string(nil), a.risks...)
+
+	if a.target != nil {
+		if kw := p.matchKeyword(a.target); kw != "" {
+			reasons = append(reasons, fmt.Sprintf("element text matches risky keyword %q", kw))
+		}
+		if p.ConfirmSubmits {
+			switch {
+			case a.tool == entity.ToolBrowserPressEnter && a.target.InForm:
+				reasons = append(reasons, "pressing Enter submits a form")
+			case a.target.IsSubmit:
+				reasons = append(reasons, "element submits a form")
+			}
+		}
+	}
+
+	if len(reasons) > 0 {
+		return DecisionRequire, reasons
+	}
+	return DecisionAllow, nil
+}
+
+func isPort(s string) bool {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (r Rule) matches(a action) bool {
+	if !policy.MatchHost(r.Domain, a.host) {
+		return false
+	}
+	if len(r.Tools) == 0 {
+		return true
+	}
+	for _, tool := range r.Tools {
+		if tool == a.tool {
+			return true
+		}
+	}
+	return false
+}
+
+func (p Policy) matchKeyword(info *entity.ElementInfo) string {
+	text := " " + normalizeWords(info.Text+" "+info.AriaLabel) + " "
+	for _, kw := range p.Keywords {
+		kw = normalizeWords(kw)
+		if kw != "" && strings.Contains(text, " "+kw+" ") {
+			return kw
+		}
+	}
+	return ""
+}
+
+func normalizeWords(s string) string {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(fields, " ")
+}
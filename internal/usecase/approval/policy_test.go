@@ -0,0 +1,124 @@
+package approval
+
+import (
+	"testing"
+
+	"browser-agent/internal/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules("require:*.bank.com; allow:localhost:browser_click,browser_fill ;deny:prod.internal")
+	require.NoError(t, err)
+	require.Len(t, rules, 3)
+
+	assert.Equal(t, DecisionRequire, rules[0].Decision)
+	assert.Equal(t, "*.bank.com", rules[0].Domain)
+	assert.Empty(t, rules[0].Tools)
+
+	assert.Equal(t, DecisionAllow, rules[1].Decision)
+	assert.Equal(t, []entity.ToolName{entity.ToolBrowserClick, entity.ToolBrowserFill}, rules[1].Tools)
+
+	assert.Equal(t, DecisionDeny, rules[2].Decision)
+
+	rules, err = ParseRules("allow:localhost:8080;require:127.0.0.1:3000:browser_click")
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	assert.Equal(t, "localhost:8080", rules[0].Domain)
+	assert.Empty(t, rules[0].Tools)
+	assert.Equal(t, "127.0.0.1:3000", rules[1].Domain)
+	assert.Equal(t, []entity.ToolName{entity.ToolBrowserClick}, rules[1].Tools)
+}
+
+func TestParseRules_Invalid(t *testing.T) {
+	tests := []string{
+		"require",
+		"maybe:example.com",
+		"allow:",
+	}
+
+	for _, spec := range tests {
+		t.Run(spec, func(t *testing.T) {
+			_, err := ParseRules(spec)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestPolicyAssess_Keywords(t *testing.T) {
+	p := DefaultPolicy()
+
+	tests := []struct {
+		name     string
+		text     string
+		expected Decision
+	}{
+		{"English buy", "Buy now", DecisionRequire},
+		{"Russian delete", "Удалить письмо", DecisionRequire},
+		{"Multi-word keyword", "Place order", DecisionRequire},
+		{"Keyword inside word", "Sender settings", DecisionAllow},
+		{"Harmless", "Next page", DecisionAllow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision, _ := p.assess(action{
+				tool:   entity.ToolBrowserClick,
+				host:   "shop.test",
+				target: &entity.ElementInfo{TagName: "button", Text: tt.text},
+			})
+			assert.Equal(t, tt.expected, decision)
+		})
+	}
+}
+
+func TestPolicyAssess_FormSubmission(t *testing.T) {
+	p := DefaultPolicy()
+
+	decision, reasons := p.assess(action{
+		tool:   entity.ToolBrowserClick,
+		target: &entity.ElementInfo{TagName: "button", Text: "Go", IsSubmit: true, InForm: true},
+	})
+	assert.Equal(t, DecisionRequire, decision)
+	assert.NotEmpty(t, reasons)
+
+	decision, _ = p.assess(action{
+		tool:   entity.ToolBrowserPressEnter,
+		target: &entity.ElementInfo{TagName: "input", InForm: true},
+	})
+	assert.Equal(t, DecisionRequire, decision)
+
+	p.ConfirmSubmits = false
+	decision, _ = p.assess(action{
+		tool:   entity.ToolBrowserPressEnter,
+		target: &entity.ElementInfo{TagName: "input", InForm: true},
+	})
+	assert.Equal(t, DecisionAllow, decision)
+}
+
+func TestPolicyAssess_RulesOverrideHeuristics(t *testing.T) {
+	p := DefaultPolicy()
+	p.Rules = []Rule{
+		{Decision: DecisionAllow, Domain: "localhost", Tools: []entity.ToolName{entity.ToolBrowserClick}},
+		{Decision: DecisionDeny, Domain: "*.prod.example.com"},
+		{Decision: DecisionRequire, Domain: "*.bank.test"},
+	}
+
+	decision, _ := p.assess(action{
+		tool:   entity.ToolBrowserClick,
+		host:   "localhost:8080",
+		target: &entity.ElementInfo{Text: "Delete"},
+	})
+	assert.Equal(t, DecisionAllow, decision)
+
+	decision, _ = p.assess(action{tool: entity.ToolBrowserFill, host: "admin.prod.example.com"})
+	assert.Equal(t, DecisionDeny, decision)
+
+	decision, _ = p.assess(action{tool: entity.ToolBrowserNavigate, host: "online.bank.test"})
+	assert.Equal(t, DecisionRequire, decision)
+
+	decision, _ = p.assess(action{tool: entity.ToolBrowserFill, host: "localhost"})
+	assert.Equal(t, DecisionAllow, decision)
+}
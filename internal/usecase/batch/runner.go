@@ -0,0 +1,217 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"browser-agent/internal/application/port/input"
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/application/runctx"
+	"browser-agent/internal/domain/entity"
+)
+
+var _ input.BatchRunner = (*Runner)(nil)
+
+// Worker is one execution slot. Tasks running in parallel must never share
+// a page: each worker owns a browser, or its executor leases one per task.
+type Worker struct {
+	Executor input.TaskExecutor
+	// Browser opens the task's start URL. Optional: without it the start
+	// URL is passed to the executor, which leases the task's browser.
+	Browser output.BrowserPort
+}
+
+type Config struct {
+	// TaskTimeout applies to tasks that do not set their own timeout.
+	TaskTimeout time.Duration
+	// OnResult is called after each task, e.g. to report progress.
+	OnResult func(result entity.BatchResult)
+}
+
+func DefaultConfig() Config {
+	return Config{TaskTimeout: 30 * time.Minute}
+}
+
+// Runner distributes batch tasks over its workers. The number of workers is
+// the concurrency of the batch.
+type Runner struct {
+	workers []Worker
+	scripts output.InteractionScripts
+	results output.BatchResultWriter
+	logger  output.LoggerPort
+	config  Config
+}
+
+func New(workers []Worker, scripts output.InteractionScripts, results output.BatchResultWriter, logger output.LoggerPort, config Config) *Runner {
+	if config.TaskTimeout <= 0 {
+		config.TaskTimeout = DefaultConfig().TaskTimeout
+	}
+	return &Runner{
+		workers: workers,
+		scripts: scripts,
+		results: results,
+		logger:  logger,
+		config:  config,
+	}
+}
+
+// Run executes tasks and returns their results in input order. A failing task
+// does not stop the batch; the returned error is about the result writer.
+// Tasks not started before ctx is canceled are reported as canceled.
+func (r *Runner) Run(ctx context.Context, tasks []entity.BatchTask) ([]entity.BatchResult, error) {
+	if len(r.workers) == 0 {
+		return nil, errors.New("batch runner has no workers")
+	}
+
+	results := make([]entity.BatchResult, len(tasks))
+	queue := make(chan int)
+
+	// emit is serialized so the writer and OnResult never run concurrently.
+	var mu sync.Mutex
+	var writeErr error
+	emit := func(i int, result entity.BatchResult) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		results[i] = result
+		if r.results != nil {
+			if err := r.results.Write(result); err != nil {
+				r.logger.Error("Failed to write batch result", "taskId", result.ID, "error", err)
+				if writeErr == nil {
+					writeErr = err
+				}
+			}
+		}
+		if r.config.OnResult != nil {
+			r.config.OnResult(result)
+		}
+	}
+
+	var wg sync.WaitGroup
+
+	for _, worker := range r.workers {
+		wg.Add(1)
+		go func(worker Worker) {
+			defer wg.Done()
+			for i := range queue {
+				emit(i, r.runTask(ctx, worker, tasks[i]))
+			}
+		}(worker)
+	}
+
+	for i := range tasks {
+		if ctx.Err() != nil {
+			emit(i, entity.BatchResult{
+				ID:     tasks[i].ID,
+				Task:   tasks[i].Task,
+				Status: entity.TaskStatusCanceled,
+				Error:  ctx.Err().Error(),
+			})
+			continue
+		}
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+
+	return results, writeErr
+}
+
+func (r *Runner) runTask(ctx context.Context, worker Worker, task entity.BatchTask) (result entity.BatchResult) {
+	timeout := task.Timeout
+	if timeout <= 0 {
+		timeout = r.config.TaskTimeout
+	}
+
+	result = entity.BatchResult{
+		ID:        task.ID,
+		Task:      task.Task,
+		StartedAt: time.Now(),
+	}
+	defer func() {
+		result.DurationMS = time.Since(result.StartedAt).Milliseconds()
+	}()
+
+	if r.scripts != nil {
+		if err := r.scripts.Load(task.ID, task.Script); err != nil {
+			result.Status = entity.TaskStatusFailed
+			result.Error = err.Error()
+			return result
+		}
+		defer r.scripts.Forget(task.ID)
+	}
+
+	taskCtx, cancel := context.WithTimeout(runctx.WithTaskID(ctx, task.ID), timeout)
+	defer cancel()
+
+	r.logger.Info("Batch task started", "taskId", task.ID)
+
+	req := input.TaskRequest{
+		Task:          task.Task,
+		MaxIterations: task.MaxIterations,
+		OutputSchema:  task.OutputSchema,
+		StartURL:      task.StartURL,
+	}
+	if worker.Browser != nil {
+		var err error
+		req.Task, err = OpenStartURL(taskCtx, worker.Browser, task.Task, task.StartURL)
+		if err != nil {
+			result.Status = entity.TaskStatusFailed
+			result.Error = err.Error()
+			return result
+		}
+		req.StartURL = ""
+	}
+
+	execResult, err := worker.Executor.Execute(taskCtx, req)
+
+	switch {
+	case err == nil:
+		result.Status = entity.TaskStatusCompleted
+		result.FinalAnswer = execResult.FinalAnswer
+		result.Iterations = execResult.Iterations
+		if task.OutputSchema != nil {
+			parsed, err := ParseOutput(execResult.FinalAnswer, task.OutputSchema)
+			if err != nil {
+				result.Status = entity.TaskStatusFailed
+				result.Error = err.Error()
+			} else {
+				result.Output = parsed
+			}
+		}
+	case ctx.Err() != nil:
+		result.Status = entity.TaskStatusCanceled
+		result.Error = err.Error()
+	case errors.Is(taskCtx.Err(), context.DeadlineExceeded):
+		result.Status = entity.TaskStatusFailed
+		result.Error = fmt.Sprintf("task timed out after %s", timeout)
+	default:
+		result.Status = entity.TaskStatusFailed
+		result.Error = err.Error()
+	}
+
+	if result.Error != "" {
+		r.logger.Warn("Batch task finished with error", "taskId", task.ID, "status", result.Status, "error", result.Error)
+	} else {
+		r.logger.Info("Batch task completed", "taskId", task.ID, "iterations", result.Iterations)
+	}
+	return result
+}
+
+// OpenStartURL navigates browser to startURL and tells the agent where it
+// starts. Without a browser the URL is only mentioned in the task.
+func OpenStartURL(ctx context.Context, browser output.BrowserPort, task, startURL string) (string, error) {
+	if startURL == "" {
+		return task, nil
+	}
+	if browser == nil {
+		return task + "\n\nStart at " + startURL + ".", nil
+	}
+	if err := browser.Navigate(ctx, startURL); err != nil {
+		return "", fmt.Errorf("open start URL: %w", err)
+	}
+	return task + "\n\nThe browser is already open at " + startURL + ".", nil
+}
@@ -0,0 +1,158 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"browser-agent/internal/application/port/input"
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/application/runctx"
+	"browser-agent/internal/domain/entity"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...any)                          {}
+func (nopLogger) Info(string, ...any)                           {}
+func (nopLogger) Warn(string, ...any)                           {}
+func (nopLogger) Error(string, ...any)                          {}
+func (l nopLogger) WithField(string, any) output.LoggerPort     { return l }
+func (l nopLogger) WithFields(map[string]any) output.LoggerPort { return l }
+func (nopLogger) Close() error                                  { return nil }
+
+type executorFunc func(ctx context.Context, req input.TaskRequest) (*input.ExecuteResult, error)
+
+func (f executorFunc) Execute(ctx context.Context, req input.TaskRequest) (*input.ExecuteResult, error) {
+	return f(ctx, req)
+}
+
+type recordingWriter struct {
+	mu      sync.Mutex
+	results []entity.BatchResult
+}
+
+func (w *recordingWriter) Write(result entity.BatchResult) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.results = append(w.results, result)
+	return nil
+}
+
+type recordingScripts struct {
+	mu     sync.Mutex
+	loaded map[string]entity.InteractionScript
+}
+
+func (s *recordingScripts) Load(taskID string, script entity.InteractionScript) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loaded[taskID] = script
+	return nil
+}
+
+func (s *recordingScripts) Forget(string) {}
+
+func TestRunner_RunsTasksAndValidatesOutput(t *testing.T) {
+	executor := executorFunc(func(ctx context.Context, req input.TaskRequest) (*input.ExecuteResult, error) {
+		switch runctx.TaskID(ctx) {
+		case "ok":
+			assert.Equal(t, 5, req.MaxIterations)
+			assert.NotNil(t, req.OutputSchema)
+			return &input.ExecuteResult{FinalAnswer: "```json\n{\"price\": 12.5}\n```", Iterations: 2}, nil
+		case "bad-output":
+			return &input.ExecuteResult{FinalAnswer: `{"price": "cheap"}`}, nil
+		default:
+			return nil, errors.New("boom")
+		}
+	})
+
+	schema := map[string]any{
+		"type":       "object",
+		"required":   []any{"price"},
+		"properties": map[string]any{"price": map[string]any{"type": "number"}},
+	}
+	writer := &recordingWriter{}
+	scripts := &recordingScripts{loaded: map[string]entity.InteractionScript{}}
+	runner := New([]Worker{{Executor: executor}, {Executor: executor}}, scripts, writer, nopLogger{}, DefaultConfig())
+
+	results, err := runner.Run(context.Background(), []entity.BatchTask{
+		{ID: "ok", Task: "find price", MaxIterations: 5, OutputSchema: schema, Script: entity.InteractionScript{Approvals: entity.ApprovalApprove}},
+		{ID: "bad-output", Task: "find price", OutputSchema: schema},
+		{ID: "error", Task: "explode"},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	assert.Equal(t, entity.TaskStatusCompleted, results[0].Status)
+	assert.Equal(t, map[string]any{"price": 12.5}, results[0].Output)
+	assert.Equal(t, 2, results[0].Iterations)
+
+	assert.Equal(t, entity.TaskStatusFailed, results[1].Status)
+	assert.Contains(t, results[1].Error, "$.price")
+
+	assert.Equal(t, entity.TaskStatusFailed, results[2].Status)
+	assert.Equal(t, "boom", results[2].Error)
+
+	assert.Len(t, writer.results, 3)
+	assert.Equal(t, entity.ApprovalApprove, scripts.loaded["ok"].Approvals)
+}
+
+func TestRunner_TimeoutAndCancel(t *testing.T) {
+	executor := executorFunc(func(ctx context.Context, req input.TaskRequest) (*input.ExecuteResult, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	runner := New([]Worker{{Executor: executor}}, nil, nil, nopLogger{}, DefaultConfig())
+
+	results, err := runner.Run(context.Background(), []entity.BatchTask{
+		{ID: "slow", Task: "wait", Timeout: 10 * time.Millisecond},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, entity.TaskStatusFailed, results[0].Status)
+	assert.Contains(t, results[0].Error, "timed out")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, err = runner.Run(ctx, []entity.BatchTask{{ID: "a", Task: "a"}, {ID: "b", Task: "b"}})
+	require.NoError(t, err)
+	for _, result := range results {
+		assert.Equal(t, entity.TaskStatusCanceled, result.Status)
+	}
+}
+
+func TestParseOutput(t *testing.T) {
+	schema := map[string]any{
+		"type": "array",
+		"items": map[string]any{
+			"type":     "object",
+			"required": []any{"name", "status"},
+			"properties": map[string]any{
+				"name":   map[string]any{"type": "string"},
+				"status": map[string]any{"enum": []any{"open", "closed"}},
+				"count":  map[string]any{"type": "integer"},
+			},
+		},
+	}
+
+	value, err := ParseOutput(`[{"name": "a", "status": "open", "count": 3}]`, schema)
+	require.NoError(t, err)
+	assert.Len(t, value, 1)
+
+	_, err = ParseOutput(`[{"name": "a", "status": "pending"}]`, schema)
+	assert.ErrorContains(t, err, "$[0].status")
+
+	_, err = ParseOutput(`[{"name": "a"}]`, schema)
+	assert.ErrorContains(t, err, `"status"`)
+
+	_, err = ParseOutput(`[{"name": "a", "status": "open", "count": 1.5}]`, schema)
+	assert.ErrorContains(t, err, "$[0].count")
+
+	_, err = ParseOutput("not json", schema)
+	assert.ErrorContains(t, err, "not valid JSON")
+}
@@ -0,0 +1,144 @@
+package batch
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// ParseOutput decodes a final answer as JSON and checks it against schema.
+// Only the JSON Schema keywords agents need for structured results are
+// supported: type, properties, required, items and enum.
+func ParseOutput(answer string, schema map[string]any) (any, error) {
+	var value any
+	if err := json.Unmarshal([]byte(stripCodeFence(answer)), &value); err != nil {
+		return nil, fmt.Errorf("final answer is not valid JSON: %w", err)
+	}
+	if err := validate(value, schema, "$"); err != nil {
+		return nil, fmt.Errorf("final answer does not match output schema: %w", err)
+	}
+	return value, nil
+}
+
+// stripCodeFence removes a ```json ... ``` wrapper models add despite being
+// asked not to.
+func stripCodeFence(answer string) string {
+	answer = strings.TrimSpace(answer)
+	if !strings.HasPrefix(answer, "```") {
+		return answer
+	}
+	answer = strings.TrimPrefix(answer, "```")
+	if newline := strings.IndexByte(answer, '\n'); newline >= 0 {
+		answer = answer[newline+1:]
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(answer), "```"))
+}
+
+func validate(value any, schema map[string]any, path string) error {
+	if enum, ok := schema["enum"].([]any); ok {
+		found := false
+		for _, allowed := range enum {
+			if reflect.DeepEqual(normalize(allowed), value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s: value %v is not one of %v", path, value, enum)
+		}
+	}
+
+	if typ, ok := schema["type"]; ok && !matchesType(value, typ) {
+		return fmt.Errorf("%s: expected %v, got %s", path, typ, jsonType(value))
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		if required, ok := schema["required"].([]any); ok {
+			for _, name := range required {
+				key, _ := name.(string)
+				if _, ok := v[key]; !ok {
+					return fmt.Errorf("%s: missing required property %q", path, key)
+				}
+			}
+		}
+		if properties, ok := schema["properties"].(map[string]any); ok {
+			for key, propSchema := range properties {
+				prop, ok := v[key]
+				sub, isSchema := propSchema.(map[string]any)
+				if !ok || !isSchema {
+					continue
+				}
+				if err := validate(prop, sub, path+"."+key); err != nil {
+					return err
+				}
+			}
+		}
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range v {
+				if err := validate(item, items, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// matchesType accepts a single type name or a list of them.
+func matchesType(value any, typ any) bool {
+	switch t := typ.(type) {
+	case string:
+		actual := jsonType(value)
+		if t == "number" && actual == "integer" {
+			return true
+		}
+		return actual == t
+	case []any:
+		for _, candidate := range t {
+			if matchesType(value, candidate) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func jsonType(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// normalize converts schema literals decoded from YAML to the types
+// encoding/json produces, so enum values compare equal.
+func normalize(value any) any {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	}
+	return value
+}
@@ -0,0 +1,89 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/domain/entity"
+)
+
+// maxCheckedElements bounds the elements a DOM check reads.
+const maxCheckedElements = 50
+
+// Check compares the outcome of a run with the expectation and returns what
+// did not match; an empty result means the case passed. DOM and URL checks
+// look at the page the browser is on now, so Check must run right after the
+// task.
+func Check(ctx context.Context, browser output.BrowserPort, expect entity.EvalExpectation, run entity.BatchResult) []string {
+	if run.Status != entity.TaskStatusCompleted {
+		return []string{fmt.Sprintf("task %s: %s", run.Status, run.Error)}
+	}
+
+	var failures []string
+	answer := strings.ToLower(run.FinalAnswer)
+	for _, want := range expect.AnswerContains {
+		if !strings.Contains(answer, strings.ToLower(want)) {
+			failures = append(failures, fmt.Sprintf("answer does not contain %q", want))
+		}
+	}
+
+	if expect.AnswerMatches != "" {
+		re, err := regexp.Compile(expect.AnswerMatches)
+		switch {
+		case err != nil:
+			failures = append(failures, fmt.Sprintf("invalid answer_matches: %v", err))
+		case !re.MatchString(run.FinalAnswer):
+			failures = append(failures, fmt.Sprintf("answer does not match %q", expect.AnswerMatches))
+		}
+	}
+
+	if expect.URLContains != "" {
+		if url := browser.CurrentURL(); !strings.Contains(url, expect.URLContains) {
+			failures = append(failures, fmt.Sprintf("url %s does not contain %q", url, expect.URLContains))
+		}
+	}
+
+	for _, check := range expect.DOM {
+		if failure := checkDOM(ctx, browser, check); failure != "" {
+			failures = append(failures, failure)
+		}
+	}
+	return failures
+}
+
+func checkDOM(ctx context.Context, browser output.BrowserPort, check entity.DOMCheck) string {
+	found, err := browser.QueryElements(ctx, entity.QueryElementsRequest{
+		Selector: check.Selector,
+		Limit:    maxCheckedElements,
+		Extract:  map[string]string{"_self": "text"},
+	})
+	if err != nil {
+		return fmt.Sprintf("%s: %v", check.Selector, err)
+	}
+
+	if check.Absent {
+		if found.Count > 0 {
+			return fmt.Sprintf("%s: expected no element, found %d", check.Selector, found.Count)
+		}
+		return ""
+	}
+	if found.Count == 0 {
+		return fmt.Sprintf("%s: no element found", check.Selector)
+	}
+	if check.TextContains == "" {
+		return ""
+	}
+
+	var texts []string
+	for _, element := range found.Elements {
+		text := element.Data["_self"]
+		if strings.Contains(strings.ToLower(text), strings.ToLower(check.TextContains)) {
+			return ""
+		}
+		texts = append(texts, strings.TrimSpace(text))
+	}
+	return fmt.Sprintf("%s: no element contains %q (found %q)", check.Selector, check.TextContains, texts)
+}
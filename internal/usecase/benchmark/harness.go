@@ -0,0 +1,148 @@
+// Package benchmark runs a suite of tasks with known outcomes against one or
+// more agent configurations and reports how well each did, so that a change
+// of model or prompt can be measured instead of guessed.
+package benchmark
+
+import (
+	"context"
+	"time"
+
+	"browser-agent/internal/application/port/input"
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/domain/entity"
+	"browser-agent/internal/usecase/batch"
+)
+
+// Target is one agent configuration under evaluation: a model with a
+// version of the prompts.
+type Target struct {
+	Model         string
+	PromptVersion string
+	Executor      input.TaskExecutor
+	// Browser opens the start URLs and is inspected by the checks.
+	Browser output.BrowserPort
+	// Usage returns the tokens a finished task spent. Optional.
+	Usage func(taskID string) entity.Usage
+}
+
+type Config struct {
+	// TaskTimeout applies to cases that do not set their own timeout.
+	TaskTimeout time.Duration
+	// OnResult is called after each case, e.g. to report progress.
+	OnResult func(result entity.EvalResult)
+}
+
+type Harness struct {
+	scripts output.InteractionScripts
+	logger  output.LoggerPort
+	config  Config
+}
+
+// New runs cases with scripts standing in for the user, as in batch mode.
+func New(scripts output.InteractionScripts, logger output.LoggerPort, config Config) *Harness {
+	if config.TaskTimeout <= 0 {
+		config.TaskTimeout = batch.DefaultConfig().TaskTimeout
+	}
+	return &Harness{
+		scripts: scripts,
+		logger:  logger,
+		config:  config,
+	}
+}
+
+// Run executes every case with every target, one at a time so that the
+// latencies are comparable. When ctx is canceled the report holds the
+// results so far.
+func (h *Harness) Run(ctx context.Context, targets []Target, cases []entity.EvalCase) (*entity.EvalReport, error) {
+	report := &entity.EvalReport{StartedAt: time.Now()}
+
+	for _, target := range targets {
+		runner := batch.New(
+			[]batch.Worker{{Executor: target.Executor, Browser: target.Browser}},
+			h.scripts, nil, h.logger,
+			batch.Config{TaskTimeout: h.config.TaskTimeout},
+		)
+
+		for _, c := range cases {
+			if ctx.Err() != nil {
+				report.Summaries = Summarize(report.Results)
+				return report, ctx.Err()
+			}
+
+			results, err := runner.Run(ctx, []entity.BatchTask{c.BatchTask})
+			if err != nil {
+				return nil, err
+			}
+			result := h.evaluate(ctx, target, c, results[0])
+
+			report.Results = append(report.Results, result)
+			if h.config.OnResult != nil {
+				h.config.OnResult(result)
+			}
+		}
+	}
+
+	report.Summaries = Summarize(report.Results)
+	return report, nil
+}
+
+func (h *Harness) evaluate(ctx context.Context, target Target, c entity.EvalCase, run entity.BatchResult) entity.EvalResult {
+	result := entity.EvalResult{
+		ID:            c.ID,
+		Model:         target.Model,
+		PromptVersion: target.PromptVersion,
+		Status:        run.Status,
+		FinalAnswer:   run.FinalAnswer,
+		Error:         run.Error,
+		Iterations:    run.Iterations,
+		DurationMS:    run.DurationMS,
+	}
+	if target.Usage != nil {
+		result.Usage = target.Usage(c.ID)
+	}
+
+	result.Failures = Check(ctx, target.Browser, c.Expect, run)
+	result.Passed = len(result.Failures) == 0
+
+	h.logger.Info("Eval case finished", "id", c.ID, "model", target.Model, "prompts", target.PromptVersion, "passed", result.Passed)
+	return result
+}
+
+// Summarize groups results by model and prompt version, in the order the
+// groups first appear.
+func Summarize(results []entity.EvalResult) []entity.EvalSummary {
+	type key struct{ model, prompts string }
+	index := make(map[key]int)
+	var summaries []entity.EvalSummary
+	var iterations []int
+	var durations []int64
+
+	for _, result := range results {
+		k := key{result.Model, result.PromptVersion}
+		i, ok := index[k]
+		if !ok {
+			i = len(summaries)
+			index[k] = i
+			summaries = append(summaries, entity.EvalSummary{Model: result.Model, PromptVersion: result.PromptVersion})
+			iterations = append(iterations, 0)
+			durations = append(durations, 0)
+		}
+
+		summary := &summaries[i]
+		summary.Tasks++
+		if result.Passed {
+			summary.Passed++
+		}
+		summary.Usage.Add(result.Usage)
+		iterations[i] += result.Iterations
+		durations[i] += result.DurationMS
+	}
+
+	for i := range summaries {
+		n := summaries[i].Tasks
+		summaries[i].SuccessRate = float64(summaries[i].Passed) / float64(n)
+		summaries[i].AvgIterations = float64(iterations[i]) / float64(n)
+		summaries[i].AvgDurationMS = durations[i] / int64(n)
+	}
+	return summaries
+}
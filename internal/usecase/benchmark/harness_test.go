@@ -0,0 +1,158 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"browser-agent/internal/application/port/input"
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/application/runctx"
+	"browser-agent/internal/domain/entity"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...any)                          {}
+func (nopLogger) Info(string, ...any)                           {}
+func (nopLogger) Warn(string, ...any)                           {}
+func (nopLogger) Error(string, ...any)                          {}
+func (l nopLogger) WithField(string, any) output.LoggerPort     { return l }
+func (l nopLogger) WithFields(map[string]any) output.LoggerPort { return l }
+func (nopLogger) Close() error                                  { return nil }
+
+// fakePage is the end state of a task: its URL and the text of the elements
+// per selector.
+type fakePage struct {
+	output.BrowserPort
+	url      string
+	elements map[string][]string
+}
+
+func (p *fakePage) Navigate(_ context.Context, url string) error {
+	p.url = url
+	return nil
+}
+
+func (p *fakePage) CurrentURL() string { return p.url }
+
+func (p *fakePage) QueryElements(_ context.Context, req entity.QueryElementsRequest) (*entity.QueryElementsResult, error) {
+	texts := p.elements[req.Selector]
+	result := &entity.QueryElementsResult{Count: len(texts)}
+	for _, text := range texts {
+		result.Elements = append(result.Elements, entity.ElementData{Data: map[string]string{"_self": text}})
+	}
+	return result, nil
+}
+
+// fakeAgent answers by task ID and moves the page where the task ends.
+type fakeAgent struct {
+	page    *fakePage
+	answers map[string]string
+}
+
+func (a *fakeAgent) Execute(ctx context.Context, _ input.TaskRequest) (*input.ExecuteResult, error) {
+	id := runctx.TaskID(ctx)
+	if id == "broken" {
+		return nil, errors.New("max iterations reached")
+	}
+	a.page.url += "/done"
+	return &input.ExecuteResult{FinalAnswer: a.answers[id], Iterations: 4}, nil
+}
+
+func suite() []entity.EvalCase {
+	return []entity.EvalCase{
+		{
+			BatchTask: entity.BatchTask{ID: "price", Task: "find the price", StartURL: "http://fixtures/shop"},
+			Expect:    entity.EvalExpectation{AnswerContains: []string{"1 190"}, URLContains: "/shop/done"},
+		},
+		{
+			BatchTask: entity.BatchTask{ID: "cart", Task: "add to cart", StartURL: "http://fixtures/shop"},
+			Expect: entity.EvalExpectation{DOM: []entity.DOMCheck{
+				{Selector: "#cart li", TextContains: "toaster"},
+				{Selector: ".error", Absent: true},
+			}},
+		},
+		{
+			BatchTask: entity.BatchTask{ID: "broken", Task: "fail"},
+			Expect:    entity.EvalExpectation{AnswerContains: []string{"x"}},
+		},
+	}
+}
+
+func TestHarness_Run(t *testing.T) {
+	page := &fakePage{elements: map[string][]string{"#cart li": {"Kettle", "Toaster"}}}
+	agent := &fakeAgent{page: page, answers: map[string]string{"price": "The grinder costs 1 190 ₽"}}
+
+	var seen []string
+	harness := New(nil, nopLogger{}, Config{OnResult: func(result entity.EvalResult) { seen = append(seen, result.ID) }})
+	report, err := harness.Run(context.Background(), []Target{{
+		Model:         "model-a",
+		PromptVersion: "abc123",
+		Executor:      agent,
+		Browser:       page,
+		Usage:         func(string) entity.Usage { return entity.Usage{TotalTokens: 100, CostUSD: 0.01} },
+	}}, suite())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"price", "cart", "broken"}, seen)
+	require.Len(t, report.Results, 3)
+	assert.True(t, report.Results[0].Passed, report.Results[0].Failures)
+	assert.True(t, report.Results[1].Passed, report.Results[1].Failures)
+	assert.False(t, report.Results[2].Passed)
+	assert.Equal(t, []string{"task failed: max iterations reached"}, report.Results[2].Failures)
+
+	require.Len(t, report.Summaries, 1)
+	summary := report.Summaries[0]
+	assert.Equal(t, "model-a", summary.Model)
+	assert.Equal(t, 2, summary.Passed)
+	assert.InDelta(t, 2.0/3, summary.SuccessRate, 0.001)
+	assert.InDelta(t, 8.0/3, summary.AvgIterations, 0.001)
+	assert.Equal(t, 300, summary.Usage.TotalTokens)
+}
+
+func TestCheck_ReportsFailures(t *testing.T) {
+	page := &fakePage{url: "http://fixtures/docs/index.html", elements: map[string][]string{
+		"#result": {"Ошибка"},
+		".error":  {"required"},
+	}}
+	run := entity.BatchResult{Status: entity.TaskStatusCompleted, FinalAnswer: "Go 1.22"}
+
+	failures := Check(context.Background(), page, entity.EvalExpectation{
+		AnswerContains: []string{"1.24"},
+		AnswerMatches:  `^\d+$`,
+		URLContains:    "install",
+		DOM: []entity.DOMCheck{
+			{Selector: "#result", TextContains: "Спасибо"},
+			{Selector: "#missing"},
+			{Selector: ".error", Absent: true},
+		},
+	}, run)
+
+	assert.Equal(t, []string{
+		`answer does not contain "1.24"`,
+		`answer does not match "^\\d+$"`,
+		`url http://fixtures/docs/index.html does not contain "install"`,
+		`#result: no element contains "Спасибо" (found ["Ошибка"])`,
+		"#missing: no element found",
+		".error: expected no element, found 1",
+	}, failures)
+}
+
+func TestSummarize_GroupsByModelAndPrompts(t *testing.T) {
+	summaries := Summarize([]entity.EvalResult{
+		{Model: "a", PromptVersion: "v1", Passed: true, DurationMS: 1000},
+		{Model: "a", PromptVersion: "v2", Passed: false, DurationMS: 3000},
+		{Model: "a", PromptVersion: "v1", Passed: false, DurationMS: 2000},
+	})
+
+	require.Len(t, summaries, 2)
+	assert.Equal(t, "v1", summaries[0].PromptVersion)
+	assert.Equal(t, 2, summaries[0].Tasks)
+	assert.Equal(t, 0.5, summaries[0].SuccessRate)
+	assert.Equal(t, int64(1500), summaries[0].AvgDurationMS)
+	assert.Equal(t, 0.0, summaries[1].SuccessRate)
+}
@@ -0,0 +1,40 @@
+// Package checkpoint stops agent loops at the user's pause (Ctrl+C) after
+// each tool call.
+package checkpoint
+
+import (
+	"context"
+
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/domain/entity"
+)
+
+const guidancePrefix = "User guidance (added while execution was paused): "
+
+// Guidance collects what the user says at the checkpoints of one turn. It
+// is sent after all tool results of the turn, which the model expects
+// right after its tool calls.
+type Guidance []string
+
+// Wait is called after each tool call. It blocks while execution is paused
+// and keeps the user's guidance; it fails with output.ErrTaskAborted when
+// the user stops the task.
+func (g *Guidance) Wait(ctx context.Context, userInteraction output.UserInteractionPort) error {
+	guidance, err := userInteraction.Checkpoint(ctx)
+	if err != nil {
+		return err
+	}
+	if guidance != "" {
+		*g = append(*g, guidance)
+	}
+	return nil
+}
+
+// Messages returns the collected guidance as user messages.
+func (g Guidance) Messages() []entity.Message {
+	messages := make([]entity.Message, len(g))
+	for i, guidance := range g {
+		messages[i] = entity.Message{Role: entity.RoleUser, Content: guidancePrefix + guidance}
+	}
+	return messages
+}
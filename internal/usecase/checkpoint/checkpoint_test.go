@@ -0,0 +1,31 @@
+package checkpoint
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/domain/entity"
+	"browser-agent/internal/testkit"
+)
+
+func TestGuidance(t *testing.T) {
+	user := &testkit.User{Guidance: []string{"", "use the search box"}}
+
+	var guidance Guidance
+	require.NoError(t, guidance.Wait(context.Background(), user))
+	require.NoError(t, guidance.Wait(context.Background(), user))
+	require.NoError(t, guidance.Wait(context.Background(), user))
+	assert.Equal(t, []entity.Message{{
+		Role:    entity.RoleUser,
+		Content: "User guidance (added while execution was paused): use the search box",
+	}}, guidance.Messages())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, guidance.Wait(ctx, user), output.ErrTaskAborted)
+	assert.Empty(t, Guidance(nil).Messages())
+}
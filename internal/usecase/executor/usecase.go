@@ -7,6 +7,7 @@ import (
 	"browser-agent/internal/application/port/input"
 	"browser-agent/internal/application/port/output"
 	"browser-agent/internal/domain/entity"
+	"browser-agent/internal/usecase/checkpoint"
 )
 
 var _ input.TaskExecutor = (*UseCase)(nil)
@@ -40,16 +41,21 @@ func New(
 	}
 }
 
-func (uc *UseCase) Execute(ctx context.Context, task string) (*input.ExecuteResult, error) {
+func (uc *UseCase) Execute(ctx context.Context, req input.TaskRequest) (*input.ExecuteResult, error) {
+	iterations := maxIterations
+	if req.MaxIterations > 0 {
+		iterations = req.MaxIterations
+	}
+
 	messages := []entity.Message{
 		{Role: entity.RoleSystem, Content: uc.systemPrompt},
-		{Role: entity.RoleUser, Content: task},
+		{Role: entity.RoleUser, Content: req.Task},
 	}
 
 	toolDefs := uc.tools.Definitions()
 
-	for iteration := 1; iteration <= maxIterations; iteration++ {
-		uc.userInteraction.ShowIteration(ctx, iteration, maxIterations)
+	for iteration := 1; iteration <= iterations; iteration++ {
+		uc.userInteraction.ShowIteration(ctx, iteration, iterations)
 		uc.logger.Debug("Starting iteration", "iteration", iteration)
 
 		resp, err := uc.llm.Chat(ctx, output.ChatRequest{
@@ -74,6 +80,7 @@ func (uc *UseCase) Execute(ctx context.Context, task string) (*input.ExecuteResu
 			}, nil
 		}
 
+		var guidance checkpoint.Guidance
 		for _, tc := range resp.Message.ToolCalls {
 			uc.userInteraction.ShowToolStart(ctx, tc.Name, tc.Arguments)
 			observation := uc.executeTool(ctx, tc)
@@ -91,10 +98,15 @@ func (uc *UseCase) Execute(ctx context.Context, task string) (*input.ExecuteResu
 				Name:       tc.Name,
 				Content:    observation,
 			})
+
+			if err := guidance.Wait(ctx, uc.userInteraction); err != nil {
+				return nil, err
+			}
 		}
+		messages = append(messages, guidance.Messages()...)
 	}
 
-	return nil, fmt.Errorf("max iterations (%d) exceeded", maxIterations)
+	return nil, fmt.Errorf("max iterations (%d) exceeded", iterations)
 }
 
 func (uc *UseCase) executeTool(ctx context.Context, tc entity.ToolCall) string {
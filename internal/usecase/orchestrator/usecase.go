@@ -2,17 +2,19 @@ package orchestrator
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"browser-agent/internal/application/port/input"
 	"browser-agent/internal/application/port/output"
 	"browser-agent/internal/domain/entity"
 	"browser-agent/internal/infrastructure/prompts"
+	"browser-agent/internal/usecase/checkpoint"
 )
 
 const (
-	maxIterations     = 30
-	maxObservationLen = 20000
+	defaultMaxIterations = 30
+	maxObservationLen    = 20000
 )
 
 var _ input.TaskExecutor = (*UseCase)(nil)
@@ -24,6 +26,7 @@ type UseCase struct {
 	logger               output.LoggerPort
 	userInteraction      output.UserInteractionPort
 	systemPromptTemplate string
+	maxIterations        int
 }
 
 func New(
@@ -41,11 +44,30 @@ func New(
 		logger:               logger,
 		userInteraction:      userInteraction,
 		systemPromptTemplate: systemPromptTemplate,
+		maxIterations:        defaultMaxIterations,
 	}
 }
 
-func (uc *UseCase) Execute(ctx context.Context, task string) (*input.ExecuteResult, error) {
-	uc.logger.Info("Orchestrator executing task", "task", task)
+// SetMaxIterations sets the limit for requests that do not carry their own;
+// values below 1 are ignored.
+func (uc *UseCase) SetMaxIterations(n int) {
+	if n > 0 {
+		uc.maxIterations = n
+	}
+}
+
+func (uc *UseCase) Execute(ctx context.Context, req input.TaskRequest) (*input.ExecuteResult, error) {
+	uc.logger.Info("Orchestrator executing task", "task", req.Task)
+
+	iterations := uc.maxIterations
+	if req.MaxIterations > 0 {
+		iterations = req.MaxIterations
+	}
+
+	task, err := taskMessage(req)
+	if err != nil {
+		return nil, err
+	}
 
 	systemPrompt, err := prompts.GenerateOrchestratorPrompt(uc.systemPromptTemplate, uc.agentRegistry)
 	if err != nil {
@@ -59,8 +81,8 @@ func (uc *UseCase) Execute(ctx context.Context, task string) (*input.ExecuteResu
 
 	toolDefs := uc.agentTools.Definitions()
 
-	for iter := 1; iter <= maxIterations; iter++ {
-		uc.userInteraction.ShowIteration(ctx, iter, maxIterations)
+	for iter := 1; iter <= iterations; iter++ {
+		uc.userInteraction.ShowIteration(ctx, iter, iterations)
 		uc.logger.Debug("Orchestrator iteration", "iteration", iter)
 
 		resp, err := uc.llm.Chat(ctx, output.ChatRequest{
@@ -86,6 +108,7 @@ func (uc *UseCase) Execute(ctx context.Context, task string) (*input.ExecuteResu
 			}, nil
 		}
 
+		var guidance checkpoint.Guidance
 		for _, tc := range resp.Message.ToolCalls {
 			uc.userInteraction.ShowToolStart(ctx, tc.Name, tc.Arguments)
 			observation := uc.executeTool(ctx, tc)
@@ -103,10 +126,35 @@ func (uc *UseCase) Execute(ctx context.Context, task string) (*input.ExecuteResu
 				Name:       tc.Name,
 				Content:    observation,
 			})
+
+			if err := guidance.Wait(ctx, uc.userInteraction); err != nil {
+				return nil, err
+			}
 		}
+		messages = append(messages, guidance.Messages()...)
+	}
+
+	return nil, fmt.Errorf("max iterations (%d) exceeded", iterations)
+}
+
+// taskMessage appends the output schema, if any, to the task so the final
+// answer comes back as machine-readable JSON.
+func taskMessage(req input.TaskRequest) (string, error) {
+	task := req.Task
+	if req.StartURL != "" {
+		task += "\n\nStart at " + req.StartURL + "."
+	}
+	if len(req.OutputSchema) == 0 {
+		return task, nil
+	}
+
+	schema, err := json.MarshalIndent(req.OutputSchema, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("invalid output schema: %w", err)
 	}
 
-	return nil, fmt.Errorf("max iterations (%d) exceeded", maxIterations)
+	return task + "\n\nYour FINAL answer must be a single JSON value matching this JSON schema, " +
+		"with no other text and no code fences:\n" + string(schema), nil
 }
 
 func (uc *UseCase) executeTool(ctx context.Context, tc entity.ToolCall) string {
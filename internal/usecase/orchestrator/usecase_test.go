@@ -0,0 +1,155 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tool "browser-agent/internal/adapter/tools"
+	"browser-agent/internal/application/port/input"
+	"browser-agent/internal/application/service"
+	"browser-agent/internal/domain/entity"
+	"browser-agent/internal/infrastructure/prompts"
+	"browser-agent/internal/testkit"
+	"browser-agent/internal/usecase/agents/navigation"
+)
+
+const site = `
+pages:
+  home:
+    url: https://news.test/
+    title: News
+    elements:
+      - {selector: "#top", tag: a, text: Top story, href: https://news.test/top}
+  top:
+    url: https://news.test/top
+    title: Top story
+    text: Go 1.24 released
+`
+
+type fixture struct {
+	llm     *testkit.ScriptedLLM
+	browser *testkit.Browser
+	tools   *testkit.ToolRecorder
+	user    *testkit.User
+	uc      *UseCase
+}
+
+// newFixture wires the orchestrator to a real navigation agent whose browser
+// tools drive a fake browser.
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	logger := testkit.NopLogger{}
+	user := &testkit.User{}
+	f := &fixture{llm: testkit.NewScriptedLLM(), browser: testkit.NewBrowser(testkit.MustParseSite(site)), user: user}
+
+	browserTools := service.NewToolRegistry()
+	browserTools.Register(tool.NewNavigateTool(f.browser, logger))
+	browserTools.Register(tool.NewObserveTool(f.browser, logger))
+	f.tools = testkit.NewToolRecorder(browserTools)
+
+	agents := service.NewSimpleAgentRegistry()
+	agents.Register(navigation.New(f.llm, f.tools, logger, user, prompts.Default().Navigation))
+
+	agentTools := service.NewToolRegistry()
+	agentTools.Register(tool.NewRunAgentTool(agents, logger))
+	recorded := testkit.NewToolRecorder(agentTools)
+
+	f.uc = New(f.llm, recorded, agents, logger, user, prompts.Default().Orchestrator)
+	t.Cleanup(func() { f.llm.AssertExhausted(t) })
+	return f
+}
+
+func TestExecuteDelegatesToSubAgent(t *testing.T) {
+	f := newFixture(t)
+	f.llm.Script(testkit.Orchestrator,
+		testkit.CallTool(entity.ToolRunAgent, map[string]string{"agent_type": "navigation", "task": "open the top story"}),
+		testkit.Answer("The top story is: Go 1.24 released"),
+	)
+	f.llm.Script("navigation",
+		testkit.CallTool(entity.ToolBrowserNavigate, map[string]string{"url": "https://news.test/"}),
+		testkit.CallTool(entity.ToolBrowserNavigate, map[string]string{"url": "https://news.test/top"}),
+		testkit.Answer("Opened https://news.test/top"),
+	)
+
+	result, err := f.uc.Execute(context.Background(), input.TaskRequest{Task: "what is the top story?"})
+	require.NoError(t, err)
+	assert.Equal(t, "The top story is: Go 1.24 released", result.FinalAnswer)
+	assert.Equal(t, 2, result.Iterations)
+
+	calls := f.tools.Calls()
+	testkit.AssertToolSequence(t, calls, entity.ToolBrowserNavigate, entity.ToolBrowserNavigate)
+	assert.Equal(t, "navigation", calls[0].Agent)
+	assert.Equal(t, []string{"navigate https://news.test/", "navigate https://news.test/top"}, f.browser.Actions())
+
+	// The sub-agent's answer comes back to the orchestrator as the tool result.
+	second := f.llm.CallsBy(testkit.Orchestrator)[1].Request.Messages
+	last := second[len(second)-1]
+	assert.Equal(t, entity.RoleTool, last.Role)
+	assert.Equal(t, "Opened https://news.test/top", last.Content)
+}
+
+func TestExecuteReportsToolErrorsToLLM(t *testing.T) {
+	f := newFixture(t)
+	f.llm.Script(testkit.Orchestrator,
+		testkit.CallTool(entity.ToolRunAgent, map[string]string{"agent_type": "form", "task": "fill it"}),
+		testkit.CallTool("no_such_tool", "{}"),
+		testkit.Answer("gave up"),
+	)
+
+	result, err := f.uc.Execute(context.Background(), input.TaskRequest{Task: "fill the form"})
+	require.NoError(t, err)
+	assert.Equal(t, "gave up", result.FinalAnswer)
+
+	calls := f.llm.CallsBy(testkit.Orchestrator)
+	require.Len(t, calls, 3)
+	second := calls[1].Request.Messages
+	assert.Equal(t, "Error: agent not found: form", second[len(second)-1].Content)
+	third := calls[2].Request.Messages
+	assert.Equal(t, "Error: unknown agent tool 'no_such_tool'", third[len(third)-1].Content)
+}
+
+func TestExecuteSendsGuidanceAfterToolResults(t *testing.T) {
+	f := newFixture(t)
+	f.user.Guidance = []string{"check the sports section"}
+	f.llm.Script(testkit.Orchestrator,
+		testkit.CallTools(testkit.Call("no_such_tool", "{}"), testkit.Call("other_tool", "{}")),
+		testkit.Answer("done"),
+	)
+
+	_, err := f.uc.Execute(context.Background(), input.TaskRequest{Task: "news"})
+	require.NoError(t, err)
+
+	// The pause after the first call waits for the second result, since
+	// tool results must directly follow their calls.
+	second := f.llm.CallsBy(testkit.Orchestrator)[1].Request.Messages
+	require.GreaterOrEqual(t, len(second), 3)
+	tail := second[len(second)-3:]
+	assert.Equal(t, entity.RoleTool, tail[0].Role)
+	assert.Equal(t, entity.RoleTool, tail[1].Role)
+	assert.Equal(t, entity.RoleUser, tail[2].Role)
+	assert.Contains(t, tail[2].Content, "check the sports section")
+}
+
+func TestExecuteStopsAtMaxIterations(t *testing.T) {
+	f := newFixture(t)
+	f.uc.SetMaxIterations(5)
+	f.llm.Script(testkit.Orchestrator,
+		testkit.CallTool("no_such_tool", "{}"),
+		testkit.CallTool("no_such_tool", "{}"),
+	)
+
+	_, err := f.uc.Execute(context.Background(), input.TaskRequest{Task: "loop", MaxIterations: 2})
+	assert.EqualError(t, err, "max iterations (2) exceeded")
+}
+
+func TestExecuteFailsOnLLMError(t *testing.T) {
+	f := newFixture(t)
+	f.llm.Script(testkit.Orchestrator, testkit.Fail(errors.New("quota exceeded")))
+
+	_, err := f.uc.Execute(context.Background(), input.TaskRequest{Task: "anything"})
+	assert.EqualError(t, err, "llm request failed: quota exceeded")
+}
@@ -0,0 +1,243 @@
+// Package replay re-executes the browser tool calls of a recorded run
+// without the LLM, turning a successful run into a repeatable script and a
+// regression check.
+package replay
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"browser-agent/internal/application/port/input"
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/domain/entity"
+)
+
+var _ input.Replayer = (*Replayer)(nil)
+
+const (
+	browserToolPrefix = "browser_"
+	truncatedSuffix   = "\n... (truncated)"
+	redactedMarker    = "[REDACTED"
+	maxSnippetLen     = 300
+)
+
+// dataTools read the page; their results are compared with the recording.
+// Other tools act on the page and only their success is compared.
+var dataTools = map[entity.ToolName]bool{
+	entity.ToolBrowserObserve:       true,
+	entity.ToolBrowserQueryElements: true,
+	entity.ToolBrowserSearch:        true,
+}
+
+type Config struct {
+	// StartURL is opened before the first step when set.
+	StartURL string
+	// KeepGoing continues after a step fails. By default the replay stops
+	// there, since the following steps would act on the wrong page.
+	KeepGoing bool
+	// OnStep is called after each step, e.g. to report progress.
+	OnStep func(step entity.ReplayStep)
+}
+
+type Replayer struct {
+	tools    output.ToolRegistry
+	browser  output.BrowserPort
+	redactor output.Redactor
+	logger   output.LoggerPort
+	config   Config
+}
+
+// New replays with tools, which must contain the browser tools. redactor
+// may be nil; when set it is applied to results before they are compared
+// with the recording, which was redacted the same way.
+func New(tools output.ToolRegistry, browser output.BrowserPort, redactor output.Redactor, logger output.LoggerPort, config Config) *Replayer {
+	return &Replayer{
+		tools:    tools,
+		browser:  browser,
+		redactor: redactor,
+		logger:   logger,
+		config:   config,
+	}
+}
+
+// step is a browser tool call of the transcript; checkURL is set on the
+// last call of a turn, after which the page URL was recorded.
+type step struct {
+	agent    string
+	call     entity.TranscriptToolCall
+	checkURL string
+}
+
+func (r *Replayer) Replay(ctx context.Context, transcript *entity.Transcript) (*entity.ReplayReport, error) {
+	started := time.Now()
+	report := &entity.ReplayReport{Task: transcript.Task}
+
+	steps := plan(transcript)
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("transcript has no browser tool calls to replay")
+	}
+
+	if r.config.StartURL != "" {
+		if err := r.browser.Navigate(ctx, r.config.StartURL); err != nil {
+			return nil, fmt.Errorf("open start url: %w", err)
+		}
+	}
+
+	stopped := false
+	for i, s := range steps {
+		result := entity.ReplayStep{
+			Index:     i + 1,
+			Agent:     s.agent,
+			Tool:      s.call.Name,
+			Arguments: s.call.Arguments,
+		}
+
+		switch {
+		case stopped:
+			result.Status = entity.ReplayStepNotRun
+		case ctx.Err() != nil:
+			return nil, ctx.Err()
+		default:
+			stepStarted := time.Now()
+			r.runStep(ctx, s, &result)
+			result.DurationMS = time.Since(stepStarted).Milliseconds()
+			stopped = result.Kind == entity.DivergenceError && !r.config.KeepGoing
+		}
+
+		switch result.Status {
+		case entity.ReplayStepMatched:
+			report.Matched++
+		case entity.ReplayStepDiverged:
+			report.Diverged++
+		case entity.ReplayStepSkipped:
+			report.Skipped++
+		}
+		report.Steps = append(report.Steps, result)
+		if r.config.OnStep != nil {
+			r.config.OnStep(result)
+		}
+	}
+
+	report.DurationMS = time.Since(started).Milliseconds()
+	r.logger.Info("Replay finished", "steps", len(steps), "matched", report.Matched, "diverged", report.Diverged, "skipped", report.Skipped)
+	return report, nil
+}
+
+func (r *Replayer) runStep(ctx context.Context, s step, result *entity.ReplayStep) {
+	if strings.Contains(s.call.Arguments, redactedMarker) {
+		result.Status = entity.ReplayStepSkipped
+		result.Expected = "arguments were redacted in the transcript"
+		return
+	}
+
+	tool, ok := r.tools.Get(entity.ToolName(s.call.Name))
+	if !ok {
+		result.Status = entity.ReplayStepSkipped
+		result.Expected = "tool is not available"
+		return
+	}
+
+	r.logger.Debug("Replaying tool", "index", result.Index, "name", s.call.Name, "args", s.call.Arguments)
+	actual, err := tool.Execute(ctx, s.call.Arguments)
+	actual = r.redact(actual)
+
+	result.Status = entity.ReplayStepDiverged
+	switch {
+	case err != nil && s.call.IsError:
+		result.Status = entity.ReplayStepMatched
+	case err != nil:
+		result.Kind = entity.DivergenceError
+		result.Expected = "success"
+		result.Actual = r.redact(err.Error())
+	case s.call.IsError:
+		result.Kind = entity.DivergenceSucceeded
+		result.Expected = snippet(s.call.Result)
+		result.Actual = "success"
+	case dataTools[entity.ToolName(s.call.Name)] && !sameData(s.call.Result, actual):
+		result.Kind = entity.DivergenceData
+		result.Expected, result.Actual = firstDifference(s.call.Result, actual)
+	case s.checkURL != "" && !sameURL(s.checkURL, r.browser.CurrentURL()):
+		result.Kind = entity.DivergenceURL
+		result.Expected = s.checkURL
+		result.Actual = r.browser.CurrentURL()
+	default:
+		result.Status = entity.ReplayStepMatched
+	}
+
+	if result.Status == entity.ReplayStepDiverged {
+		r.logger.Warn("Replay step diverged", "index", result.Index, "name", s.call.Name, "kind", result.Kind)
+	}
+}
+
+func (r *Replayer) redact(text string) string {
+	if r.redactor == nil {
+		return text
+	}
+	return r.redactor.Redact(text)
+}
+
+// plan lists the browser tool calls of the transcript in execution order.
+func plan(transcript *entity.Transcript) []step {
+	var steps []step
+	for _, turn := range transcript.Turns {
+		last := -1
+		for _, call := range turn.ToolCalls {
+			if !strings.HasPrefix(call.Name, browserToolPrefix) {
+				continue
+			}
+			steps = append(steps, step{agent: turn.Agent, call: call})
+			last = len(steps) - 1
+		}
+		if last >= 0 {
+			steps[last].checkURL = turn.URL
+		}
+	}
+	return steps
+}
+
+// sameData compares tool output ignoring whitespace differences. Recorded
+// results may have been truncated; then only the recorded part counts.
+func sameData(recorded, actual string) bool {
+	if prefix, truncated := strings.CutSuffix(recorded, truncatedSuffix); truncated {
+		return strings.HasPrefix(normalize(actual), normalize(prefix))
+	}
+	return normalize(recorded) == normalize(actual)
+}
+
+func normalize(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func sameURL(expected, actual string) bool {
+	return strings.TrimSuffix(expected, "/") == strings.TrimSuffix(actual, "/")
+}
+
+// firstDifference returns the first lines that differ.
+func firstDifference(expected, actual string) (string, string) {
+	expectedLines := strings.Split(strings.TrimSuffix(expected, truncatedSuffix), "\n")
+	actualLines := strings.Split(actual, "\n")
+
+	for i := 0; i < max(len(expectedLines), len(actualLines)); i++ {
+		var e, a string
+		if i < len(expectedLines) {
+			e = expectedLines[i]
+		}
+		if i < len(actualLines) {
+			a = actualLines[i]
+		}
+		if normalize(e) != normalize(a) {
+			return fmt.Sprintf("line %d: %s", i+1, snippet(e)), fmt.Sprintf("line %d: %s", i+1, snippet(a))
+		}
+	}
+	return snippet(expected), snippet(actual)
+}
+
+func snippet(text string) string {
+	runes := []rune(strings.TrimSpace(text))
+	if len(runes) > maxSnippetLen {
+		return string(runes[:maxSnippetLen]) + "..."
+	}
+	return string(runes)
+}
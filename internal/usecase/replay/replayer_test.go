@@ -0,0 +1,170 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/application/service"
+	"browser-agent/internal/domain/entity"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...any)                          {}
+func (nopLogger) Info(string, ...any)                           {}
+func (nopLogger) Warn(string, ...any)                           {}
+func (nopLogger) Error(string, ...any)                          {}
+func (l nopLogger) WithField(string, any) output.LoggerPort     { return l }
+func (l nopLogger) WithFields(map[string]any) output.LoggerPort { return l }
+func (nopLogger) Close() error                                  { return nil }
+
+// fakeBrowser only tracks the URL; the tools below act on it.
+type fakeBrowser struct {
+	output.BrowserPort
+	url string
+}
+
+func (b *fakeBrowser) Navigate(_ context.Context, url string) error {
+	b.url = url
+	return nil
+}
+
+func (b *fakeBrowser) CurrentURL() string { return b.url }
+
+type fakeTool struct {
+	name entity.ToolName
+	run  func(args string) (string, error)
+}
+
+func (t fakeTool) Name() entity.ToolName                                  { return t.name }
+func (t fakeTool) Description() string                                    { return "" }
+func (t fakeTool) Parameters() map[string]any                             { return nil }
+func (t fakeTool) Execute(_ context.Context, args string) (string, error) { return t.run(args) }
+
+func newTools(browser *fakeBrowser, page map[string]string) *service.ToolRegistryImpl {
+	tools := service.NewToolRegistry()
+	tools.Register(fakeTool{name: entity.ToolBrowserNavigate, run: func(args string) (string, error) {
+		browser.url = args
+		return "Navigated to " + args, nil
+	}})
+	tools.Register(fakeTool{name: entity.ToolBrowserClick, run: func(args string) (string, error) {
+		target, ok := page[args]
+		if !ok {
+			return "", errors.New("element not found: " + args)
+		}
+		browser.url = target
+		return "Click successful", nil
+	}})
+	tools.Register(fakeTool{name: entity.ToolBrowserObserve, run: func(string) (string, error) {
+		return "Title: Shop\nPrice: " + page["price"], nil
+	}})
+	return tools
+}
+
+func recording() *entity.Transcript {
+	return &entity.Transcript{
+		Task: "buy",
+		Turns: []entity.TranscriptTurn{
+			{Agent: "orchestrator", ToolCalls: []entity.TranscriptToolCall{{Name: "run_agent", Arguments: "{}"}}},
+			{
+				Agent: "navigation",
+				URL:   "https://shop.test/item",
+				ToolCalls: []entity.TranscriptToolCall{
+					{Name: "browser_navigate", Arguments: "https://shop.test", Result: "Navigated to https://shop.test"},
+					{Name: "browser_click", Arguments: "#item", Result: "Click successful"},
+				},
+			},
+			{
+				Agent: "extraction",
+				ToolCalls: []entity.TranscriptToolCall{
+					{Name: "browser_observe", Arguments: "{}", Result: "Title: Shop\nPrice:   10 EUR"},
+					{Name: "browser_click", Arguments: "#missing", Result: "Error: element not found", IsError: true},
+				},
+			},
+		},
+	}
+}
+
+func TestReplayer_MatchesRecording(t *testing.T) {
+	browser := &fakeBrowser{}
+	tools := newTools(browser, map[string]string{"#item": "https://shop.test/item/", "price": "10 EUR"})
+
+	var seen []int
+	report, err := New(tools, browser, nil, nopLogger{}, Config{
+		OnStep: func(step entity.ReplayStep) { seen = append(seen, step.Index) },
+	}).Replay(context.Background(), recording())
+	require.NoError(t, err)
+
+	assert.True(t, report.Passed(), "%+v", report.Steps)
+	assert.Equal(t, 4, report.Matched)
+	assert.Equal(t, []int{1, 2, 3, 4}, seen)
+	assert.Equal(t, "browser_navigate", report.Steps[0].Tool, "run_agent is not replayed")
+}
+
+func TestReplayer_ReportsDivergences(t *testing.T) {
+	browser := &fakeBrowser{}
+	tools := newTools(browser, map[string]string{"#item": "https://shop.test/sold-out", "price": "12 EUR", "#missing": "x"})
+
+	report, err := New(tools, browser, nil, nopLogger{}, Config{}).Replay(context.Background(), recording())
+	require.NoError(t, err)
+	require.Len(t, report.Steps, 4)
+
+	assert.False(t, report.Passed())
+	assert.Equal(t, 3, report.Diverged)
+
+	assert.Equal(t, entity.DivergenceURL, report.Steps[1].Kind)
+	assert.Equal(t, "https://shop.test/item", report.Steps[1].Expected)
+	assert.Equal(t, "https://shop.test/sold-out", report.Steps[1].Actual)
+
+	assert.Equal(t, entity.DivergenceData, report.Steps[2].Kind)
+	assert.Equal(t, "line 2: Price:   10 EUR", report.Steps[2].Expected)
+	assert.Equal(t, "line 2: Price: 12 EUR", report.Steps[2].Actual)
+
+	assert.Equal(t, entity.DivergenceSucceeded, report.Steps[3].Kind)
+}
+
+func TestReplayer_StopsAfterFailedStep(t *testing.T) {
+	browser := &fakeBrowser{}
+	tools := newTools(browser, map[string]string{"price": "10 EUR"})
+
+	report, err := New(tools, browser, nil, nopLogger{}, Config{StartURL: "https://shop.test"}).Replay(context.Background(), recording())
+	require.NoError(t, err)
+
+	assert.Equal(t, entity.DivergenceError, report.Steps[1].Kind)
+	assert.Equal(t, "element not found: #item", report.Steps[1].Actual)
+	assert.Equal(t, entity.ReplayStepNotRun, report.Steps[2].Status)
+	assert.Equal(t, entity.ReplayStepNotRun, report.Steps[3].Status)
+
+	report, err = New(tools, browser, nil, nopLogger{}, Config{KeepGoing: true}).Replay(context.Background(), recording())
+	require.NoError(t, err)
+	assert.Equal(t, entity.ReplayStepMatched, report.Steps[2].Status)
+}
+
+func TestReplayer_SkipsRedactedArguments(t *testing.T) {
+	browser := &fakeBrowser{}
+	recorded := &entity.Transcript{Turns: []entity.TranscriptTurn{{
+		Agent: "form",
+		ToolCalls: []entity.TranscriptToolCall{
+			{Name: "browser_fill", Arguments: `{"text":"[REDACTED:email]"}`},
+			{Name: "browser_unknown", Arguments: "{}"},
+		},
+	}}}
+
+	report, err := New(newTools(browser, nil), browser, nil, nopLogger{}, Config{}).Replay(context.Background(), recorded)
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Skipped)
+	assert.True(t, report.Passed())
+
+	_, err = New(newTools(browser, nil), browser, nil, nopLogger{}, Config{}).Replay(context.Background(), &entity.Transcript{})
+	assert.Error(t, err)
+}
+
+func TestSameData_Truncated(t *testing.T) {
+	assert.True(t, sameData("a b\n... (truncated)", "a   b c d"))
+	assert.False(t, sameData("a b\n... (truncated)", "a c"))
+}
@@ -0,0 +1,299 @@
+package taskmanager
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"browser-agent/internal/application/port/input"
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/application/runctx"
+	"browser-agent/internal/domain/entity"
+)
+
+var _ input.TaskManager = (*Manager)(nil)
+
+type Config struct {
+	// Workers is the number of tasks executed at once. The executor must
+	// give each of them its own browser, e.g. from a browser pool.
+	Workers     int
+	QueueSize   int
+	TaskTimeout time.Duration
+	// MaxFinished is how many finished tasks are kept for status queries.
+	MaxFinished int
+	// OnFinish is called with every task that reaches a final status.
+	OnFinish func(task entity.Task)
+	// OnEvict is called when a finished task is dropped from memory.
+	OnEvict func(taskID string)
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Workers:     1,
+		QueueSize:   100,
+		TaskTimeout: 30 * time.Minute,
+		MaxFinished: 200,
+	}
+}
+
+// Manager queues tasks and runs them on a TaskExecutor in the background,
+// publishing status changes as task events.
+type Manager struct {
+	executor input.TaskExecutor
+	events   output.EventPublisher
+	logger   output.LoggerPort
+	config   Config
+
+	mu       sync.Mutex
+	tasks    map[string]*taskState
+	finished []string
+	queue    chan *taskState
+	wg       sync.WaitGroup
+}
+
+type taskState struct {
+	task   entity.Task
+	cancel context.CancelFunc
+}
+
+func New(executor input.TaskExecutor, events output.EventPublisher, logger output.LoggerPort, config Config) *Manager {
+	defaults := DefaultConfig()
+	if config.Workers <= 0 {
+		config.Workers = defaults.Workers
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaults.QueueSize
+	}
+	if config.TaskTimeout <= 0 {
+		config.TaskTimeout = defaults.TaskTimeout
+	}
+	if config.MaxFinished <= 0 {
+		config.MaxFinished = defaults.MaxFinished
+	}
+
+	return &Manager{
+		executor: executor,
+		events:   events,
+		logger:   logger,
+		config:   config,
+		tasks:    make(map[string]*taskState),
+		queue:    make(chan *taskState, config.QueueSize),
+	}
+}
+
+// Start launches the workers. Canceling ctx cancels running tasks and stops
+// the workers; Wait blocks until they are done.
+func (m *Manager) Start(ctx context.Context) {
+	for i := 0; i < m.config.Workers; i++ {
+		m.wg.Add(1)
+		go m.worker(ctx)
+	}
+}
+
+func (m *Manager) Wait() {
+	m.wg.Wait()
+}
+
+func (m *Manager) Submit(description string) (entity.Task, error) {
+	description = strings.TrimSpace(description)
+	if description == "" {
+		return entity.Task{}, errors.New("task description is empty")
+	}
+
+	state := &taskState{
+		task: entity.Task{
+			ID:          newTaskID(),
+			Description: description,
+			Status:      entity.TaskStatusPending,
+			CreatedAt:   time.Now(),
+		},
+	}
+
+	m.mu.Lock()
+	select {
+	case m.queue <- state:
+		m.tasks[state.task.ID] = state
+	default:
+		m.mu.Unlock()
+		return entity.Task{}, input.ErrQueueFull
+	}
+	task := state.task
+	m.mu.Unlock()
+
+	m.logger.Info("Task submitted", "taskId", task.ID)
+	m.publishStatus(task)
+	return task, nil
+}
+
+func (m *Manager) Get(id string) (entity.Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, ok := m.tasks[id]
+	if !ok {
+		return entity.Task{}, input.ErrTaskNotFound
+	}
+	return state.task, nil
+}
+
+// List returns all known tasks, newest first.
+func (m *Manager) List() []entity.Task {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := make([]entity.Task, 0, len(m.tasks))
+	for _, state := range m.tasks {
+		result = append(result, state.task)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+	return result
+}
+
+// Cancel stops a running task or removes a pending one from the queue.
+func (m *Manager) Cancel(id string) error {
+	m.mu.Lock()
+	state, ok := m.tasks[id]
+	if !ok {
+		m.mu.Unlock()
+		return input.ErrTaskNotFound
+	}
+	if state.task.Status.Finished() {
+		m.mu.Unlock()
+		return input.ErrTaskFinished
+	}
+
+	if state.cancel != nil {
+		cancel := state.cancel
+		m.mu.Unlock()
+		cancel()
+		return nil
+	}
+
+	// Still queued: the worker skips tasks that are already finished.
+	task, evicted := m.finishLocked(state, func(t *entity.Task) {
+		t.Status = entity.TaskStatusCanceled
+	})
+	m.mu.Unlock()
+
+	m.reportFinished(task, evicted)
+	m.publishStatus(task)
+	return nil
+}
+
+func (m *Manager) worker(ctx context.Context) {
+	defer m.wg.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case state := <-m.queue:
+			m.run(ctx, state)
+		}
+	}
+}
+
+func (m *Manager) run(ctx context.Context, state *taskState) {
+	taskCtx, cancel := context.WithTimeout(runctx.WithTaskID(ctx, state.task.ID), m.config.TaskTimeout)
+	defer cancel()
+
+	m.mu.Lock()
+	if state.task.Status != entity.TaskStatusPending {
+		m.mu.Unlock()
+		return
+	}
+	now := time.Now()
+	state.task.Status = entity.TaskStatusRunning
+	state.task.StartedAt = &now
+	state.cancel = cancel
+	task := state.task
+	m.mu.Unlock()
+
+	m.logger.Info("Task started", "taskId", task.ID)
+	m.publishStatus(task)
+
+	result, err := m.executor.Execute(taskCtx, input.TaskRequest{Task: task.Description})
+
+	m.mu.Lock()
+	task, evicted := m.finishLocked(state, func(t *entity.Task) {
+		switch {
+		case err == nil:
+			t.Status = entity.TaskStatusCompleted
+			t.FinalAnswer = result.FinalAnswer
+			t.Iterations = result.Iterations
+		case errors.Is(err, output.ErrTaskAborted) || errors.Is(taskCtx.Err(), context.Canceled):
+			t.Status = entity.TaskStatusCanceled
+			t.Error = err.Error()
+		case errors.Is(taskCtx.Err(), context.DeadlineExceeded):
+			t.Status = entity.TaskStatusFailed
+			t.Error = fmt.Sprintf("task timed out after %s", m.config.TaskTimeout)
+		default:
+			t.Status = entity.TaskStatusFailed
+			t.Error = err.Error()
+		}
+	})
+	m.mu.Unlock()
+
+	m.reportFinished(task, evicted)
+	if err != nil {
+		m.logger.Warn("Task finished with error", "taskId", task.ID, "status", task.Status, "error", err)
+	} else {
+		m.logger.Info("Task completed", "taskId", task.ID, "iterations", task.Iterations)
+	}
+	m.publishStatus(task)
+}
+
+// finishLocked applies update, stamps the finish time and evicts the oldest
+// finished tasks beyond MaxFinished, returning the IDs it evicted. m.mu must
+// be held; the callbacks run later in reportFinished, once it is released.
+func (m *Manager) finishLocked(state *taskState, update func(*entity.Task)) (entity.Task, []string) {
+	update(&state.task)
+	now := time.Now()
+	state.task.FinishedAt = &now
+	state.cancel = nil
+
+	var evicted []string
+	m.finished = append(m.finished, state.task.ID)
+	for len(m.finished) > m.config.MaxFinished {
+		evicted = append(evicted, m.finished[0])
+		m.finished = m.finished[1:]
+		delete(m.tasks, evicted[len(evicted)-1])
+	}
+	return state.task, evicted
+}
+
+// reportFinished reports a task returned by finishLocked to OnFinish and the tasks
+// it evicted to OnEvict. It must be called without m.mu held, so callbacks
+// may call back into the manager.
+func (m *Manager) reportFinished(task entity.Task, evicted []string) {
+	if m.config.OnFinish != nil {
+		m.config.OnFinish(task)
+	}
+	if m.config.OnEvict != nil {
+		for _, id := range evicted {
+			m.config.OnEvict(id)
+		}
+	}
+}
+
+func (m *Manager) publishStatus(task entity.Task) {
+	m.events.Publish(entity.Event{
+		TaskID: task.ID,
+		Type:   entity.EventTaskStatus,
+		Data:   map[string]any{"task": task},
+	})
+}
+
+func newTaskID() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,151 @@
+package taskmanager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"browser-agent/internal/application/port/input"
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/application/runctx"
+	"browser-agent/internal/domain/entity"
+	"browser-agent/internal/infrastructure/eventbus"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...any)                          {}
+func (nopLogger) Info(string, ...any)                           {}
+func (nopLogger) Warn(string, ...any)                           {}
+func (nopLogger) Error(string, ...any)                          {}
+func (l nopLogger) WithField(string, any) output.LoggerPort     { return l }
+func (l nopLogger) WithFields(map[string]any) output.LoggerPort { return l }
+func (nopLogger) Close() error                                  { return nil }
+
+type executorFunc func(ctx context.Context, task string) (*input.ExecuteResult, error)
+
+func (f executorFunc) Execute(ctx context.Context, req input.TaskRequest) (*input.ExecuteResult, error) {
+	return f(ctx, req.Task)
+}
+
+func waitStatus(t *testing.T, m *Manager, id string, status entity.TaskStatus) entity.Task {
+	t.Helper()
+	var task entity.Task
+	require.Eventually(t, func() bool {
+		task, _ = m.Get(id)
+		return task.Status == status
+	}, 2*time.Second, 5*time.Millisecond)
+	return task
+}
+
+func TestManager_RunsTaskToCompletion(t *testing.T) {
+	bus := eventbus.New()
+	executor := executorFunc(func(ctx context.Context, task string) (*input.ExecuteResult, error) {
+		assert.NotEmpty(t, runctx.TaskID(ctx))
+		return &input.ExecuteResult{FinalAnswer: "done: " + task, Iterations: 3}, nil
+	})
+
+	m := New(executor, bus, nopLogger{}, DefaultConfig())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m.Start(ctx)
+
+	task, err := m.Submit("  open example.com ")
+	require.NoError(t, err)
+	assert.Equal(t, "open example.com", task.Description)
+
+	done := waitStatus(t, m, task.ID, entity.TaskStatusCompleted)
+	assert.Equal(t, "done: open example.com", done.FinalAnswer)
+	assert.Equal(t, 3, done.Iterations)
+	assert.NotNil(t, done.FinishedAt)
+
+	history, _, unsubscribe := bus.Subscribe(task.ID)
+	unsubscribe()
+	require.Len(t, history, 3)
+	assert.Equal(t, entity.EventTaskStatus, history[2].Type)
+}
+
+func TestManager_CancelRunningAndQueued(t *testing.T) {
+	started := make(chan struct{})
+	executor := executorFunc(func(ctx context.Context, task string) (*input.ExecuteResult, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, errors.New("stopped")
+	})
+
+	m := New(executor, eventbus.New(), nopLogger{}, DefaultConfig())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m.Start(ctx)
+
+	running, err := m.Submit("first")
+	require.NoError(t, err)
+	queued, err := m.Submit("second")
+	require.NoError(t, err)
+
+	<-started
+	require.NoError(t, m.Cancel(queued.ID))
+	assert.Equal(t, entity.TaskStatusCanceled, waitStatus(t, m, queued.ID, entity.TaskStatusCanceled).Status)
+
+	require.NoError(t, m.Cancel(running.ID))
+	waitStatus(t, m, running.ID, entity.TaskStatusCanceled)
+
+	assert.ErrorIs(t, m.Cancel(running.ID), input.ErrTaskFinished)
+	assert.ErrorIs(t, m.Cancel("missing"), input.ErrTaskNotFound)
+}
+
+func TestManager_TimeoutAndEviction(t *testing.T) {
+	var evicted, finished []string
+	executor := executorFunc(func(ctx context.Context, task string) (*input.ExecuteResult, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	var m *Manager
+	m = New(executor, eventbus.New(), nopLogger{}, Config{
+		TaskTimeout: 10 * time.Millisecond,
+		MaxFinished: 1,
+		OnFinish: func(task entity.Task) {
+			// Callbacks run without the manager's lock held.
+			_ = m.List()
+			finished = append(finished, task.ID)
+		},
+		OnEvict: func(id string) { evicted = append(evicted, id) },
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m.Start(ctx)
+
+	first, err := m.Submit("first")
+	require.NoError(t, err)
+	failed := waitStatus(t, m, first.ID, entity.TaskStatusFailed)
+	assert.Contains(t, failed.Error, "timed out")
+
+	second, err := m.Submit("second")
+	require.NoError(t, err)
+	waitStatus(t, m, second.ID, entity.TaskStatusFailed)
+	cancel()
+	m.Wait()
+
+	_, err = m.Get(first.ID)
+	assert.ErrorIs(t, err, input.ErrTaskNotFound)
+	assert.Equal(t, []string{first.ID}, evicted)
+	assert.Equal(t, []string{first.ID, second.ID}, finished)
+	assert.Len(t, m.List(), 1)
+}
+
+func TestManager_SubmitValidation(t *testing.T) {
+	m := New(executorFunc(nil), eventbus.New(), nopLogger{}, Config{QueueSize: 1})
+
+	_, err := m.Submit("   ")
+	assert.Error(t, err)
+
+	_, err = m.Submit("one")
+	require.NoError(t, err)
+	_, err = m.Submit("two")
+	assert.ErrorIs(t, err, input.ErrQueueFull)
+}
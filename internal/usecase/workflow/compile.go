@@ -0,0 +1,193 @@
+// Package workflow turns a successful run into a parameterized action
+// sequence and executes it through the browser, so a recurring task does
+// not need the LLM unless the page has changed.
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"browser-agent/internal/domain/entity"
+)
+
+// minInputLen keeps short answers such as "yes" from being substituted
+// inside unrelated text.
+const minInputLen = 3
+
+var (
+	secretPattern   = regexp.MustCompile(`\{\{secret:([^}]+)\}\}`)
+	redactedPattern = regexp.MustCompile(`\[REDACTED(?::([a-z_]+))?\]`)
+)
+
+// Compile extracts the effective actions of a transcript: successful
+// navigate, click, fill, press_enter, scroll and query_elements calls.
+// Observation calls and failed attempts are dropped. Secrets, redacted
+// values and the user's answers to questions become variables.
+func Compile(transcript *entity.Transcript, name string) (*entity.Workflow, error) {
+	if transcript.Status != "" && transcript.Status != entity.TaskStatusCompleted {
+		return nil, fmt.Errorf("only successful runs can be compiled, this one is %s", transcript.Status)
+	}
+
+	c := &compiler{
+		workflow: &entity.Workflow{Name: name, Task: transcript.Task, CreatedAt: time.Now()},
+		seen:     make(map[string]bool),
+	}
+	c.collectInputs(transcript)
+
+	for _, turn := range transcript.Turns {
+		agent := turn.Agent
+		if i := strings.LastIndex(agent, "/"); i >= 0 {
+			agent = agent[i+1:]
+		}
+		for _, call := range turn.ToolCalls {
+			if call.IsError {
+				continue
+			}
+			step, ok, err := c.step(call)
+			if err != nil {
+				return nil, fmt.Errorf("%s call %s: %w", call.Name, call.ID, err)
+			}
+			if ok {
+				step.Agent = agent
+				c.workflow.Steps = append(c.workflow.Steps, step)
+			}
+		}
+	}
+
+	if len(c.workflow.Steps) == 0 {
+		return nil, fmt.Errorf("transcript has no browser actions to compile")
+	}
+	return c.workflow, nil
+}
+
+type compiler struct {
+	workflow *entity.Workflow
+	// inputs maps the user's answers to the variables replacing them.
+	inputs []userInput
+	seen   map[string]bool
+}
+
+type userInput struct {
+	value    string
+	variable string
+}
+
+// collectInputs declares a variable for every answer the user gave, with
+// the answer as its default.
+func (c *compiler) collectInputs(transcript *entity.Transcript) {
+	for _, turn := range transcript.Turns {
+		for _, call := range turn.ToolCalls {
+			if call.Name != string(entity.ToolUserAskQuestion) || call.IsError {
+				continue
+			}
+			answer := strings.TrimSpace(call.Result)
+			if len(answer) < minInputLen || strings.Contains(answer, "[REDACTED") {
+				continue
+			}
+			var args struct {
+				Question string `json:"question"`
+			}
+			_ = json.Unmarshal([]byte(call.Arguments), &args)
+
+			name := c.declare("input", entity.WorkflowVariable{Description: args.Question, Default: answer})
+			c.inputs = append(c.inputs, userInput{value: answer, variable: name})
+		}
+	}
+}
+
+func (c *compiler) step(call entity.TranscriptToolCall) (entity.WorkflowStep, bool, error) {
+	var args struct {
+		URL       string            `json:"url"`
+		Selectors []string          `json:"selectors"`
+		Selector  string            `json:"selector"`
+		Text      string            `json:"text"`
+		Fields    map[string]string `json:"fields"`
+		Direction string            `json:"direction"`
+		Extract   map[string]string `json:"extract"`
+		Limit     float64           `json:"limit"`
+	}
+
+	var step entity.WorkflowStep
+	switch entity.ToolName(call.Name) {
+	case entity.ToolBrowserNavigate:
+		step.Action = entity.WorkflowNavigate
+	case entity.ToolBrowserClick:
+		step.Action = entity.WorkflowClick
+	case entity.ToolBrowserFill:
+		step.Action = entity.WorkflowFill
+	case entity.ToolBrowserPressEnter:
+		step.Action = entity.WorkflowPressEnter
+		return step, true, nil
+	case entity.ToolBrowserScroll:
+		step.Action = entity.WorkflowScroll
+	case entity.ToolBrowserQueryElements:
+		step.Action = entity.WorkflowQuery
+	default:
+		return step, false, nil
+	}
+
+	if err := json.Unmarshal([]byte(call.Arguments), &args); err != nil {
+		return step, false, fmt.Errorf("invalid arguments: %w", err)
+	}
+
+	step.URL = c.parameterize(args.URL)
+	step.Selectors = args.Selectors
+	step.Direction = args.Direction
+	step.Extract = args.Extract
+	step.Limit = int(args.Limit)
+	if len(args.Fields) > 0 {
+		step.Fields = make(map[string]string, len(args.Fields))
+		for selector, text := range args.Fields {
+			step.Fields[selector] = c.parameterize(text)
+		}
+	} else {
+		step.Selector = args.Selector
+		step.Text = c.parameterize(args.Text)
+	}
+	return step, true, nil
+}
+
+// parameterize replaces secrets, redacted values and the user's answers in
+// value with variable placeholders.
+func (c *compiler) parameterize(value string) string {
+	for _, match := range secretPattern.FindAllStringSubmatch(value, -1) {
+		if !c.seen["secret:"+match[1]] {
+			c.seen["secret:"+match[1]] = true
+			c.workflow.Variables = append(c.workflow.Variables, entity.WorkflowVariable{Name: match[1], Secret: true})
+		}
+	}
+
+	// Each redacted value gets its own variable: the recording does not
+	// tell whether two of them were equal.
+	value = redactedPattern.ReplaceAllStringFunc(value, func(match string) string {
+		kind := redactedPattern.FindStringSubmatch(match)[1]
+		if kind == "" {
+			kind = "value"
+		}
+		return placeholder(c.declare(kind, entity.WorkflowVariable{Description: "redacted " + kind}))
+	})
+
+	for _, in := range c.inputs {
+		value = strings.ReplaceAll(value, in.value, placeholder(in.variable))
+	}
+	return value
+}
+
+// declare adds a variable named base, or base_2, base_3... when taken.
+func (c *compiler) declare(base string, variable entity.WorkflowVariable) string {
+	name := base
+	for i := 2; c.seen["var:"+name]; i++ {
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+	c.seen["var:"+name] = true
+	variable.Name = name
+	c.workflow.Variables = append(c.workflow.Variables, variable)
+	return name
+}
+
+func placeholder(name string) string {
+	return "{{var:" + name + "}}"
+}
@@ -0,0 +1,82 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"browser-agent/internal/domain/entity"
+)
+
+func loginRun() *entity.Transcript {
+	return &entity.Transcript{
+		Task:   "Log in and list the orders",
+		Status: entity.TaskStatusCompleted,
+		Turns: []entity.TranscriptTurn{
+			{
+				Agent: "orchestrator",
+				ToolCalls: []entity.TranscriptToolCall{
+					{Name: "user_ask_question", Arguments: `{"question":"Which account?"}`, Result: "alice"},
+					{Name: "run_agent", Arguments: `{"agent_type":"form"}`},
+				},
+			},
+			{
+				Agent: "form",
+				ToolCalls: []entity.TranscriptToolCall{
+					{Name: "browser_navigate", Arguments: `{"url":"https://shop.test/login"}`},
+					{Name: "browser_observe", Arguments: `{}`, Result: "form"},
+					{Name: "browser_click", Arguments: `{"selectors":["#old"]}`, IsError: true},
+					{Name: "browser_fill", Arguments: `{"fields":{"#user":"alice","#password":"{{secret:shop_password}}"}}`},
+					{Name: "browser_fill", Arguments: `{"selector":"#email","text":"[REDACTED:email]"}`},
+					{Name: "browser_click", Arguments: `{"selectors":["#submit"],"observe":true}`},
+				},
+			},
+			{
+				Agent: "form/extraction",
+				ToolCalls: []entity.TranscriptToolCall{
+					{Name: "browser_search", Arguments: `{"query":"orders"}`},
+					{Name: "browser_query_elements", Arguments: `{"selector":".order","extract":{"_self":"text"},"limit":5}`},
+				},
+			},
+		},
+	}
+}
+
+func TestCompile(t *testing.T) {
+	workflow, err := Compile(loginRun(), "orders")
+	require.NoError(t, err)
+
+	assert.Equal(t, "orders", workflow.Name)
+	assert.Equal(t, []entity.WorkflowStep{
+		{Action: entity.WorkflowNavigate, Agent: "form", URL: "https://shop.test/login"},
+		{Action: entity.WorkflowFill, Agent: "form", Fields: map[string]string{"#user": "{{var:input}}", "#password": "{{secret:shop_password}}"}},
+		{Action: entity.WorkflowFill, Agent: "form", Selector: "#email", Text: "{{var:email}}"},
+		{Action: entity.WorkflowClick, Agent: "form", Selectors: []string{"#submit"}},
+		{Action: entity.WorkflowQuery, Agent: "extraction", Selector: ".order", Extract: map[string]string{"_self": "text"}, Limit: 5},
+	}, workflow.Steps)
+
+	assert.ElementsMatch(t, []entity.WorkflowVariable{
+		{Name: "input", Description: "Which account?", Default: "alice"},
+		{Name: "shop_password", Secret: true},
+		{Name: "email", Description: "redacted email"},
+	}, workflow.Variables)
+}
+
+func TestCompile_RejectsUnusableRuns(t *testing.T) {
+	failed := loginRun()
+	failed.Status = entity.TaskStatusFailed
+	_, err := Compile(failed, "x")
+	assert.ErrorContains(t, err, "only successful runs")
+
+	_, err = Compile(&entity.Transcript{Turns: []entity.TranscriptTurn{{
+		ToolCalls: []entity.TranscriptToolCall{{Name: "browser_observe", Arguments: "{}"}},
+	}}}, "x")
+	assert.ErrorContains(t, err, "no browser actions")
+}
+
+func TestCompiler_Declare(t *testing.T) {
+	c := &compiler{workflow: &entity.Workflow{}, seen: make(map[string]bool)}
+	assert.Equal(t, "{{var:email}} {{var:email_2}}", c.parameterize("[REDACTED:email] [REDACTED:email]"))
+	assert.Equal(t, "{{var:value}}", c.parameterize("[REDACTED]"))
+}
@@ -0,0 +1,293 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"browser-agent/internal/application/port/input"
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/domain/entity"
+)
+
+var _ input.WorkflowRunner = (*Runner)(nil)
+
+var variablePattern = regexp.MustCompile(`\{\{var:([^}]+)\}\}`)
+
+type Config struct {
+	// OnStep is called after each step, e.g. to report progress.
+	OnStep func(step entity.WorkflowStepResult)
+}
+
+type Runner struct {
+	browser output.BrowserPort
+	secrets output.SecretsPort
+	agents  output.SimpleAgentRegistry
+	logger  output.LoggerPort
+	config  Config
+}
+
+// NewRunner executes steps directly through browser. secrets may be nil
+// when the workflow has no secret variables. agents may be nil; otherwise
+// a failed step is handed to the sub-agent that made it in the recorded
+// run, and the workflow continues if the agent succeeds.
+func NewRunner(browser output.BrowserPort, secrets output.SecretsPort, agents output.SimpleAgentRegistry, logger output.LoggerPort, config Config) *Runner {
+	return &Runner{
+		browser: browser,
+		secrets: secrets,
+		agents:  agents,
+		logger:  logger,
+		config:  config,
+	}
+}
+
+func (r *Runner) Run(ctx context.Context, workflow *entity.Workflow, vars map[string]string) (*entity.WorkflowRunResult, error) {
+	values, err := r.bind(workflow, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	started := time.Now()
+	result := &entity.WorkflowRunResult{Workflow: workflow.Name}
+	var outputs []string
+
+	failed := false
+	for i, step := range workflow.Steps {
+		stepResult := entity.WorkflowStepResult{Index: i + 1, Action: step.Action}
+
+		if failed {
+			stepResult.Status = entity.WorkflowStepNotRun
+		} else {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			stepStarted := time.Now()
+			r.runStep(ctx, expand(step, values), &stepResult)
+			stepResult.DurationMS = time.Since(stepStarted).Milliseconds()
+			failed = stepResult.Status == entity.WorkflowStepFailed
+		}
+
+		if stepResult.Status == entity.WorkflowStepRecovered {
+			result.Recovered++
+		}
+		if step.Action == entity.WorkflowQuery && stepResult.Output != "" {
+			outputs = append(outputs, stepResult.Output)
+		}
+		result.Steps = append(result.Steps, stepResult)
+		if r.config.OnStep != nil {
+			r.config.OnStep(stepResult)
+		}
+	}
+
+	result.Output = strings.Join(outputs, "\n")
+	result.DurationMS = time.Since(started).Milliseconds()
+	r.logger.Info("Workflow finished", "name", workflow.Name, "steps", len(workflow.Steps), "recovered", result.Recovered, "succeeded", result.Succeeded())
+	return result, nil
+}
+
+// bind checks that every variable has a value: given, its default, or a
+// secret known to the store.
+func (r *Runner) bind(workflow *entity.Workflow, vars map[string]string) (map[string]string, error) {
+	declared := make(map[string]bool, len(workflow.Variables))
+	values := make(map[string]string, len(workflow.Variables))
+	var missing []string
+
+	for _, variable := range workflow.Variables {
+		if variable.Secret {
+			if !r.hasSecret(variable.Name) {
+				missing = append(missing, "secret "+variable.Name)
+			}
+			continue
+		}
+		declared[variable.Name] = true
+		value, ok := vars[variable.Name]
+		if !ok {
+			value, ok = variable.Default, variable.Default != ""
+		}
+		if !ok {
+			missing = append(missing, variable.Name)
+			continue
+		}
+		values[variable.Name] = value
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing values for %s", strings.Join(missing, ", "))
+	}
+
+	for name := range vars {
+		if !declared[name] {
+			return nil, fmt.Errorf("unknown variable %q", name)
+		}
+	}
+	return values, nil
+}
+
+func (r *Runner) hasSecret(name string) bool {
+	if r.secrets == nil {
+		return false
+	}
+	for _, known := range r.secrets.Names() {
+		if known == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Runner) runStep(ctx context.Context, step entity.WorkflowStep, result *entity.WorkflowStepResult) {
+	r.logger.Debug("Running workflow step", "index", result.Index, "action", step.Action)
+
+	out, err := r.execute(ctx, step)
+	if err == nil {
+		result.Status = entity.WorkflowStepDone
+		result.Output = out
+		return
+	}
+
+	r.logger.Warn("Workflow step failed", "index", result.Index, "action", step.Action, "error", err)
+	result.Error = err.Error()
+	result.Status = entity.WorkflowStepFailed
+
+	agent, ok := r.fallbackAgent(step)
+	if !ok {
+		return
+	}
+	answer, agentErr := agent.Execute(ctx, fallbackTask(step, err))
+	if agentErr != nil {
+		result.Error += "; fallback: " + agentErr.Error()
+		return
+	}
+	result.Status = entity.WorkflowStepRecovered
+	result.Output = answer
+}
+
+func (r *Runner) fallbackAgent(step entity.WorkflowStep) (output.SimpleAgent, bool) {
+	if r.agents == nil {
+		return nil, false
+	}
+	if agent, ok := r.agents.GetBySubType(entity.SubAgentType(step.Agent)); ok {
+		return agent, true
+	}
+	return r.agents.GetBySubType(entity.SubAgentNavigation)
+}
+
+// execute runs a step with its variables expanded; secrets are resolved
+// here, right before the values reach the browser.
+func (r *Runner) execute(ctx context.Context, step entity.WorkflowStep) (string, error) {
+	switch step.Action {
+	case entity.WorkflowNavigate:
+		return "", r.browser.Navigate(ctx, step.URL)
+	case entity.WorkflowClick:
+		switch len(step.Selectors) {
+		case 0:
+			return "", fmt.Errorf("click step has no selectors")
+		case 1:
+			return "", r.browser.Click(ctx, step.Selectors[0])
+		default:
+			return "", r.browser.BatchClick(ctx, step.Selectors)
+		}
+	case entity.WorkflowFill:
+		if len(step.Fields) > 0 {
+			fields := make(map[string]string, len(step.Fields))
+			for selector, text := range step.Fields {
+				resolved, err := r.resolveSecrets(text)
+				if err != nil {
+					return "", fmt.Errorf("field %q: %w", selector, err)
+				}
+				fields[selector] = resolved
+			}
+			return "", r.browser.BatchFill(ctx, fields)
+		}
+		text, err := r.resolveSecrets(step.Text)
+		if err != nil {
+			return "", err
+		}
+		return "", r.browser.Fill(ctx, step.Selector, text)
+	case entity.WorkflowPressEnter:
+		return "", r.browser.PressEnter(ctx)
+	case entity.WorkflowScroll:
+		return "", r.browser.Scroll(ctx, step.Direction, 0)
+	case entity.WorkflowQuery:
+		found, err := r.browser.QueryElements(ctx, entity.QueryElementsRequest{
+			Selector: step.Selector,
+			Limit:    step.Limit,
+			Extract:  step.Extract,
+		})
+		if err != nil {
+			return "", err
+		}
+		return formatElements(found), nil
+	default:
+		return "", fmt.Errorf("unknown action %q", step.Action)
+	}
+}
+
+func (r *Runner) resolveSecrets(text string) (string, error) {
+	if r.secrets == nil {
+		return text, nil
+	}
+	return r.secrets.Resolve(text)
+}
+
+// expand substitutes variables; secret placeholders stay in place so that
+// they never reach logs or the LLM fallback.
+func expand(step entity.WorkflowStep, values map[string]string) entity.WorkflowStep {
+	replace := func(text string) string {
+		return variablePattern.ReplaceAllStringFunc(text, func(match string) string {
+			return values[variablePattern.FindStringSubmatch(match)[1]]
+		})
+	}
+	step.URL = replace(step.URL)
+	step.Text = replace(step.Text)
+	if len(step.Fields) > 0 {
+		fields := make(map[string]string, len(step.Fields))
+		for selector, text := range step.Fields {
+			fields[selector] = replace(text)
+		}
+		step.Fields = fields
+	}
+	return step
+}
+
+// formatElements renders query results as one JSON object per line.
+func formatElements(result *entity.QueryElementsResult) string {
+	lines := make([]string, 0, len(result.Elements))
+	for _, element := range result.Elements {
+		data, err := json.Marshal(element.Data)
+		if err != nil {
+			continue
+		}
+		lines = append(lines, string(data))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func fallbackTask(step entity.WorkflowStep, err error) string {
+	var action string
+	switch step.Action {
+	case entity.WorkflowNavigate:
+		action = "open " + step.URL
+	case entity.WorkflowClick:
+		action = "click " + strings.Join(step.Selectors, ", ")
+	case entity.WorkflowFill:
+		if len(step.Fields) > 0 {
+			data, _ := json.Marshal(step.Fields)
+			action = "fill the fields " + string(data)
+		} else {
+			action = fmt.Sprintf("fill %s with %q", step.Selector, step.Text)
+		}
+	case entity.WorkflowPressEnter:
+		action = "press Enter"
+	case entity.WorkflowScroll:
+		action = "scroll " + step.Direction
+	case entity.WorkflowQuery:
+		data, _ := json.Marshal(step.Extract)
+		action = fmt.Sprintf("extract %s from the elements matching %s", data, step.Selector)
+	}
+	return fmt.Sprintf("A scripted step failed because the page has changed: %s (error: %v). "+
+		"Perform this step on the current page with the elements that now serve the same purpose, then stop. "+
+		"Pass {{secret:...}} placeholders verbatim.", action, err)
+}
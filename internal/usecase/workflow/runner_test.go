@@ -0,0 +1,148 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"browser-agent/internal/application/port/output"
+	"browser-agent/internal/application/service"
+	"browser-agent/internal/domain/entity"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...any)                          {}
+func (nopLogger) Info(string, ...any)                           {}
+func (nopLogger) Warn(string, ...any)                           {}
+func (nopLogger) Error(string, ...any)                          {}
+func (l nopLogger) WithField(string, any) output.LoggerPort     { return l }
+func (l nopLogger) WithFields(map[string]any) output.LoggerPort { return l }
+func (nopLogger) Close() error                                  { return nil }
+
+// fakeBrowser records the actions; clicks on missing selectors fail.
+type fakeBrowser struct {
+	output.BrowserPort
+	missing map[string]bool
+	actions []string
+}
+
+func (b *fakeBrowser) Navigate(_ context.Context, url string) error {
+	b.actions = append(b.actions, "navigate "+url)
+	return nil
+}
+
+func (b *fakeBrowser) Click(_ context.Context, selector string) error {
+	if b.missing[selector] {
+		return errors.New("element not found: " + selector)
+	}
+	b.actions = append(b.actions, "click "+selector)
+	return nil
+}
+
+func (b *fakeBrowser) Fill(_ context.Context, selector, text string) error {
+	b.actions = append(b.actions, "fill "+selector+"="+text)
+	return nil
+}
+
+func (b *fakeBrowser) QueryElements(_ context.Context, req entity.QueryElementsRequest) (*entity.QueryElementsResult, error) {
+	return &entity.QueryElementsResult{Count: 1, Elements: []entity.ElementData{{Data: map[string]string{"_self": "order 1"}}}}, nil
+}
+
+type fakeSecrets struct{ values map[string]string }
+
+func (s fakeSecrets) Redact(text string) string { return text }
+func (s fakeSecrets) Names() []string {
+	var names []string
+	for name := range s.values {
+		names = append(names, name)
+	}
+	return names
+}
+func (s fakeSecrets) Resolve(text string) (string, error) {
+	for name, value := range s.values {
+		text = strings.ReplaceAll(text, "{{secret:"+name+"}}", value)
+	}
+	return text, nil
+}
+
+type fakeAgent struct {
+	subType entity.SubAgentType
+	tasks   []string
+	err     error
+}
+
+func (a *fakeAgent) GetType() entity.AgentType            { return "" }
+func (a *fakeAgent) GetSubAgentType() entity.SubAgentType { return a.subType }
+func (a *fakeAgent) GetDescription() string               { return "" }
+func (a *fakeAgent) Execute(_ context.Context, task string) (string, error) {
+	a.tasks = append(a.tasks, task)
+	return "clicked the new button", a.err
+}
+
+func script() *entity.Workflow {
+	return &entity.Workflow{
+		Name: "orders",
+		Variables: []entity.WorkflowVariable{
+			{Name: "user", Default: "alice"},
+			{Name: "password", Secret: true},
+		},
+		Steps: []entity.WorkflowStep{
+			{Action: entity.WorkflowNavigate, URL: "https://shop.test/{{var:user}}"},
+			{Action: entity.WorkflowFill, Selector: "#password", Text: "{{secret:password}}"},
+			{Action: entity.WorkflowClick, Agent: "form", Selectors: []string{"#submit"}},
+			{Action: entity.WorkflowQuery, Selector: ".order", Extract: map[string]string{"_self": "text"}},
+		},
+	}
+}
+
+func TestRunner_RunsThroughBrowser(t *testing.T) {
+	browser := &fakeBrowser{}
+	secrets := fakeSecrets{values: map[string]string{"password": "hunter2"}}
+
+	result, err := NewRunner(browser, secrets, nil, nopLogger{}, Config{}).Run(context.Background(), script(), map[string]string{"user": "bob"})
+	require.NoError(t, err)
+
+	assert.True(t, result.Succeeded())
+	assert.Equal(t, []string{"navigate https://shop.test/bob", "fill #password=hunter2", "click #submit"}, browser.actions)
+	assert.Equal(t, `{"_self":"order 1"}`, result.Output)
+}
+
+func TestRunner_FallsBackToAgent(t *testing.T) {
+	browser := &fakeBrowser{missing: map[string]bool{"#submit": true}}
+	secrets := fakeSecrets{values: map[string]string{"password": "hunter2"}}
+	agent := &fakeAgent{subType: entity.SubAgentForm}
+	agents := service.NewSimpleAgentRegistry()
+	agents.Register(agent)
+
+	result, err := NewRunner(browser, secrets, agents, nopLogger{}, Config{}).Run(context.Background(), script(), nil)
+	require.NoError(t, err)
+
+	assert.True(t, result.Succeeded())
+	assert.Equal(t, 1, result.Recovered)
+	assert.Equal(t, entity.WorkflowStepRecovered, result.Steps[2].Status)
+	require.Len(t, agent.tasks, 1)
+	assert.Contains(t, agent.tasks[0], "click #submit")
+
+	// Without a fallback the run stops at the broken step.
+	result, err = NewRunner(browser, secrets, nil, nopLogger{}, Config{}).Run(context.Background(), script(), nil)
+	require.NoError(t, err)
+	assert.False(t, result.Succeeded())
+	assert.Equal(t, entity.WorkflowStepFailed, result.Steps[2].Status)
+	assert.Equal(t, entity.WorkflowStepNotRun, result.Steps[3].Status)
+}
+
+func TestRunner_ChecksVariables(t *testing.T) {
+	runner := NewRunner(&fakeBrowser{}, fakeSecrets{}, nil, nopLogger{}, Config{})
+
+	_, err := runner.Run(context.Background(), script(), nil)
+	assert.ErrorContains(t, err, "secret password")
+
+	runner = NewRunner(&fakeBrowser{}, fakeSecrets{values: map[string]string{"password": "x"}}, nil, nopLogger{}, Config{})
+	_, err = runner.Run(context.Background(), script(), map[string]string{"usr": "typo"})
+	assert.ErrorContains(t, err, `unknown variable "usr"`)
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"browser-agent/internal/domain/entity"
+	"browser-agent/internal/domain/policy"
 	"browser-agent/internal/infrastructure/browser/rod"
 
 	"github.com/stretchr/testify/assert"
@@ -857,3 +858,63 @@ func TestBrowserAdapter_ValidateSelector(t *testing.T) {
 		})
 	}
 }
+
+func TestBrowserAdapter_NavigationPolicy(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<!DOCTYPE html>
+<html>
+<body>
+	<a id="adminLink" href="/admin/users">Admin</a>
+</body>
+</html>`)
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/admin/panel", http.StatusFound)
+	})
+	mux.HandleFunc("/admin/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "secret")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	ctx := context.Background()
+	cfg := rod.DefaultConfig()
+	cfg.Headless = true
+	cfg.SlowMotion = 0
+	cfg.NavigationPolicy = policy.Navigation{
+		BlockedPaths:   []string{"/admin"},
+		DenyFileAccess: true,
+	}
+
+	adapter, err := rod.NewBrowserAdapter(ctx, cfg)
+	require.NoError(t, err)
+	defer adapter.Close()
+
+	require.NoError(t, adapter.Navigate(ctx, server.URL))
+
+	t.Run("direct navigation", func(t *testing.T) {
+		err := adapter.Navigate(ctx, server.URL+"/admin/panel")
+		assert.ErrorIs(t, err, policy.ErrNavigationBlocked)
+	})
+
+	t.Run("redirect", func(t *testing.T) {
+		err := adapter.Navigate(ctx, server.URL+"/redirect")
+		assert.ErrorIs(t, err, policy.ErrNavigationBlocked)
+		assert.NotContains(t, adapter.CurrentURL(), "/admin")
+	})
+
+	t.Run("link click", func(t *testing.T) {
+		require.NoError(t, adapter.Navigate(ctx, server.URL))
+		result, err := adapter.ClickWithChanges(ctx, "#adminLink")
+		assert.ErrorIs(t, err, policy.ErrNavigationBlocked)
+		assert.False(t, result.Success)
+		assert.NotContains(t, adapter.CurrentURL(), "/admin")
+	})
+
+	t.Run("file access", func(t *testing.T) {
+		err := adapter.Navigate(ctx, "file:///etc/hosts")
+		assert.ErrorIs(t, err, policy.ErrNavigationBlocked)
+	})
+}